@@ -23,8 +23,10 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
       infracost breakdown --path plan.json`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
-				return err
+			if !ctx.Config.IsOffline() {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
 			}
 
 			err := loadRunFlags(ctx.Config, cmd)
@@ -49,7 +51,7 @@ func breakdownCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("out-file", "", "Save output to a file, helpful with format flag")
 	cmd.Flags().Bool("terraform-use-state", false, "Use Terraform state instead of generating a plan. Applicable with --terraform-force-cli")
 	newEnumFlag(cmd, "format", "table", "Output format", []string{"json", "table", "html"})
-	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,listPrice,price,monthlyQuantity,unit,hourlyCost,committedMonthlyCost,monthlyCost.\nSupported by table and html output formats")
 
 	// This is deprecated and will show a warning if used without --terraform-force-cli
 	_ = cmd.Flags().MarkHidden("terraform-use-state")
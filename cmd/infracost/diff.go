@@ -33,8 +33,10 @@ func diffCmd(ctx *config.RunContext) *cobra.Command {
       infracost diff --path plan.json`,
 		ValidArgs: []string{"--", "-"},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
-				return err
+			if !ctx.Config.IsOffline() {
+				if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+					return err
+				}
 			}
 
 			err := loadRunFlags(ctx.Config, cmd)
@@ -105,7 +105,7 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 			combined.Metadata.InfracostCommand = "output"
 
 			includeAllFields := "all"
-			validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
+			validFields := []string{"listPrice", "price", "monthlyQuantity", "unit", "hourlyCost", "committedMonthlyCost", "monthlyCost"}
 
 			fields := []string{"monthlyQuantity", "unit", "monthlyCost"}
 			if cmd.Flags().Changed("fields") {
@@ -127,6 +127,13 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 				}
 			}
 
+			if !cmd.Flags().Changed("fields") && combined.HasPriceAdjustments() {
+				fields = withPriceAdjustmentFields(fields)
+			}
+			if !cmd.Flags().Changed("fields") && combined.TotalCommittedMonthlyCost != nil {
+				fields = withCommittedCostFields(fields)
+			}
+
 			opts := output.Options{
 				DashboardEndpoint: ctx.Config.DashboardEndpoint,
 				NoColor:           ctx.Config.NoColor,
@@ -180,7 +187,7 @@ func outputCmd(ctx *config.RunContext) *cobra.Command {
 	cmd.Flags().String("format", "table", "Output format: json, diff, table, html, github-comment, gitlab-comment, azure-repos-comment, bitbucket-comment, bitbucket-comment-summary, slack-message")
 	cmd.Flags().Bool("show-all-projects", false, "Show all projects in the table of the comment output")
 	cmd.Flags().Bool("show-skipped", false, "List unsupported and free resources")
-	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,price,monthlyQuantity,unit,hourlyCost,monthlyCost.\nSupported by table and html output formats")
+	cmd.Flags().StringSlice("fields", []string{"monthlyQuantity", "unit", "monthlyCost"}, "Comma separated list of output fields: all,listPrice,price,monthlyQuantity,unit,hourlyCost,committedMonthlyCost,monthlyCost.\nSupported by table and html output formats")
 
 	_ = cmd.MarkFlagRequired("path")
 	_ = cmd.MarkFlagFilename("path", "json")
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/logging"
+	"github.com/infracost/infracost/internal/prices"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/infracost/infracost/internal/vcs"
+)
+
+func pricingCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage local pricing snapshots for offline runs",
+		Long:  "Manage local pricing snapshots for offline runs",
+		Example: `  Create a pricing snapshot of the resources in a Terraform directory:
+
+      infracost pricing snapshot --path /code --out-file infracost-prices.jsonl.gz
+
+  Use the pricing snapshot without access to the pricing API:
+
+      INFRACOST_PRICING_SNAPSHOT_FILE=infracost-prices.jsonl.gz infracost breakdown --path /code`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmds := []*cobra.Command{pricingSnapshotCmd(ctx)}
+	cmd.AddCommand(cmds...)
+
+	return cmd
+}
+
+func pricingSnapshotCmd(ctx *config.RunContext) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Create or refresh a pricing snapshot from the pricing API",
+		Long: `Create or refresh a pricing snapshot from the pricing API
+
+The snapshot has the products and prices of the resources of the given projects. If the out file
+exists, the products are added to it and their prices are refreshed, so one snapshot can be built
+from multiple runs. Gzip the snapshot by giving the out file a .gz extension.
+
+Set INFRACOST_PRICING_SNAPSHOT_FILE to the snapshot to resolve prices from it instead of the
+pricing API, e.g. on build agents that can't reach the pricing API.`,
+		Example: `  Create a pricing snapshot from a Terraform directory:
+
+      infracost pricing snapshot --path /code --out-file infracost-prices.jsonl.gz
+
+  Add the prices of multiple projects and currencies to a snapshot:
+
+      infracost pricing snapshot --config-file infracost.yml --out-file infracost-prices.jsonl.gz
+      INFRACOST_CURRENCY=EUR infracost pricing snapshot --config-file infracost.yml --out-file infracost-prices.jsonl.gz`,
+		ValidArgs: []string{"--", "-"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkAPIKey(ctx.Config.APIKey, ctx.Config.PricingAPIEndpoint, ctx.Config.DefaultPricingAPIEndpoint); err != nil {
+				return err
+			}
+
+			err := loadRunFlags(ctx.Config, cmd)
+			if err != nil {
+				return err
+			}
+
+			err = checkRunConfig(cmd.ErrOrStderr(), ctx.Config)
+			if err != nil {
+				ui.PrintUsage(cmd)
+				return err
+			}
+
+			return runPricingSnapshot(cmd, ctx)
+		},
+	}
+
+	addRunFlags(cmd)
+
+	cmd.Flags().String("out-file", "", "Path of the pricing snapshot to create or refresh")
+	_ = cmd.MarkFlagRequired("out-file")
+	_ = cmd.MarkFlagFilename("out-file", "jsonl", "gz")
+
+	return cmd
+}
+
+func runPricingSnapshot(cmd *cobra.Command, runCtx *config.RunContext) error {
+	outFile, _ := cmd.Flags().GetString("out-file")
+
+	// Prices are always fetched from the pricing API when creating a snapshot
+	runCtx.Config.PricingSnapshotFile = ""
+
+	snapshot := apiclient.NewPriceSnapshot()
+	if _, err := os.Stat(outFile); err == nil {
+		snapshot, err = apiclient.LoadPriceSnapshot(outFile)
+		if err != nil {
+			return err
+		}
+	}
+	existing := snapshot.Len()
+
+	prices.RecordSnapshot(runCtx, snapshot)
+
+	repoPath := runCtx.Config.RepoPath()
+	metadata, err := vcs.MetadataFetcher.Get(repoPath, runCtx.Config.GitDiffTarget)
+	if err != nil {
+		logging.Logger.WithError(err).Debugf("failed to fetch vcs metadata for path %s", repoPath)
+	}
+	runCtx.VCSMetadata = metadata
+
+	pr, err := newParallelRunner(cmd, runCtx)
+	if err != nil {
+		return err
+	}
+
+	projectResults, err := pr.run()
+	if err != nil {
+		return err
+	}
+
+	projects := make([]*schema.Project, 0)
+	for _, projectResult := range projectResults {
+		projects = append(projects, projectResult.projectOut.projects...)
+	}
+
+	err = checkIfAllProjectsErrored(projects)
+	if err != nil {
+		return err
+	}
+
+	err = snapshot.Write(outFile)
+	if err != nil {
+		return err
+	}
+
+	msg := fmt.Sprintf("Pricing snapshot saved to %s with %d products, %d of them new", outFile, snapshot.Len(), snapshot.Len()-existing)
+	if runCtx.Config.IsLogging() {
+		logging.Logger.Info(msg)
+	} else {
+		cmd.PrintErrf("%s\n", msg)
+	}
+
+	return nil
+}
@@ -151,11 +151,19 @@ func runMain(cmd *cobra.Command, runCtx *config.RunContext) error {
 		return errors.New("The --compare-to option cannot be used with table and html formats as they output breakdowns, specify a different --format.")
 	}
 
+	fields := runCtx.Config.Fields
+	if !cmd.Flags().Changed("fields") && r.HasPriceAdjustments() {
+		fields = withPriceAdjustmentFields(fields)
+	}
+	if !cmd.Flags().Changed("fields") && r.TotalCommittedMonthlyCost != nil {
+		fields = withCommittedCostFields(fields)
+	}
+
 	b, err := output.FormatOutput(format, r, output.Options{
 		DashboardEndpoint: runCtx.Config.DashboardEndpoint,
 		ShowSkipped:       runCtx.Config.ShowSkipped,
 		NoColor:           runCtx.Config.NoColor,
-		Fields:            runCtx.Config.Fields,
+		Fields:            fields,
 		CurrencyFormat:    runCtx.Config.CurrencyFormat,
 	})
 	if err != nil {
@@ -825,7 +833,7 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	cfg.SyncUsageFile, _ = cmd.Flags().GetBool("sync-usage-file")
 
 	includeAllFields := "all"
-	validFields := []string{"price", "monthlyQuantity", "unit", "hourlyCost", "monthlyCost"}
+	validFields := []string{"listPrice", "price", "monthlyQuantity", "unit", "hourlyCost", "committedMonthlyCost", "monthlyCost"}
 	validFieldsFormats := []string{"table", "html"}
 
 	if cmd.Flags().Changed("fields") {
@@ -852,6 +860,41 @@ func loadRunFlags(cfg *config.Config, cmd *cobra.Command) error {
 	return nil
 }
 
+// withPriceAdjustmentFields adds the list price and the adjusted price to the output fields, so
+// they're shown side by side when prices have been adjusted.
+func withPriceAdjustmentFields(fields []string) []string {
+	adjusted := []string{"listPrice", "price"}
+	for _, f := range fields {
+		if !contains(adjusted, f) {
+			adjusted = append(adjusted, f)
+		}
+	}
+
+	return adjusted
+}
+
+// withCommittedCostFields adds the committed monthly cost to the output fields before the monthly
+// cost, so the on-demand and committed costs are shown side by side.
+func withCommittedCostFields(fields []string) []string {
+	if contains(fields, "committedMonthlyCost") {
+		return fields
+	}
+
+	committed := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		if f == "monthlyCost" {
+			committed = append(committed, "committedMonthlyCost")
+		}
+		committed = append(committed, f)
+	}
+
+	if !contains(committed, "committedMonthlyCost") {
+		committed = append(committed, "committedMonthlyCost")
+	}
+
+	return committed
+}
+
 func tfVarsToMap(vars []string) map[string]string {
 	if len(vars) == 0 {
 		return nil
@@ -891,6 +934,14 @@ func checkRunConfig(warningWriter io.Writer, cfg *config.Config) error {
 		}
 	}
 
+	if err := prices.ValidateCommitmentScenario(cfg.CommitmentScenario); err != nil {
+		return err
+	}
+
+	if cfg.AzurePricingSource != "" && !cfg.UsesAzureRetailPrices() {
+		return fmt.Errorf("Invalid Azure pricing source '%s', the only supported source is %s", cfg.AzurePricingSource, config.AzureRetailPricingSource)
+	}
+
 	if money.GetCurrency(cfg.Currency) == nil {
 		ui.PrintWarning(warningWriter, fmt.Sprintf("Ignoring unknown currency '%s', using USD.\n", cfg.Currency))
 		cfg.Currency = "USD"
@@ -0,0 +1,333 @@
+package apiclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dlclark/regexp2"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// DefaultAzureRetailPricesEndpoint is the public Azure Retail Prices API.
+const DefaultAzureRetailPricesEndpoint = "https://prices.azure.com/api/retail/prices"
+
+// azureRetailFilterFields are the fields of the Azure Retail Prices API that can be used in its
+// $filter parameter. The attributes of Azure products in the pricing API have the same names.
+var azureRetailFilterFields = map[string]struct{}{
+	"armRegionName": {},
+	"armSkuName":    {},
+	"location":      {},
+	"meterId":       {},
+	"meterName":     {},
+	"productId":     {},
+	"productName":   {},
+	"serviceFamily": {},
+	"serviceId":     {},
+	"serviceName":   {},
+	"skuId":         {},
+	"skuName":       {},
+}
+
+// AzureRetailPricesClient resolves the price queries of Azure cost components against the Azure
+// Retail Prices API instead of the pricing API. The product and price filters of the queries are
+// translated to the fields of the Retail Prices API, and the prices are returned in the shape of
+// the pricing API so they can be used in its place.
+type AzureRetailPricesClient struct {
+	httpClient *http.Client
+	endpoint   string
+
+	mu      sync.RWMutex
+	regexes map[string]*regexp2.Regexp
+}
+
+// azureRetailPricesPage is a page of items of the Azure Retail Prices API. Items are kept as maps
+// since the product filters can use any of their fields.
+type azureRetailPricesPage struct {
+	Items        []map[string]interface{} `json:"Items"`
+	NextPageLink string                   `json:"NextPageLink"`
+}
+
+func NewAzureRetailPricesClient(httpClient *http.Client, endpoint string) *AzureRetailPricesClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if endpoint == "" {
+		endpoint = DefaultAzureRetailPricesEndpoint
+	}
+
+	return &AzureRetailPricesClient{
+		httpClient: httpClient,
+		endpoint:   endpoint,
+		regexes:    make(map[string]*regexp2.Regexp),
+	}
+}
+
+// IsAzureQuery returns true if the query is for the price of an Azure product.
+func IsAzureQuery(q GraphQLQuery) bool {
+	f, _ := q.Variables["productFilter"].(*schema.ProductFilter)
+	return f != nil && f.VendorName != nil && *f.VendorName == "azure"
+}
+
+// RunQueries resolves the queries against the Azure Retail Prices API in the given currency.
+func (c *AzureRetailPricesClient) RunQueries(currency string, queries []GraphQLQuery) ([]gjson.Result, error) {
+	results := make([]gjson.Result, 0, len(queries))
+
+	for _, q := range queries {
+		productFilter, _ := q.Variables["productFilter"].(*schema.ProductFilter)
+		priceFilter, _ := q.Variables["priceFilter"].(*schema.PriceFilter)
+
+		items, err := c.fetchItems(currency, azureRetailFilter(productFilter, priceFilter))
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, c.buildResult(currency, items, productFilter, priceFilter))
+	}
+
+	return results, nil
+}
+
+// azureRetailFilter translates the filters to an OData filter of the Azure Retail Prices API.
+// Regexes, and fields that the API can't filter by, are matched after the items are fetched.
+func azureRetailFilter(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) string {
+	var clauses []string
+	add := func(field string, value *string) {
+		if value != nil {
+			clauses = append(clauses, fmt.Sprintf("%s eq '%s'", field, strings.ReplaceAll(*value, "'", "''")))
+		}
+	}
+
+	if productFilter != nil {
+		add("serviceName", productFilter.Service)
+		add("serviceFamily", productFilter.ProductFamily)
+		add("armRegionName", productFilter.Region)
+		add("skuId", productFilter.Sku)
+
+		for _, a := range productFilter.AttributeFilters {
+			if a == nil {
+				continue
+			}
+
+			if _, ok := azureRetailFilterFields[a.Key]; ok {
+				add(a.Key, a.Value)
+			}
+		}
+	}
+
+	if priceFilter != nil {
+		add("priceType", priceFilter.PurchaseOption)
+	}
+
+	return strings.Join(clauses, " and ")
+}
+
+// fetchItems fetches the items matching the filter from all the pages of the Azure Retail Prices API.
+func (c *AzureRetailPricesClient) fetchItems(currency string, filter string) ([]map[string]interface{}, error) {
+	params := url.Values{}
+	params.Set("currencyCode", currency)
+	if filter != "" {
+		params.Set("$filter", filter)
+	}
+
+	next := c.endpoint + "?" + params.Encode()
+	seen := make(map[string]bool)
+
+	var items []map[string]interface{}
+	for next != "" && !seen[next] {
+		seen[next] = true
+
+		log.Debugf("Getting prices from the Azure Retail Prices API: %s", next)
+		page, err := c.fetchPage(next)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Items...)
+		next = page.NextPageLink
+	}
+
+	return items, nil
+}
+
+func (c *AzureRetailPricesClient) fetchPage(pageURL string) (*azureRetailPricesPage, error) {
+	resp, err := c.httpClient.Get(pageURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error sending request to the Azure Retail Prices API")
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading response from the Azure Retail Prices API")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Azure Retail Prices API returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	// Keep the exact prices of the API rather than parsing them as floats
+	dec.UseNumber()
+
+	var page azureRetailPricesPage
+	err = dec.Decode(&page)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing response from the Azure Retail Prices API")
+	}
+
+	return &page, nil
+}
+
+// buildResult groups the items that match the filters by product, in the shape of the pricing API.
+func (c *AzureRetailPricesClient) buildResult(currency string, items []map[string]interface{}, productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) gjson.Result {
+	productPrices := make(map[string][]map[string]string)
+	var productKeys []string
+
+	for _, item := range items {
+		// The API returns USD prices for currencies it doesn't support
+		if code := azureRetailField(item, "currencyCode"); code != "" && code != currency {
+			continue
+		}
+
+		if !c.matchItem(item, productFilter, priceFilter) {
+			continue
+		}
+
+		key := strings.Join([]string{azureRetailField(item, "productId"), azureRetailField(item, "skuId"), azureRetailField(item, "meterId")}, "/")
+		if _, ok := productPrices[key]; !ok {
+			productKeys = append(productKeys, key)
+		}
+
+		productPrices[key] = append(productPrices[key], map[string]string{
+			"priceHash": azureRetailPriceHash(item),
+			currency:    azureRetailField(item, "retailPrice"),
+		})
+	}
+
+	sort.Strings(productKeys)
+
+	products := make([]interface{}, 0, len(productKeys))
+	for _, key := range productKeys {
+		products = append(products, map[string]interface{}{"prices": productPrices[key]})
+	}
+
+	b, _ := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{"products": products},
+	})
+
+	return gjson.ParseBytes(b)
+}
+
+func (c *AzureRetailPricesClient) matchItem(item map[string]interface{}, productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) bool {
+	if productFilter != nil {
+		if !matchesField(azureRetailField(item, "serviceName"), productFilter.Service) ||
+			!matchesField(azureRetailField(item, "serviceFamily"), productFilter.ProductFamily) ||
+			!matchesField(azureRetailField(item, "armRegionName"), productFilter.Region) ||
+			!matchesField(azureRetailField(item, "skuId"), productFilter.Sku) {
+			return false
+		}
+
+		for _, a := range productFilter.AttributeFilters {
+			if a == nil {
+				continue
+			}
+
+			value := azureRetailField(item, a.Key)
+			if a.Value != nil && value != *a.Value {
+				return false
+			}
+
+			if a.ValueRegex != nil && !c.matchRegex(value, *a.ValueRegex) {
+				return false
+			}
+		}
+	}
+
+	if priceFilter != nil {
+		if !matchesField(azureRetailField(item, "type"), priceFilter.PurchaseOption) ||
+			!matchesField(azureRetailField(item, "unitOfMeasure"), priceFilter.Unit) ||
+			!matchesField(azureRetailField(item, "reservationTerm"), priceFilter.TermLength) {
+			return false
+		}
+
+		if priceFilter.StartUsageAmount != nil && !matchesAmount(azureRetailField(item, "tierMinimumUnits"), *priceFilter.StartUsageAmount) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesAmount compares amounts numerically, since the API returns tiers such as 0.0 and the
+// pricing API uses 0.
+func matchesAmount(value string, filter string) bool {
+	v, err := decimal.NewFromString(value)
+	if err != nil {
+		return false
+	}
+
+	f, err := decimal.NewFromString(filter)
+	if err != nil {
+		return false
+	}
+
+	return v.Equal(f)
+}
+
+// matchRegex matches value against a regex of a filter, which can have lookarounds like the regexes
+// the pricing API matches, see compileFilterRegex.
+func (c *AzureRetailPricesClient) matchRegex(value string, regex string) bool {
+	c.mu.RLock()
+	re, ok := c.regexes[regex]
+	c.mu.RUnlock()
+
+	if !ok {
+		var err error
+		re, err = compileFilterRegex(regex)
+		if err != nil {
+			log.Warnf("Azure Retail Prices API results can't be matched with the regex %s: %v", regex, err)
+		}
+
+		c.mu.Lock()
+		c.regexes[regex] = re
+		c.mu.Unlock()
+	}
+
+	return matchFilterRegex(re, value)
+}
+
+func azureRetailField(item map[string]interface{}, key string) string {
+	v, ok := item[key]
+	if !ok || v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// azureRetailPriceHash identifies a price by its meter and the terms it's sold with.
+func azureRetailPriceHash(item map[string]interface{}) string {
+	key := strings.Join([]string{
+		azureRetailField(item, "meterId"),
+		azureRetailField(item, "skuId"),
+		azureRetailField(item, "type"),
+		azureRetailField(item, "reservationTerm"),
+		azureRetailField(item, "tierMinimumUnits"),
+	}, "/")
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(key)))[:32]
+}
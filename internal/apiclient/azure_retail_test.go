@@ -0,0 +1,167 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// newAzureRetailPricesServer serves the recorded pages of the Azure Retail Prices API in testdata
+// and records the filters of the requests.
+func newAzureRetailPricesServer(t *testing.T, filters *[]string) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := "vm_page1.json"
+		if r.URL.Query().Get("$skip") != "" {
+			page = "vm_page2.json"
+		} else {
+			*filters = append(*filters, r.URL.Query().Get("$filter"))
+		}
+
+		b, err := os.ReadFile(filepath.Join("testdata", "azure_retail_prices", page))
+		require.NoError(t, err)
+
+		_, _ = w.Write([]byte(strings.ReplaceAll(string(b), "{{.Server}}", server.URL)))
+	}))
+
+	return server
+}
+
+func azureVMQuery(priceFilter *schema.PriceFilter, attrs ...*schema.AttributeFilter) GraphQLQuery {
+	return GraphQLQuery{
+		Variables: map[string]interface{}{
+			"productFilter": &schema.ProductFilter{
+				VendorName:       strPtr("azure"),
+				Region:           strPtr("eastus"),
+				Service:          strPtr("Virtual Machines"),
+				ProductFamily:    strPtr("Compute"),
+				AttributeFilters: attrs,
+			},
+			"priceFilter": priceFilter,
+		},
+	}
+}
+
+func TestAzureRetailPricesClientRunQueries(t *testing.T) {
+	var filters []string
+	server := newAzureRetailPricesServer(t, &filters)
+	defer server.Close()
+
+	c := NewAzureRetailPricesClient(server.Client(), server.URL+"/api/retail/prices")
+
+	results, err := c.RunQueries("USD", []GraphQLQuery{
+		azureVMQuery(
+			&schema.PriceFilter{PurchaseOption: strPtr("Consumption"), Unit: strPtr("1 Hour")},
+			&schema.AttributeFilter{Key: "armSkuName", Value: strPtr("Standard_D2s_v3")},
+			&schema.AttributeFilter{Key: "meterName", ValueRegex: strPtr("/^D2s v3$/i")},
+		),
+		azureVMQuery(
+			&schema.PriceFilter{PurchaseOption: strPtr("Reservation"), TermLength: strPtr("1 Year"), StartUsageAmount: strPtr("0")},
+			&schema.AttributeFilter{Key: "skuName", Value: strPtr("D2s v3")},
+		),
+		azureVMQuery(
+			&schema.PriceFilter{PurchaseOption: strPtr("Consumption")},
+			&schema.AttributeFilter{Key: "skuName", Value: strPtr("D4s v3")},
+		),
+		// Regexes of the pricing API can have lookarounds
+		azureVMQuery(
+			&schema.PriceFilter{PurchaseOption: strPtr("Consumption"), Unit: strPtr("1 Hour")},
+			&schema.AttributeFilter{Key: "armSkuName", Value: strPtr("Standard_D2s_v3")},
+			&schema.AttributeFilter{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")},
+		),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, []string{
+		"serviceName eq 'Virtual Machines' and serviceFamily eq 'Compute' and armRegionName eq 'eastus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'",
+		"serviceName eq 'Virtual Machines' and serviceFamily eq 'Compute' and armRegionName eq 'eastus' and skuName eq 'D2s v3' and priceType eq 'Reservation'",
+		"serviceName eq 'Virtual Machines' and serviceFamily eq 'Compute' and armRegionName eq 'eastus' and skuName eq 'D4s v3' and priceType eq 'Consumption'",
+		"serviceName eq 'Virtual Machines' and serviceFamily eq 'Compute' and armRegionName eq 'eastus' and armSkuName eq 'Standard_D2s_v3' and priceType eq 'Consumption'",
+	}, filters)
+
+	// The items of all pages are matched, keeping the exact prices of the API
+	prices := results[0].Get("data.products.0.prices").Array()
+	require.Len(t, prices, 1)
+	assert.Equal(t, "0.096", prices[0].Get("USD").String())
+	assert.NotEmpty(t, prices[0].Get("priceHash").String())
+	assert.Len(t, results[0].Get("data.products").Array(), 1)
+
+	assert.Equal(t, "500", results[1].Get("data.products.0.prices.0.USD").String())
+	assert.Empty(t, results[2].Get("data.products").Array())
+
+	var lookaroundPrices []string
+	for _, p := range results[3].Get("data.products.#.prices.0.USD").Array() {
+		lookaroundPrices = append(lookaroundPrices, p.String())
+	}
+	assert.NotEmpty(t, lookaroundPrices)
+	assert.NotContains(t, lookaroundPrices, "0.0192")
+}
+
+func TestAzureRetailPricesClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"Error":{"Code":"BadRequest","Message":"Invalid OData parameters supplied"}}`))
+	}))
+	defer server.Close()
+
+	c := NewAzureRetailPricesClient(server.Client(), server.URL)
+	_, err := c.RunQueries("USD", []GraphQLQuery{azureVMQuery(nil)})
+	assert.ErrorContains(t, err, "Azure Retail Prices API returned 400 Bad Request")
+}
+
+func TestPricingAPIClientAzureRetailPrices(t *testing.T) {
+	var filters []string
+	azureServer := newAzureRetailPricesServer(t, &filters)
+	defer azureServer.Close()
+
+	pricingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+		// Only the queries of other vendors are sent to the pricing API
+		require.Len(t, queries, 1)
+
+		_, _ = w.Write([]byte(`[{"data":{"products":[{"prices":[{"priceHash":"ec2","USD":"0.0416"}]}]}}]`))
+	}))
+	defer pricingServer.Close()
+
+	c := &PricingAPIClient{
+		APIClient:   APIClient{httpClient: pricingServer.Client(), endpoint: pricingServer.URL},
+		Currency:    "USD",
+		AzureRetail: NewAzureRetailPricesClient(azureServer.Client(), azureServer.URL),
+	}
+
+	vm := azureVMQuery(&schema.PriceFilter{PurchaseOption: strPtr("Consumption")}, &schema.AttributeFilter{Key: "skuName", Value: strPtr("D2s v3")})
+	r := &schema.Resource{
+		Name: "vms",
+		CostComponents: []*schema.CostComponent{
+			{
+				Name:          "Azure VM",
+				ProductFilter: vm.Variables["productFilter"].(*schema.ProductFilter),
+				PriceFilter:   vm.Variables["priceFilter"].(*schema.PriceFilter),
+			},
+			{
+				Name:          "EC2 instance",
+				ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")},
+				PriceFilter:   &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+			},
+		},
+	}
+
+	results, err := c.RunQueries(r)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Azure VM", results[0].CostComponent.Name)
+	assert.Equal(t, "0.096", results[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, "EC2 instance", results[1].CostComponent.Name)
+	assert.Equal(t, "0.0416", results[1].Result.Get("data.products.0.prices.0.USD").String())
+}
@@ -0,0 +1,173 @@
+package apiclient
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// DefaultPriceCacheTTL is how long prices are cached on disk when no TTL is configured.
+var DefaultPriceCacheTTL = 24 * time.Hour
+
+// PriceQueryCache caches the results of price queries by a hash of the query, which includes
+// the product filter, price filter and currency. Results are kept in memory, so identical queries
+// of different resources and projects are only sent once per run, and on disk in dir for ttl,
+// so they're reused by later runs. The disk cache is disabled if dir is empty.
+type PriceQueryCache struct {
+	dir string
+	ttl time.Duration
+
+	mu      sync.Mutex
+	results map[string]gjson.Result
+	// pending are the queries being fetched, which are closed once the result is cached
+	// or the request has failed
+	pending map[string]chan struct{}
+}
+
+func NewPriceQueryCache(dir string, ttl time.Duration) *PriceQueryCache {
+	if ttl <= 0 {
+		ttl = DefaultPriceCacheTTL
+	}
+
+	return &PriceQueryCache{
+		dir:     dir,
+		ttl:     ttl,
+		results: make(map[string]gjson.Result),
+		pending: make(map[string]chan struct{}),
+	}
+}
+
+// claim returns the cached result of key. If it isn't cached it returns the channel of the
+// request that's fetching it, or claims key, so the caller has to fetch it and then call
+// set or release.
+func (c *PriceQueryCache) claim(key string) (gjson.Result, chan struct{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if res, ok := c.results[key]; ok {
+		return res, nil, true
+	}
+
+	if wait, ok := c.pending[key]; ok {
+		return gjson.Result{}, wait, false
+	}
+
+	if res, ok := c.readFile(key); ok {
+		c.results[key] = res
+		return res, nil, true
+	}
+
+	c.pending[key] = make(chan struct{})
+	return gjson.Result{}, nil, false
+}
+
+func (c *PriceQueryCache) get(key string) (gjson.Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	res, ok := c.results[key]
+	return res, ok
+}
+
+// set caches the result of a claimed key. Results with errors aren't cached, so they're retried.
+func (c *PriceQueryCache) set(key string, res gjson.Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !res.Get("errors").Exists() {
+		c.results[key] = res
+		c.writeFile(key, res)
+	}
+
+	c.done(key)
+}
+
+// release gives up a claimed key whose request failed, so waiting requests can fetch it.
+func (c *PriceQueryCache) release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.done(key)
+}
+
+func (c *PriceQueryCache) done(key string) {
+	if wait, ok := c.pending[key]; ok {
+		close(wait)
+		delete(c.pending, key)
+	}
+}
+
+func (c *PriceQueryCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+func (c *PriceQueryCache) readFile(key string) (gjson.Result, bool) {
+	if c.dir == "" {
+		return gjson.Result{}, false
+	}
+
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return gjson.Result{}, false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil || !gjson.ValidBytes(b) {
+		log.Debugf("Ignoring invalid price cache file %s", path)
+		return gjson.Result{}, false
+	}
+
+	return gjson.ParseBytes(b), true
+}
+
+// writeFile writes the result to a temporary file first, so other processes sharing the
+// cache never read a partially written file.
+func (c *PriceQueryCache) writeFile(key string, res gjson.Result) {
+	if c.dir == "" {
+		return
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		log.Debugf("Couldn't create price cache directory: %v", err)
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		log.Debugf("Couldn't write price cache file %s: %v", path, err)
+		return
+	}
+
+	_, err = tmp.WriteString(res.Raw)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		log.Debugf("Couldn't write price cache file %s: %v", path, err)
+		_ = os.Remove(tmp.Name())
+	}
+}
+
+// priceQueryKey returns the key of a query in the cache. The endpoint is included, since
+// self-hosted pricing APIs can have different prices to the Cloud Pricing API.
+func priceQueryKey(endpoint string, q GraphQLQuery) string {
+	b, _ := json.Marshal(struct {
+		Endpoint string       `json:"endpoint"`
+		Query    GraphQLQuery `json:"query"`
+	}{endpoint, q})
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,152 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+type pricingAPIStub struct {
+	server  *httptest.Server
+	queries int64
+	fail    bool
+}
+
+func newPricingAPIStub(t *testing.T) *pricingAPIStub {
+	stub := &pricingAPIStub{}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+		atomic.AddInt64(&stub.queries, int64(len(queries)))
+
+		// Give concurrent requests for the same query a chance to overlap
+		time.Sleep(10 * time.Millisecond)
+
+		results := make([]interface{}, 0, len(queries))
+		for range queries {
+			if stub.fail {
+				results = append(results, map[string]interface{}{"errors": []interface{}{map[string]string{"message": "failed"}}})
+				continue
+			}
+			results = append(results, map[string]interface{}{
+				"data": map[string]interface{}{
+					"products": []interface{}{
+						map[string]interface{}{"prices": []interface{}{map[string]string{"priceHash": "hash", "USD": "1.5"}}},
+					},
+				},
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(results)
+	}))
+	t.Cleanup(stub.server.Close)
+
+	return stub
+}
+
+func (s *pricingAPIStub) client(cache *PriceQueryCache) *PricingAPIClient {
+	return &PricingAPIClient{
+		APIClient: APIClient{endpoint: s.server.URL},
+		Currency:  "USD",
+		Cache:     cache,
+	}
+}
+
+func testPricedResource(name string, skus ...string) *schema.Resource {
+	r := &schema.Resource{Name: name}
+	for _, sku := range skus {
+		sku := sku
+		r.CostComponents = append(r.CostComponents, &schema.CostComponent{
+			Name:          sku,
+			ProductFilter: &schema.ProductFilter{Sku: &sku},
+		})
+	}
+	return r
+}
+
+func TestPriceQueryCacheDeduplicatesQueries(t *testing.T) {
+	stub := newPricingAPIStub(t)
+	c := stub.client(NewPriceQueryCache(t.TempDir(), time.Hour))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := c.RunQueries(testPricedResource("vm", "D2s_v3", "D2s_v3", "P10"))
+			assert.NoError(t, err)
+			assert.Len(t, results, 3)
+			for _, res := range results {
+				assert.Equal(t, "1.5", res.Result.Get("data.products.0.prices.0.USD").String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(2), atomic.LoadInt64(&stub.queries))
+}
+
+func TestPriceQueryCacheOnDisk(t *testing.T) {
+	stub := newPricingAPIStub(t)
+	dir := t.TempDir()
+
+	_, err := stub.client(NewPriceQueryCache(dir, time.Hour)).RunQueries(testPricedResource("disk", "P10"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stub.queries)
+
+	// A later run reads the prices from disk
+	results, err := stub.client(NewPriceQueryCache(dir, time.Hour)).RunQueries(testPricedResource("disk", "P10"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stub.queries)
+	assert.Equal(t, "hash", results[0].Result.Get("data.products.0.prices.0.priceHash").String())
+
+	// The currency is part of the key
+	c := stub.client(NewPriceQueryCache(dir, time.Hour))
+	c.Currency = "EUR"
+	_, err = c.RunQueries(testPricedResource("disk", "P10"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stub.queries)
+
+	// Expired prices are fetched again
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	old := time.Now().Add(-2 * time.Hour)
+	for _, f := range files {
+		require.NoError(t, os.Chtimes(f, old, old))
+	}
+
+	_, err = stub.client(NewPriceQueryCache(dir, time.Hour)).RunQueries(testPricedResource("disk", "P10"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), stub.queries)
+}
+
+func TestPriceQueryCacheSkipsErrors(t *testing.T) {
+	stub := newPricingAPIStub(t)
+	stub.fail = true
+	dir := t.TempDir()
+	c := stub.client(NewPriceQueryCache(dir, time.Hour))
+
+	results, err := c.RunQueries(testPricedResource("vm", "D2s_v3"))
+	require.NoError(t, err)
+	assert.True(t, results[0].Result.Get("errors").Exists())
+
+	_, err = c.RunQueries(testPricedResource("vm", "D2s_v3"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stub.queries)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
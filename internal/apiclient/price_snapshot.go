@@ -0,0 +1,498 @@
+package apiclient
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// snapshotQuery requests the full products and prices of a price query, so they can be added to a
+// PriceSnapshot and matched against other filters later.
+var snapshotQuery = `
+		query($productFilter: ProductFilter!, $priceFilter: PriceFilter) {
+			products(filter: $productFilter) {
+				productHash
+				vendorName
+				service
+				productFamily
+				region
+				sku
+				attributes {
+					key
+					value
+				}
+				prices(filter: $priceFilter) {
+					priceHash
+					purchaseOption
+					unit
+					description
+					startUsageAmount
+					endUsageAmount
+					termLength
+					termPurchaseOption
+					termOfferingClass
+					%s
+				}
+			}
+		}
+	`
+
+// snapshotPriceFields are the fields of a price that can be filtered on by a schema.PriceFilter.
+var snapshotPriceFields = map[string]struct{}{
+	"priceHash":          {},
+	"purchaseOption":     {},
+	"unit":               {},
+	"description":        {},
+	"startUsageAmount":   {},
+	"endUsageAmount":     {},
+	"termLength":         {},
+	"termPurchaseOption": {},
+	"termOfferingClass":  {},
+}
+
+type SnapshotAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// SnapshotProduct is a product of the pricing API with the prices that have been fetched for it.
+// Prices are keyed by their field names and currency codes, e.g. "unit" and "USD".
+type SnapshotProduct struct {
+	ProductHash   string              `json:"productHash"`
+	VendorName    string              `json:"vendorName"`
+	Service       string              `json:"service"`
+	ProductFamily string              `json:"productFamily"`
+	Region        string              `json:"region"`
+	Sku           string              `json:"sku"`
+	Attributes    []SnapshotAttribute `json:"attributes"`
+	Prices        []map[string]string `json:"prices"`
+}
+
+func (p *SnapshotProduct) attribute(key string) string {
+	for _, a := range p.Attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+// PriceSnapshot is a local snapshot of the pricing API, so prices can be looked up without network
+// access, e.g. on air-gapped build agents. Snapshots are JSON lines files, which are gzipped if the
+// path ends in .gz, with one product per line in the shape the pricing API returns it:
+//
+//	{"productHash":"...","vendorName":"aws","service":"AmazonEC2","productFamily":"Compute Instance","region":"us-east-1","sku":"...","attributes":[{"key":"instanceType","value":"t3.micro"}],"prices":[{"priceHash":"...","purchaseOption":"on_demand","unit":"Hrs","USD":"0.0104"}]}
+type PriceSnapshot struct {
+	mu       sync.RWMutex
+	products map[string]*SnapshotProduct
+	// services are the hashes of the products of each vendor and service in the order they were
+	// added, since every product filter has a vendor and service.
+	services   map[string][]string
+	currencies map[string]struct{}
+	regexes    map[string]*regexp2.Regexp
+}
+
+func NewPriceSnapshot() *PriceSnapshot {
+	return &PriceSnapshot{
+		products:   make(map[string]*SnapshotProduct),
+		services:   make(map[string][]string),
+		currencies: make(map[string]struct{}),
+		regexes:    make(map[string]*regexp2.Regexp),
+	}
+}
+
+// LoadPriceSnapshot reads the snapshot at path.
+func LoadPriceSnapshot(path string) (*PriceSnapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading pricing snapshot")
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error reading gzipped pricing snapshot %s", path)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	s := NewPriceSnapshot()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		b := scanner.Bytes()
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+
+		var p SnapshotProduct
+		err := json.Unmarshal(b, &p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing line %d of pricing snapshot %s", line, path)
+		}
+
+		s.Add(p)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Error reading pricing snapshot %s", path)
+	}
+
+	return s, nil
+}
+
+// Len returns the number of products in the snapshot.
+func (s *PriceSnapshot) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.products)
+}
+
+// HasCurrency returns true if the snapshot has prices in currency.
+func (s *PriceSnapshot) HasCurrency(currency string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.currencies[currency]
+	return ok
+}
+
+// Add adds a product to the snapshot. If the snapshot already has the product, its fields are
+// replaced and its prices are merged by their hash, so refreshing a snapshot updates the prices that
+// are fetched again and keeps the others.
+func (s *PriceSnapshot) Add(p SnapshotProduct) {
+	if p.ProductHash == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prices := make([]map[string]string, 0, len(p.Prices))
+	for _, price := range p.Prices {
+		price = cleanSnapshotPrice(price)
+		for k := range price {
+			if _, ok := snapshotPriceFields[k]; !ok {
+				s.currencies[k] = struct{}{}
+			}
+		}
+		prices = append(prices, price)
+	}
+
+	existing, ok := s.products[p.ProductHash]
+	if !ok {
+		p.Prices = prices
+		s.products[p.ProductHash] = &p
+
+		key := snapshotServiceKey(p.VendorName, p.Service)
+		s.services[key] = append(s.services[key], p.ProductHash)
+		return
+	}
+
+	for _, price := range prices {
+		merged := false
+		for _, existingPrice := range existing.Prices {
+			if existingPrice["priceHash"] == price["priceHash"] {
+				for k, v := range price {
+					existingPrice[k] = v
+				}
+				merged = true
+				break
+			}
+		}
+
+		if !merged {
+			existing.Prices = append(existing.Prices, price)
+		}
+	}
+
+	existing.ProductFamily = p.ProductFamily
+	existing.Region = p.Region
+	existing.Sku = p.Sku
+	existing.Attributes = p.Attributes
+}
+
+// cleanSnapshotPrice removes the fields that the pricing API returned as null.
+func cleanSnapshotPrice(price map[string]string) map[string]string {
+	cleaned := make(map[string]string, len(price))
+	for k, v := range price {
+		if v != "" {
+			cleaned[k] = v
+		}
+	}
+
+	return cleaned
+}
+
+func snapshotServiceKey(vendorName, service string) string {
+	return vendorName + "/" + service
+}
+
+// addResult adds the products of the result of a snapshotQuery to the snapshot.
+func (s *PriceSnapshot) addResult(res gjson.Result) {
+	if res.Get("errors").Exists() {
+		return
+	}
+
+	for _, product := range res.Get("data.products").Array() {
+		var p SnapshotProduct
+		err := json.Unmarshal([]byte(product.Raw), &p)
+		if err != nil {
+			log.Debugf("Skipping invalid product in pricing snapshot: %v", err)
+			continue
+		}
+
+		s.Add(p)
+	}
+}
+
+// Write writes the snapshot to path. Products are sorted by vendor, service and hash, so refreshing
+// a snapshot gives a small diff.
+func (s *PriceSnapshot) Write(path string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	products := make([]*SnapshotProduct, 0, len(s.products))
+	for _, p := range s.products {
+		products = append(products, p)
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if a.VendorName != b.VendorName {
+			return a.VendorName < b.VendorName
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.ProductHash < b.ProductHash
+	})
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "Error writing pricing snapshot")
+	}
+	defer os.Remove(tmp.Name())
+
+	var w io.Writer = tmp
+	var gz *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		gz = gzip.NewWriter(tmp)
+		w = gz
+	}
+
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	for _, p := range products {
+		err = enc.Encode(p)
+		if err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = buf.Flush()
+	}
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	return errors.Wrap(err, "Error writing pricing snapshot")
+}
+
+// RunQueries resolves price queries against the snapshot. The results have the same shape as the
+// results of the pricing API, with the price in currency.
+func (s *PriceSnapshot) RunQueries(currency string, queries []GraphQLQuery) []gjson.Result {
+	results := make([]gjson.Result, 0, len(queries))
+
+	for _, q := range queries {
+		productFilter, _ := q.Variables["productFilter"].(*schema.ProductFilter)
+		priceFilter, _ := q.Variables["priceFilter"].(*schema.PriceFilter)
+
+		products := make([]interface{}, 0)
+		for _, p := range s.matchProducts(productFilter) {
+			prices := make([]map[string]string, 0)
+			for _, price := range p.Prices {
+				if s.matchPrice(price, priceFilter) {
+					prices = append(prices, map[string]string{
+						"priceHash": price["priceHash"],
+						currency:    price[currency],
+					})
+				}
+			}
+
+			products = append(products, map[string]interface{}{"prices": prices})
+		}
+
+		b, _ := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{"products": products},
+		})
+		results = append(results, gjson.ParseBytes(b))
+	}
+
+	return results
+}
+
+func (s *PriceSnapshot) matchProducts(f *schema.ProductFilter) []*SnapshotProduct {
+	if f == nil || f.VendorName == nil || f.Service == nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	hashes := s.services[snapshotServiceKey(*f.VendorName, *f.Service)]
+	candidates := make([]*SnapshotProduct, 0, len(hashes))
+	for _, hash := range hashes {
+		candidates = append(candidates, s.products[hash])
+	}
+	s.mu.RUnlock()
+
+	var matched []*SnapshotProduct
+	for _, p := range candidates {
+		if s.matchProduct(p, f) {
+			matched = append(matched, p)
+		}
+	}
+
+	return matched
+}
+
+func (s *PriceSnapshot) matchProduct(p *SnapshotProduct, f *schema.ProductFilter) bool {
+	if !matchesField(p.ProductFamily, f.ProductFamily) || !matchesField(p.Region, f.Region) || !matchesField(p.Sku, f.Sku) {
+		return false
+	}
+
+	for _, a := range f.AttributeFilters {
+		if a == nil {
+			continue
+		}
+
+		value := p.attribute(a.Key)
+		if a.Value != nil && value != *a.Value {
+			return false
+		}
+
+		if a.ValueRegex != nil && !s.matchRegex(value, *a.ValueRegex) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *PriceSnapshot) matchPrice(price map[string]string, f *schema.PriceFilter) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.DescriptionRegex != nil && !s.matchRegex(price["description"], *f.DescriptionRegex) {
+		return false
+	}
+
+	return matchesField(price["purchaseOption"], f.PurchaseOption) &&
+		matchesField(price["unit"], f.Unit) &&
+		matchesField(price["description"], f.Description) &&
+		matchesField(price["startUsageAmount"], f.StartUsageAmount) &&
+		matchesField(price["endUsageAmount"], f.EndUsageAmount) &&
+		matchesField(price["termLength"], f.TermLength) &&
+		matchesField(price["termPurchaseOption"], f.TermPurchaseOption) &&
+		matchesField(price["termOfferingClass"], f.TermOfferingClass)
+}
+
+func matchesField(value string, filter *string) bool {
+	return filter == nil || value == *filter
+}
+
+// matchRegex matches value against a regex of a filter, which is in the JavaScript /pattern/flags
+// form used by the pricing API. Regexes that can't be compiled never match.
+func (s *PriceSnapshot) matchRegex(value string, regex string) bool {
+	s.mu.RLock()
+	re, ok := s.regexes[regex]
+	s.mu.RUnlock()
+
+	if !ok {
+		var err error
+		re, err = compileFilterRegex(regex)
+		if err != nil {
+			log.Warnf("Pricing snapshot can't match the regex %s: %v", regex, err)
+		}
+
+		s.mu.Lock()
+		s.regexes[regex] = re
+		s.mu.Unlock()
+	}
+
+	return matchFilterRegex(re, value)
+}
+
+// compileFilterRegex compiles a regex of a filter. The regexes of the pricing API can have
+// lookarounds, e.g. to exclude Spot VMs, which the regexp package doesn't support, so they're
+// compiled with a backtracking engine.
+func compileFilterRegex(regex string) (*regexp2.Regexp, error) {
+	pattern := regex
+	flags := ""
+
+	if i := strings.LastIndex(regex, "/"); strings.HasPrefix(regex, "/") && i > 0 {
+		pattern = regex[1:i]
+		flags = regex[i+1:]
+	}
+
+	opts := regexp2.None
+	if strings.Contains(flags, "i") {
+		opts |= regexp2.IgnoreCase
+	}
+
+	re, err := regexp2.Compile(pattern, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	re.MatchTimeout = filterRegexTimeout
+	return re, nil
+}
+
+// filterRegexTimeout stops a backtracking regex from matching a value for too long.
+const filterRegexTimeout = time.Second
+
+// matchFilterRegex returns true if re is set and matches value. Regexes that time out don't match.
+func matchFilterRegex(re *regexp2.Regexp, value string) bool {
+	if re == nil {
+		return false
+	}
+
+	matched, err := re.MatchString(value)
+	if err != nil {
+		log.Debugf("Error matching regex %s: %v", re.String(), err)
+		return false
+	}
+
+	return matched
+}
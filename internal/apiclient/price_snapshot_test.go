@@ -0,0 +1,186 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func testSnapshot() *PriceSnapshot {
+	s := NewPriceSnapshot()
+	s.Add(SnapshotProduct{
+		ProductHash:   "vm-linux",
+		VendorName:    "azure",
+		Service:       "Virtual Machines",
+		ProductFamily: "Compute",
+		Region:        "eastus",
+		Sku:           "D2s_v3",
+		Attributes:    []SnapshotAttribute{{Key: "skuName", Value: "D2s v3"}, {Key: "meterName", Value: "D2s v3"}},
+		Prices: []map[string]string{
+			{"priceHash": "vm-linux-od", "purchaseOption": "Consumption", "unit": "1 Hour", "USD": "0.096"},
+			{"priceHash": "vm-linux-ri", "purchaseOption": "Reservation", "unit": "1 Hour", "termLength": "1 Year", "USD": "500"},
+		},
+	})
+	s.Add(SnapshotProduct{
+		ProductHash:   "vm-spot",
+		VendorName:    "azure",
+		Service:       "Virtual Machines",
+		ProductFamily: "Compute",
+		Region:        "eastus",
+		Sku:           "D2s_v3",
+		Attributes:    []SnapshotAttribute{{Key: "skuName", Value: "D2s v3 Spot"}, {Key: "meterName", Value: "D2s v3 Spot"}},
+		Prices: []map[string]string{
+			{"priceHash": "vm-spot-od", "purchaseOption": "Consumption", "unit": "1 Hour", "USD": "0.02"},
+		},
+	})
+
+	return s
+}
+
+func snapshotQueryFor(productFilter *schema.ProductFilter, priceFilter *schema.PriceFilter) GraphQLQuery {
+	return GraphQLQuery{
+		Variables: map[string]interface{}{
+			"productFilter": productFilter,
+			"priceFilter":   priceFilter,
+		},
+	}
+}
+
+func TestPriceSnapshotRunQueries(t *testing.T) {
+	s := testSnapshot()
+
+	vm := func(attrs ...*schema.AttributeFilter) *schema.ProductFilter {
+		return &schema.ProductFilter{
+			VendorName:       strPtr("azure"),
+			Service:          strPtr("Virtual Machines"),
+			Region:           strPtr("eastus"),
+			AttributeFilters: attrs,
+		}
+	}
+
+	results := s.RunQueries("USD", []GraphQLQuery{
+		snapshotQueryFor(vm(&schema.AttributeFilter{Key: "skuName", Value: strPtr("D2s v3")}), &schema.PriceFilter{PurchaseOption: strPtr("Consumption")}),
+		snapshotQueryFor(vm(&schema.AttributeFilter{Key: "meterName", ValueRegex: strPtr("/spot$/i")}), nil),
+		snapshotQueryFor(vm(&schema.AttributeFilter{Key: "meterName", ValueRegex: strPtr("/^D2s v3$/")}), &schema.PriceFilter{TermLength: strPtr("3 Years")}),
+		snapshotQueryFor(vm(), &schema.PriceFilter{PurchaseOption: strPtr("Consumption")}),
+		snapshotQueryFor(&schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")}, nil),
+		// Regexes of the pricing API can have lookarounds
+		snapshotQueryFor(vm(&schema.AttributeFilter{Key: "skuName", ValueRegex: strPtr("/^(?!.*(Low Priority|Spot)$).*$/i")}), &schema.PriceFilter{PurchaseOption: strPtr("Consumption")}),
+		snapshotQueryFor(vm(&schema.AttributeFilter{Key: "meterName", ValueRegex: strPtr("(?<!v3 )Spot$")}), nil),
+	})
+	require.Len(t, results, 7)
+
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[{"priceHash":"vm-linux-od","USD":"0.096"}]}]}}`, results[0].Raw)
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[{"priceHash":"vm-spot-od","USD":"0.02"}]}]}}`, results[1].Raw)
+	// Products are returned without prices if none of their prices match, like the pricing API
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[]}]}}`, results[2].Raw)
+	assert.Len(t, results[3].Get("data.products").Array(), 2)
+	assert.JSONEq(t, `{"data":{"products":[]}}`, results[4].Raw)
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[{"priceHash":"vm-linux-od","USD":"0.096"}]}]}}`, results[5].Raw)
+	assert.JSONEq(t, `{"data":{"products":[]}}`, results[6].Raw)
+}
+
+func TestPriceSnapshotWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.jsonl.gz")
+	require.NoError(t, testSnapshot().Write(path))
+
+	s, err := LoadPriceSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.HasCurrency("USD"))
+	assert.False(t, s.HasCurrency("EUR"))
+
+	// Refreshing a product updates its prices and keeps the others
+	s.Add(SnapshotProduct{
+		ProductHash:   "vm-linux",
+		VendorName:    "azure",
+		Service:       "Virtual Machines",
+		ProductFamily: "Compute",
+		Region:        "eastus",
+		Sku:           "D2s_v3",
+		Attributes:    []SnapshotAttribute{{Key: "skuName", Value: "D2s v3"}, {Key: "meterName", Value: "D2s v3"}},
+		Prices: []map[string]string{
+			{"priceHash": "vm-linux-od", "purchaseOption": "Consumption", "unit": "1 Hour", "USD": "0.1", "EUR": "0.09"},
+		},
+	})
+	assert.Equal(t, 2, s.Len())
+	assert.True(t, s.HasCurrency("EUR"))
+
+	productFilter := &schema.ProductFilter{
+		VendorName:       strPtr("azure"),
+		Service:          strPtr("Virtual Machines"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "skuName", Value: strPtr("D2s v3")}},
+	}
+
+	results := s.RunQueries("EUR", []GraphQLQuery{
+		snapshotQueryFor(productFilter, &schema.PriceFilter{PurchaseOption: strPtr("Consumption")}),
+		snapshotQueryFor(productFilter, &schema.PriceFilter{PurchaseOption: strPtr("Reservation")}),
+	})
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[{"priceHash":"vm-linux-od","EUR":"0.09"}]}]}}`, results[0].Raw)
+	assert.Equal(t, "vm-linux-ri", results[1].Get("data.products.0.prices.0.priceHash").String())
+
+	results = s.RunQueries("USD", []GraphQLQuery{
+		snapshotQueryFor(productFilter, &schema.PriceFilter{PurchaseOption: strPtr("Reservation")}),
+	})
+	assert.Equal(t, "500", results[0].Get("data.products.0.prices.0.USD").String())
+}
+
+func TestPricingAPIClientRecordsSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var queries []GraphQLQuery
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&queries))
+		require.Len(t, queries, 1)
+		assert.Contains(t, queries[0].Query, "productHash")
+
+		_, _ = w.Write([]byte(`[{"data":{"products":[{
+			"productHash":"disk","vendorName":"azure","service":"Storage","productFamily":"Storage","region":"eastus","sku":"P10",
+			"attributes":[{"key":"skuName","value":"P10 LRS"}],
+			"prices":[{"priceHash":"disk-od","purchaseOption":"Consumption","unit":"1/Month","description":null,"termLength":null,"USD":"19.71"}]
+		}]}}]`))
+	}))
+	defer server.Close()
+
+	snapshot := NewPriceSnapshot()
+	c := &PricingAPIClient{
+		APIClient:      APIClient{endpoint: server.URL},
+		Currency:       "USD",
+		Snapshot:       snapshot,
+		RecordSnapshot: true,
+	}
+
+	r := &schema.Resource{
+		Name: "disk",
+		CostComponents: []*schema.CostComponent{{
+			Name: "Storage",
+			ProductFilter: &schema.ProductFilter{
+				VendorName:       strPtr("azure"),
+				Service:          strPtr("Storage"),
+				AttributeFilters: []*schema.AttributeFilter{{Key: "skuName", Value: strPtr("P10 LRS")}},
+			},
+			PriceFilter: &schema.PriceFilter{PurchaseOption: strPtr("Consumption")},
+		}},
+	}
+
+	recorded, err := c.RunQueries(r)
+	require.NoError(t, err)
+	assert.Equal(t, "19.71", recorded[0].Result.Get("data.products.0.prices.0.USD").String())
+	assert.Equal(t, 1, snapshot.Len())
+
+	// The recorded snapshot resolves the same query without the pricing API
+	server.Close()
+	offline := &PricingAPIClient{Currency: "USD", Snapshot: snapshot}
+	results, err := offline.RunQueries(r)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data":{"products":[{"prices":[{"priceHash":"disk-od","USD":"19.71"}]}]}}`, results[0].Result.Raw)
+}
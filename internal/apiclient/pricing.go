@@ -26,6 +26,16 @@ type PricingAPIClient struct {
 	APIClient
 	Currency       string
 	EventsDisabled bool
+	// Cache of the query results, which is optional
+	Cache *PriceQueryCache
+	// Snapshot is a local snapshot of the pricing API that queries are resolved against instead of
+	// the pricing API, which is optional. If RecordSnapshot is set, queries are sent to the pricing
+	// API and the products it returns are added to the snapshot.
+	Snapshot       *PriceSnapshot
+	RecordSnapshot bool
+	// AzureRetail resolves the queries of Azure cost components against the Azure Retail Prices
+	// API instead of the pricing API, which is optional. It isn't used with a snapshot.
+	AzureRetail *AzureRetailPricesClient
 }
 
 type PriceQueryKey struct {
@@ -76,16 +86,23 @@ func NewPricingAPIClient(ctx *config.RunContext) *PricingAPIClient {
 	client.Logger = &LeveledLogger{Logger: logging.Logger.WithField("library", "retryablehttp")}
 	client.HTTPClient.Transport.(*http.Transport).TLSClientConfig = &tlsConfig
 
-	return &PricingAPIClient{
+	c := &PricingAPIClient{
 		APIClient: APIClient{
 			httpClient: client.StandardClient(),
 			endpoint:   ctx.Config.PricingAPIEndpoint,
 			apiKey:     ctx.Config.APIKey,
 			uuid:       ctx.UUID(),
 		},
-		Currency:       currency,
-		EventsDisabled: ctx.Config.EventsDisabled,
+		Currency: currency,
+		// Events are disabled in offline runs since the pricing API can't be reached
+		EventsDisabled: ctx.Config.EventsDisabled || ctx.Config.IsOffline(),
 	}
+
+	if ctx.Config.UsesAzureRetailPrices() {
+		c.AzureRetail = NewAzureRetailPricesClient(client.StandardClient(), ctx.Config.AzureRetailPricesEndpoint)
+	}
+
+	return c
 }
 
 func (c *PricingAPIClient) AddEvent(name string, env map[string]interface{}) error {
@@ -119,9 +136,18 @@ func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, e
 		return []PriceQueryResult{}, nil
 	}
 
-	log.Debugf("Getting pricing details from %s for %s", c.endpoint, r.Name)
+	if c.Cache != nil {
+		results, err := c.runCachedQueries(r, queries)
+		if err != nil {
+			return []PriceQueryResult{}, err
+		}
 
-	results, err := c.doQueries(queries)
+		return c.zipQueryResults(keys, results), nil
+	}
+
+	log.Debugf("Getting pricing details from %s for %s", c.source(), r.Name)
+
+	results, err := c.runQueries(queries)
 	if err != nil {
 		return []PriceQueryResult{}, err
 	}
@@ -129,6 +155,191 @@ func (c *PricingAPIClient) RunQueries(r *schema.Resource) ([]PriceQueryResult, e
 	return c.zipQueryResults(keys, results), nil
 }
 
+// runQueries sends the queries to the pricing API, or resolves them against the snapshot.
+func (c *PricingAPIClient) runQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	switch {
+	case c.Snapshot == nil && c.AzureRetail != nil:
+		return c.runAzureRetailQueries(queries)
+	case c.Snapshot == nil:
+		return c.doQueries(queries)
+	case c.RecordSnapshot:
+		return c.recordSnapshotQueries(queries)
+	default:
+		return c.Snapshot.RunQueries(c.Currency, queries), nil
+	}
+}
+
+// runAzureRetailQueries resolves the queries of Azure products against the Azure Retail Prices API
+// and sends the others to the pricing API.
+func (c *PricingAPIClient) runAzureRetailQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	var azureQueries, otherQueries []GraphQLQuery
+	for _, q := range queries {
+		if IsAzureQuery(q) {
+			azureQueries = append(azureQueries, q)
+		} else {
+			otherQueries = append(otherQueries, q)
+		}
+	}
+
+	azureResults, err := c.AzureRetail.RunQueries(c.Currency, azureQueries)
+	if err != nil {
+		return nil, err
+	}
+
+	var otherResults []gjson.Result
+	if len(otherQueries) > 0 {
+		otherResults, err = c.doQueries(otherQueries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]gjson.Result, 0, len(queries))
+	for _, q := range queries {
+		if IsAzureQuery(q) {
+			results = append(results, azureResults[0])
+			azureResults = azureResults[1:]
+		} else {
+			results = append(results, otherResults[0])
+			otherResults = otherResults[1:]
+		}
+	}
+
+	return results, nil
+}
+
+// recordSnapshotQueries sends the queries to the pricing API requesting the full products and
+// prices, and adds them to the snapshot.
+func (c *PricingAPIClient) recordSnapshotQueries(queries []GraphQLQuery) ([]gjson.Result, error) {
+	snapshotQueries := make([]GraphQLQuery, 0, len(queries))
+	for _, q := range queries {
+		snapshotQueries = append(snapshotQueries, GraphQLQuery{fmt.Sprintf(snapshotQuery, c.Currency), q.Variables})
+	}
+
+	results, err := c.doQueries(snapshotQueries)
+	if err != nil {
+		return results, err
+	}
+
+	for _, res := range results {
+		c.Snapshot.addResult(res)
+	}
+
+	return results, nil
+}
+
+func (c *PricingAPIClient) source() string {
+	if c.Snapshot != nil && !c.RecordSnapshot {
+		return "pricing snapshot"
+	}
+
+	return c.endpoint
+}
+
+// runCachedQueries returns the results of queries from the cache and only sends the queries that
+// aren't cached, or being fetched for another resource, to the pricing API.
+func (c *PricingAPIClient) runCachedQueries(r *schema.Resource, queries []GraphQLQuery) ([]gjson.Result, error) {
+	keys := make([]string, len(queries))
+	queriesByKey := make(map[string]GraphQLQuery, len(queries))
+	// fetched are the results of this request, including any that aren't cached because of errors
+	fetched := make(map[string]gjson.Result)
+	waiting := make(map[string]chan struct{})
+	var fetchKeys []string
+
+	for i, q := range queries {
+		key := priceQueryKey(c.endpoint, q)
+		keys[i] = key
+
+		if _, ok := queriesByKey[key]; ok {
+			continue
+		}
+		queriesByKey[key] = q
+
+		res, wait, ok := c.Cache.claim(key)
+		switch {
+		case ok:
+			fetched[key] = res
+		case wait != nil:
+			waiting[key] = wait
+		default:
+			fetchKeys = append(fetchKeys, key)
+		}
+	}
+
+	if len(fetchKeys) > 0 {
+		log.Debugf("Getting pricing details from %s for %s", c.source(), r.Name)
+	} else {
+		log.Debugf("Using cached pricing details for %s", r.Name)
+	}
+
+	err := c.fetchQueries(fetchKeys, queriesByKey, fetched, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Queries whose request failed for another resource are fetched again
+	var retryKeys []string
+	for key, wait := range waiting {
+		<-wait
+		if res, ok := c.Cache.get(key); ok {
+			fetched[key] = res
+		} else {
+			retryKeys = append(retryKeys, key)
+		}
+	}
+
+	err = c.fetchQueries(retryKeys, queriesByKey, fetched, false)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]gjson.Result, len(queries))
+	for i, key := range keys {
+		results[i] = fetched[key]
+	}
+
+	return results, nil
+}
+
+// fetchQueries sends the queries of keys in a single request and adds their results to fetched.
+// If claimed is set the keys were claimed in the cache, so their results are cached.
+func (c *PricingAPIClient) fetchQueries(keys []string, queriesByKey map[string]GraphQLQuery, fetched map[string]gjson.Result, claimed bool) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	queries := make([]GraphQLQuery, 0, len(keys))
+	for _, key := range keys {
+		queries = append(queries, queriesByKey[key])
+	}
+
+	results, err := c.runQueries(queries)
+	if err != nil {
+		if claimed {
+			for _, key := range keys {
+				c.Cache.release(key)
+			}
+		}
+		return err
+	}
+
+	for i, key := range keys {
+		if i >= len(results) {
+			if claimed {
+				c.Cache.release(key)
+			}
+			continue
+		}
+
+		fetched[key] = results[i]
+		if claimed {
+			c.Cache.set(key, results[i])
+		}
+	}
+
+	return nil
+}
+
 func (c *PricingAPIClient) buildQuery(product *schema.ProductFilter, price *schema.PriceFilter) GraphQLQuery {
 	v := map[string]interface{}{}
 	v["productFilter"] = product
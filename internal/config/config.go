@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -16,6 +17,9 @@ import (
 
 const InfracostDir = ".infracost"
 
+// AzureRetailPricingSource is the AzurePricingSource of the Azure Retail Prices API.
+const AzureRetailPricingSource = "azure_retail"
+
 // Project defines a specific terraform project config. This can be used
 // specify per folder/project configurations so that users don't have
 // to provide flags every run. Fields are documented below. More info
@@ -61,22 +65,86 @@ type Project struct {
 
 	// Path to the Azure CLI binary
 	AzBinary string `yaml:"az_binary,omitempty" ignored:"true"`
+	// ArmForceCLI will run a project by calling out to the az CLI to generate a WhatIf result,
+	// instead of evaluating the ARM template locally.
+	ArmForceCLI bool `yaml:"arm_force_cli,omitempty" ignored:"true"`
 	// Target scope of the deployment. Allowed values: 'tenant','managementGroup','subscription','resourceGroup'
 	ArmDeploymentScope string `yaml:"arm_deployment_scope" ignored:"true"`
 	// Mode of the deployment. Allowed values: 'complete','incremental'
 	ArmDeploymentMode string `yaml:"arm_deployment_mode,omitempty" ignored:"true"`
 	// Path to an AzureRM JSON parameters file
 	ArmParametersPath string `yaml:"arm_parameters_file,omitempty" ignored:"true"`
+	// Paths to additional AzureRM JSON or .bicepparam parameters files, applied in order
+	// after ArmParametersPath so that later files override earlier values
+	ArmParametersFiles []string `yaml:"arm_parameters_files,omitempty" ignored:"true"`
 	// Azure location to use for subscription and management group scoped deployments
 	ArmLocation string `yaml:"arm_location,omitempty" ignored:"true"`
 	// Azure resource group name for resource group scoped deployments
 	ArmResourceGroup string `yaml:"arm_resource_group,omitempty" ignored:"true"`
 	// Azure management group ID for management group scoped deployments
 	ArmManagementGroupId string `yaml:"arm_management_group_id" ignored:"true"`
+	// Azure subscription ID to deploy to, defaults to the current subscription of the az CLI
+	ArmSubscriptionId string `yaml:"arm_subscription_id,omitempty" ignored:"true"`
+	// Path to a template exported with `az group export` or the JSON output of `az resource list`,
+	// which is used as the past resources of the project so it can be diffed without calling Azure
+	ArmBaselinePath string `yaml:"arm_baseline_file,omitempty" ignored:"true"`
+
+	// CloudFormationRegion is the AWS region a CloudFormation template is deployed to, defaults to
+	// the AWS_REGION or AWS_DEFAULT_REGION environment variables, or us-east-1
+	CloudFormationRegion string `yaml:"cloudformation_region,omitempty" ignored:"true"`
+	// Path to a CloudFormation parameters file, in the format of the --parameters option of the
+	// AWS CLI ([{"ParameterKey": "...", "ParameterValue": "..."}])
+	CloudFormationParametersFile string `yaml:"cloudformation_parameters_file,omitempty" ignored:"true"`
+	// CloudFormationParameters are values of CloudFormation parameters, which override the values of
+	// CloudFormationParametersFile
+	CloudFormationParameters map[string]string `yaml:"cloudformation_parameters,omitempty" ignored:"true"`
+	// Path to the proposed template of a CloudFormation change set, used when the path of the project
+	// is the output of `aws cloudformation describe-change-set`
+	CloudFormationTemplateFile string `yaml:"cloudformation_template_file,omitempty" ignored:"true"`
+	// Path to the current template of the stack of a CloudFormation change set. If it's not set, the
+	// resources of the stack are worked out from the proposed template and the changes.
+	CloudFormationCurrentTemplateFile string `yaml:"cloudformation_current_template_file,omitempty" ignored:"true"`
+	// CloudFormationStackSets are the accounts and regions that the StackSets of a CloudFormation
+	// template are deployed to, by logical ID. They override the StackInstancesGroup of the StackSet.
+	CloudFormationStackSets map[string]*CloudFormationStackSet `yaml:"cloudformation_stack_sets,omitempty" ignored:"true"`
+
+	// KubernetesCloud is the cloud that Kubernetes manifests are deployed to, aws or azure. If it's
+	// not set, it's detected from the storage classes and annotations of the manifests.
+	KubernetesCloud string `yaml:"kubernetes_cloud,omitempty" ignored:"true"`
+	// KubernetesRegion is the region of the Kubernetes cluster, defaults to the default region of
+	// the Terraform provider of the cloud
+	KubernetesRegion string `yaml:"kubernetes_region,omitempty" ignored:"true"`
+	// KubernetesStorageClasses are the Azure disk SKUs or EBS volume types of the volumes of storage
+	// classes by name. They override the parameters of the StorageClasses in the manifests.
+	KubernetesStorageClasses map[string]string `yaml:"kubernetes_storage_classes,omitempty" ignored:"true"`
+	// KubernetesNodePool is the node size that the resource requests of the workloads are fitted
+	// to. The nodes of the cluster aren't costed if it's not set.
+	KubernetesNodePool *KubernetesNodePool `yaml:"kubernetes_node_pool,omitempty" ignored:"true"`
 
 	Env map[string]string `yaml:"env,omitempty" ignored:"true"`
 }
 
+// CloudFormationStackSet are the targets of the stack instances of a CloudFormation StackSet.
+// A stack instance is created in each region of each account.
+type CloudFormationStackSet struct {
+	// Accounts are the IDs of the target accounts, or any name for them when the StackSet is
+	// deployed to organizational units
+	Accounts []string `yaml:"accounts,omitempty"`
+	Regions  []string `yaml:"regions,omitempty"`
+}
+
+// KubernetesNodePool is the size of the nodes of a Kubernetes cluster.
+type KubernetesNodePool struct {
+	// InstanceType is the Azure VM size or EC2 instance type of the nodes
+	InstanceType string `yaml:"instance_type"`
+	// CPU and Memory are the allocatable resources of each node, as Kubernetes quantities, e.g. 3860m
+	// and 12Gi. They're less than the size of the instance type since some is reserved by the kubelet.
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+	// MinCount is the minimum number of nodes, whatever the requests of the workloads
+	MinCount int64 `yaml:"min_count,omitempty"`
+}
+
 type Config struct {
 	Credentials   Credentials
 	Configuration Configuration
@@ -107,6 +175,24 @@ type Config struct {
 	Currency       string `envconfig:"CURRENCY"`
 	CurrencyFormat string `envconfig:"CURRENCY_FORMAT"`
 
+	// PricingCacheDisabled turns off the cache of prices in the .infracost directory
+	PricingCacheDisabled bool `yaml:"pricing_cache_disabled,omitempty" envconfig:"PRICING_CACHE_DISABLED"`
+	// PricingCacheTTL is how long cached prices are used for, e.g. 12h. Defaults to 24 hours.
+	PricingCacheTTL time.Duration `yaml:"pricing_cache_ttl,omitempty" envconfig:"PRICING_CACHE_TTL"`
+	// PricingSnapshotFile is a local pricing snapshot that prices are resolved from instead of the
+	// pricing API, see `infracost pricing snapshot`.
+	PricingSnapshotFile string `yaml:"pricing_snapshot_file,omitempty" envconfig:"PRICING_SNAPSHOT_FILE"`
+	// PriceAdjustmentsFile is a file of discounts and price overrides, such as negotiated rates,
+	// that are applied to the prices of the pricing API.
+	PriceAdjustmentsFile string `yaml:"price_adjustments_file,omitempty" envconfig:"PRICE_ADJUSTMENTS_FILE"`
+	// CommitmentScenario re-prices eligible VM, database and App Service cost components with a
+	// commitment, e.g. reserved_1yr, so they can be compared with their on-demand costs.
+	CommitmentScenario string `yaml:"commitment_scenario,omitempty" envconfig:"COMMITMENT_SCENARIO"`
+	// AzurePricingSource is where the prices of Azure cost components come from, either the pricing
+	// API by default or azure_retail for the Azure Retail Prices API.
+	AzurePricingSource        string `yaml:"azure_pricing_source,omitempty" envconfig:"AZURE_PRICING_SOURCE"`
+	AzureRetailPricesEndpoint string `yaml:"azure_retail_prices_endpoint,omitempty" envconfig:"AZURE_RETAIL_PRICES_ENDPOINT"`
+
 	AWSOverrideRegion    string `envconfig:"AWS_OVERRIDE_REGION"`
 	AzureOverrideRegion  string `envconfig:"AZURE_OVERRIDE_REGION"`
 	GoogleOverrideRegion string `envconfig:"GOOGLE_OVERRIDE_REGION"`
@@ -163,7 +249,8 @@ func DefaultConfig() *Config {
 		Format: "table",
 		Fields: []string{"monthlyQuantity", "unit", "monthlyCost"},
 
-		EventsDisabled: IsTest(),
+		EventsDisabled:       IsTest(),
+		PricingCacheDisabled: IsTest(),
 	}
 }
 
@@ -183,6 +270,12 @@ func (c *Config) LoadFromConfigFile(path string) error {
 	}
 
 	c.Projects = cfgFile.Projects
+	if cfgFile.PriceAdjustmentsFile != "" {
+		c.PriceAdjustmentsFile = cfgFile.PriceAdjustmentsFile
+	}
+	if cfgFile.CommitmentScenario != "" {
+		c.CommitmentScenario = cfgFile.CommitmentScenario
+	}
 
 	// Reload the environment to overwrite any of the config file configs
 	err = c.LoadFromEnv()
@@ -322,6 +415,17 @@ func (c *Config) IsSelfHosted() bool {
 	return c.PricingAPIEndpoint != "" && c.PricingAPIEndpoint != c.DefaultPricingAPIEndpoint
 }
 
+// UsesAzureRetailPrices returns true if Azure prices come from the Azure Retail Prices API.
+func (c *Config) UsesAzureRetailPrices() bool {
+	return c.AzurePricingSource == AzureRetailPricingSource
+}
+
+// IsOffline returns true if prices are resolved from a local pricing snapshot, so the pricing API
+// isn't needed.
+func (c *Config) IsOffline() bool {
+	return c.PricingSnapshotFile != ""
+}
+
 func IsTest() bool {
 	return os.Getenv("INFRACOST_ENV") == "test" || strings.HasSuffix(os.Args[0], ".test")
 }
@@ -108,6 +108,10 @@ func (y *YamlError) Error() string {
 type fileSpec struct {
 	Version  string     `yaml:"version"`
 	Projects []*Project `yaml:"projects" ignored:"true"`
+	// PriceAdjustmentsFile is the path to a price adjustments file that applies to all projects
+	PriceAdjustmentsFile string `yaml:"price_adjustments_file,omitempty"`
+	// CommitmentScenario is the commitment scenario that applies to all projects, e.g. reserved_1yr
+	CommitmentScenario string `yaml:"commitment_scenario,omitempty"`
 }
 
 // UnmarshalYAML implements the yaml.v2.Unmarshaller interface. Marshalls the
@@ -196,6 +200,8 @@ func (f *fileSpec) UnmarshalYAML(unmarshal func(interface{}) error) error {
 
 	f.Version = c.Version
 	f.Projects = c.Projects
+	f.PriceAdjustmentsFile = c.PriceAdjustmentsFile
+	f.CommitmentScenario = c.CommitmentScenario
 	return nil
 }
 
@@ -129,3 +129,22 @@ projects:
 		})
 	}
 }
+
+func TestConfigLoadPricingOptionsFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infracost.yml")
+	err := os.WriteFile(path, []byte(`version: 0.1
+price_adjustments_file: price-adjustments.yml
+commitment_scenario: reserved_3yr
+
+projects:
+  - path: path/to/my_terraform
+`), os.ModePerm)
+	require.NoError(t, err)
+
+	c := Config{}
+	err = c.LoadFromConfigFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "price-adjustments.yml", c.PriceAdjustmentsFile)
+	require.Equal(t, "reserved_3yr", c.CommitmentScenario)
+	require.Len(t, c.Projects, 1)
+}
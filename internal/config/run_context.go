@@ -35,6 +35,9 @@ type RunContext struct {
 
 	isCommentCmd bool
 
+	// runValues is the state that's shared by all the projects of the run, e.g. caches, by key
+	runValues sync.Map
+
 	OutWriter io.Writer
 	ErrWriter io.Writer
 	Exit      func(code int)
@@ -151,6 +154,24 @@ func (r *RunContext) ContextValues() map[string]interface{} {
 	return r.contextVals
 }
 
+// LoadOrStoreRunValue returns the value of key for the run, setting it to value if it isn't set.
+// Packages use it for the state that's shared by all the projects of the run, e.g. caches, so that
+// state isn't kept for longer than the run. Keys should be of an unexported type of the package.
+func (r *RunContext) LoadOrStoreRunValue(key interface{}, value interface{}) interface{} {
+	v, _ := r.runValues.LoadOrStore(key, value)
+	return v
+}
+
+// LoadRunValue returns the value of key for the run, and whether it's set.
+func (r *RunContext) LoadRunValue(key interface{}) (interface{}, bool) {
+	return r.runValues.Load(key)
+}
+
+// StoreRunValue sets the value of key for the run.
+func (r *RunContext) StoreRunValue(key interface{}, value interface{}) {
+	r.runValues.Store(key, value)
+}
+
 func (r *RunContext) GetResourceWarnings() map[string]map[string]int {
 	contextValues := r.ContextValues()
 
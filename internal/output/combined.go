@@ -276,6 +276,7 @@ func Combine(inputs []ReportInput) (Root, error) {
 	combined.PastTotalMonthlyCost = pastTotalMonthlyCost
 	combined.DiffTotalHourlyCost = diffTotalHourlyCost
 	combined.DiffTotalMonthlyCost = diffTotalMonthlyCost
+	combined.TotalCommittedMonthlyCost = calculateTotalCommittedBreakdownCost(projectBreakdowns(projects))
 	combined.TimeGenerated = time.Now().UTC()
 	combined.Summary = MergeSummaries(summaries)
 	combined.Metadata = metadata
@@ -379,3 +380,12 @@ func addCurrencyFormat(currencyFormat string) {
 
 	money.AddCurrency(currency, grapheme, template, decimal, thousand, fraction)
 }
+
+func projectBreakdowns(projects []Project) []*Breakdown {
+	breakdowns := make([]*Breakdown, 0, len(projects))
+	for _, p := range projects {
+		breakdowns = append(breakdowns, p.Breakdown)
+	}
+
+	return breakdowns
+}
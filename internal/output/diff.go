@@ -1,6 +1,7 @@
 package output
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/shopspring/decimal"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 )
 
@@ -172,6 +174,11 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 				ui.FaintString(formatCostChangeDetails(currency, oldCost, newCost)),
 			)
 		}
+
+		// Properties that change without changing the cost are left out, so they don't hide the ones that did
+		if op == UPDATED && diffResource.MonthlyCost != nil && !diffResource.MonthlyCost.IsZero() {
+			s += propertyChangesToDiff(newResource)
+		}
 	}
 
 	for _, diffComponent := range diffResource.CostComponents {
@@ -207,6 +214,48 @@ func resourceToDiff(currency string, diffResource Resource, oldResource *Resourc
 	return s
 }
 
+// propertyChangesToDiff lists the properties the provider reported as changed on a resource whose
+// cost changed, e.g. from the delta of an AzureRM WhatIf result, so it's clear what caused the change. The
+// markdown PR comment templates embed the diff, so the changes are shown in comments too.
+func propertyChangesToDiff(resource *Resource) string {
+	changes, ok := resource.Metadata[schema.PropertyChangesMetadataKey].([]interface{})
+	if !ok || len(changes) == 0 {
+		return ""
+	}
+
+	s := "  Changed properties:\n"
+
+	for _, c := range changes {
+		change, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		s += fmt.Sprintf("    %s %s\n",
+			change["path"],
+			ui.FaintStringf("(%s → %s)", formatPropertyValue(change["before"]), formatPropertyValue(change["after"])),
+		)
+	}
+
+	return s
+}
+
+func formatPropertyValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "none"
+	case string:
+		return val
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
 func costComponentToDiff(currency string, diffComponent CostComponent, oldComponent *CostComponent, newComponent *CostComponent) string {
 	s := ""
 
@@ -0,0 +1,83 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestResourceToDiffPropertyChanges(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	oldResource := Resource{Name: "asp", MonthlyCost: decimalPtr(decimal.NewFromInt(70))}
+	newResource := Resource{
+		Name:        "asp",
+		MonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+		Metadata: map[string]interface{}{
+			schema.PropertyChangesMetadataKey: []interface{}{
+				map[string]interface{}{"path": "sku.name", "before": "S1", "after": "P1v2"},
+				map[string]interface{}{"path": "properties.capacity", "before": nil, "after": float64(2)},
+			},
+		},
+	}
+	diffResource := Resource{Name: "asp", MonthlyCost: decimalPtr(decimal.NewFromInt(80))}
+
+	actual := resourceToDiff("USD", diffResource, &oldResource, &newResource, true)
+
+	expected := "  Changed properties:\n" +
+		"    sku.name (S1 → P1v2)\n" +
+		"    properties.capacity (none → 2)\n"
+	assert.Contains(t, actual, expected)
+
+	// Added resources have nothing to compare against
+	actual = resourceToDiff("USD", diffResource, nil, &newResource, true)
+	assert.NotContains(t, actual, "Changed properties")
+
+	// Properties that change without changing the cost aren't shown
+	unchanged := Resource{Name: "asp", MonthlyCost: decimalPtr(decimal.Zero)}
+	actual = resourceToDiff("USD", unchanged, &oldResource, &newResource, true)
+	assert.NotContains(t, actual, "Changed properties")
+}
+
+func TestToMarkdownPropertyChanges(t *testing.T) {
+	changes := []interface{}{
+		map[string]interface{}{"path": "sku.name", "before": "S1", "after": "P1v2"},
+	}
+
+	out := Root{
+		Currency: "USD",
+		Projects: []Project{{
+			Name:     "app",
+			Metadata: &schema.ProjectMetadata{},
+			PastBreakdown: &Breakdown{
+				Resources:        []Resource{{Name: "asp", MonthlyCost: decimalPtr(decimal.NewFromInt(70))}},
+				TotalMonthlyCost: decimalPtr(decimal.NewFromInt(70)),
+			},
+			Breakdown: &Breakdown{
+				Resources: []Resource{{
+					Name:        "asp",
+					MonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+					Metadata:    map[string]interface{}{schema.PropertyChangesMetadataKey: changes},
+				}},
+				TotalMonthlyCost: decimalPtr(decimal.NewFromInt(150)),
+			},
+			Diff: &Breakdown{
+				Resources:        []Resource{{Name: "asp", MonthlyCost: decimalPtr(decimal.NewFromInt(80))}},
+				TotalMonthlyCost: decimalPtr(decimal.NewFromInt(80)),
+			},
+		}},
+	}
+
+	// The property changes are shown in the diff of the PR comment with either syntax
+	for _, basic := range []bool{false, true} {
+		b, err := ToMarkdown(out, Options{}, MarkdownOptions{BasicSyntax: basic})
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "Changed properties:\n    sku.name (S1 → P1v2)", "basic syntax: %v", basic)
+	}
+}
@@ -45,6 +45,8 @@ func ToHTML(out Root, opts Options) ([]byte, error) {
 		"filterZeroValResources":  filterZeroValResources,
 		"formatCost2DP":           func(d *decimal.Decimal) string { return FormatCost2DP(out.Currency, d) },
 		"formatPrice":             func(d decimal.Decimal) string { return formatPrice(out.Currency, d) },
+		"listPrice":               func(c CostComponent) decimal.Decimal { return c.listPrice() },
+		"committedMonthlyCost":    func(c CostComponent) *decimal.Decimal { return c.committedMonthlyCost() },
 		"formatTitleWithCurrency": func(title string) string { return formatTitleWithCurrency(title, out.Currency) },
 		"formatQuantity":          formatQuantity,
 		"projectLabel": func(p Project) string {
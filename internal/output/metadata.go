@@ -26,6 +26,9 @@ type Metadata struct {
 	VCSPullRequestLabels []string `json:"vcsPullRequestLabels,omitempty"`
 	VCSPipelineRunID     string   `json:"vcsPipelineRunId,omitempty"`
 	VCSPullRequestID     string   `json:"vcsPullRequestId,omitempty"`
+
+	// CommitmentScenario is the commitment scenario that committed costs were priced with
+	CommitmentScenario string `json:"commitmentScenario,omitempty"`
 }
 
 // NewMetadata returns a Metadata struct filled with information built from the RunContext.
@@ -41,6 +44,10 @@ func NewMetadata(ctx *config.RunContext) Metadata {
 		VCSRepositoryURL:  ctx.VCSRepositoryURL(),
 	}
 
+	if ctx.Config != nil {
+		m.CommitmentScenario = ctx.Config.CommitmentScenario
+	}
+
 	if ctx.VCSMetadata.PullRequest != nil {
 		m.VCSProvider = ctx.VCSMetadata.PullRequest.VCSProvider
 		m.VCSPullRequestID = ctx.VCSMetadata.PullRequest.ID
@@ -21,22 +21,24 @@ import (
 var outputVersion = "0.2"
 
 type Root struct {
-	Version              string           `json:"version"`
-	Metadata             Metadata         `json:"metadata"`
-	RunID                string           `json:"runId,omitempty"`
-	ShareURL             string           `json:"shareUrl,omitempty"`
-	Currency             string           `json:"currency"`
-	Projects             Projects         `json:"projects"`
-	TotalHourlyCost      *decimal.Decimal `json:"totalHourlyCost"`
-	TotalMonthlyCost     *decimal.Decimal `json:"totalMonthlyCost"`
-	PastTotalHourlyCost  *decimal.Decimal `json:"pastTotalHourlyCost"`
-	PastTotalMonthlyCost *decimal.Decimal `json:"pastTotalMonthlyCost"`
-	DiffTotalHourlyCost  *decimal.Decimal `json:"diffTotalHourlyCost"`
-	DiffTotalMonthlyCost *decimal.Decimal `json:"diffTotalMonthlyCost"`
-	TimeGenerated        time.Time        `json:"timeGenerated"`
-	Summary              *Summary         `json:"summary"`
-	FullSummary          *Summary         `json:"-"`
-	IsCIRun              bool             `json:"-"`
+	Version          string           `json:"version"`
+	Metadata         Metadata         `json:"metadata"`
+	RunID            string           `json:"runId,omitempty"`
+	ShareURL         string           `json:"shareUrl,omitempty"`
+	Currency         string           `json:"currency"`
+	Projects         Projects         `json:"projects"`
+	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
+	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+	// TotalCommittedMonthlyCost is the total monthly cost under the commitment scenario of the run
+	TotalCommittedMonthlyCost *decimal.Decimal `json:"totalCommittedMonthlyCost,omitempty"`
+	PastTotalHourlyCost       *decimal.Decimal `json:"pastTotalHourlyCost"`
+	PastTotalMonthlyCost      *decimal.Decimal `json:"pastTotalMonthlyCost"`
+	DiffTotalHourlyCost       *decimal.Decimal `json:"diffTotalHourlyCost"`
+	DiffTotalMonthlyCost      *decimal.Decimal `json:"diffTotalMonthlyCost"`
+	TimeGenerated             time.Time        `json:"timeGenerated"`
+	Summary                   *Summary         `json:"summary"`
+	FullSummary               *Summary         `json:"-"`
+	IsCIRun                   bool             `json:"-"`
 }
 
 type Project struct {
@@ -83,6 +85,8 @@ func convertOutputResources(outResources []Resource) []*schema.Resource {
 			HourlyCost:     resource.HourlyCost,
 			MonthlyCost:    resource.MonthlyCost,
 			ResourceType:   resource.ResourceType(),
+
+			CommittedMonthlyCost: resource.CommittedMonthlyCost,
 		}
 	}
 
@@ -101,8 +105,18 @@ func convertCostComponents(outComponents []CostComponent) []*schema.CostComponen
 			MonthlyCost:     c.MonthlyCost,
 			HourlyQuantity:  c.HourlyQuantity,
 			MonthlyQuantity: c.MonthlyQuantity,
+
+			CommittedMonthlyCost: c.CommittedMonthlyCost,
+		}
+		if c.CommittedPrice != nil {
+			sc.SetCommittedPrice(*c.CommittedPrice)
+		}
+		if c.ListPrice != nil {
+			sc.SetPrice(*c.ListPrice)
+			sc.SetPriceAdjustment(c.PriceAdjustment, c.Price)
+		} else {
+			sc.SetPrice(c.Price)
 		}
-		sc.SetPrice(c.Price)
 
 		components[i] = sc
 	}
@@ -156,6 +170,33 @@ func (r *Root) HasDiff() bool {
 	return false
 }
 
+// HasPriceAdjustments returns true if the price of any cost component has been adjusted
+func (r *Root) HasPriceAdjustments() bool {
+	for _, p := range r.Projects {
+		if p.Breakdown != nil && resourcesHavePriceAdjustments(p.Breakdown.Resources) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resourcesHavePriceAdjustments(resources []Resource) bool {
+	for _, r := range resources {
+		for _, c := range r.CostComponents {
+			if c.ListPrice != nil {
+				return true
+			}
+		}
+
+		if resourcesHavePriceAdjustments(r.SubResources) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Label returns the display name of the project
 func (p *Project) Label() string {
 	return p.Name
@@ -180,9 +221,10 @@ func (p *Project) LabelWithMetadata() string {
 }
 
 type Breakdown struct {
-	Resources        []Resource       `json:"resources"`
-	TotalHourlyCost  *decimal.Decimal `json:"totalHourlyCost"`
-	TotalMonthlyCost *decimal.Decimal `json:"totalMonthlyCost"`
+	Resources                 []Resource       `json:"resources"`
+	TotalHourlyCost           *decimal.Decimal `json:"totalHourlyCost"`
+	TotalMonthlyCost          *decimal.Decimal `json:"totalMonthlyCost"`
+	TotalCommittedMonthlyCost *decimal.Decimal `json:"totalCommittedMonthlyCost,omitempty"`
 }
 
 type CostComponent struct {
@@ -191,8 +233,33 @@ type CostComponent struct {
 	HourlyQuantity  *decimal.Decimal `json:"hourlyQuantity"`
 	MonthlyQuantity *decimal.Decimal `json:"monthlyQuantity"`
 	Price           decimal.Decimal  `json:"price"`
+	ListPrice       *decimal.Decimal `json:"listPrice,omitempty"`
+	PriceAdjustment string           `json:"priceAdjustment,omitempty"`
 	HourlyCost      *decimal.Decimal `json:"hourlyCost"`
 	MonthlyCost     *decimal.Decimal `json:"monthlyCost"`
+	// CommittedPrice and CommittedMonthlyCost are only set for cost components that have been
+	// re-priced with the commitment scenario of the run
+	CommittedPrice       *decimal.Decimal `json:"committedPrice,omitempty"`
+	CommittedMonthlyCost *decimal.Decimal `json:"committedMonthlyCost,omitempty"`
+}
+
+// listPrice returns the price before any price adjustment.
+func (c CostComponent) listPrice() decimal.Decimal {
+	if c.ListPrice != nil {
+		return *c.ListPrice
+	}
+
+	return c.Price
+}
+
+// committedMonthlyCost returns the monthly cost under the commitment scenario, which is the
+// on-demand monthly cost if the component hasn't been re-priced.
+func (c CostComponent) committedMonthlyCost() *decimal.Decimal {
+	if c.CommittedMonthlyCost != nil {
+		return c.CommittedMonthlyCost
+	}
+
+	return c.MonthlyCost
 }
 
 type ActualCosts struct {
@@ -203,14 +270,17 @@ type ActualCosts struct {
 }
 
 type Resource struct {
-	Name           string                 `json:"name"`
-	Tags           map[string]string      `json:"tags,omitempty"`
-	Metadata       map[string]interface{} `json:"metadata"`
-	HourlyCost     *decimal.Decimal       `json:"hourlyCost"`
-	MonthlyCost    *decimal.Decimal       `json:"monthlyCost"`
-	CostComponents []CostComponent        `json:"costComponents,omitempty"`
-	ActualCosts    []ActualCosts          `json:"actualCosts,omitempty"`
-	SubResources   []Resource             `json:"subresources,omitempty"`
+	Name        string                 `json:"name"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata"`
+	HourlyCost  *decimal.Decimal       `json:"hourlyCost"`
+	MonthlyCost *decimal.Decimal       `json:"monthlyCost"`
+	// CommittedMonthlyCost is only set for resources with cost components that have been
+	// re-priced with the commitment scenario of the run
+	CommittedMonthlyCost *decimal.Decimal `json:"committedMonthlyCost,omitempty"`
+	CostComponents       []CostComponent  `json:"costComponents,omitempty"`
+	ActualCosts          []ActualCosts    `json:"actualCosts,omitempty"`
+	SubResources         []Resource       `json:"subresources,omitempty"`
 }
 
 func (r Resource) ResourceType() string {
@@ -410,9 +480,10 @@ func outputBreakdown(resources []*schema.Resource) *Breakdown {
 	totalMonthlyCost, totalHourlyCost := calculateTotalCosts(arr)
 
 	return &Breakdown{
-		Resources:        arr,
-		TotalHourlyCost:  totalMonthlyCost,
-		TotalMonthlyCost: totalHourlyCost,
+		Resources:                 arr,
+		TotalHourlyCost:           totalMonthlyCost,
+		TotalMonthlyCost:          totalHourlyCost,
+		TotalCommittedMonthlyCost: calculateTotalCommittedCost(arr),
 	}
 }
 
@@ -440,8 +511,10 @@ func outputResource(r *schema.Resource) Resource {
 		HourlyCost:     r.HourlyCost,
 		MonthlyCost:    r.MonthlyCost,
 		CostComponents: comps,
-		ActualCosts:    actualCosts,
-		SubResources:   subresources,
+
+		CommittedMonthlyCost: r.CommittedMonthlyCost,
+		ActualCosts:          actualCosts,
+		SubResources:         subresources,
 	}
 }
 
@@ -454,8 +527,13 @@ func outputCostComponents(costComponents []*schema.CostComponent) []CostComponen
 			HourlyQuantity:  c.UnitMultiplierHourlyQuantity(),
 			MonthlyQuantity: c.UnitMultiplierMonthlyQuantity(),
 			Price:           c.UnitMultiplierPrice(),
+			ListPrice:       c.UnitMultiplierListPrice(),
+			PriceAdjustment: c.PriceAdjustment(),
 			HourlyCost:      c.HourlyCost,
 			MonthlyCost:     c.MonthlyCost,
+
+			CommittedPrice:       c.UnitMultiplierCommittedPrice(),
+			CommittedMonthlyCost: c.CommittedMonthlyCost,
 		})
 	}
 	return comps
@@ -479,6 +557,8 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		pastTotalMonthlyCost, pastTotalHourlyCost,
 		diffTotalMonthlyCost, diffTotalHourlyCost *decimal.Decimal
 
+	breakdowns := make([]*Breakdown, 0, len(projects))
+
 	outProjects := make([]Project, 0, len(projects))
 	summaries := make([]*Summary, 0, len(projects))
 	fullSummaries := make([]*Summary, 0, len(projects))
@@ -487,6 +567,7 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		var pastBreakdown, breakdown, diff *Breakdown
 
 		breakdown = outputBreakdown(project.Resources)
+		breakdowns = append(breakdowns, breakdown)
 
 		if breakdown != nil {
 			if breakdown.TotalHourlyCost != nil {
@@ -586,6 +667,8 @@ func ToOutputFormat(projects []*schema.Project) (Root, error) {
 		TimeGenerated:        time.Now().UTC(),
 		Summary:              MergeSummaries(summaries),
 		FullSummary:          MergeSummaries(fullSummaries),
+
+		TotalCommittedMonthlyCost: calculateTotalCommittedBreakdownCost(breakdowns),
 	}
 
 	return out, nil
@@ -879,6 +962,61 @@ func calculateTotalCosts(resources []Resource) (*decimal.Decimal, *decimal.Decim
 	return totalHourlyCost, totalMonthlyCost
 }
 
+// calculateTotalCommittedCost returns the total monthly cost of the resources under the commitment
+// scenario, or nil if none of them have been re-priced. Resources that haven't been re-priced are
+// included at their on-demand cost.
+func calculateTotalCommittedCost(resources []Resource) *decimal.Decimal {
+	var total *decimal.Decimal
+	onDemand := decimal.Zero
+
+	for _, r := range resources {
+		if r.CommittedMonthlyCost != nil {
+			if total == nil {
+				total = decimalPtr(decimal.Zero)
+			}
+
+			total = decimalPtr(total.Add(*r.CommittedMonthlyCost))
+		} else if r.MonthlyCost != nil {
+			onDemand = onDemand.Add(*r.MonthlyCost)
+		}
+	}
+
+	if total == nil {
+		return nil
+	}
+
+	return decimalPtr(total.Add(onDemand))
+}
+
+// calculateTotalCommittedBreakdownCost returns the total committed monthly cost of the breakdowns,
+// or nil if none of them have been re-priced.
+func calculateTotalCommittedBreakdownCost(breakdowns []*Breakdown) *decimal.Decimal {
+	var total *decimal.Decimal
+	onDemand := decimal.Zero
+
+	for _, b := range breakdowns {
+		if b == nil {
+			continue
+		}
+
+		if b.TotalCommittedMonthlyCost != nil {
+			if total == nil {
+				total = decimalPtr(decimal.Zero)
+			}
+
+			total = decimalPtr(total.Add(*b.TotalCommittedMonthlyCost))
+		} else if b.TotalMonthlyCost != nil {
+			onDemand = onDemand.Add(*b.TotalMonthlyCost)
+		}
+	}
+
+	if total == nil {
+		return nil
+	}
+
+	return decimalPtr(total.Add(onDemand))
+}
+
 func sortResources(resources []Resource, groupKey string) {
 	sort.Slice(resources, func(i, j int) bool {
 		// If an empty group key is passed just sort by name
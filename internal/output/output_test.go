@@ -32,3 +32,89 @@ func TestCalculateTotalCosts(t *testing.T) {
 	actual, _ = totalMonthlyCost.Float64()
 	assert.Equal(t, expected, actual)
 }
+
+func TestTableWithPriceAdjustments(t *testing.T) {
+	listPrice := decimal.NewFromFloat(0.1)
+	root := Root{
+		Currency: "USD",
+		Projects: []Project{{
+			Name: "test",
+			Breakdown: &Breakdown{
+				Resources: []Resource{{
+					Name: "vm",
+					CostComponents: []CostComponent{
+						{
+							Name:            "Instance usage",
+							Unit:            "hours",
+							MonthlyQuantity: decimalPtr(decimal.NewFromInt(730)),
+							Price:           decimal.NewFromFloat(0.082),
+							ListPrice:       &listPrice,
+							PriceAdjustment: "EA discount",
+							MonthlyCost:     decimalPtr(decimal.NewFromFloat(59.86)),
+						},
+						{
+							Name:            "OS disk",
+							Unit:            "months",
+							MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+							Price:           decimal.NewFromFloat(1.54),
+							MonthlyCost:     decimalPtr(decimal.NewFromFloat(1.54)),
+						},
+					},
+				}},
+			},
+		}},
+	}
+
+	assert.True(t, root.HasPriceAdjustments())
+
+	out := tableForBreakdown("USD", *root.Projects[0].Breakdown, []string{"listPrice", "price", "monthlyCost"}, false)
+	assert.Contains(t, out, "List price")
+	assert.Regexp(t, `Instance usage\s+\$0\.10\s+\$0\.082\s+\$59\.86`, out)
+	assert.Regexp(t, `OS disk\s+\$1\.54\s+\$1\.54\s+\$1\.54`, out)
+}
+
+func TestTableWithCommittedCosts(t *testing.T) {
+	vm := Resource{
+		Name:                 "vm",
+		MonthlyCost:          decimalPtr(decimal.NewFromFloat(71.62)),
+		CommittedMonthlyCost: decimalPtr(decimal.NewFromFloat(38.04)),
+		CostComponents: []CostComponent{
+			{
+				Name:                 "Instance usage",
+				Unit:                 "hours",
+				MonthlyQuantity:      decimalPtr(decimal.NewFromInt(730)),
+				Price:                decimal.NewFromFloat(0.096),
+				MonthlyCost:          decimalPtr(decimal.NewFromFloat(70.08)),
+				CommittedPrice:       decimalPtr(decimal.NewFromFloat(0.05)),
+				CommittedMonthlyCost: decimalPtr(decimal.NewFromFloat(36.5)),
+			},
+			{
+				Name:            "OS disk",
+				Unit:            "months",
+				MonthlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+				Price:           decimal.NewFromFloat(1.54),
+				MonthlyCost:     decimalPtr(decimal.NewFromFloat(1.54)),
+			},
+		},
+	}
+	disk := Resource{
+		Name:        "disk",
+		MonthlyCost: decimalPtr(decimal.NewFromFloat(10)),
+	}
+
+	breakdown := Breakdown{Resources: []Resource{vm, disk}}
+	breakdown.TotalHourlyCost, breakdown.TotalMonthlyCost = calculateTotalCosts(breakdown.Resources)
+	breakdown.TotalCommittedMonthlyCost = calculateTotalCommittedCost(breakdown.Resources)
+	assert.Equal(t, "48.04", breakdown.TotalCommittedMonthlyCost.String())
+
+	out := tableForBreakdown("USD", breakdown, []string{"monthlyQuantity", "committedMonthlyCost", "monthlyCost"}, true)
+	assert.Contains(t, out, "Committed Cost")
+	assert.Regexp(t, `Instance usage\s+730\s+\$36\.50\s+\$70\.08`, out)
+	// Components that can't be committed to are shown at their on-demand cost
+	assert.Regexp(t, `OS disk\s+1\s+\$1\.54\s+\$1\.54`, out)
+	assert.Regexp(t, `Project total\s+\$48\.04\s+\$81\.62`, out)
+
+	// Totals without committed costs are left out
+	assert.Nil(t, calculateTotalCommittedCost([]Resource{disk}))
+	assert.Equal(t, "58.04", calculateTotalCommittedBreakdownCost([]*Breakdown{&breakdown, {TotalMonthlyCost: decimalPtr(decimal.NewFromInt(10))}}).String())
+}
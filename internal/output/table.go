@@ -96,6 +96,19 @@ func ToTable(out Root, opts Options) ([]byte, error) {
 		fmt.Sprintf("%*s ", tableLen-(len(overallTitle)+1), totalOut), // pad based on the last line length
 	)
 
+	if contains(opts.Fields, "committedMonthlyCost") && out.TotalCommittedMonthlyCost != nil {
+		committedOut := FormatCost2DP(out.Currency, out.TotalCommittedMonthlyCost)
+
+		committedTitle := formatTitleWithCurrency(" OVERALL COMMITTED TOTAL", out.Currency)
+		if out.Metadata.CommitmentScenario != "" {
+			committedTitle = formatTitleWithCurrency(fmt.Sprintf(" OVERALL TOTAL WITH %s", out.Metadata.CommitmentScenario), out.Currency)
+		}
+		s += fmt.Sprintf("\n%s%s",
+			ui.BoldString(committedTitle),
+			fmt.Sprintf("%*s ", tableLen-(len(committedTitle)+1), committedOut),
+		)
+	}
+
 	summaryMsg := out.summaryMessage(opts.ShowSkipped)
 
 	if summaryMsg != "" {
@@ -128,6 +141,15 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 	})
 	i++
 
+	if contains(fields, "listPrice") {
+		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("List price", currency)))
+		columns = append(columns, table.ColumnConfig{
+			Number:      i,
+			Align:       text.AlignRight,
+			AlignHeader: text.AlignRight,
+		})
+		i++
+	}
 	if contains(fields, "price") {
 		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Price", currency)))
 		columns = append(columns, table.ColumnConfig{
@@ -164,6 +186,15 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		})
 		i++
 	}
+	if contains(fields, "committedMonthlyCost") {
+		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Committed Cost", currency)))
+		columns = append(columns, table.ColumnConfig{
+			Number:      i,
+			Align:       text.AlignRight,
+			AlignHeader: text.AlignRight,
+		})
+		i++
+	}
 	if contains(fields, "monthlyCost") {
 		headers = append(headers, ui.UnderlineString(formatTitleWithCurrency("Monthly Cost", currency)))
 		columns = append(columns, table.ColumnConfig{
@@ -200,9 +231,19 @@ func tableForBreakdown(currency string, breakdown Breakdown, fields []string, in
 		var totalCostRow table.Row
 		totalCostRow = append(totalCostRow, ui.BoldString(formatTitleWithCurrency("Project total", currency)))
 		numOfFields := i - 3
+		if contains(fields, "committedMonthlyCost") {
+			numOfFields--
+		}
 		for q := 0; q < numOfFields; q++ {
 			totalCostRow = append(totalCostRow, "")
 		}
+		if contains(fields, "committedMonthlyCost") {
+			totalCommittedCost := breakdown.TotalCommittedMonthlyCost
+			if totalCommittedCost == nil {
+				totalCommittedCost = breakdown.TotalMonthlyCost
+			}
+			totalCostRow = append(totalCostRow, FormatCost2DP(currency, totalCommittedCost))
+		}
 		totalCostRow = append(totalCostRow, FormatCost2DP(currency, breakdown.TotalMonthlyCost))
 		t.AppendRow(totalCostRow)
 	}
@@ -259,6 +300,9 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			var tableRow table.Row
 			tableRow = append(tableRow, label)
 
+			if contains(fields, "listPrice") {
+				tableRow = append(tableRow, formatPrice(currency, c.listPrice()))
+			}
 			if contains(fields, "price") {
 				tableRow = append(tableRow, formatPrice(currency, c.Price))
 			}
@@ -271,6 +315,9 @@ func buildCostComponentRows(t table.Writer, currency string, costComponents []Co
 			if contains(fields, "hourlyCost") {
 				tableRow = append(tableRow, FormatCost2DP(currency, c.HourlyCost))
 			}
+			if contains(fields, "committedMonthlyCost") {
+				tableRow = append(tableRow, FormatCost2DP(currency, c.committedMonthlyCost()))
+			}
 			if contains(fields, "monthlyCost") {
 				tableRow = append(tableRow, FormatCost2DP(currency, c.MonthlyCost))
 			}
@@ -51,7 +51,7 @@ td.name {
   max-width: 32rem;
 }
 
-td.monthly-quantity, td.price, td.hourly-cost, td.monthly-cost {
+td.monthly-quantity, td.list-price, td.price, td.hourly-cost, td.committed-monthly-cost, td.monthly-cost {
   text-align: right;
 }
 
@@ -127,12 +127,18 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
   {{if contains .Fields "unit"}}
     <td class="unit"></td>
   {{end}}
+  {{if contains .Fields "listPrice"}}
+    <td class="list-price"></td>
+  {{end}}
   {{if contains .Fields "price"}}
     <td class="price"></td>
   {{end}}
   {{if contains .Fields "hourlyCost"}}
     <td class="hourly-cost"></td>
   {{end}}
+  {{if contains .Fields "committedMonthlyCost"}}
+    <td class="committed-monthly-cost"></td>
+  {{end}}
   {{if contains .Fields "monthlyCost"}}
     <td class="monthly-cost"></td>
   {{end}}
@@ -189,12 +195,18 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
       {{if contains .Fields "unit"}}
         <td class="unit">{{.CostComponent.Unit}}</td>
       {{end}}
+      {{if contains .Fields "listPrice"}}
+        <td class="list-price">{{.CostComponent | listPrice | formatPrice }}</td>
+      {{end}}
       {{if contains .Fields "price"}}
         <td class="price">{{.CostComponent.Price | formatPrice }}</td>
       {{end}}
       {{if contains .Fields "hourlyCost"}}
         <td class="hourly-cost">{{.CostComponent.HourlyCost | formatCost2DP}}</td>
       {{end}}
+      {{if contains .Fields "committedMonthlyCost"}}
+        <td class="committed-monthly-cost">{{.CostComponent | committedMonthlyCost | formatCost2DP}}</td>
+      {{end}}
       {{if contains .Fields "monthlyCost"}}
         <td class="monthly-cost">{{.CostComponent.MonthlyCost | formatCost2DP}}</td>
       {{end}}
@@ -212,12 +224,18 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
   {{if contains .Fields "unit"}}
     <td class="unit">Unit</td>
   {{end}}
+  {{if contains .Fields "listPrice"}}
+    <td class="list-price">{{ "List price" | formatTitleWithCurrency }}</td>
+  {{end}}
   {{if contains .Fields "price"}}
     <td class="price">{{ "Price" | formatTitleWithCurrency }}</td>
   {{end}}
   {{if contains .Fields "hourlyCost"}}
     <td class="hourly-cost">{{ "Hourly Cost" | formatTitleWithCurrency }}</td>
   {{end}}
+  {{if contains .Fields "committedMonthlyCost"}}
+    <td class="committed-monthly-cost">{{ "Committed Cost" | formatTitleWithCurrency }}</td>
+  {{end}}
   {{if contains .Fields "monthlyCost"}}
     <td class="monthly-cost">{{ "Monthly Cost" | formatTitleWithCurrency }}</td>
   {{end}}
@@ -241,7 +259,12 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
         {{template "resourceRows" dict "Resource" . "Fields" $fields "Indent" 0}}
       {{end}}
       <tr class="total">
-        <td class="name" colspan="{{len .Options.Fields}}">Project total</td>
+        {{if contains .Options.Fields "committedMonthlyCost"}}
+          <td class="name" colspan="{{add (len .Options.Fields) -1}}">Project total</td>
+          <td class="committed-monthly-cost">{{or .Project.Breakdown.TotalCommittedMonthlyCost .Project.Breakdown.TotalMonthlyCost | formatCost2DP}}</td>
+        {{else}}
+          <td class="name" colspan="{{len .Options.Fields}}">Project total</td>
+        {{end}}
         <td class="monthly-cost">{{.Project.Breakdown.TotalMonthlyCost | formatCost2DP}}</td>
       </tr>
     </tbody>
@@ -282,7 +305,12 @@ iVBORw0KGgoAAAANSUhEUgAAAMAAAADACAMAAABlApw1AAAABGdBTUEAALGPC/xhBQAAAAFzUkdCAK7O
     <table class="overall-total">
       <tbody>
         <tr class="total">
-          <td class="name" colspan="{{len .Options.Fields}}">{{ "Overall total" | formatTitleWithCurrency }}</td>
+          {{if contains .Options.Fields "committedMonthlyCost"}}
+            <td class="name" colspan="{{add (len .Options.Fields) -1}}">{{ "Overall total" | formatTitleWithCurrency }}</td>
+            <td class="committed-monthly-cost">{{or .Root.TotalCommittedMonthlyCost .Root.TotalMonthlyCost | formatCost2DP}}</td>
+          {{else}}
+            <td class="name" colspan="{{len .Options.Fields}}">{{ "Overall total" | formatTitleWithCurrency }}</td>
+          {{end}}
           <td class="monthly-cost">{{.Root.TotalMonthlyCost | formatCost2DP}}</td>
         </tr>
       </tbody>
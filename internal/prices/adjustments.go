@@ -0,0 +1,249 @@
+package prices
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PriceAdjustments are discounts and price overrides, such as the negotiated rates of an enterprise
+// agreement, that are applied to the prices of the pricing API. They're loaded from a YAML file:
+//
+//	version: 0.1
+//	adjustments:
+//	  - name: EA discount on VMs
+//	    match:
+//	      provider: azure
+//	      service: Virtual Machines
+//	    discount_percentage: 18
+//	  - name: Negotiated P10 disk price
+//	    match:
+//	      provider: azure
+//	      sku: P10*
+//	      tags:
+//	        environment: prod
+//	    price: 15.50
+//
+// The first adjustment that matches a cost component is applied to it.
+type PriceAdjustments struct {
+	Version     string             `yaml:"version"`
+	Adjustments []*PriceAdjustment `yaml:"adjustments"`
+}
+
+// PriceAdjustment changes the price of the cost components it matches, either by a percentage or
+// to an absolute price in the currency of the run.
+type PriceAdjustment struct {
+	Name  string               `yaml:"name,omitempty"`
+	Match PriceAdjustmentMatch `yaml:"match,omitempty"`
+	// DiscountPercentage reduces the price by a percentage, e.g. 18 for 18% off. Negative
+	// percentages increase the price.
+	DiscountPercentage *float64 `yaml:"discount_percentage,omitempty"`
+	// Price replaces the price of the unit the cost component is shown in, e.g. the price of 1M
+	// requests for a cost component with the unit "1M requests".
+	Price *string `yaml:"price,omitempty"`
+
+	price decimal.Decimal
+}
+
+// PriceAdjustmentMatch matches cost components by their product filter and the tags of their
+// resource. Values are case-insensitive and can be glob patterns. Empty values match anything.
+type PriceAdjustmentMatch struct {
+	// Provider is the vendor name of the pricing API, e.g. aws, azure or gcp
+	Provider      string `yaml:"provider,omitempty"`
+	Service       string `yaml:"service,omitempty"`
+	ProductFamily string `yaml:"product_family,omitempty"`
+	Region        string `yaml:"region,omitempty"`
+	// Sku is matched against the SKU of the product filter and its attributes that contain "sku",
+	// e.g. skuName and armSkuName
+	Sku  string            `yaml:"sku,omitempty"`
+	Tags map[string]string `yaml:"tags,omitempty"`
+}
+
+// providerVendorNames are the vendor names of the pricing API of Terraform provider names.
+var providerVendorNames = map[string]string{
+	"azurerm": "azure",
+	"google":  "gcp",
+}
+
+// LoadPriceAdjustments reads and validates a price adjustments file.
+func LoadPriceAdjustments(filename string) (*PriceAdjustments, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading price adjustments file")
+	}
+
+	var adjustments PriceAdjustments
+	err = yaml.UnmarshalStrict(b, &adjustments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing price adjustments file %s", filename)
+	}
+
+	for i, a := range adjustments.Adjustments {
+		if a.Name == "" {
+			a.Name = fmt.Sprintf("adjustment %d", i+1)
+		}
+
+		if (a.DiscountPercentage == nil) == (a.Price == nil) {
+			return nil, fmt.Errorf("Price adjustment %q in %s must set one of discount_percentage or price", a.Name, filename)
+		}
+
+		if a.Price != nil {
+			a.price, err = decimal.NewFromString(*a.Price)
+			if err != nil {
+				return nil, fmt.Errorf("Price adjustment %q in %s has an invalid price %q", a.Name, filename, *a.Price)
+			}
+		}
+
+		if v, ok := providerVendorNames[strings.ToLower(a.Match.Provider)]; ok {
+			a.Match.Provider = v
+		}
+	}
+
+	return &adjustments, nil
+}
+
+// Apply adjusts the price of c with the first adjustment that matches it. Tags are the tags of the
+// top-level resource of c, since sub-resources don't have tags.
+func (p *PriceAdjustments) Apply(r *schema.Resource, c *schema.CostComponent, tags map[string]string) {
+	a := p.match(c, tags)
+	if a == nil {
+		return
+	}
+
+	// Prices are stored per unit of the pricing API, e.g. per request, so the absolute price
+	// of the shown unit is divided by its multiplier
+	price := a.price
+	if !c.UnitMultiplier.IsZero() {
+		price = price.Div(c.UnitMultiplier)
+	}
+
+	if a.DiscountPercentage != nil {
+		price = a.discount(c.Price())
+	}
+
+	log.Debugf("Using price adjustment %q for %s %s, adjusting the price %s to %s", a.Name, r.Name, c.Name, c.Price(), price)
+	c.SetPriceAdjustment(a.Name, price)
+}
+
+// ApplyCommitted returns the committed price of c with the discount of the first adjustment that
+// matches it. Absolute prices replace the on-demand price of c, so they don't change its committed
+// price, which stays the list price of the commitment.
+func (p *PriceAdjustments) ApplyCommitted(r *schema.Resource, c *schema.CostComponent, tags map[string]string, price decimal.Decimal) decimal.Decimal {
+	a := p.match(c, tags)
+	if a == nil || a.DiscountPercentage == nil {
+		return price
+	}
+
+	adjusted := a.discount(price)
+	log.Debugf("Using price adjustment %q for %s %s, adjusting the committed price %s to %s", a.Name, r.Name, c.Name, price, adjusted)
+	return adjusted
+}
+
+// match returns the first adjustment that matches c, or nil if there isn't one. Custom prices
+// aren't adjusted.
+func (p *PriceAdjustments) match(c *schema.CostComponent, tags map[string]string) *PriceAdjustment {
+	if p == nil || c.CustomPrice() != nil {
+		return nil
+	}
+
+	for _, a := range p.Adjustments {
+		if a.matches(c.ProductFilter, tags) {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func (a *PriceAdjustment) discount(price decimal.Decimal) decimal.Decimal {
+	multiplier := decimal.NewFromInt(1).Sub(decimal.NewFromFloat(*a.DiscountPercentage).Div(decimal.NewFromInt(100)))
+	return price.Mul(multiplier)
+}
+
+func (a *PriceAdjustment) matches(f *schema.ProductFilter, tags map[string]string) bool {
+	if f == nil {
+		return false
+	}
+
+	m := a.Match
+	if !matchesPattern(m.Provider, f.VendorName) ||
+		!matchesPattern(m.Service, f.Service) ||
+		!matchesPattern(m.ProductFamily, f.ProductFamily) ||
+		!matchesPattern(m.Region, f.Region) {
+		return false
+	}
+
+	if m.Sku != "" && !matchesSku(m.Sku, f) {
+		return false
+	}
+
+	for k, v := range m.Tags {
+		tag, ok := tags[k]
+		if !ok || !matchesPattern(v, &tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesSku(pattern string, f *schema.ProductFilter) bool {
+	if matchesPattern(pattern, f.Sku) {
+		return true
+	}
+
+	for _, attr := range f.AttributeFilters {
+		if attr != nil && strings.Contains(strings.ToLower(attr.Key), "sku") && attr.Value != nil && matchesPattern(pattern, attr.Value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPattern returns true if the pattern is empty, or matches the value ignoring case.
+func matchesPattern(pattern string, value *string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	if value == nil {
+		return false
+	}
+
+	matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(*value))
+	return err == nil && matched
+}
+
+// runPriceAdjustments are the price adjustments of a run. They're kept on the RunContext so the file
+// is only loaded once across all the projects of the run.
+type runPriceAdjustments struct {
+	once        sync.Once
+	adjustments *PriceAdjustments
+	err         error
+}
+
+// priceAdjustments returns the price adjustments of the run, or nil if there aren't any.
+func priceAdjustments(ctx *config.RunContext) (*PriceAdjustments, error) {
+	if ctx.Config.PriceAdjustmentsFile == "" {
+		return nil, nil
+	}
+
+	v := ctx.LoadOrStoreRunValue(priceAdjustmentsKey, &runPriceAdjustments{})
+	a := v.(*runPriceAdjustments)
+	a.once.Do(func() {
+		a.adjustments, a.err = LoadPriceAdjustments(ctx.Config.PriceAdjustmentsFile)
+	})
+
+	return a.adjustments, a.err
+}
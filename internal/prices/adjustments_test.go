@@ -0,0 +1,131 @@
+package prices
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func writePriceAdjustments(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "price-adjustments.yml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func pricedComponent(name string, price string, f *schema.ProductFilter) *schema.CostComponent {
+	c := &schema.CostComponent{Name: name, ProductFilter: f, UnitMultiplier: decimal.NewFromInt(1)}
+	c.SetPrice(decimal.RequireFromString(price))
+	return c
+}
+
+func TestPriceAdjustmentsApply(t *testing.T) {
+	adjustments, err := LoadPriceAdjustments(writePriceAdjustments(t, `version: 0.1
+adjustments:
+  - name: Negotiated P10 price
+    match:
+      provider: azurerm
+      sku: p10*
+      tags:
+        environment: prod
+    price: 15.50
+  - name: EA discount
+    match:
+      provider: azure
+      service: Virtual Machines
+    discount_percentage: 18
+  - match:
+      provider: aws
+      region: eu-*
+    discount_percentage: -10
+`))
+	require.NoError(t, err)
+
+	r := &schema.Resource{Name: "vm"}
+	prod := map[string]string{"environment": "prod"}
+
+	vm := pricedComponent("Instance usage", "0.1", &schema.ProductFilter{
+		VendorName: strPtr("azure"),
+		Service:    strPtr("Virtual Machines"),
+	})
+	adjustments.Apply(r, vm, nil)
+	assert.Equal(t, "0.082", vm.Price().String())
+	assert.Equal(t, "0.1", vm.ListPrice().String())
+	assert.Equal(t, "EA discount", vm.PriceAdjustment())
+
+	disk := func() *schema.CostComponent {
+		return pricedComponent("Storage", "19.71", &schema.ProductFilter{
+			VendorName:       strPtr("azure"),
+			Service:          strPtr("Storage"),
+			AttributeFilters: []*schema.AttributeFilter{{Key: "skuName", Value: strPtr("P10 LRS")}},
+		})
+	}
+
+	prodDisk := disk()
+	adjustments.Apply(r, prodDisk, prod)
+	assert.Equal(t, "15.5", prodDisk.Price().String())
+	assert.Equal(t, "19.71", prodDisk.ListPrice().String())
+
+	devDisk := disk()
+	adjustments.Apply(r, devDisk, map[string]string{"environment": "dev"})
+	assert.Equal(t, "19.71", devDisk.Price().String())
+	assert.Nil(t, devDisk.ListPrice())
+
+	ec2 := pricedComponent("Instance usage", "2", &schema.ProductFilter{
+		VendorName: strPtr("aws"),
+		Service:    strPtr("AmazonEC2"),
+		Region:     strPtr("eu-west-1"),
+	})
+	adjustments.Apply(r, ec2, nil)
+	assert.Equal(t, "2.2", ec2.Price().String())
+	assert.Equal(t, "adjustment 3", ec2.PriceAdjustment())
+
+	// Absolute prices are for the unit the cost component is shown in
+	requests := pricedComponent("Requests", "0.0000004", &schema.ProductFilter{
+		VendorName:       strPtr("azure"),
+		Service:          strPtr("Storage"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "skuName", Value: strPtr("P10 LRS")}},
+	})
+	requests.UnitMultiplier = decimal.NewFromInt(1000000)
+	adjustments.Apply(r, requests, prod)
+	assert.Equal(t, "0.0000155", requests.Price().String())
+	assert.Equal(t, "15.5", requests.UnitMultiplierPrice().String())
+
+	// Custom prices aren't adjusted
+	custom := pricedComponent("Instance usage", "0.1", &schema.ProductFilter{
+		VendorName: strPtr("azure"),
+		Service:    strPtr("Virtual Machines"),
+	})
+	customPrice := decimal.NewFromFloat(0.1)
+	custom.SetCustomPrice(&customPrice)
+	adjustments.Apply(r, custom, nil)
+	assert.Nil(t, custom.ListPrice())
+}
+
+func TestLoadPriceAdjustmentsErrors(t *testing.T) {
+	_, err := LoadPriceAdjustments(writePriceAdjustments(t, `adjustments:
+  - name: both
+    discount_percentage: 10
+    price: 1
+`))
+	assert.ErrorContains(t, err, "must set one of discount_percentage or price")
+
+	_, err = LoadPriceAdjustments(writePriceAdjustments(t, `adjustments:
+  - name: invalid
+    price: cheap
+`))
+	assert.ErrorContains(t, err, `has an invalid price "cheap"`)
+
+	_, err = LoadPriceAdjustments(writePriceAdjustments(t, `adjustments:
+  - name: typo
+    match:
+      servce: Virtual Machines
+    price: 1
+`))
+	assert.ErrorContains(t, err, "field servce not found")
+}
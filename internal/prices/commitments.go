@@ -0,0 +1,196 @@
+package prices
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// CommitmentScenarios are the commitment scenarios that a run can re-price eligible cost
+// components with. Savings plans aren't included since the pricing API doesn't have their rates.
+var CommitmentScenarios = []string{"reserved_1yr", "reserved_3yr"}
+
+var commitmentScenarioYears = map[string]int64{
+	"reserved_1yr": 1,
+	"reserved_3yr": 3,
+}
+
+// commitmentEligibleServices are the services of each vendor whose hourly cost components can be
+// reserved. On-demand prices are priced with the purchase option of the vendor.
+var commitmentEligibleServices = map[string]struct {
+	onDemandPurchaseOption string
+	services               []string
+}{
+	"aws": {
+		onDemandPurchaseOption: "on_demand",
+		services:               []string{"AmazonEC2", "AmazonRDS"},
+	},
+	"azure": {
+		onDemandPurchaseOption: "Consumption",
+		services:               []string{"Virtual Machines", "SQL Database", "Azure App Service"},
+	},
+}
+
+// ValidateCommitmentScenario returns an error if the scenario isn't a known commitment scenario.
+func ValidateCommitmentScenario(scenario string) error {
+	if scenario == "" {
+		return nil
+	}
+
+	if _, ok := commitmentScenarioYears[scenario]; !ok {
+		return fmt.Errorf("Invalid commitment scenario '%s', valid scenarios are: %s", scenario, strings.Join(CommitmentScenarios, ", "))
+	}
+
+	return nil
+}
+
+// commitmentPriceFilter returns the price filter of the committed price of c for the scenario,
+// or nil if c can't be committed to.
+func commitmentPriceFilter(scenario string, c *schema.CostComponent) *schema.PriceFilter {
+	years, ok := commitmentScenarioYears[scenario]
+	if !ok || c.CustomPrice() != nil || c.ProductFilter == nil || c.PriceFilter == nil {
+		return nil
+	}
+
+	if !strings.Contains(strings.ToLower(c.Unit), "hour") {
+		return nil
+	}
+
+	vendor := strVal(c.ProductFilter.VendorName)
+	eligible, ok := commitmentEligibleServices[vendor]
+	if !ok || strVal(c.PriceFilter.PurchaseOption) != eligible.onDemandPurchaseOption || !containsString(eligible.services, strVal(c.ProductFilter.Service)) {
+		return nil
+	}
+
+	f := *c.PriceFilter
+	switch vendor {
+	case "aws":
+		// No upfront reservations are priced by the hour like on-demand usage
+		f.PurchaseOption = strPtr("reserved")
+		f.TermLength = strPtr(fmt.Sprintf("%dyr", years))
+		f.TermPurchaseOption = strPtr("No Upfront")
+		f.StartUsageAmount = strPtr("0")
+		if strVal(c.ProductFilter.Service) == "AmazonEC2" {
+			f.TermOfferingClass = strPtr("standard")
+		}
+	case "azure":
+		f.PurchaseOption = strPtr("Reservation")
+		f.TermLength = strPtr("1 Year")
+		if years > 1 {
+			f.TermLength = strPtr(fmt.Sprintf("%d Years", years))
+		}
+	}
+
+	return &f
+}
+
+// setCommittedPrices re-prices the cost components of r that are eligible for the commitment
+// scenario of the run. Components without a committed price keep their on-demand price. The
+// discounts of the price adjustments of the run are applied to the committed prices.
+func setCommittedPrices(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.Resource, adjustments *PriceAdjustments) error {
+	scenario := ctx.Config.CommitmentScenario
+	if scenario == "" {
+		return nil
+	}
+
+	components := append([]*schema.CostComponent{}, r.CostComponents...)
+	for _, s := range r.FlattenedSubResources() {
+		components = append(components, s.CostComponents...)
+	}
+
+	committed := &schema.Resource{Name: r.Name}
+	onDemand := make(map[*schema.CostComponent]*schema.CostComponent)
+	for _, comp := range components {
+		f := commitmentPriceFilter(scenario, comp)
+		if f == nil {
+			continue
+		}
+
+		cc := &schema.CostComponent{
+			Name:           comp.Name,
+			Unit:           comp.Unit,
+			UnitMultiplier: comp.UnitMultiplier,
+			ProductFilter:  comp.ProductFilter,
+			PriceFilter:    f,
+		}
+		committed.CostComponents = append(committed.CostComponents, cc)
+		onDemand[cc] = comp
+	}
+
+	if len(committed.CostComponents) == 0 {
+		return nil
+	}
+
+	results, err := c.RunQueries(committed)
+	if err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		comp := onDemand[res.CostComponent]
+
+		price, ok := committedPrice(c.Currency, res.Result, strVal(res.CostComponent.ProductFilter.VendorName), commitmentScenarioYears[scenario])
+		if !ok {
+			log.Debugf("No %s price found for %s %s, using the on-demand price", scenario, r.Name, comp.Name)
+			continue
+		}
+
+		comp.SetCommittedPrice(adjustments.ApplyCommitted(r, comp, r.Tags, price))
+	}
+
+	return nil
+}
+
+// committedPrice returns the hourly price of the first product of the result with a price. Azure
+// reservation prices are for the whole term, so they're converted to hourly prices.
+func committedPrice(currency string, res gjson.Result, vendor string, years int64) (decimal.Decimal, bool) {
+	for _, product := range res.Get("data.products").Array() {
+		prices := product.Get("prices").Array()
+		if len(prices) == 0 {
+			continue
+		}
+
+		price, err := decimal.NewFromString(prices[0].Get(currency).String())
+		if err != nil {
+			continue
+		}
+
+		if vendor == "azure" {
+			hours := schema.HourToMonthUnitMultiplier.Mul(decimal.NewFromInt(12 * years))
+			price = price.Div(hours)
+		}
+
+		return price, true
+	}
+
+	return decimal.Zero, false
+}
+
+func strVal(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestCommitmentPriceFilter(t *testing.T) {
+	ec2 := &schema.CostComponent{
+		Unit:          "hours",
+		ProductFilter: &schema.ProductFilter{VendorName: strPtr("aws"), Service: strPtr("AmazonEC2")},
+		PriceFilter:   &schema.PriceFilter{PurchaseOption: strPtr("on_demand")},
+	}
+	f := commitmentPriceFilter("reserved_3yr", ec2)
+	require.NotNil(t, f)
+	assert.Equal(t, "reserved", *f.PurchaseOption)
+	assert.Equal(t, "3yr", *f.TermLength)
+	assert.Equal(t, "No Upfront", *f.TermPurchaseOption)
+	assert.Equal(t, "standard", *f.TermOfferingClass)
+	// The on-demand price filter isn't changed
+	assert.Equal(t, "on_demand", *ec2.PriceFilter.PurchaseOption)
+
+	vm := &schema.CostComponent{
+		Unit:          "hours",
+		ProductFilter: &schema.ProductFilter{VendorName: strPtr("azure"), Service: strPtr("Virtual Machines")},
+		PriceFilter:   &schema.PriceFilter{PurchaseOption: strPtr("Consumption"), Unit: strPtr("1 Hour")},
+	}
+	f = commitmentPriceFilter("reserved_1yr", vm)
+	require.NotNil(t, f)
+	assert.Equal(t, "Reservation", *f.PurchaseOption)
+	assert.Equal(t, "1 Year", *f.TermLength)
+	assert.Equal(t, "1 Hour", *f.Unit)
+
+	// Spot VMs, storage and components that are already reserved aren't eligible
+	spot := *vm
+	spot.PriceFilter = &schema.PriceFilter{PurchaseOption: strPtr("Spot")}
+	assert.Nil(t, commitmentPriceFilter("reserved_1yr", &spot))
+
+	storage := *ec2
+	storage.Unit = "GB"
+	assert.Nil(t, commitmentPriceFilter("reserved_1yr", &storage))
+
+	reserved := *ec2
+	reserved.PriceFilter = &schema.PriceFilter{PurchaseOption: strPtr("reserved")}
+	assert.Nil(t, commitmentPriceFilter("reserved_1yr", &reserved))
+
+	assert.Nil(t, commitmentPriceFilter("savings_plan_1yr", ec2))
+}
+
+func TestSetCommittedPrices(t *testing.T) {
+	snapshot := apiclient.NewPriceSnapshot()
+	snapshot.Add(apiclient.SnapshotProduct{
+		ProductHash:   "vm",
+		VendorName:    "azure",
+		Service:       "Virtual Machines",
+		ProductFamily: "Compute",
+		Region:        "eastus",
+		Attributes:    []apiclient.SnapshotAttribute{{Key: "skuName", Value: "D2s v3"}},
+		Prices: []map[string]string{
+			{"priceHash": "vm-od", "purchaseOption": "Consumption", "unit": "1 Hour", "USD": "0.096"},
+			{"priceHash": "vm-1yr", "purchaseOption": "Reservation", "unit": "1 Hour", "termLength": "1 Year", "USD": "438"},
+		},
+	})
+
+	productFilter := &schema.ProductFilter{
+		VendorName:       strPtr("azure"),
+		Service:          strPtr("Virtual Machines"),
+		Region:           strPtr("eastus"),
+		AttributeFilters: []*schema.AttributeFilter{{Key: "skuName", Value: strPtr("D2s v3")}},
+	}
+
+	vm := &schema.CostComponent{
+		Name:           "Instance usage",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		HourlyQuantity: decimalPtr(decimal.NewFromInt(1)),
+		ProductFilter:  productFilter,
+		PriceFilter:    &schema.PriceFilter{PurchaseOption: strPtr("Consumption"), Unit: strPtr("1 Hour")},
+	}
+	vm.SetPrice(decimal.RequireFromString("0.096"))
+
+	r := &schema.Resource{Name: "azurerm_linux_virtual_machine.vm", CostComponents: []*schema.CostComponent{vm}}
+
+	ctx := &config.RunContext{Config: &config.Config{CommitmentScenario: "reserved_1yr"}}
+	c := &apiclient.PricingAPIClient{Currency: "USD", Snapshot: snapshot}
+	require.NoError(t, setCommittedPrices(ctx, c, r, nil))
+
+	// Azure reservation prices are for the whole term
+	require.NotNil(t, vm.CommittedPrice())
+	assert.Equal(t, "0.05", vm.CommittedPrice().String())
+
+	r.CalculateCosts()
+	assert.Equal(t, "70.08", r.MonthlyCost.String())
+	assert.Equal(t, "36.5", r.CommittedMonthlyCost.String())
+
+	// Discounts of price adjustments are applied to committed prices
+	discount := 20.0
+	adjustments := &PriceAdjustments{Adjustments: []*PriceAdjustment{{
+		Name:               "EA discount",
+		Match:              PriceAdjustmentMatch{Service: "Virtual Machines"},
+		DiscountPercentage: &discount,
+	}}}
+	require.NoError(t, setCommittedPrices(ctx, c, r, adjustments))
+	assert.Equal(t, "0.04", vm.CommittedPrice().String())
+
+	// Components keep their on-demand price if there's no committed price
+	ctx.Config.CommitmentScenario = "reserved_3yr"
+	other := &schema.CostComponent{
+		Name:           "Instance usage",
+		Unit:           "hours",
+		UnitMultiplier: decimal.NewFromInt(1),
+		ProductFilter:  productFilter,
+		PriceFilter:    &schema.PriceFilter{PurchaseOption: strPtr("Consumption"), Unit: strPtr("1 Hour")},
+	}
+	require.NoError(t, setCommittedPrices(ctx, c, &schema.Resource{Name: "vm", CostComponents: []*schema.CostComponent{other}}, nil))
+	assert.Nil(t, other.CommittedPrice())
+}
+
+func TestValidateCommitmentScenario(t *testing.T) {
+	assert.NoError(t, ValidateCommitmentScenario(""))
+	assert.NoError(t, ValidateCommitmentScenario("reserved_3yr"))
+	assert.EqualError(t, ValidateCommitmentScenario("reserved_5yr"), "Invalid commitment scenario 'reserved_5yr', valid scenarios are: reserved_1yr, reserved_3yr")
+}
+
+func decimalPtr(d decimal.Decimal) *decimal.Decimal {
+	return &d
+}
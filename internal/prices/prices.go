@@ -1,6 +1,8 @@
 package prices
 
 import (
+	"os"
+	"path/filepath"
 	"runtime"
 
 	"github.com/infracost/infracost/internal/apiclient"
@@ -24,10 +26,70 @@ func PopulatePrices(ctx *config.RunContext, project *schema.Project) error {
 	return nil
 }
 
+// runValueKey is the type of the keys of the state of the prices package that's kept on the
+// RunContext, so it's shared by all the projects of a run.
+type runValueKey int
+
+const (
+	priceCacheKey runValueKey = iota
+	priceSnapshotKey
+	priceAdjustmentsKey
+)
+
+// priceCache returns the price cache of the run, so identical price queries are only sent once
+// across all the projects of a run. Prices are cached on disk in the .infracost directory of the
+// path the run was started in, unless the cache is disabled.
+func priceCache(ctx *config.RunContext) *apiclient.PriceQueryCache {
+	if cache, ok := ctx.LoadRunValue(priceCacheKey); ok {
+		return cache.(*apiclient.PriceQueryCache)
+	}
+
+	// Prices from or for a pricing snapshot aren't cached on disk
+	var dir string
+	if !ctx.Config.PricingCacheDisabled && !ctx.Config.NoCache && !usesSnapshot(ctx) {
+		dir = priceCacheDir(ctx)
+	}
+
+	cache := ctx.LoadOrStoreRunValue(priceCacheKey, apiclient.NewPriceQueryCache(dir, ctx.Config.PricingCacheTTL))
+	return cache.(*apiclient.PriceQueryCache)
+}
+
+func priceCacheDir(ctx *config.RunContext) string {
+	base := ctx.Config.RepoPath()
+	if base == "" {
+		base = "."
+	}
+
+	if info, err := os.Stat(base); err == nil && !info.IsDir() {
+		base = filepath.Dir(base)
+	}
+
+	// Prices from the Azure Retail Prices API are cached separately from the pricing API
+	if ctx.Config.UsesAzureRetailPrices() {
+		return filepath.Join(base, config.InfracostDir, "pricing", config.AzureRetailPricingSource)
+	}
+
+	return filepath.Join(base, config.InfracostDir, "pricing")
+}
+
 // GetPricesConcurrent gets the prices of all resources concurrently.
 // Concurrency level is calculated using the following formula:
 // max(min(4, numCPU * 4), 16)
+// Identical queries of different resources are deduplicated by the price cache of the run.
 func GetPricesConcurrent(ctx *config.RunContext, c *apiclient.PricingAPIClient, resources []*schema.Resource) error {
+	if c.Snapshot == nil {
+		snapshot, record, err := priceSnapshot(ctx)
+		if err != nil {
+			return err
+		}
+
+		c.Snapshot, c.RecordSnapshot = snapshot, record
+	}
+
+	if c.Cache == nil {
+		c.Cache = priceCache(ctx)
+	}
+
 	// Set the number of workers
 	numWorkers := 4
 	numCPU := runtime.NumCPU()
@@ -76,11 +138,17 @@ func GetPrices(ctx *config.RunContext, c *apiclient.PricingAPIClient, r *schema.
 		return err
 	}
 
-	for _, r := range results {
-		setCostComponentPrice(ctx, c.Currency, r.Resource, r.CostComponent, r.Result)
+	adjustments, err := priceAdjustments(ctx)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	for _, res := range results {
+		setCostComponentPrice(ctx, c.Currency, res.Resource, res.CostComponent, res.Result)
+		adjustments.Apply(res.Resource, res.CostComponent, r.Tags)
+	}
+
+	return setCommittedPrices(ctx, c, r, adjustments)
 }
 
 func setCostComponentPrice(ctx *config.RunContext, currency string, r *schema.Resource, c *schema.CostComponent, res gjson.Result) {
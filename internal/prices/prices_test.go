@@ -0,0 +1,20 @@
+package prices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestPriceCacheIsPerRun(t *testing.T) {
+	ctx := config.EmptyRunContext()
+	ctx.Config.NoCache = true
+
+	assert.Same(t, priceCache(ctx), priceCache(ctx))
+
+	other := config.EmptyRunContext()
+	other.Config.NoCache = true
+	assert.NotSame(t, priceCache(ctx), priceCache(other))
+}
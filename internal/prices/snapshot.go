@@ -0,0 +1,69 @@
+package prices
+
+import (
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/apiclient"
+	"github.com/infracost/infracost/internal/config"
+)
+
+// runSnapshot is the pricing snapshot of a run that resolves prices from, or records prices to, a
+// local snapshot. It's kept on the RunContext so the snapshot is only loaded once across all the
+// projects of the run.
+type runSnapshot struct {
+	once     sync.Once
+	snapshot *apiclient.PriceSnapshot
+	record   bool
+	err      error
+}
+
+// RecordSnapshot adds the products and prices that are fetched from the pricing API during the run
+// to snapshot, instead of resolving prices from the configured snapshot.
+func RecordSnapshot(ctx *config.RunContext, snapshot *apiclient.PriceSnapshot) {
+	s := &runSnapshot{snapshot: snapshot, record: true}
+	s.once.Do(func() {})
+	ctx.StoreRunValue(priceSnapshotKey, s)
+}
+
+func usesSnapshot(ctx *config.RunContext) bool {
+	_, ok := ctx.LoadRunValue(priceSnapshotKey)
+	return ok || ctx.Config.IsOffline()
+}
+
+// priceSnapshot returns the pricing snapshot of the run, and whether prices are recorded to it, or
+// nil if prices are fetched from the pricing API.
+func priceSnapshot(ctx *config.RunContext) (*apiclient.PriceSnapshot, bool, error) {
+	v, ok := ctx.LoadRunValue(priceSnapshotKey)
+	if !ok {
+		if !ctx.Config.IsOffline() {
+			return nil, false, nil
+		}
+
+		v = ctx.LoadOrStoreRunValue(priceSnapshotKey, &runSnapshot{})
+	}
+
+	s := v.(*runSnapshot)
+	s.once.Do(func() {
+		path := ctx.Config.PricingSnapshotFile
+		log.Debugf("Loading pricing snapshot %s", path)
+
+		s.snapshot, s.err = apiclient.LoadPriceSnapshot(path)
+		if s.err != nil {
+			return
+		}
+
+		currency := ctx.Config.Currency
+		if currency == "" {
+			currency = "USD"
+		}
+
+		if !s.snapshot.HasCurrency(currency) {
+			s.err = fmt.Errorf("Pricing snapshot %s has no prices in %s. Create it with INFRACOST_CURRENCY=%s infracost pricing snapshot", path, currency, currency)
+		}
+	})
+
+	return s.snapshot, s.record, s.err
+}
@@ -0,0 +1,114 @@
+package azurerm
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+// ArmTemplateDirProvider costs every ARM template and Bicep file in a directory tree that is
+// deployed on its own, see FindTemplateProjects. Each template and parameters file pair is
+// loaded as its own project by an ArmTemplateProvider.
+type ArmTemplateDirProvider struct {
+	ctx                  *config.ProjectContext
+	includePastResources bool
+}
+
+func NewArmTemplateDirProvider(ctx *config.ProjectContext, includePastResources bool) *ArmTemplateDirProvider {
+	return &ArmTemplateDirProvider{
+		ctx:                  ctx,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *ArmTemplateDirProvider) Type() string {
+	return "azurerm_template_dir"
+}
+
+func (p *ArmTemplateDirProvider) DisplayType() string {
+	return "Azure Resource Manager directory"
+}
+
+func (p *ArmTemplateDirProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	// no op
+}
+
+func (p *ArmTemplateDirProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	templates := FindTemplateProjects(p.ctx.ProjectConfig.Path)
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("No ARM templates or Bicep files found at path %s, try a different directory", p.ctx.ProjectConfig.Path)
+	}
+
+	// A baseline is the deployed resources of a single template, so it can't be used for
+	// each of the templates in a directory
+	ignoreBaseline := p.ctx.ProjectConfig.ArmBaselinePath != "" && len(templates) > 1
+	if ignoreBaseline {
+		log.Warnf("Ignoring ARM baseline file %s as %s has more than one template, add a project for each template instead", p.ctx.ProjectConfig.ArmBaselinePath, p.ctx.ProjectConfig.Path)
+	}
+
+	var projects []*schema.Project
+	for _, t := range templates {
+		projectCfg := p.templateProjectConfig(t)
+		if ignoreBaseline {
+			projectCfg.ArmBaselinePath = ""
+		}
+
+		ctx := config.NewProjectContext(p.ctx.RunContext, projectCfg, log.Fields{})
+
+		provider, err := NewArmTemplateProvider(ctx, p.includePastResources)
+		if err != nil {
+			return nil, err
+		}
+
+		loaded, err := provider.LoadResources(usage)
+		if err != nil {
+			log.Warnf("Error loading %s: %s", t.Path, err)
+			metadata := config.DetectProjectMetadata(ctx.ProjectConfig.Path)
+			metadata.Type = provider.Type()
+			name := ctx.ProjectConfig.Name
+			if name == "" {
+				name = metadata.GenerateProjectName(ctx.RunContext.VCSMetadata.Remote, ctx.RunContext.IsCloudEnabled())
+			}
+			loaded = schema.AddProjectError(loaded, name, metadata, err)
+		}
+
+		projects = append(projects, loaded...)
+	}
+
+	return projects, nil
+}
+
+// templateProjectConfig returns the config of a template project, which inherits the config of the directory.
+func (p *ArmTemplateDirProvider) templateProjectConfig(t TemplateProject) *config.Project {
+	projectCfg := *p.ctx.ProjectConfig
+	projectCfg.Path = t.Path
+	projectCfg.ArmParametersPath = ""
+	projectCfg.ArmParametersFiles = nil
+	if t.ParametersFile != "" {
+		projectCfg.ArmParametersFiles = []string{t.ParametersFile}
+	}
+
+	var name string
+	if projectCfg.Name != "" {
+		rel, err := filepath.Rel(p.ctx.ProjectConfig.Path, t.Path)
+		if err != nil {
+			rel = t.Path
+		}
+		name = projectCfg.Name + "/" + filepath.ToSlash(rel)
+	}
+
+	// The same template can be deployed with different parameter files, so the
+	// projects are named after both
+	if t.ParametersFile != "" {
+		if name == "" {
+			name = t.Path
+		}
+		name += fmt.Sprintf(" (%s)", filepath.Base(t.ParametersFile))
+	}
+	projectCfg.Name = name
+
+	return &projectCfg
+}
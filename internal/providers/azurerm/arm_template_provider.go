@@ -2,12 +2,16 @@ package azurerm
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
 )
 
 type DeploymentScope string
@@ -15,10 +19,27 @@ type DeploymentScope string
 const (
 	ResourceGroup   DeploymentScope = "resourceGroup"
 	ManagementGroup DeploymentScope = "managementGroup"
-	Subscription                    = "subscription"
-	Tenant                          = "tenant"
+	Subscription    DeploymentScope = "subscription"
+	Tenant          DeploymentScope = "tenant"
 )
 
+// deploymentScopeFromSchema returns the deployment scope a template targets based on its $schema,
+// see https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/deploy-to-subscription#schema
+func deploymentScopeFromSchema(schema string) DeploymentScope {
+	s := strings.ToLower(schema)
+
+	switch {
+	case strings.Contains(s, "/subscriptiondeploymenttemplate.json"):
+		return Subscription
+	case strings.Contains(s, "/managementgroupdeploymenttemplate.json"):
+		return ManagementGroup
+	case strings.Contains(s, "/tenantdeploymenttemplate.json"):
+		return Tenant
+	}
+
+	return ResourceGroup
+}
+
 type DeploymentMode string
 
 const (
@@ -28,36 +49,48 @@ const (
 
 type ArmDeploymentOpts struct {
 	Binary            string
+	ForceCLI          bool
 	Scope             DeploymentScope
 	Mode              DeploymentMode
-	ParameterFile     string
+	ParameterFiles    []string
 	Location          string
 	ResourceGroup     string
 	ManagementGroupId string
+	SubscriptionId    string
 }
 
 func (p *ArmTemplateProvider) Type() string {
-	if filepath.Ext(p.ctx.ProjectConfig.Path) == ".bicep" {
+	if isBicepFile(p.ctx.ProjectConfig.Path) {
 		return "azurerm_bicep_template"
 	}
 	return "azurerm_template_json"
 }
 
 func (p *ArmTemplateProvider) DisplayType() string {
-	if filepath.Ext(p.ctx.ProjectConfig.Path) == ".bicep" {
+	if isBicepFile(p.ctx.ProjectConfig.Path) {
 		return "Azure Bicep Template"
 	}
-	return "Azure Resource Manager WhatIf JSON"
+	return "Azure Resource Manager Template"
 }
 
 func (p *ArmTemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
-	// no op
+	metadata.ArmDeploymentScope = string(p.opts.Scope)
+	// In CLI mode the WhatIf provider has already taken the subscription and resource group from
+	// the IDs of the resources, so they're only overridden when they're configured
+	if p.opts.SubscriptionId != "" {
+		metadata.ArmSubscriptionId = p.opts.SubscriptionId
+	}
+	if p.opts.Scope == ResourceGroup && p.opts.ResourceGroup != "" {
+		metadata.ArmResourceGroup = p.opts.ResourceGroup
+	}
+	metadata.ArmLocation = p.opts.Location
+	metadata.ArmParametersFiles = p.opts.ParameterFiles
 }
 
 func NewArmTemplateProviderOptsFromProject(ctx *config.ProjectContext) *ArmDeploymentOpts {
-	deploymentMode := ctx.ProjectConfig.ArmResourceGroup
+	deploymentMode := ctx.ProjectConfig.ArmDeploymentMode
 	if deploymentMode == "" {
-		deploymentMode = "incremental"
+		deploymentMode = string(Incremental)
 	}
 
 	azBinary := ctx.ProjectConfig.AzBinary
@@ -65,14 +98,27 @@ func NewArmTemplateProviderOptsFromProject(ctx *config.ProjectContext) *ArmDeplo
 		azBinary = defaultAzBinary
 	}
 
+	scope := DeploymentScope(ctx.ProjectConfig.ArmDeploymentScope)
+	if scope == "" {
+		scope = detectDeploymentScope(ctx.ProjectConfig.Path)
+	}
+
+	var parameterFiles []string
+	if ctx.ProjectConfig.ArmParametersPath != "" {
+		parameterFiles = append(parameterFiles, ctx.ProjectConfig.ArmParametersPath)
+	}
+	parameterFiles = append(parameterFiles, ctx.ProjectConfig.ArmParametersFiles...)
+
 	return &ArmDeploymentOpts{
 		Binary:            azBinary,
-		Scope:             DeploymentScope(ctx.ProjectConfig.ArmDeploymentScope),
+		ForceCLI:          ctx.ProjectConfig.ArmForceCLI,
+		Scope:             scope,
 		Mode:              DeploymentMode(deploymentMode),
-		ParameterFile:     ctx.ProjectConfig.ArmParametersPath,
+		ParameterFiles:    parameterFiles,
 		Location:          ctx.ProjectConfig.ArmLocation,
 		ResourceGroup:     ctx.ProjectConfig.ArmResourceGroup,
 		ManagementGroupId: ctx.ProjectConfig.ArmManagementGroupId,
+		SubscriptionId:    ctx.ProjectConfig.ArmSubscriptionId,
 	}
 }
 
@@ -95,6 +141,10 @@ func NewArmTemplateProvider(ctx *config.ProjectContext, includePastResources boo
 }
 
 func (p *ArmTemplateProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	if !p.opts.ForceCLI {
+		return p.loadResourcesFromTemplate(usage)
+	}
+
 	spinner := ui.NewSpinner("Converting ARM template to WhatIf file...", ui.SpinnerOptions{
 		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
 		NoColor:       p.ctx.RunContext.Config.NoColor,
@@ -110,7 +160,118 @@ func (p *ArmTemplateProvider) LoadResources(usage map[string]*schema.UsageData)
 		return nil, err
 	}
 
-	return p.inner.LoadResources(usage)
+	// WhatIf results only have resource IDs, so the template is evaluated to find the
+	// copy loop addresses of the resources
+	addresses, err := p.templateAddresses()
+	if err != nil {
+		log.Debugf("Could not evaluate ARM template for resource addresses: %s", err)
+	}
+	p.inner.addresses = addresses
+
+	projects, err := p.inner.LoadResources(usage)
+	for _, project := range projects {
+		p.AddMetadata(project.Metadata)
+	}
+
+	return projects, err
+}
+
+// loadResourcesFromTemplate evaluates the template locally, so no az CLI or Azure login is needed.
+func (p *ArmTemplateProvider) loadResourcesFromTemplate(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	spinner := ui.NewSpinner("Evaluating ARM template", ui.SpinnerOptions{
+		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
+		NoColor:       p.ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	template, parameterValues, err := p.loadTemplate()
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	evaluator := NewTemplateEvaluator(template, NewDeploymentContext(p.opts), parameterValues)
+	evaluated, err := evaluator.Evaluate()
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error evaluating ARM template")
+	}
+
+	parser := NewParser(p.ctx)
+	partials, err := parser.parseTemplateResources(evaluated, usage)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error parsing ARM template resources")
+	}
+	project.PartialResources = partials
+
+	if p.includePastResources && p.ctx.ProjectConfig.ArmBaselinePath != "" {
+		project.PartialPastResources, err = loadBaselineResources(p.ctx, p.opts, p.ctx.ProjectConfig.ArmBaselinePath, partials, usage)
+		if err != nil {
+			return []*schema.Project{project}, err
+		}
+	}
+
+	spinner.Success()
+
+	return []*schema.Project{project}, nil
+}
+
+// loadTemplate returns the template of the project and the values of its parameter files.
+func (p *ArmTemplateProvider) loadTemplate() (*ArmTemplate, map[string]interface{}, error) {
+	var template *ArmTemplate
+	var err error
+	if isBicepFile(p.ctx.ProjectConfig.Path) {
+		template, err = loadBicepTemplate(p.ctx.ProjectConfig.Path)
+	} else {
+		template, err = loadArmTemplate(p.ctx.ProjectConfig.Path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parameterValues, err := loadParameterFiles(p.opts.ParameterFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnUnknownParameters(template, parameterValues)
+
+	return template, parameterValues, nil
+}
+
+// templateAddresses evaluates the template of the project and returns the addresses of
+// its resources by lowercase resource ID, for the resources whose address isn't their ID. They're
+// also keyed by the address of the ID, since the IDs have the placeholder subscription and resource
+// group when they aren't configured, so they don't match the IDs of the WhatIf result.
+func (p *ArmTemplateProvider) templateAddresses() (map[string]string, error) {
+	template, parameterValues, err := p.loadTemplate()
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated, err := NewTemplateEvaluator(template, NewDeploymentContext(p.opts), parameterValues).Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make(map[string]string)
+	for _, r := range evaluated {
+		if r.Address != r.Id {
+			addresses[strings.ToLower(r.Id)] = r.Address
+			addresses[strings.ToLower(addressFromId(r.Id))] = r.Address
+		}
+	}
+
+	return addresses, nil
 }
 
 func (p *ArmTemplateProvider) getWhatIfFromArmTemplate() ([]byte, error) {
@@ -121,6 +282,12 @@ func (p *ArmTemplateProvider) getWhatIfFromArmTemplate() ([]byte, error) {
 	switch p.opts.Scope {
 	case ResourceGroup:
 		args, err = getGroupDeploymentArgs(templateFile, p.opts)
+	case Subscription:
+		args, err = getSubscriptionDeploymentArgs(templateFile, p.opts)
+	case ManagementGroup:
+		args, err = getManagementGroupDeploymentArgs(templateFile, p.opts)
+	case Tenant:
+		args, err = getTenantDeploymentArgs(templateFile, p.opts)
 	default:
 		err = errors.New(fmt.Sprintf("Unsupported scope %s", p.opts.Scope))
 	}
@@ -142,3 +309,23 @@ func (p *ArmTemplateProvider) getWhatIfFromArmTemplate() ([]byte, error) {
 
 	return output, nil
 }
+
+// detectDeploymentScope reads the $schema of the template at path, or the targetScope of a Bicep
+// file, to find its deployment scope, defaulting to a resource group deployment if the template
+// can't be read.
+func detectDeploymentScope(path string) DeploymentScope {
+	if isBicepFile(path) {
+		f, err := readBicepFile(path)
+		if err != nil {
+			return ResourceGroup
+		}
+		return DeploymentScope(f.targetScope)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ResourceGroup
+	}
+
+	return deploymentScopeFromSchema(gjson.GetBytes(b, "$schema").String())
+}
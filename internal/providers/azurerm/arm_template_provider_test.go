@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/usage"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
@@ -34,4 +35,255 @@ func TestArmTemplateProvider(t *testing.T) {
 	// Ensure all resources in the whatif are returned from the provider
 	assert.Equal(t, 2, len(project[0].PartialResources))
 	assert.Equal(t, 0, len(project[0].PartialPastResources))
+
+	metadata := project[0].Metadata
+	assert.Equal(t, "resourceGroup", metadata.ArmDeploymentScope)
+	assert.Equal(t, "rg-infracost-test", metadata.ArmResourceGroup)
+	assert.Equal(t, "westeurope", metadata.ArmLocation)
+	assert.Empty(t, metadata.ArmParametersFiles)
+}
+
+func TestArmTemplateProviderBicep(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmLocation:      "westeurope",
+		ArmResourceGroup: "rg-infracost-test",
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("..", "..", "..", "examples", "azurerm", "web_app", "bicep", "main.bicep")
+
+	provider, err := NewArmTemplateProvider(ctx, true)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	// Bicep files are compiled locally rather than with the az CLI
+	opts := NewArmTemplateProviderOptsFromProject(ctx)
+	assert.False(t, opts.ForceCLI)
+	assert.Equal(t, ResourceGroup, opts.Scope)
+
+	usage := usage.NewBlankUsageFile().ToUsageDataMap()
+	project, err := provider.LoadResources(usage)
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	assert.Equal(t, "azurerm_bicep_template", project[0].Metadata.Type)
+	assert.Equal(t, 2, len(project[0].PartialResources))
+}
+
+func TestArmTemplateProviderParameterFiles(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmLocation:        "westeurope",
+		ArmResourceGroup:   "rg-infracost-test",
+		ArmParametersPath:  filepath.Join("testdata", "parameters", "base.parameters.json"),
+		ArmParametersFiles: []string{filepath.Join("testdata", "parameters", "prod.bicepparam")},
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("..", "..", "..", "examples", "azurerm", "web_app", "bicep", "main.bicep")
+	t.Setenv("INFRACOST_TEST_OWNER", "platform")
+
+	opts := NewArmTemplateProviderOptsFromProject(ctx)
+	assert.Equal(t, []string{ctx.ProjectConfig.ArmParametersPath, ctx.ProjectConfig.ArmParametersFiles[0]}, opts.ParameterFiles)
+
+	provider, err := NewArmTemplateProvider(ctx, true)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	usage := usage.NewBlankUsageFile().ToUsageDataMap()
+	project, err := provider.LoadResources(usage)
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	assert.Equal(t, 2, len(project[0].PartialResources))
+
+	var planSku string
+	for _, r := range project[0].PartialResources {
+		if sku := r.ResourceData.Get("sku.name"); sku.Exists() {
+			planSku = sku.String()
+		}
+	}
+	assert.Equal(t, "P1v3", planSku)
+	assert.Equal(t, opts.ParameterFiles, project[0].Metadata.ArmParametersFiles)
+}
+
+func TestArmTemplateProviderCopyLoops(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmLocation:      "westeurope",
+		ArmResourceGroup: "rg-infracost-test",
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("testdata", "copy_loops.json")
+
+	provider, err := NewArmTemplateProvider(ctx, true)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	usage := usage.NewBlankUsageFile().ToUsageDataMap()
+	project, err := provider.LoadResources(usage)
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	// Each VM of the copy loop is a resource, and the public IP's condition is false
+	var addresses []string
+	for _, r := range project[0].PartialResources {
+		addresses = append(addresses, r.ResourceData.Address)
+	}
+	assert.Equal(t, []string{"vmCopy[0]", "vmCopy[1]", "vmCopy[2]"}, addresses)
+}
+
+func TestArmTemplateProviderAddMetadataKeepsWhatIfSubscription(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmDeploymentScope: "resourceGroup",
+		ArmLocation:        "westeurope",
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("./testdata", "azuredeploy.group.json")
+
+	provider, err := NewArmTemplateProvider(ctx, true)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	// The WhatIf provider sets the subscription and resource group from the resource IDs in CLI mode
+	metadata := &schema.ProjectMetadata{
+		ArmSubscriptionId: "00000000-0000-0000-0000-000000000001",
+		ArmResourceGroup:  "rg-from-whatif",
+	}
+	provider.AddMetadata(metadata)
+
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", metadata.ArmSubscriptionId)
+	assert.Equal(t, "rg-from-whatif", metadata.ArmResourceGroup)
+	assert.Equal(t, "westeurope", metadata.ArmLocation)
+}
+
+func TestArmTemplateProviderTemplateAddresses(t *testing.T) {
+	// Without a configured subscription the template is evaluated with the placeholder subscription,
+	// so the addresses of the WhatIf resources are found by the address of their ID
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmLocation:      "westeurope",
+		ArmResourceGroup: "rg-infracost-test",
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("testdata", "copy_loops.json")
+
+	provider, err := NewArmTemplateProvider(ctx, true)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	addresses, err := provider.(*ArmTemplateProvider).templateAddresses()
+	if err != nil {
+		t.Fatalf("Error evaluating template: " + err.Error())
+	}
+
+	parser := NewParser(ctx)
+	parser.addresses = addresses
+	assert.Equal(t, "vmCopy[1]", parser.address("/subscriptions/6f1b2c3d-0000-4000-8000-000000000000/resourceGroups/rg-infracost-test/providers/Microsoft.Compute/virtualMachines/vm-2"))
+}
+
+func TestArmTemplateDirProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Name:             "repo",
+		ArmLocation:      "westeurope",
+		ArmResourceGroup: "rg-infracost-test",
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("testdata", "repo")
+
+	provider := NewArmTemplateDirProvider(ctx, true)
+
+	usage := usage.NewBlankUsageFile().ToUsageDataMap()
+	projects, err := provider.LoadResources(usage)
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	var names []string
+	var counts []int
+	for _, project := range projects {
+		names = append(names, project.Name)
+		counts = append(counts, len(project.PartialResources))
+		assert.Empty(t, project.Metadata.Errors)
+	}
+
+	assert.Equal(t, []string{
+		"repo/apps/web/main.bicep (main.bicepparam)",
+		"repo/apps/web/main.bicep (main.prod.bicepparam)",
+		"repo/infra/azuredeploy.json (azuredeploy.parameters.json)",
+	}, names)
+	assert.Equal(t, []int{2, 2, 1}, counts)
+}
+
+func TestArmTemplateProviderBaseline(t *testing.T) {
+	tests := []struct {
+		baseline string
+		expected []string
+	}{
+		{
+			baseline: "resource_list.json",
+			expected: []string{
+				"Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp",
+				"Microsoft.Web/sites/AzureLinuxApp-webapp",
+				"Microsoft.Storage/storageAccounts/oldstorage",
+			},
+		},
+		{
+			baseline: "exported.json",
+			expected: []string{
+				"Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp",
+				"Microsoft.Web/sites/AzureLinuxApp-webapp",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+			ArmLocation:      "westeurope",
+			ArmResourceGroup: "rg-infracost-test",
+			ArmBaselinePath:  filepath.Join("testdata", "baseline", test.baseline),
+		}, log.Fields{})
+		ctx.ProjectConfig.Path = filepath.Join("testdata", "azuredeploy.group.json")
+
+		provider, err := NewArmTemplateProvider(ctx, true)
+		if err != nil {
+			t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+		}
+
+		usage := usage.NewBlankUsageFile().ToUsageDataMap()
+		project, err := provider.LoadResources(usage)
+		if err != nil {
+			t.Fatalf("Error loading resources: " + err.Error())
+		}
+
+		var addresses []string
+		var planSku string
+		for _, r := range project[0].PartialPastResources {
+			addresses = append(addresses, r.ResourceData.Address)
+			if r.ResourceData.Type == "azurerm_service_plan" || r.ResourceData.Type == "azurerm_app_service_plan" {
+				planSku = r.ResourceData.Get("sku.name").String()
+			}
+		}
+
+		// Past resources are matched to the current resources by type and name, ignoring case
+		assert.Equal(t, test.expected, addresses, test.baseline)
+		assert.Equal(t, "B1", planSku, test.baseline)
+		assert.Equal(t, 2, len(project[0].PartialResources), test.baseline)
+	}
+}
+
+func TestArmTemplateProviderBaselineWithoutPastResources(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		ArmBaselinePath: filepath.Join("testdata", "baseline", "resource_list.json"),
+	}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("testdata", "azuredeploy.group.json")
+
+	provider, err := NewArmTemplateProvider(ctx, false)
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Failed constructing ARM template provider").Error())
+	}
+
+	project, err := provider.LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	assert.Empty(t, project[0].PartialPastResources)
 }
@@ -0,0 +1,152 @@
+package azurerm
+
+import (
+	"os"
+	"strings"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// IsResourceListJSON returns true for the output of `az resource list`, a JSON array of resources,
+// or the same resources wrapped in a "value" array as returned by the Azure REST API.
+func IsResourceListJSON(b []byte) bool {
+	resources := resourceListValues(gjson.ParseBytes(b))
+	if len(resources) == 0 {
+		return false
+	}
+
+	for _, r := range resources {
+		if !strings.HasPrefix(strings.ToLower(r.Get("id").String()), "/subscriptions/") || !r.Get("type").Exists() {
+			return false
+		}
+	}
+
+	return true
+}
+
+func resourceListValues(parsed gjson.Result) []gjson.Result {
+	if parsed.IsObject() {
+		parsed = parsed.Get("value")
+	}
+
+	if !parsed.IsArray() {
+		return nil
+	}
+
+	return parsed.Array()
+}
+
+// loadBaselineResources returns the resources of an existing deployment, which is either a template
+// exported with `az group export` or the output of `az resource list`. These are loaded without
+// calling Azure, so a template can be diffed against the resources that are deployed in CI
+// without Azure credentials.
+//
+// Baseline resources that have the same ID or address as one of the current resources, ignoring
+// case, are given the address of the current resource, so both are matched in the diff.
+func loadBaselineResources(ctx *config.ProjectContext, opts *ArmDeploymentOpts, path string, current []*schema.PartialResource, usage usageMap) ([]*schema.PartialResource, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading ARM baseline file")
+	}
+
+	var data []gjson.Result
+	if IsResourceListJSON(b) {
+		data = resourceListValues(gjson.ParseBytes(b))
+		warnMissingProperties(data, path)
+	} else {
+		data, err = exportedTemplateValues(b, path, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error evaluating ARM baseline template %s", path)
+		}
+	}
+
+	parser := NewParser(ctx)
+	parser.addresses = baselineAddresses(current)
+	parser.addValues(data...)
+
+	var partials []*schema.PartialResource
+	for i := range data {
+		partial, err := parser.parsePartialResource(&data[i], usage)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing ARM baseline file %s", path)
+		}
+
+		if partial != nil {
+			partials = append(partials, partial)
+		}
+	}
+
+	return partials, nil
+}
+
+// warnMissingProperties warns about resources of `az resource list` output without properties.
+// `az resource list` doesn't include the properties of most resources, which many resources need
+// to be priced, e.g. the size of a VM, so their baseline cost can be wrong.
+func warnMissingProperties(data []gjson.Result, path string) {
+	var ids []string
+	for _, r := range data {
+		if p := r.Get("properties"); !p.Exists() || p.Type == gjson.Null {
+			ids = append(ids, r.Get("id").String())
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	log.Warnf("%d resources in ARM baseline file %s have no properties, so their cost may be wrong. Use the output of `az resource show --ids` to include them", len(ids), path)
+	log.Debugf("ARM baseline resources without properties: %s", strings.Join(ids, ", "))
+}
+
+// exportedTemplateValues evaluates an exported template, which sets the names of its resources as
+// the default values of its parameters, and returns its resources in the shape of `az resource list`.
+func exportedTemplateValues(b []byte, path string, opts *ArmDeploymentOpts) ([]gjson.Result, error) {
+	template, err := parseArmTemplate(b)
+	if err != nil {
+		return nil, err
+	}
+	template.path = path
+
+	evaluated, err := NewTemplateEvaluator(template, NewDeploymentContext(opts), nil).Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]gjson.Result, 0, len(evaluated))
+	for _, r := range evaluated {
+		raw, err := r.RawValues()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, raw)
+	}
+
+	return data, nil
+}
+
+// baselineAddresses returns the addresses of the current resources by their lowercase ID and
+// lowercase readable address, see Parser.address.
+func baselineAddresses(current []*schema.PartialResource) map[string]string {
+	addresses := make(map[string]string, len(current)*2)
+
+	for _, partial := range current {
+		rd := partial.ResourceData
+		if rd == nil {
+			continue
+		}
+
+		id := rd.Get("id").String()
+		if id == "" {
+			continue
+		}
+
+		addresses[strings.ToLower(id)] = rd.Address
+		addresses[strings.ToLower(addressFromId(id))] = rd.Address
+	}
+
+	return addresses
+}
@@ -0,0 +1,1483 @@
+package azurerm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// bicepSchemas are the $schema of the ARM template a Bicep file compiles to, by its targetScope.
+var bicepSchemas = map[string]string{
+	"resourcegroup":   "https://schema.management.azure.com/schemas/2019-04-01/deploymentTemplate.json#",
+	"subscription":    "https://schema.management.azure.com/schemas/2018-05-01/subscriptionDeploymentTemplate.json#",
+	"managementgroup": "https://schema.management.azure.com/schemas/2019-08-01/managementGroupDeploymentTemplate.json#",
+	"tenant":          "https://schema.management.azure.com/schemas/2019-08-01/tenantDeploymentTemplate.json#",
+}
+
+// bicepModuleApiVersion is the API version of the nested deployments modules compile to.
+const bicepModuleApiVersion = "2022-09-01"
+
+// bicepBinaryFunctions are the template functions Bicep operators compile to.
+var bicepBinaryFunctions = map[string]string{
+	"+":  "add",
+	"-":  "sub",
+	"*":  "mul",
+	"/":  "div",
+	"%":  "mod",
+	"==": "equals",
+	"<":  "less",
+	"<=": "lessOrEquals",
+	">":  "greater",
+	">=": "greaterOrEquals",
+	"&&": "and",
+	"||": "or",
+	"??": "coalesce",
+}
+
+func isBicepFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".bicep")
+}
+
+// loadBicepTemplate compiles the Bicep file at path to an ARM template, the same way
+// `bicep build` does. Modules compile to nested deployments with inline templates.
+func loadBicepTemplate(path string) (*ArmTemplate, error) {
+	obj, err := compileBicepFile(path, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	t := newArmTemplate(obj)
+	t.path = path
+
+	return t, nil
+}
+
+func readBicepFile(path string) (*bicepFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading Bicep file")
+	}
+
+	f, err := parseBicep(string(b))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing Bicep file %s", path)
+	}
+
+	return f, nil
+}
+
+// compileBicepFile compiles the Bicep file at path to the JSON of an ARM template. visited
+// holds the files of the modules being compiled, so a module can't reference itself.
+func compileBicepFile(path string, visited map[string]bool) (map[string]interface{}, error) {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if visited[path] {
+		return nil, fmt.Errorf("Bicep module %s references itself", path)
+	}
+
+	f, err := readBicepFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inner := make(map[string]bool, len(visited)+1)
+	for k := range visited {
+		inner[k] = true
+	}
+	inner[path] = true
+
+	c := &bicepCompiler{path: path, file: f, visited: inner, symbols: map[string]interface{}{}}
+	obj, err := c.compile()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error compiling Bicep file %s", path)
+	}
+
+	return obj, nil
+}
+
+// bicepEnv maps local variables, i.e. loop items and lambda parameters, to the
+// template expressions they compile to.
+type bicepEnv map[string]string
+
+func (env bicepEnv) with(name, expr string) bicepEnv {
+	out := make(bicepEnv, len(env)+1)
+	for k, v := range env {
+		out[k] = v
+	}
+	out[name] = expr
+	return out
+}
+
+type bicepCompiler struct {
+	path    string
+	file    *bicepFile
+	visited map[string]bool
+
+	// symbols maps the names of params, vars, resources and modules to their declarations
+	symbols map[string]interface{}
+}
+
+func (c *bicepCompiler) compile() (map[string]interface{}, error) {
+	c.declare()
+
+	schema, ok := bicepSchemas[strings.ToLower(c.file.targetScope)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported targetScope '%s'", c.file.targetScope)
+	}
+
+	params := make(map[string]interface{}, len(c.file.params))
+	for _, p := range c.file.params {
+		v, err := c.compileParam(p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "param '%s'", p.name)
+		}
+		params[p.name] = v
+	}
+
+	variables := make(map[string]interface{}, len(c.file.vars))
+	for _, v := range c.file.vars {
+		value, err := c.compileValue(v.value, bicepEnv{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "var '%s'", v.name)
+		}
+		variables[v.name] = value
+	}
+
+	resources := []interface{}{}
+	for _, d := range c.file.declarations {
+		switch d := d.(type) {
+		case *bicepResource:
+			if err := c.compileResource(d, &resources); err != nil {
+				return nil, err
+			}
+		case *bicepModule:
+			if err := c.compileModule(d, &resources); err != nil {
+				return nil, errors.Wrapf(err, "module '%s'", d.name)
+			}
+		}
+	}
+
+	outputs := make(map[string]interface{}, len(c.file.outputs))
+	for _, o := range c.file.outputs {
+		value, err := c.compileValue(o.value, bicepEnv{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "output '%s'", o.name)
+		}
+		outputs[o.name] = map[string]interface{}{
+			"type":  c.armType(o.typ),
+			"value": value,
+		}
+	}
+
+	return map[string]interface{}{
+		"$schema":        schema,
+		"contentVersion": "1.0.0.0",
+		"parameters":     params,
+		"variables":      variables,
+		"resources":      resources,
+		"outputs":        outputs,
+	}, nil
+}
+
+func (c *bicepCompiler) declare() {
+	for _, p := range c.file.params {
+		c.symbols[p.name] = p
+	}
+	for _, v := range c.file.vars {
+		c.symbols[v.name] = v
+	}
+
+	var declareResource func(r *bicepResource)
+	declareResource = func(r *bicepResource) {
+		c.symbols[r.name] = r
+		for _, child := range r.children {
+			declareResource(child)
+		}
+	}
+
+	for _, d := range c.file.declarations {
+		switch d := d.(type) {
+		case *bicepResource:
+			declareResource(d)
+		case *bicepModule:
+			c.symbols[d.name] = d
+		}
+	}
+}
+
+// resolveType follows user-defined type aliases to the type they're declared as.
+func (c *bicepCompiler) resolveType(t *bicepType) *bicepType {
+	for i := 0; i < 10 && t != nil && !t.isLiteral; i++ {
+		alias, ok := c.file.types[t.name]
+		if !ok {
+			break
+		}
+
+		resolved := *alias
+		resolved.nullable = resolved.nullable || t.nullable
+		resolved.isArray = resolved.isArray || t.isArray
+		t = &resolved
+	}
+
+	return t
+}
+
+func (c *bicepCompiler) armType(t *bicepType) string {
+	t = c.resolveType(t)
+	if t == nil {
+		return "object"
+	}
+	if t.isArray {
+		return "array"
+	}
+
+	switch strings.ToLower(t.name) {
+	case "string", "int", "bool", "object", "array", "securestring", "secureobject":
+		return strings.ToLower(t.name)
+	}
+
+	return "object"
+}
+
+func (c *bicepCompiler) compileParam(p *bicepParam) (map[string]interface{}, error) {
+	typ := c.resolveType(p.typ)
+	armType := c.armType(typ)
+	obj := map[string]interface{}{}
+
+	if typ != nil && typ.isLiteral && !typ.isArray {
+		obj["allowedValues"] = typ.allowed
+	}
+
+	for _, d := range p.decorators {
+		switch d.name {
+		case "secure":
+			if armType == "string" {
+				armType = "securestring"
+			} else if armType == "object" {
+				armType = "secureobject"
+			}
+		case "allowed":
+			if len(d.args) == 1 {
+				v, err := c.compileValue(d.args[0], bicepEnv{})
+				if err != nil {
+					return nil, err
+				}
+				obj["allowedValues"] = v
+			}
+		}
+	}
+	obj["type"] = armType
+
+	if p.defaultValue != nil {
+		v, err := c.compileValue(p.defaultValue, bicepEnv{})
+		if err != nil {
+			return nil, err
+		}
+		obj["defaultValue"] = v
+	} else if typ != nil && typ.nullable {
+		obj["defaultValue"] = nil
+	}
+
+	return obj, nil
+}
+
+// bicepDeclarationBody splits the body of a resource or module into its loop, condition and object.
+func bicepDeclarationBody(body bicepExpr) (*bicepFor, bicepExpr, *bicepObject) {
+	var loop *bicepFor
+	if l, ok := body.(*bicepFor); ok {
+		loop = l
+		body = l.body
+	}
+
+	var cond bicepExpr
+	if i, ok := body.(*bicepIf); ok {
+		cond = i.cond
+		body = i.body
+	}
+
+	obj, _ := body.(*bicepObject)
+	if obj == nil {
+		obj = &bicepObject{}
+	}
+
+	return loop, cond, obj
+}
+
+func (o *bicepObject) get(key string) bicepExpr {
+	for _, p := range o.props {
+		if !p.spread && strings.EqualFold(p.key, key) {
+			return p.value
+		}
+	}
+	return nil
+}
+
+func (c *bicepCompiler) resourceType(r *bicepResource) string {
+	if r.parent != nil && !strings.Contains(r.typ, "/") {
+		return c.resourceType(r.parent) + "/" + r.typ
+	}
+	return r.typ
+}
+
+func (c *bicepCompiler) resourceApiVersion(r *bicepResource) string {
+	if r.apiVersion == "" && r.parent != nil {
+		return c.resourceApiVersion(r.parent)
+	}
+	return r.apiVersion
+}
+
+// resourceLoop returns the resource whose loop r is deployed in. Child resources
+// declared inside a looped parent are deployed once for each copy of the parent.
+func (c *bicepCompiler) resourceLoop(r *bicepResource) (*bicepResource, *bicepFor) {
+	for ; r != nil; r = r.parent {
+		if loop, _, _ := bicepDeclarationBody(r.body); loop != nil {
+			return r, loop
+		}
+	}
+	return nil, nil
+}
+
+// loopEnv binds the item and index variables of a loop for the copy at index.
+func (c *bicepCompiler) loopEnv(loop *bicepFor, env bicepEnv, index string) (bicepEnv, error) {
+	iter, err := c.compileExpr(loop.iter, env)
+	if err != nil {
+		return nil, err
+	}
+
+	env = env.with(loop.item, fmt.Sprintf("%s[%s]", iter, index))
+	if loop.index != "" {
+		env = env.with(loop.index, index)
+	}
+
+	return env, nil
+}
+
+// resourceEnv returns the environment the body of r compiles in for the copy at index.
+func (c *bicepCompiler) resourceEnv(r *bicepResource, index string) (bicepEnv, error) {
+	_, loop := c.resourceLoop(r)
+	if loop == nil {
+		return bicepEnv{}, nil
+	}
+	return c.loopEnv(loop, bicepEnv{}, index)
+}
+
+func copyIndexExpr(loopName string) string {
+	return fmt.Sprintf("copyIndex(%s)", quoteTemplateString(loopName))
+}
+
+// resourceParent returns the parent of a child resource, which is either declared
+// inside its parent or references it with the parent property.
+func (c *bicepCompiler) resourceParent(r *bicepResource, env bicepEnv) (*bicepResource, bicepEnv, error) {
+	if r.parent != nil {
+		return r.parent, env, nil
+	}
+
+	_, _, obj := bicepDeclarationBody(r.body)
+	parentExpr := obj.get("parent")
+	if parentExpr == nil {
+		return nil, nil, nil
+	}
+
+	parent, parentEnv, ok, err := c.resourceReference(parentExpr, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("parent of resource '%s' must reference a resource", r.name)
+	}
+
+	return parent, parentEnv, nil
+}
+
+// resourceNameExpr returns the expression of the full name of r, which for a child
+// resource includes the names of its parents, e.g. vnet/subnet.
+func (c *bicepCompiler) resourceNameExpr(r *bicepResource, env bicepEnv) (string, error) {
+	_, _, obj := bicepDeclarationBody(r.body)
+	nameValue := obj.get("name")
+	if nameValue == nil {
+		return "", fmt.Errorf("resource '%s' has no name", r.name)
+	}
+
+	name, err := c.compileExpr(nameValue, env)
+	if err != nil {
+		return "", err
+	}
+
+	parent, parentEnv, err := c.resourceParent(r, env)
+	if err != nil {
+		return "", err
+	}
+	if parent == nil {
+		return name, nil
+	}
+
+	parentName, err := c.resourceNameExpr(parent, parentEnv)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("format('{0}/{1}', %s, %s)", parentName, name), nil
+}
+
+// resourceScope returns the scope property of r or its parents, which existing
+// resources use to reference resources in other resource groups or subscriptions.
+func (c *bicepCompiler) resourceScope(r *bicepResource, env bicepEnv) (bicepExpr, bicepEnv, error) {
+	_, _, obj := bicepDeclarationBody(r.body)
+	if scope := obj.get("scope"); scope != nil {
+		return scope, env, nil
+	}
+
+	parent, parentEnv, err := c.resourceParent(r, env)
+	if err != nil || parent == nil {
+		return nil, nil, err
+	}
+
+	return c.resourceScope(parent, parentEnv)
+}
+
+func (c *bicepCompiler) resourceIdExpr(r *bicepResource, env bicepEnv) (string, error) {
+	name, err := c.resourceNameExpr(r, env)
+	if err != nil {
+		return "", err
+	}
+
+	fn := "resourceId"
+	var args []string
+
+	scope, scopeEnv, err := c.resourceScope(r, env)
+	if err != nil {
+		return "", err
+	}
+
+	if scope != nil {
+		if scopeFn, scopeArgs, ok := bicepScopeCall(scope); ok {
+			for _, a := range scopeArgs {
+				s, err := c.compileExpr(a, scopeEnv)
+				if err != nil {
+					return "", err
+				}
+				args = append(args, s)
+			}
+
+			switch strings.ToLower(scopeFn) {
+			case "subscription":
+				fn = "subscriptionResourceId"
+			case "managementgroup":
+				fn = "managementGroupResourceId"
+			case "tenant":
+				fn = "tenantResourceId"
+			}
+		} else if scopeRes, scopeResEnv, ok, err := c.resourceReference(scope, scopeEnv); err != nil {
+			return "", err
+		} else if ok {
+			if strings.EqualFold(c.resourceType(scopeRes), resourceGroupsResourceType) {
+				rg, err := c.resourceNameExpr(scopeRes, scopeResEnv)
+				if err != nil {
+					return "", err
+				}
+				args = append(args, rg)
+			} else {
+				// Extension resources, e.g. locks and diagnostic settings, are scoped to another resource
+				id, err := c.resourceIdExpr(scopeRes, scopeResEnv)
+				if err != nil {
+					return "", err
+				}
+				fn = "extensionResourceId"
+				args = append(args, id)
+			}
+		}
+	}
+
+	args = append(args, quoteTemplateString(c.resourceType(r)), name)
+	return fmt.Sprintf("%s(%s)", fn, strings.Join(args, ", ")), nil
+}
+
+// bicepScopeCall returns the scope function an expression calls, e.g. resourceGroup('rg')
+// or az.subscription().
+func bicepScopeCall(expr bicepExpr) (string, []bicepExpr, bool) {
+	var name string
+	var args []bicepExpr
+
+	switch e := expr.(type) {
+	case *bicepCall:
+		name, args = e.name, e.args
+	case *bicepMethodCall:
+		ns, ok := e.target.(*bicepIdentifier)
+		if !ok || (ns.name != "az" && ns.name != "sys") {
+			return "", nil, false
+		}
+		name, args = e.name, e.args
+	default:
+		return "", nil, false
+	}
+
+	switch strings.ToLower(name) {
+	case "resourcegroup", "subscription", "managementgroup", "tenant":
+		return name, args, true
+	}
+	return "", nil, false
+}
+
+// resourceReference resolves an expression that references a resource symbol, e.g. storage
+// or storage[i], returning the resource and the environment its loop variables resolve in.
+func (c *bicepCompiler) resourceReference(expr bicepExpr, env bicepEnv) (*bicepResource, bicepEnv, bool, error) {
+	switch e := expr.(type) {
+	case *bicepIdentifier:
+		if _, ok := env[e.name]; ok {
+			return nil, nil, false, nil
+		}
+		r, ok := c.symbols[e.name].(*bicepResource)
+		if !ok {
+			return nil, nil, false, nil
+		}
+
+		// A looped resource referenced without an index is the copy being deployed
+		index := ""
+		if owner, _ := c.resourceLoop(r); owner != nil {
+			index = copyIndexExpr(owner.name)
+		}
+		refEnv, err := c.resourceEnv(r, index)
+		return r, refEnv, true, err
+	case *bicepIndex:
+		id, ok := e.target.(*bicepIdentifier)
+		if !ok {
+			return nil, nil, false, nil
+		}
+		if _, ok := env[id.name]; ok {
+			return nil, nil, false, nil
+		}
+		r, ok := c.symbols[id.name].(*bicepResource)
+		if !ok {
+			return nil, nil, false, nil
+		}
+
+		index, err := c.compileExpr(e.index, env)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		refEnv, err := c.resourceEnv(r, index)
+		return r, refEnv, true, err
+	}
+
+	return nil, nil, false, nil
+}
+
+// moduleReference resolves an expression that references a module symbol, e.g. app or app[i],
+// returning the module and the expression of its deployment name.
+func (c *bicepCompiler) moduleReference(expr bicepExpr, env bicepEnv) (*bicepModule, string, bool, error) {
+	var name string
+	var indexExpr bicepExpr
+
+	switch e := expr.(type) {
+	case *bicepIdentifier:
+		name = e.name
+	case *bicepIndex:
+		id, ok := e.target.(*bicepIdentifier)
+		if !ok {
+			return nil, "", false, nil
+		}
+		name, indexExpr = id.name, e.index
+	default:
+		return nil, "", false, nil
+	}
+
+	if _, ok := env[name]; ok {
+		return nil, "", false, nil
+	}
+	m, ok := c.symbols[name].(*bicepModule)
+	if !ok {
+		return nil, "", false, nil
+	}
+
+	index := copyIndexExpr(m.name)
+	if indexExpr != nil {
+		var err error
+		if index, err = c.compileExpr(indexExpr, env); err != nil {
+			return nil, "", false, err
+		}
+	}
+
+	deploymentName, err := c.moduleNameExpr(m, index)
+	return m, deploymentName, true, err
+}
+
+// moduleNameExpr returns the expression of the deployment name of a module, for the copy at
+// index if it's in a loop. Modules without a name are named after their symbol.
+func (c *bicepCompiler) moduleNameExpr(m *bicepModule, index string) (string, error) {
+	loop, _, obj := bicepDeclarationBody(m.body)
+
+	env := bicepEnv{}
+	if loop != nil {
+		var err error
+		if env, err = c.loopEnv(loop, env, index); err != nil {
+			return "", err
+		}
+	}
+
+	if nameValue := obj.get("name"); nameValue != nil {
+		return c.compileExpr(nameValue, env)
+	}
+
+	if loop != nil {
+		return fmt.Sprintf("format('{0}-{1}', %s, %s)", quoteTemplateString(m.name), index), nil
+	}
+	return quoteTemplateString(m.name), nil
+}
+
+// compileConditions returns the condition of r and the parents it's declared inside,
+// since child resources aren't deployed if their parent isn't.
+func (c *bicepCompiler) compileConditions(r *bicepResource, env bicepEnv) (string, error) {
+	var conds []string
+	for ; r != nil; r = r.parent {
+		_, cond, _ := bicepDeclarationBody(r.body)
+		if cond == nil {
+			continue
+		}
+
+		s, err := c.compileExpr(cond, env)
+		if err != nil {
+			return "", err
+		}
+		conds = append([]string{s}, conds...)
+	}
+
+	switch len(conds) {
+	case 0:
+		return "", nil
+	case 1:
+		return conds[0], nil
+	}
+	return fmt.Sprintf("and(%s)", strings.Join(conds, ", ")), nil
+}
+
+// compileCopy returns the copy loop of a resource or module deployed in a for loop.
+func (c *bicepCompiler) compileCopy(name string, loop *bicepFor, decorators []*bicepDecorator) (map[string]interface{}, error) {
+	iter, err := c.compileExpr(loop.iter, bicepEnv{})
+	if err != nil {
+		return nil, err
+	}
+
+	copyObj := map[string]interface{}{
+		"name":  name,
+		"count": fmt.Sprintf("[length(%s)]", iter),
+	}
+
+	for _, d := range decorators {
+		if d.name != "batchsize" || len(d.args) != 1 {
+			continue
+		}
+		if lit, ok := d.args[0].(*bicepLiteral); ok {
+			copyObj["mode"] = "serial"
+			copyObj["batchSize"] = lit.value
+		}
+	}
+
+	return copyObj, nil
+}
+
+// compileResource compiles a resource and the child resources declared inside it, which are
+// flattened into top level resources with their full type and name. Existing resources are
+// only referenced, so they're not compiled but their children are.
+func (c *bicepCompiler) compileResource(r *bicepResource, out *[]interface{}) error {
+	loop, _, obj := bicepDeclarationBody(r.body)
+	if loop != nil && r.parent != nil {
+		if owner, _ := c.resourceLoop(r.parent); owner != nil {
+			return fmt.Errorf("resource '%s' has a loop inside the loop of resource '%s', which is not supported", r.name, owner.name)
+		}
+	}
+
+	owner, ownerLoop := c.resourceLoop(r)
+	index := ""
+	if owner != nil {
+		index = copyIndexExpr(owner.name)
+	}
+
+	env, err := c.resourceEnv(r, index)
+	if err != nil {
+		return errors.Wrapf(err, "resource '%s'", r.name)
+	}
+
+	if !r.existing {
+		res, err := c.compileResourceBody(r, obj, env)
+		if err != nil {
+			return errors.Wrapf(err, "resource '%s'", r.name)
+		}
+
+		if owner != nil {
+			copyObj, err := c.compileCopy(owner.name, ownerLoop, owner.decorators)
+			if err != nil {
+				return errors.Wrapf(err, "resource '%s'", r.name)
+			}
+			res["copy"] = copyObj
+		}
+
+		*out = append(*out, res)
+	}
+
+	for _, child := range r.children {
+		if err := c.compileResource(child, out); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *bicepCompiler) compileResourceBody(r *bicepResource, obj *bicepObject, env bicepEnv) (map[string]interface{}, error) {
+	name, err := c.resourceNameExpr(r, env)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]interface{}{
+		"type":       c.resourceType(r),
+		"apiVersion": c.resourceApiVersion(r),
+		"name":       "[" + name + "]",
+	}
+
+	for _, prop := range obj.props {
+		if prop.spread {
+			return nil, errors.New("spread properties are not supported in resource bodies")
+		}
+
+		switch strings.ToLower(prop.key) {
+		case "name", "parent", "scope", "dependson":
+			continue
+		}
+
+		v, err := c.compileValue(prop.value, env)
+		if err != nil {
+			return nil, errors.Wrapf(err, "property '%s'", prop.key)
+		}
+		res[prop.key] = v
+	}
+
+	cond, err := c.compileConditions(r, env)
+	if err != nil {
+		return nil, errors.Wrap(err, "condition")
+	}
+	if cond != "" {
+		res["condition"] = "[" + cond + "]"
+	}
+
+	return res, nil
+}
+
+// compileModule compiles a module to a nested deployment with an inline template, using
+// the inner expression scope so the module's parameters are passed in like a separate deployment.
+func (c *bicepCompiler) compileModule(m *bicepModule, out *[]interface{}) error {
+	loop, cond, obj := bicepDeclarationBody(m.body)
+
+	env := bicepEnv{}
+	index := copyIndexExpr(m.name)
+	if loop != nil {
+		var err error
+		if env, err = c.loopEnv(loop, env, index); err != nil {
+			return err
+		}
+	}
+
+	name, err := c.moduleNameExpr(m, index)
+	if err != nil {
+		return err
+	}
+
+	params := map[string]interface{}{}
+	if p := obj.get("params"); p != nil {
+		paramsObj, ok := p.(*bicepObject)
+		if !ok {
+			return errors.New("params must be an object")
+		}
+
+		for _, prop := range paramsObj.props {
+			if prop.spread {
+				return errors.New("spread properties are not supported in module params")
+			}
+
+			v, err := c.compileModuleParam(prop.value, env)
+			if err != nil {
+				return errors.Wrapf(err, "param '%s'", prop.key)
+			}
+			params[prop.key] = v
+		}
+	}
+
+	props := map[string]interface{}{
+		"expressionEvaluationOptions": map[string]interface{}{"scope": "inner"},
+		"mode":                        string(Incremental),
+		"parameters":                  params,
+	}
+
+	template, err := c.moduleTemplate(m)
+	if err != nil {
+		return err
+	}
+	if template != nil {
+		props["template"] = template
+	}
+
+	res := map[string]interface{}{
+		"type":       deploymentsResourceType,
+		"apiVersion": bicepModuleApiVersion,
+		"name":       "[" + name + "]",
+		"properties": props,
+	}
+
+	if scope := obj.get("scope"); scope != nil {
+		if err := c.compileModuleScope(res, scope, env); err != nil {
+			return errors.Wrap(err, "scope")
+		}
+	}
+
+	if cond != nil {
+		s, err := c.compileExpr(cond, env)
+		if err != nil {
+			return errors.Wrap(err, "condition")
+		}
+		res["condition"] = "[" + s + "]"
+	}
+
+	if loop != nil {
+		copyObj, err := c.compileCopy(m.name, loop, m.decorators)
+		if err != nil {
+			return err
+		}
+		res["copy"] = copyObj
+	}
+
+	*out = append(*out, res)
+	return nil
+}
+
+// compileModuleParam compiles the value of a module parameter. Secrets read from an existing
+// Key Vault with getSecret() are passed as a Key Vault reference, like in a parameters file.
+func (c *bicepCompiler) compileModuleParam(expr bicepExpr, env bicepEnv) (map[string]interface{}, error) {
+	call, ok := expr.(*bicepMethodCall)
+	if !ok || call.name != "getSecret" {
+		v, err := c.compileValue(expr, env)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"value": v}, nil
+	}
+
+	if len(call.args) == 0 || len(call.args) > 2 {
+		return nil, fmt.Errorf("function 'getSecret' expects between 1 and 2 arguments, got %d", len(call.args))
+	}
+
+	r, refEnv, ok, err := c.resourceReference(call.target, env)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("function 'getSecret' must be called on a Key Vault resource")
+	}
+
+	id, err := c.resourceIdExpr(r, refEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := map[string]interface{}{
+		"keyVault": map[string]interface{}{"id": "[" + id + "]"},
+	}
+	if ref["secretName"], err = c.compileValue(call.args[0], env); err != nil {
+		return nil, err
+	}
+	if len(call.args) == 2 {
+		if ref["secretVersion"], err = c.compileValue(call.args[1], env); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{"reference": ref}, nil
+}
+
+// compileModuleScope sets the resource group and subscription a module deploys to.
+func (c *bicepCompiler) compileModuleScope(res map[string]interface{}, scope bicepExpr, env bicepEnv) error {
+	if fn, args, ok := bicepScopeCall(scope); ok {
+		values := make([]interface{}, len(args))
+		for i, a := range args {
+			v, err := c.compileValue(a, env)
+			if err != nil {
+				return err
+			}
+			values[i] = v
+		}
+
+		switch strings.ToLower(fn) {
+		case "resourcegroup":
+			if len(values) == 1 {
+				res["resourceGroup"] = values[0]
+			} else if len(values) == 2 {
+				res["subscriptionId"] = values[0]
+				res["resourceGroup"] = values[1]
+			}
+		case "subscription":
+			if len(values) == 1 {
+				res["subscriptionId"] = values[0]
+			}
+		}
+		return nil
+	}
+
+	r, refEnv, ok, err := c.resourceReference(scope, env)
+	if err != nil {
+		return err
+	}
+	if ok && strings.EqualFold(c.resourceType(r), resourceGroupsResourceType) {
+		name, err := c.resourceNameExpr(r, refEnv)
+		if err != nil {
+			return err
+		}
+		res["resourceGroup"] = "[" + name + "]"
+	}
+
+	return nil
+}
+
+// isBicepRegistryModule returns true for modules published to a Bicep registry or as a template spec,
+// which can't be read without access to Azure.
+func isBicepRegistryModule(path string) bool {
+	for _, prefix := range []string{"br:", "br/", "ts:", "ts/"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleTemplate compiles the file a module references. Modules from a registry or template
+// spec can't be fetched, so they are deployed without a template and aren't expanded.
+func (c *bicepCompiler) moduleTemplate(m *bicepModule) (map[string]interface{}, error) {
+	if isBicepRegistryModule(m.path) {
+		log.Warnf("Bicep module '%s' references %s, which is not supported. Its resources will not be included in the estimate", m.name, m.path)
+		return nil, nil
+	}
+
+	path := filepath.Join(filepath.Dir(c.path), filepath.FromSlash(m.path))
+	if isBicepFile(path) {
+		return compileBicepFile(path, c.visited)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading ARM template file")
+	}
+
+	v, err := decodeJSON(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing ARM template JSON %s", path)
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ARM template %s must be a JSON object", path)
+	}
+
+	return obj, nil
+}
+
+// compileValue compiles an expression to a JSON value of the template. Literals, objects and
+// arrays stay as JSON so the template reads like one built by the bicep CLI, anything else
+// becomes a template expression string.
+func (c *bicepCompiler) compileValue(expr bicepExpr, env bicepEnv) (interface{}, error) {
+	switch e := expr.(type) {
+	case *bicepLiteral:
+		if s, ok := e.value.(string); ok && strings.HasPrefix(s, "[") {
+			// Escape strings that would otherwise be evaluated as an expression
+			return "[" + s, nil
+		}
+		return e.value, nil
+	case *bicepObject:
+		if !hasSpread(e) {
+			out := make(map[string]interface{}, len(e.props))
+			for _, p := range e.props {
+				v, err := c.compileValue(p.value, env)
+				if err != nil {
+					return nil, err
+				}
+				out[p.key] = v
+			}
+			return out, nil
+		}
+	case *bicepArray:
+		out := make([]interface{}, len(e.items))
+		for i, item := range e.items {
+			v, err := c.compileValue(item, env)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = v
+		}
+		return out, nil
+	}
+
+	s, err := c.compileExpr(expr, env)
+	if err != nil {
+		return nil, err
+	}
+	return "[" + s + "]", nil
+}
+
+func hasSpread(obj *bicepObject) bool {
+	for _, p := range obj.props {
+		if p.spread {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteTemplateString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// compileExpr compiles an expression to the text of an equivalent template expression.
+func (c *bicepCompiler) compileExpr(expr bicepExpr, env bicepEnv) (string, error) {
+	switch e := expr.(type) {
+	case *bicepLiteral:
+		switch v := e.value.(type) {
+		case string:
+			return quoteTemplateString(v), nil
+		case int64:
+			return strconv.FormatInt(v, 10), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		case nil:
+			return "null", nil
+		}
+		return "", fmt.Errorf("unsupported literal %v", e.value)
+	case *bicepInterpolation:
+		return c.compileInterpolation(e, env)
+	case *bicepIdentifier:
+		return c.compileIdentifier(e, env)
+	case *bicepCall:
+		return c.compileCall(e, env)
+	case *bicepMember:
+		return c.compileMember(e, env)
+	case *bicepIndex:
+		return c.compileIndex(e, env)
+	case *bicepMethodCall:
+		return c.compileMethodCall(e, env)
+	case *bicepUnary:
+		return c.compileUnary(e, env)
+	case *bicepBinary:
+		return c.compileBinary(e, env)
+	case *bicepTernary:
+		return c.compileFunction("if", env, e.cond, e.trueExpr, e.falseExpr)
+	case *bicepArray:
+		return c.compileFunction("createArray", env, e.items...)
+	case *bicepObject:
+		return c.compileObject(e, env)
+	case *bicepFor:
+		return c.compileFor(e, env)
+	case *bicepLambda:
+		return c.compileLambda(e, env)
+	case *bicepIf:
+		return "", errors.New("if conditions can only be used with resources and modules")
+	}
+
+	return "", fmt.Errorf("unsupported expression %T", expr)
+}
+
+func (c *bicepCompiler) compileFunction(name string, env bicepEnv, args ...bicepExpr) (string, error) {
+	compiled := make([]string, len(args))
+	for i, a := range args {
+		s, err := c.compileExpr(a, env)
+		if err != nil {
+			return "", err
+		}
+		compiled[i] = s
+	}
+
+	return fmt.Sprintf("%s(%s)", name, strings.Join(compiled, ", ")), nil
+}
+
+// compileInterpolation compiles 'a${b}c' to format('a{0}c', b).
+func (c *bicepCompiler) compileInterpolation(e *bicepInterpolation, env bicepEnv) (string, error) {
+	var sb strings.Builder
+	args := make([]bicepExpr, 0, len(e.exprs))
+
+	for i, part := range e.parts {
+		part = strings.ReplaceAll(part, "{", "{{")
+		part = strings.ReplaceAll(part, "}", "}}")
+		sb.WriteString(part)
+
+		if i < len(e.exprs) {
+			fmt.Fprintf(&sb, "{%d}", i)
+			args = append(args, e.exprs[i])
+		}
+	}
+
+	return c.compileFunction("format", env, append([]bicepExpr{&bicepLiteral{value: sb.String()}}, args...)...)
+}
+
+func (c *bicepCompiler) compileIdentifier(e *bicepIdentifier, env bicepEnv) (string, error) {
+	if v, ok := env[e.name]; ok {
+		return v, nil
+	}
+
+	switch s := c.symbols[e.name].(type) {
+	case *bicepParam:
+		return fmt.Sprintf("parameters(%s)", quoteTemplateString(s.name)), nil
+	case *bicepVar:
+		return fmt.Sprintf("variables(%s)", quoteTemplateString(s.name)), nil
+	case *bicepResource:
+		return c.compileResourceReference(e, env)
+	case *bicepModule:
+		_, name, _, err := c.moduleReference(e, env)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("reference(%s)", name), nil
+	}
+
+	return "", fmt.Errorf("unknown symbol '%s'", e.name)
+}
+
+// compileResourceReference compiles a reference to the whole of a resource, e.g. storage or storage[0].
+func (c *bicepCompiler) compileResourceReference(expr bicepExpr, env bicepEnv) (string, error) {
+	r, refEnv, _, err := c.resourceReference(expr, env)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := c.resourceIdExpr(r, refEnv)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("reference(%s, %s, 'Full')", id, quoteTemplateString(c.resourceApiVersion(r))), nil
+}
+
+func (c *bicepCompiler) compileMember(e *bicepMember, env bicepEnv) (string, error) {
+	// ARM wraps the outputs of a deployment in {type, value} objects
+	if outputs, ok := e.target.(*bicepMember); ok && outputs.name == "outputs" {
+		if _, name, ok, err := c.moduleReference(outputs.target, env); err != nil {
+			return "", err
+		} else if ok {
+			return fmt.Sprintf("reference(%s).outputs.%s.value", name, e.name), nil
+		}
+	}
+
+	if r, refEnv, ok, err := c.resourceReference(e.target, env); err != nil {
+		return "", err
+	} else if ok {
+		switch e.name {
+		case "name":
+			return c.resourceNameExpr(r, refEnv)
+		case "type":
+			return quoteTemplateString(c.resourceType(r)), nil
+		case "apiVersion":
+			return quoteTemplateString(c.resourceApiVersion(r)), nil
+		}
+
+		id, err := c.resourceIdExpr(r, refEnv)
+		if err != nil {
+			return "", err
+		}
+
+		switch e.name {
+		case "id":
+			return id, nil
+		case "properties":
+			return fmt.Sprintf("reference(%s)", id), nil
+		}
+		return fmt.Sprintf("reference(%s, %s, 'Full').%s", id, quoteTemplateString(c.resourceApiVersion(r)), e.name), nil
+	}
+
+	if _, name, ok, err := c.moduleReference(e.target, env); err != nil {
+		return "", err
+	} else if ok && e.name == "name" {
+		return name, nil
+	}
+
+	target, err := c.compileExpr(e.target, env)
+	if err != nil {
+		return "", err
+	}
+	return target + "." + e.name, nil
+}
+
+func (c *bicepCompiler) compileIndex(e *bicepIndex, env bicepEnv) (string, error) {
+	index, err := c.compileExpr(e.index, env)
+	if err != nil {
+		return "", err
+	}
+
+	if outputs, ok := e.target.(*bicepMember); ok && outputs.name == "outputs" {
+		if _, name, ok, err := c.moduleReference(outputs.target, env); err != nil {
+			return "", err
+		} else if ok {
+			return fmt.Sprintf("reference(%s).outputs[%s].value", name, index), nil
+		}
+	}
+
+	if _, _, ok, err := c.resourceReference(e, env); err != nil {
+		return "", err
+	} else if ok {
+		return c.compileResourceReference(e, env)
+	}
+
+	if _, name, ok, err := c.moduleReference(e, env); err != nil {
+		return "", err
+	} else if ok {
+		return fmt.Sprintf("reference(%s)", name), nil
+	}
+
+	target, err := c.compileExpr(e.target, env)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s[%s]", target, index), nil
+}
+
+// compileMethodCall compiles namespaced function calls, e.g. az.resourceGroup(), and resource
+// functions such as storage.listKeys(). Resource functions are called with the ID of the resource,
+// followed by their arguments or, without arguments, the API version of the resource.
+func (c *bicepCompiler) compileMethodCall(e *bicepMethodCall, env bicepEnv) (string, error) {
+	if ns, ok := e.target.(*bicepIdentifier); ok && (ns.name == "az" || ns.name == "sys") && c.symbols[ns.name] == nil {
+		return c.compileCall(&bicepCall{name: e.name, args: e.args}, env)
+	}
+
+	if e.name == "getSecret" {
+		return "", errors.New("function 'getSecret' can only be used in module params")
+	}
+
+	r, refEnv, ok, err := c.resourceReference(e.target, env)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("unsupported function call '%s'", e.name)
+	}
+
+	id, err := c.resourceIdExpr(r, refEnv)
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{id}
+	if len(e.args) == 0 {
+		args = append(args, quoteTemplateString(c.resourceApiVersion(r)))
+	}
+	for _, a := range e.args {
+		s, err := c.compileExpr(a, env)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, s)
+	}
+
+	return fmt.Sprintf("%s(%s)", e.name, strings.Join(args, ", ")), nil
+}
+
+func (c *bicepCompiler) compileCall(e *bicepCall, env bicepEnv) (string, error) {
+	switch strings.ToLower(e.name) {
+	case "any":
+		if len(e.args) != 1 {
+			return "", fmt.Errorf("function 'any' expects 1 argument, got %d", len(e.args))
+		}
+		return c.compileExpr(e.args[0], env)
+	case "loadtextcontent", "loadjsoncontent", "loadfileasbase64":
+		return c.compileLoadFunction(e)
+	case "readenvironmentvariable":
+		return c.compileReadEnvironmentVariable(e, env)
+	}
+
+	return c.compileFunction(e.name, env, e.args...)
+}
+
+// compileReadEnvironmentVariable inlines the value of an environment variable read with
+// readEnvironmentVariable(name, [default]), which is only allowed in .bicepparam files.
+func (c *bicepCompiler) compileReadEnvironmentVariable(e *bicepCall, env bicepEnv) (string, error) {
+	if len(e.args) == 0 || len(e.args) > 2 {
+		return "", fmt.Errorf("function '%s' expects between 1 and 2 arguments, got %d", e.name, len(e.args))
+	}
+
+	var name string
+	if lit, ok := e.args[0].(*bicepLiteral); ok {
+		name, _ = lit.value.(string)
+	}
+	if name == "" {
+		return "", fmt.Errorf("function '%s' expects a literal variable name", e.name)
+	}
+
+	if v, ok := os.LookupEnv(name); ok {
+		return quoteTemplateString(v), nil
+	}
+
+	if len(e.args) == 2 {
+		return c.compileExpr(e.args[1], env)
+	}
+
+	return "", fmt.Errorf("environment variable '%s' is not set", name)
+}
+
+// compileLoadFunction inlines the contents of a file loaded with loadTextContent, loadJsonContent
+// or loadFileAsBase64, which Bicep reads at compile time.
+func (c *bicepCompiler) compileLoadFunction(e *bicepCall) (string, error) {
+	if len(e.args) == 0 {
+		return "", fmt.Errorf("function '%s' expects a file path", e.name)
+	}
+
+	var path string
+	if lit, ok := e.args[0].(*bicepLiteral); ok {
+		path, _ = lit.value.(string)
+	}
+	if path == "" {
+		return "", fmt.Errorf("function '%s' expects a literal file path", e.name)
+	}
+
+	b, err := os.ReadFile(filepath.Join(filepath.Dir(c.path), filepath.FromSlash(path)))
+	if err != nil {
+		return "", errors.Wrapf(err, "function '%s'", e.name)
+	}
+
+	switch strings.ToLower(e.name) {
+	case "loadfileasbase64":
+		return quoteTemplateString(base64.StdEncoding.EncodeToString(b)), nil
+	case "loadjsoncontent":
+		content := string(b)
+		if len(e.args) > 1 {
+			if lit, ok := e.args[1].(*bicepLiteral); ok {
+				if jsonPath, ok := lit.value.(string); ok {
+					content = gjson.Get(content, strings.TrimPrefix(strings.TrimPrefix(jsonPath, "$"), ".")).Raw
+				}
+			}
+		}
+		return fmt.Sprintf("json(%s)", quoteTemplateString(content)), nil
+	}
+
+	return quoteTemplateString(string(b)), nil
+}
+
+func (c *bicepCompiler) compileUnary(e *bicepUnary, env bicepEnv) (string, error) {
+	if e.op == "-" {
+		if lit, ok := e.operand.(*bicepLiteral); ok {
+			if n, ok := lit.value.(int64); ok {
+				return strconv.FormatInt(-n, 10), nil
+			}
+		}
+		return c.compileFunction("sub", env, &bicepLiteral{value: int64(0)}, e.operand)
+	}
+
+	return c.compileFunction("not", env, e.operand)
+}
+
+func (c *bicepCompiler) compileBinary(e *bicepBinary, env bicepEnv) (string, error) {
+	switch e.op {
+	case "!=":
+		s, err := c.compileFunction("equals", env, e.left, e.right)
+		return "not(" + s + ")", err
+	case "=~", "!~":
+		left, err := c.compileFunction("toLower", env, e.left)
+		if err != nil {
+			return "", err
+		}
+		right, err := c.compileFunction("toLower", env, e.right)
+		if err != nil {
+			return "", err
+		}
+		s := fmt.Sprintf("equals(%s, %s)", left, right)
+		if e.op == "!~" {
+			s = "not(" + s + ")"
+		}
+		return s, nil
+	}
+
+	fn, ok := bicepBinaryFunctions[e.op]
+	if !ok {
+		return "", fmt.Errorf("unsupported operator '%s'", e.op)
+	}
+	return c.compileFunction(fn, env, e.left, e.right)
+}
+
+// compileObject compiles an object to createObject(), with spread properties merged in using union().
+func (c *bicepCompiler) compileObject(e *bicepObject, env bicepEnv) (string, error) {
+	var parts []string
+	var args []string
+
+	for _, p := range e.props {
+		v, err := c.compileExpr(p.value, env)
+		if err != nil {
+			return "", err
+		}
+
+		if !p.spread {
+			args = append(args, quoteTemplateString(p.key), v)
+			continue
+		}
+
+		if len(args) > 0 {
+			parts = append(parts, fmt.Sprintf("createObject(%s)", strings.Join(args, ", ")))
+			args = nil
+		}
+		parts = append(parts, v)
+	}
+	if len(args) > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("createObject(%s)", strings.Join(args, ", ")))
+	}
+
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return fmt.Sprintf("union(%s)", strings.Join(parts, ", ")), nil
+}
+
+// compileFor compiles a for expression to map() with a lambda. Loops that use the index
+// map over the range of indexes instead.
+func (c *bicepCompiler) compileFor(e *bicepFor, env bicepEnv) (string, error) {
+	if _, ok := e.body.(*bicepIf); ok {
+		return "", errors.New("filtered for expressions are only supported for resources and modules")
+	}
+
+	iter, err := c.compileExpr(e.iter, env)
+	if err != nil {
+		return "", err
+	}
+
+	if e.index == "" {
+		body, err := c.compileExpr(e.body, env.with(e.item, lambdaVariablesExpr(e.item)))
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map(%s, lambda(%s, %s))", iter, quoteTemplateString(e.item), body), nil
+	}
+
+	index := lambdaVariablesExpr(e.index)
+	inner := env.with(e.item, fmt.Sprintf("%s[%s]", iter, index)).with(e.index, index)
+	body, err := c.compileExpr(e.body, inner)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("map(range(0, length(%s)), lambda(%s, %s))", iter, quoteTemplateString(e.index), body), nil
+}
+
+func lambdaVariablesExpr(name string) string {
+	return fmt.Sprintf("lambdaVariables(%s)", quoteTemplateString(name))
+}
+
+func (c *bicepCompiler) compileLambda(e *bicepLambda, env bicepEnv) (string, error) {
+	args := make([]string, 0, len(e.params)+1)
+	for _, p := range e.params {
+		args = append(args, quoteTemplateString(p))
+		env = env.with(p, lambdaVariablesExpr(p))
+	}
+
+	body, err := c.compileExpr(e.body, env)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("lambda(%s)", strings.Join(append(args, body), ", ")), nil
+}
@@ -0,0 +1,346 @@
+package azurerm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type bicepTokenKind int
+
+const (
+	bicepEOF bicepTokenKind = iota
+	bicepNewline
+	bicepIdent
+	bicepString
+	bicepNumber
+	bicepOperator
+)
+
+// bicepToken is a token of a Bicep file. Strings keep their interpolated expressions
+// as source, e.g. 'app-${name}' has parts ["app-", ""] and exprs ["name"], which are
+// parsed separately.
+type bicepToken struct {
+	kind  bicepTokenKind
+	value string
+	parts []string
+	exprs []bicepSource
+	line  int
+	col   int
+}
+
+// bicepSource is a piece of Bicep source with the position it starts at, so errors
+// in interpolated expressions point at the right line.
+type bicepSource struct {
+	text string
+	line int
+	col  int
+}
+
+func (t bicepToken) is(kind bicepTokenKind, value string) bool {
+	return t.kind == kind && t.value == value
+}
+
+func (t bicepToken) String() string {
+	switch t.kind {
+	case bicepEOF:
+		return "end of file"
+	case bicepNewline:
+		return "new line"
+	case bicepString:
+		return "string"
+	}
+	return fmt.Sprintf("'%s'", t.value)
+}
+
+// bicepOperators are sorted longest first, so the longest operator is matched.
+var bicepOperators = []string{
+	"==", "!=", "<=", ">=", "&&", "||", "??", "=~", "!~", ".?", "=>", "...",
+	"{", "}", "[", "]", "(", ")", ",", ":", ".", "?", "!", "=", "<", ">", "+", "-", "*", "/", "%", "@", "|",
+}
+
+type bicepLexer struct {
+	src  []rune
+	pos  int
+	line int
+	col  int
+}
+
+func tokenizeBicep(src bicepSource) ([]bicepToken, error) {
+	l := &bicepLexer{src: []rune(src.text), line: src.line, col: src.col}
+
+	var tokens []bicepToken
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == bicepEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *bicepLexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("line %d, column %d: %s", l.line, l.col, fmt.Sprintf(format, args...))
+}
+
+func (l *bicepLexer) peek(offset int) rune {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *bicepLexer) advance() rune {
+	r := l.src[l.pos]
+	l.pos++
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+func (l *bicepLexer) hasPrefix(s string) bool {
+	end := l.pos + len(s)
+	if end > len(l.src) {
+		return false
+	}
+	return string(l.src[l.pos:end]) == s
+}
+
+func (l *bicepLexer) next() (bicepToken, error) {
+	l.skipWhitespaceAndComments()
+
+	t := bicepToken{line: l.line, col: l.col}
+	if l.pos >= len(l.src) {
+		t.kind = bicepEOF
+		return t, nil
+	}
+
+	r := l.peek(0)
+	switch {
+	case r == '\n':
+		l.advance()
+		t.kind = bicepNewline
+		t.value = "\n"
+		return t, nil
+	case l.hasPrefix("'''"):
+		return l.multilineString(t)
+	case r == '\'':
+		return l.string(t)
+	case unicode.IsDigit(r):
+		start := l.pos
+		for l.pos < len(l.src) && unicode.IsDigit(l.peek(0)) {
+			l.advance()
+		}
+		t.kind = bicepNumber
+		t.value = string(l.src[start:l.pos])
+		return t, nil
+	case unicode.IsLetter(r) || r == '_':
+		start := l.pos
+		for l.pos < len(l.src) && (unicode.IsLetter(l.peek(0)) || unicode.IsDigit(l.peek(0)) || l.peek(0) == '_') {
+			l.advance()
+		}
+		t.kind = bicepIdent
+		t.value = string(l.src[start:l.pos])
+		return t, nil
+	}
+
+	for _, op := range bicepOperators {
+		if l.hasPrefix(op) {
+			for range op {
+				l.advance()
+			}
+			t.kind = bicepOperator
+			t.value = op
+			return t, nil
+		}
+	}
+
+	return t, l.errorf("unexpected character '%c'", r)
+}
+
+func (l *bicepLexer) skipWhitespaceAndComments() {
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		switch {
+		case r == '\n':
+			return
+		case unicode.IsSpace(r):
+			l.advance()
+		case r == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		case r == '/' && l.peek(1) == '*':
+			l.advance()
+			l.advance()
+			for l.pos < len(l.src) && !(l.peek(0) == '*' && l.peek(1) == '/') {
+				l.advance()
+			}
+			if l.pos < len(l.src) {
+				l.advance()
+				l.advance()
+			}
+		case r == '#' && l.col == 1:
+			// Pragmas such as #disable-next-line only affect the linter
+			for l.pos < len(l.src) && l.peek(0) != '\n' {
+				l.advance()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// multilineString lexes a string in triple quotes, which has no escapes or interpolation.
+func (l *bicepLexer) multilineString(t bicepToken) (bicepToken, error) {
+	for i := 0; i < 3; i++ {
+		l.advance()
+	}
+
+	// A line break straight after the opening quotes isn't part of the string
+	if l.peek(0) == '\r' && l.peek(1) == '\n' {
+		l.advance()
+		l.advance()
+	} else if l.peek(0) == '\n' {
+		l.advance()
+	}
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			return t, fmt.Errorf("line %d, column %d: unterminated multi-line string", t.line, t.col)
+		}
+		if l.hasPrefix("'''") {
+			for i := 0; i < 3; i++ {
+				l.advance()
+			}
+			break
+		}
+		sb.WriteRune(l.advance())
+	}
+
+	t.kind = bicepString
+	t.parts = []string{sb.String()}
+	return t, nil
+}
+
+func (l *bicepLexer) string(t bicepToken) (bicepToken, error) {
+	l.advance()
+
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.peek(0) == '\n' {
+			return t, fmt.Errorf("line %d, column %d: unterminated string", t.line, t.col)
+		}
+
+		r := l.peek(0)
+		switch {
+		case r == '\'':
+			l.advance()
+			t.kind = bicepString
+			t.parts = append(t.parts, sb.String())
+			return t, nil
+		case r == '\\':
+			l.advance()
+			if l.pos >= len(l.src) {
+				continue
+			}
+			esc := l.advance()
+			switch esc {
+			case 'n':
+				sb.WriteRune('\n')
+			case 'r':
+				sb.WriteRune('\r')
+			case 't':
+				sb.WriteRune('\t')
+			case 'u':
+				s, err := l.unicodeEscape()
+				if err != nil {
+					return t, err
+				}
+				sb.WriteString(s)
+			default:
+				// \\, \' and \$
+				sb.WriteRune(esc)
+			}
+		case r == '$' && l.peek(1) == '{':
+			l.advance()
+			l.advance()
+			src, err := l.interpolation()
+			if err != nil {
+				return t, err
+			}
+			t.parts = append(t.parts, sb.String())
+			t.exprs = append(t.exprs, src)
+			sb.Reset()
+		default:
+			sb.WriteRune(l.advance())
+		}
+	}
+}
+
+// unicodeEscape lexes the code point of a \u{...} escape.
+func (l *bicepLexer) unicodeEscape() (string, error) {
+	if l.peek(0) != '{' {
+		return "", l.errorf("invalid unicode escape")
+	}
+	l.advance()
+
+	start := l.pos
+	for l.pos < len(l.src) && l.peek(0) != '}' {
+		l.advance()
+	}
+	hex := string(l.src[start:l.pos])
+	if l.pos < len(l.src) {
+		l.advance()
+	}
+
+	cp, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return "", l.errorf("invalid unicode escape '%s'", hex)
+	}
+	return string(rune(cp)), nil
+}
+
+// interpolation returns the source of an interpolated expression, up to the matching closing brace.
+func (l *bicepLexer) interpolation() (bicepSource, error) {
+	src := bicepSource{line: l.line, col: l.col}
+	start := l.pos
+	depth := 0
+
+	for l.pos < len(l.src) {
+		r := l.peek(0)
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				src.text = string(l.src[start:l.pos])
+				l.advance()
+				return src, nil
+			}
+			depth--
+		case '\'':
+			// Skip nested strings, which can contain braces
+			nested := &bicepLexer{src: l.src, pos: l.pos, line: l.line, col: l.col}
+			if _, err := nested.string(bicepToken{}); err != nil {
+				return src, err
+			}
+			for l.pos < nested.pos {
+				l.advance()
+			}
+			continue
+		}
+		l.advance()
+	}
+
+	return src, fmt.Errorf("line %d, column %d: unterminated string interpolation", src.line, src.col)
+}
@@ -0,0 +1,1263 @@
+package azurerm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bicepFile is the parsed form of a Bicep file. Declarations are kept in source order.
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/bicep/file
+type bicepFile struct {
+	targetScope string
+
+	// using is the template a .bicepparam file provides parameters for
+	using string
+
+	params  []*bicepParam
+	vars    []*bicepVar
+	outputs []*bicepOutput
+
+	// declarations holds the resources and modules, which are deployed in source order
+	declarations []interface{}
+	types        map[string]*bicepType
+}
+
+type bicepDecorator struct {
+	name string
+	args []bicepExpr
+}
+
+type bicepType struct {
+	name      string
+	allowed   []interface{}
+	nullable  bool
+	isArray   bool
+	isLiteral bool
+}
+
+type bicepParam struct {
+	name         string
+	typ          *bicepType
+	defaultValue bicepExpr
+	decorators   []*bicepDecorator
+}
+
+type bicepVar struct {
+	name  string
+	value bicepExpr
+}
+
+// bicepResource is a resource declaration. The body is an object, or an
+// if or for expression wrapping an object.
+type bicepResource struct {
+	name       string
+	typ        string
+	apiVersion string
+	existing   bool
+	body       bicepExpr
+	decorators []*bicepDecorator
+	parent     *bicepResource
+	children   []*bicepResource
+}
+
+type bicepModule struct {
+	name       string
+	path       string
+	body       bicepExpr
+	decorators []*bicepDecorator
+}
+
+type bicepOutput struct {
+	name  string
+	typ   *bicepType
+	value bicepExpr
+}
+
+// bicepExpr is a node in the parsed tree of a Bicep expression.
+type bicepExpr interface{}
+
+type bicepLiteral struct {
+	value interface{}
+}
+
+type bicepInterpolation struct {
+	parts []string
+	exprs []bicepExpr
+}
+
+type bicepIdentifier struct {
+	name string
+}
+
+type bicepCall struct {
+	name string
+	args []bicepExpr
+}
+
+type bicepMember struct {
+	target bicepExpr
+	name   string
+}
+
+type bicepIndex struct {
+	target bicepExpr
+	index  bicepExpr
+}
+
+type bicepMethodCall struct {
+	target bicepExpr
+	name   string
+	args   []bicepExpr
+}
+
+type bicepUnary struct {
+	op      string
+	operand bicepExpr
+}
+
+type bicepBinary struct {
+	op    string
+	left  bicepExpr
+	right bicepExpr
+}
+
+type bicepTernary struct {
+	cond      bicepExpr
+	trueExpr  bicepExpr
+	falseExpr bicepExpr
+}
+
+type bicepArray struct {
+	items []bicepExpr
+}
+
+// bicepProperty is a property of an object. Spread properties (...expr) have no key.
+type bicepProperty struct {
+	key    string
+	value  bicepExpr
+	spread bool
+}
+
+type bicepObject struct {
+	props     []*bicepProperty
+	resources []*bicepResource
+}
+
+type bicepFor struct {
+	item  string
+	index string
+	iter  bicepExpr
+	body  bicepExpr
+}
+
+type bicepIf struct {
+	cond bicepExpr
+	body bicepExpr
+}
+
+type bicepLambda struct {
+	params []string
+	body   bicepExpr
+}
+
+// bicepSkippedKeywords are statements that don't affect the deployed
+// resources, so the parser skips them. User-defined functions and imports
+// do affect them, but aren't supported, so they fail parsing instead.
+var bicepSkippedKeywords = map[string]bool{
+	"metadata":  true,
+	"extension": true,
+	"provider":  true,
+}
+
+type bicepParser struct {
+	tokens []bicepToken
+	pos    int
+
+	// newlines tracks whether new lines are significant in the current bracket,
+	// they aren't inside parentheses but separate the items of objects and arrays.
+	newlines []bool
+}
+
+func parseBicep(src string) (*bicepFile, error) {
+	tokens, err := tokenizeBicep(bicepSource{text: src, line: 1, col: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &bicepParser{tokens: tokens}
+	return p.parseFile()
+}
+
+// parseBicepExpression parses an expression embedded in a string interpolation.
+func parseBicepExpression(src bicepSource) (bicepExpr, error) {
+	tokens, err := tokenizeBicep(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &bicepParser{tokens: tokens, newlines: []bool{false}}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != bicepEOF {
+		return nil, p.unexpected(t)
+	}
+	return expr, nil
+}
+
+func (p *bicepParser) errorf(t bicepToken, format string, args ...interface{}) error {
+	return fmt.Errorf("line %d, column %d: %s", t.line, t.col, fmt.Sprintf(format, args...))
+}
+
+func (p *bicepParser) unexpected(t bicepToken) error {
+	return p.errorf(t, "unexpected %s", t)
+}
+
+func (p *bicepParser) newlinesSignificant() bool {
+	return len(p.newlines) == 0 || p.newlines[len(p.newlines)-1]
+}
+
+// peek returns the next token, skipping new lines where they aren't significant.
+func (p *bicepParser) peek() bicepToken {
+	if !p.newlinesSignificant() {
+		p.skipNewlines()
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *bicepParser) peekAt(offset int) bicepToken {
+	if p.pos+offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+offset]
+}
+
+func (p *bicepParser) next() bicepToken {
+	t := p.peek()
+	if t.kind != bicepEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *bicepParser) skipNewlines() {
+	for p.tokens[p.pos].kind == bicepNewline {
+		p.pos++
+	}
+}
+
+func (p *bicepParser) accept(kind bicepTokenKind, value string) bool {
+	if p.peek().is(kind, value) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *bicepParser) expect(kind bicepTokenKind, value string) (bicepToken, error) {
+	t := p.next()
+	if !t.is(kind, value) {
+		return t, p.errorf(t, "expected '%s' but found %s", value, t)
+	}
+	return t, nil
+}
+
+func (p *bicepParser) expectIdent() (string, error) {
+	t := p.next()
+	if t.kind != bicepIdent {
+		return "", p.errorf(t, "expected an identifier but found %s", t)
+	}
+	return t.value, nil
+}
+
+// expectEndOfStatement consumes the new line or end of file after a statement.
+func (p *bicepParser) expectEndOfStatement() error {
+	t := p.next()
+	if t.kind != bicepNewline && t.kind != bicepEOF {
+		return p.errorf(t, "expected a new line but found %s", t)
+	}
+	return nil
+}
+
+func (p *bicepParser) parseFile() (*bicepFile, error) {
+	f := &bicepFile{
+		targetScope: "resourceGroup",
+		types:       map[string]*bicepType{},
+	}
+
+	var decorators []*bicepDecorator
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.kind == bicepEOF {
+			return f, nil
+		}
+
+		if t.is(bicepOperator, "@") {
+			d, err := p.parseDecorator()
+			if err != nil {
+				return nil, err
+			}
+			decorators = append(decorators, d)
+			continue
+		}
+
+		if t.kind != bicepIdent {
+			return nil, p.unexpected(t)
+		}
+
+		var err error
+		switch t.value {
+		case "targetScope":
+			err = p.parseTargetScope(f)
+		case "param":
+			err = p.parseParam(f, decorators)
+		case "var":
+			err = p.parseVar(f)
+		case "resource":
+			var r *bicepResource
+			r, err = p.parseResource(decorators, nil)
+			if r != nil {
+				f.declarations = append(f.declarations, r)
+			}
+		case "module":
+			err = p.parseModule(f, decorators)
+		case "output":
+			err = p.parseOutput(f)
+		case "type":
+			err = p.parseTypeDeclaration(f)
+		case "using":
+			err = p.parseUsing(f)
+		default:
+			if !bicepSkippedKeywords[t.value] {
+				return nil, p.errorf(t, "unsupported Bicep statement '%s'", t.value)
+			}
+			p.skipStatement()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		decorators = nil
+	}
+}
+
+// skipStatement consumes tokens up to the end of the current statement, which
+// may span lines inside brackets.
+func (p *bicepParser) skipStatement() {
+	depth := 0
+	for {
+		t := p.tokens[p.pos]
+		switch {
+		case t.kind == bicepEOF:
+			return
+		case t.kind == bicepNewline && depth == 0:
+			p.pos++
+			return
+		case t.is(bicepOperator, "("), t.is(bicepOperator, "["), t.is(bicepOperator, "{"):
+			depth++
+		case t.is(bicepOperator, ")"), t.is(bicepOperator, "]"), t.is(bicepOperator, "}"):
+			depth--
+		}
+		p.pos++
+	}
+}
+
+func (p *bicepParser) parseDecorator() (*bicepDecorator, error) {
+	if _, err := p.expect(bicepOperator, "@"); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+	// Decorators can be qualified with the sys namespace, e.g. @sys.description
+	if p.accept(bicepOperator, ".") {
+		if name, err = p.expectIdent(); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &bicepDecorator{name: strings.ToLower(name)}
+	if p.peek().is(bicepOperator, "(") {
+		if d.args, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+func (p *bicepParser) parseTargetScope(f *bicepFile) error {
+	p.next()
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return err
+	}
+
+	t := p.next()
+	if t.kind != bicepString || len(t.exprs) > 0 {
+		return p.errorf(t, "targetScope must be a string")
+	}
+	f.targetScope = t.parts[0]
+
+	return p.expectEndOfStatement()
+}
+
+func (p *bicepParser) parseParam(f *bicepFile, decorators []*bicepDecorator) error {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	// Params in .bicepparam files are assigned a value without a type
+	param := &bicepParam{name: name, decorators: decorators}
+	if !p.peek().is(bicepOperator, "=") {
+		if param.typ, err = p.parseType(); err != nil {
+			return err
+		}
+	}
+
+	if p.accept(bicepOperator, "=") {
+		if param.defaultValue, err = p.parseExpression(); err != nil {
+			return err
+		}
+	}
+
+	f.params = append(f.params, param)
+	return p.expectEndOfStatement()
+}
+
+// parseUsing parses the using statement of a .bicepparam file, e.g. using './main.bicep'
+func (p *bicepParser) parseUsing(f *bicepFile) error {
+	p.next()
+
+	t := p.next()
+	switch {
+	case t.kind == bicepString && len(t.exprs) == 0:
+		f.using = t.parts[0]
+	case t.is(bicepIdent, "none"):
+	default:
+		return p.errorf(t, "expected a template path but found %s", t)
+	}
+
+	// Ignore any extension configuration that follows, e.g. using './main.bicep' with {...}
+	if !p.peek().is(bicepNewline, "\n") && p.peek().kind != bicepEOF {
+		p.skipStatement()
+		return nil
+	}
+
+	return p.expectEndOfStatement()
+}
+
+func (p *bicepParser) parseVar(f *bicepFile) error {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	// Variables can optionally be typed
+	if !p.peek().is(bicepOperator, "=") {
+		if _, err := p.parseType(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return err
+	}
+
+	value, err := p.parseExpression()
+	if err != nil {
+		return err
+	}
+
+	f.vars = append(f.vars, &bicepVar{name: name, value: value})
+	return p.expectEndOfStatement()
+}
+
+func (p *bicepParser) parseOutput(f *bicepFile) error {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	output := &bicepOutput{name: name}
+	if p.peek().is(bicepIdent, "resource") {
+		// Resource typed outputs, e.g. output foo resource 'Microsoft.Foo/bar@2020-01-01' = foo
+		p.next()
+		p.next()
+		output.typ = &bicepType{name: "string"}
+	} else if output.typ, err = p.parseType(); err != nil {
+		return err
+	}
+
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return err
+	}
+	if output.value, err = p.parseExpression(); err != nil {
+		return err
+	}
+
+	f.outputs = append(f.outputs, output)
+	return p.expectEndOfStatement()
+}
+
+func (p *bicepParser) parseTypeDeclaration(f *bicepFile) error {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return err
+	}
+
+	typ, err := p.parseType()
+	if err != nil {
+		return err
+	}
+	f.types[name] = typ
+
+	return p.expectEndOfStatement()
+}
+
+// parseType parses a type expression. Only the parts that affect the ARM
+// parameter type are kept: the base type, allowed literal values, whether
+// it's an array and whether it's nullable.
+func (p *bicepParser) parseType() (*bicepType, error) {
+	typ, err := p.parseSingleType()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept(bicepOperator, "|") {
+		other, err := p.parseSingleType()
+		if err != nil {
+			return nil, err
+		}
+		if typ.isLiteral && other.isLiteral && !typ.isArray && !other.isArray {
+			typ.allowed = append(typ.allowed, other.allowed...)
+		} else {
+			typ.isLiteral = false
+			typ.allowed = nil
+		}
+		typ.nullable = typ.nullable || other.nullable
+	}
+
+	return typ, nil
+}
+
+func (p *bicepParser) parseSingleType() (*bicepType, error) {
+	typ := &bicepType{}
+
+	t := p.next()
+	switch {
+	case t.kind == bicepIdent:
+		typ.name = t.value
+		// Qualified and generic types, e.g. sys.string or resourceInput<'Microsoft.Foo/bar@2020-01-01'>
+		for p.accept(bicepOperator, ".") {
+			name, err := p.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			typ.name = name
+		}
+		if p.peek().is(bicepOperator, "<") {
+			p.skipBalanced("<", ">")
+			typ.name = "object"
+		}
+	case t.kind == bicepString:
+		typ.name = "string"
+		typ.isLiteral = true
+		typ.allowed = []interface{}{strings.Join(t.parts, "")}
+	case t.kind == bicepNumber:
+		n, _ := strconv.ParseInt(t.value, 10, 64)
+		typ.name = "int"
+		typ.isLiteral = true
+		typ.allowed = []interface{}{n}
+	case t.is(bicepOperator, "-") && p.peek().kind == bicepNumber:
+		n, _ := strconv.ParseInt(p.next().value, 10, 64)
+		typ.name = "int"
+		typ.isLiteral = true
+		typ.allowed = []interface{}{-n}
+	case t.is(bicepOperator, "{"):
+		p.pos--
+		p.skipBalanced("{", "}")
+		typ.name = "object"
+	case t.is(bicepOperator, "["):
+		p.pos--
+		p.skipBalanced("[", "]")
+		typ.name = "array"
+	case t.is(bicepOperator, "("):
+		inner, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(bicepOperator, ")"); err != nil {
+			return nil, err
+		}
+		typ = inner
+	default:
+		return nil, p.errorf(t, "expected a type but found %s", t)
+	}
+
+	for {
+		tok := p.tokens[p.pos]
+		switch {
+		case tok.is(bicepOperator, "[") && p.peekAt(1).is(bicepOperator, "]"):
+			p.pos += 2
+			typ.isArray = true
+		case tok.is(bicepOperator, "?"):
+			p.pos++
+			typ.nullable = true
+		default:
+			return typ, nil
+		}
+	}
+}
+
+// skipBalanced consumes tokens from an opening bracket up to the matching closing one.
+func (p *bicepParser) skipBalanced(open, close string) {
+	depth := 0
+	for {
+		t := p.tokens[p.pos]
+		if t.kind == bicepEOF {
+			return
+		}
+		p.pos++
+		if t.is(bicepOperator, open) {
+			depth++
+		} else if t.is(bicepOperator, close) {
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// parseTypeReference parses the 'Type@version' string of a resource declaration.
+func (p *bicepParser) parseTypeReference() (string, string, error) {
+	t := p.next()
+	if t.kind != bicepString || len(t.exprs) > 0 {
+		return "", "", p.errorf(t, "expected a resource type string but found %s", t)
+	}
+
+	typ, apiVersion, _ := strings.Cut(t.parts[0], "@")
+	return typ, apiVersion, nil
+}
+
+// parseResource parses a resource declaration. Resources declared inside the body
+// of another resource are its children.
+func (p *bicepParser) parseResource(decorators []*bicepDecorator, parent *bicepResource) (*bicepResource, error) {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &bicepResource{name: name, decorators: decorators, parent: parent}
+	if r.typ, r.apiVersion, err = p.parseTypeReference(); err != nil {
+		return nil, err
+	}
+
+	if p.accept(bicepIdent, "existing") {
+		r.existing = true
+	}
+
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return nil, err
+	}
+
+	if r.body, err = p.parseDeclarationBody(r); err != nil {
+		return nil, err
+	}
+
+	return r, p.expectEndOfStatement()
+}
+
+func (p *bicepParser) parseModule(f *bicepFile, decorators []*bicepDecorator) error {
+	p.next()
+	name, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+
+	t := p.next()
+	if t.kind != bicepString || len(t.exprs) > 0 {
+		return p.errorf(t, "expected a module path string but found %s", t)
+	}
+
+	m := &bicepModule{name: name, path: t.parts[0], decorators: decorators}
+	if _, err := p.expect(bicepOperator, "="); err != nil {
+		return err
+	}
+
+	if m.body, err = p.parseDeclarationBody(nil); err != nil {
+		return err
+	}
+
+	f.declarations = append(f.declarations, m)
+	return p.expectEndOfStatement()
+}
+
+// parseDeclarationBody parses the body of a resource or module: an object,
+// if (cond) object, or a for loop over either.
+func (p *bicepParser) parseDeclarationBody(r *bicepResource) (bicepExpr, error) {
+	t := p.peek()
+	switch {
+	case t.is(bicepIdent, "if"):
+		return p.parseIfBody(r)
+	case t.is(bicepOperator, "["):
+		p.next()
+		p.newlines = append(p.newlines, false)
+		defer p.popNewlines()
+
+		loop, err := p.parseForHeader()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().is(bicepIdent, "if") {
+			loop.body, err = p.parseIfBody(r)
+		} else {
+			loop.body, err = p.parseObject(r)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := p.expect(bicepOperator, "]"); err != nil {
+			return nil, err
+		}
+		return loop, nil
+	default:
+		return p.parseObject(r)
+	}
+}
+
+func (p *bicepParser) parseIfBody(r *bicepResource) (bicepExpr, error) {
+	p.next()
+	if _, err := p.expect(bicepOperator, "("); err != nil {
+		return nil, err
+	}
+	p.newlines = append(p.newlines, false)
+	cond, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(bicepOperator, ")"); err != nil {
+		return nil, err
+	}
+	p.popNewlines()
+
+	body, err := p.parseObject(r)
+	if err != nil {
+		return nil, err
+	}
+	return &bicepIf{cond: cond, body: body}, nil
+}
+
+// parseForHeader parses the "for item in iter:" part of a loop. The opening
+// bracket must already be consumed.
+func (p *bicepParser) parseForHeader() (*bicepFor, error) {
+	if _, err := p.expect(bicepIdent, "for"); err != nil {
+		return nil, err
+	}
+
+	loop := &bicepFor{}
+	var err error
+	if p.accept(bicepOperator, "(") {
+		if loop.item, err = p.expectIdent(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(bicepOperator, ","); err != nil {
+			return nil, err
+		}
+		if loop.index, err = p.expectIdent(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(bicepOperator, ")"); err != nil {
+			return nil, err
+		}
+	} else if loop.item, err = p.expectIdent(); err != nil {
+		return nil, err
+	}
+
+	if _, err := p.expect(bicepIdent, "in"); err != nil {
+		return nil, err
+	}
+	if loop.iter, err = p.parseExpression(); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(bicepOperator, ":"); err != nil {
+		return nil, err
+	}
+
+	return loop, nil
+}
+
+func (p *bicepParser) popNewlines() {
+	p.newlines = p.newlines[:len(p.newlines)-1]
+}
+
+// parseObject parses an object literal. If r is set, the object is the body of
+// that resource and can declare child resources.
+func (p *bicepParser) parseObject(r *bicepResource) (*bicepObject, error) {
+	if _, err := p.expect(bicepOperator, "{"); err != nil {
+		return nil, err
+	}
+	p.newlines = append(p.newlines, true)
+	defer p.popNewlines()
+
+	obj := &bicepObject{}
+	var decorators []*bicepDecorator
+	for {
+		p.skipNewlines()
+		t := p.peek()
+
+		switch {
+		case t.is(bicepOperator, "}"):
+			p.next()
+			return obj, nil
+		case t.is(bicepOperator, "@"):
+			d, err := p.parseDecorator()
+			if err != nil {
+				return nil, err
+			}
+			decorators = append(decorators, d)
+			continue
+		case t.is(bicepOperator, "..."):
+			p.next()
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			obj.props = append(obj.props, &bicepProperty{value: value, spread: true})
+		case t.is(bicepIdent, "resource") && p.peekAt(1).kind == bicepIdent:
+			if r == nil {
+				return nil, p.errorf(t, "resources can only be declared at the top level or inside another resource")
+			}
+			child, err := p.parseResource(decorators, r)
+			if err != nil {
+				return nil, err
+			}
+			r.children = append(r.children, child)
+			obj.resources = append(obj.resources, child)
+			decorators = nil
+			continue
+		case t.kind == bicepIdent || (t.kind == bicepString && len(t.exprs) == 0):
+			p.next()
+			key := t.value
+			if t.kind == bicepString {
+				key = t.parts[0]
+			}
+			if _, err := p.expect(bicepOperator, ":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			obj.props = append(obj.props, &bicepProperty{key: key, value: value})
+		default:
+			return nil, p.unexpected(t)
+		}
+
+		decorators = nil
+		if err := p.expectItemSeparator("}"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// expectItemSeparator consumes the new line or comma after an object property
+// or array item, unless the closing bracket follows.
+func (p *bicepParser) expectItemSeparator(closing string) error {
+	t := p.tokens[p.pos]
+	switch {
+	case t.kind == bicepNewline, t.is(bicepOperator, ","):
+		p.pos++
+		return nil
+	case t.is(bicepOperator, closing):
+		return nil
+	}
+	return p.errorf(t, "expected a new line or ',' but found %s", t)
+}
+
+func (p *bicepParser) parseArray() (bicepExpr, error) {
+	if _, err := p.expect(bicepOperator, "["); err != nil {
+		return nil, err
+	}
+	p.newlines = append(p.newlines, true)
+	defer p.popNewlines()
+
+	p.skipNewlines()
+	if p.peek().is(bicepIdent, "for") {
+		p.newlines[len(p.newlines)-1] = false
+		loop, err := p.parseForHeader()
+		if err != nil {
+			return nil, err
+		}
+		if loop.body, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(bicepOperator, "]"); err != nil {
+			return nil, err
+		}
+		return loop, nil
+	}
+
+	arr := &bicepArray{}
+	for {
+		p.skipNewlines()
+		if p.accept(bicepOperator, "]") {
+			return arr, nil
+		}
+
+		item, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		arr.items = append(arr.items, item)
+
+		if err := p.expectItemSeparator("]"); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *bicepParser) parseArguments() ([]bicepExpr, error) {
+	if _, err := p.expect(bicepOperator, "("); err != nil {
+		return nil, err
+	}
+	p.newlines = append(p.newlines, false)
+	defer p.popNewlines()
+
+	var args []bicepExpr
+	if p.accept(bicepOperator, ")") {
+		return args, nil
+	}
+
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if p.accept(bicepOperator, ")") {
+			return args, nil
+		}
+		if _, err := p.expect(bicepOperator, ","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *bicepParser) parseExpression() (bicepExpr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.acceptOperatorAcrossLines("?") {
+		return cond, nil
+	}
+
+	trueExpr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptOperatorAcrossLines(":") {
+		return nil, p.errorf(p.peek(), "expected ':' but found %s", p.peek())
+	}
+	falseExpr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return &bicepTernary{cond: cond, trueExpr: trueExpr, falseExpr: falseExpr}, nil
+}
+
+// acceptOperatorAcrossLines accepts an operator that can start a continuation
+// line, e.g. a multi-line ternary.
+func (p *bicepParser) acceptOperatorAcrossLines(op string) bool {
+	i := p.pos
+	for p.tokens[i].kind == bicepNewline {
+		i++
+	}
+	if p.tokens[i].is(bicepOperator, op) {
+		p.pos = i + 1
+		return true
+	}
+	return false
+}
+
+// bicepBinaryOperators are grouped by precedence, lowest first.
+var bicepBinaryOperators = [][]string{
+	{"??"},
+	{"||"},
+	{"&&"},
+	{"==", "!=", "=~", "!~"},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *bicepParser) parseBinary(level int) (bicepExpr, error) {
+	if level == len(bicepBinaryOperators) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.acceptBinaryOperator(bicepBinaryOperators[level])
+		if !ok {
+			return left, nil
+		}
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &bicepBinary{op: op, left: left, right: right}
+	}
+}
+
+func (p *bicepParser) acceptBinaryOperator(ops []string) (string, bool) {
+	t := p.peek()
+	if t.kind != bicepOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.value == op {
+			p.next()
+			// An operator can be followed by a line break
+			p.skipNewlines()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *bicepParser) parseUnary() (bicepExpr, error) {
+	t := p.peek()
+	if t.is(bicepOperator, "!") || t.is(bicepOperator, "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &bicepUnary{op: t.value, operand: operand}, nil
+	}
+
+	return p.parsePostfix()
+}
+
+func (p *bicepParser) parsePostfix() (bicepExpr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		switch {
+		case t.is(bicepOperator, "."), t.is(bicepOperator, ".?"):
+			p.next()
+			name, err := p.expectIdent()
+			if err != nil {
+				return nil, err
+			}
+			if p.peek().is(bicepOperator, "(") {
+				args, err := p.parseArguments()
+				if err != nil {
+					return nil, err
+				}
+				expr = &bicepMethodCall{target: expr, name: name, args: args}
+			} else {
+				expr = &bicepMember{target: expr, name: name}
+			}
+		case t.is(bicepOperator, "["):
+			p.next()
+			p.newlines = append(p.newlines, false)
+			// Safe dereference, e.g. foo[?'bar']
+			p.accept(bicepOperator, "?")
+			index, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(bicepOperator, "]"); err != nil {
+				return nil, err
+			}
+			p.popNewlines()
+			expr = &bicepIndex{target: expr, index: index}
+		case t.is(bicepOperator, "!"):
+			// Non-null assertion
+			p.next()
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *bicepParser) parsePrimary() (bicepExpr, error) {
+	t := p.peek()
+
+	switch {
+	case t.kind == bicepNumber:
+		p.next()
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid integer %s", t.value)
+		}
+		return &bicepLiteral{value: n}, nil
+	case t.kind == bicepString:
+		p.next()
+		return p.parseString(t)
+	case t.kind == bicepIdent:
+		return p.parseIdentifier()
+	case t.is(bicepOperator, "("):
+		if p.isLambda() {
+			return p.parseLambda()
+		}
+		p.next()
+		p.newlines = append(p.newlines, false)
+		defer p.popNewlines()
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(bicepOperator, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case t.is(bicepOperator, "["):
+		return p.parseArray()
+	case t.is(bicepOperator, "{"):
+		return p.parseObject(nil)
+	}
+
+	return nil, p.unexpected(t)
+}
+
+func (p *bicepParser) parseString(t bicepToken) (bicepExpr, error) {
+	if len(t.exprs) == 0 {
+		return &bicepLiteral{value: t.parts[0]}, nil
+	}
+
+	interp := &bicepInterpolation{parts: t.parts}
+	for _, src := range t.exprs {
+		expr, err := parseBicepExpression(src)
+		if err != nil {
+			return nil, err
+		}
+		interp.exprs = append(interp.exprs, expr)
+	}
+	return interp, nil
+}
+
+func (p *bicepParser) parseIdentifier() (bicepExpr, error) {
+	t := p.next()
+
+	switch t.value {
+	case "true":
+		return &bicepLiteral{value: true}, nil
+	case "false":
+		return &bicepLiteral{value: false}, nil
+	case "null":
+		return &bicepLiteral{value: nil}, nil
+	}
+
+	if p.peek().is(bicepOperator, "=>") {
+		p.next()
+		body, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return &bicepLambda{params: []string{t.value}, body: body}, nil
+	}
+
+	if p.peek().is(bicepOperator, "(") {
+		args, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		return &bicepCall{name: t.value, args: args}, nil
+	}
+
+	return &bicepIdentifier{name: t.value}, nil
+}
+
+// isLambda returns true if the parenthesis at the current position starts the
+// parameter list of a lambda, e.g. (a, b) => a + b
+func (p *bicepParser) isLambda() bool {
+	i := p.pos + 1
+	skip := func() {
+		for p.tokens[i].kind == bicepNewline {
+			i++
+		}
+	}
+
+	skip()
+	if !p.tokens[i].is(bicepOperator, ")") {
+		for {
+			skip()
+			if p.tokens[i].kind != bicepIdent {
+				return false
+			}
+			i++
+			skip()
+			if p.tokens[i].is(bicepOperator, ")") {
+				break
+			}
+			if !p.tokens[i].is(bicepOperator, ",") {
+				return false
+			}
+			i++
+		}
+	}
+	i++
+	skip()
+
+	return p.tokens[i].is(bicepOperator, "=>")
+}
+
+func (p *bicepParser) parseLambda() (bicepExpr, error) {
+	p.next()
+	p.newlines = append(p.newlines, false)
+
+	lambda := &bicepLambda{}
+	for !p.accept(bicepOperator, ")") {
+		name, err := p.expectIdent()
+		if err != nil {
+			return nil, err
+		}
+		lambda.params = append(lambda.params, name)
+		p.accept(bicepOperator, ",")
+	}
+	p.popNewlines()
+
+	if _, err := p.expect(bicepOperator, "=>"); err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	lambda.body = body
+
+	return lambda, nil
+}
@@ -0,0 +1,297 @@
+package azurerm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func rawTestValues(t *testing.T, r *EvaluatedResource) gjson.Result {
+	t.Helper()
+
+	v, err := r.RawValues()
+	require.NoError(t, err)
+	return v
+}
+
+func TestParseBicep(t *testing.T) {
+	f, err := parseBicep(`
+targetScope = 'subscription'
+
+@secure()
+param adminPassword string
+param tier 'Basic' | 'Standard' = 'Basic'
+param zones string[]?
+
+var name = 'app' // comment
+/* block
+   comment */
+#disable-next-line no-unused-vars
+var unused = {
+  a: 1, b: [
+    'x'
+  ]
+}
+
+resource rg 'Microsoft.Resources/resourceGroups@2022-09-01' = {
+  name: name
+  location: 'westeurope'
+}
+
+module app 'app.bicep' = if (tier == 'Standard') {
+  name: 'app'
+  scope: rg
+}
+
+output rgName string = rg.name
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, "subscription", f.targetScope)
+
+	require.Len(t, f.params, 3)
+	assert.Equal(t, "secure", f.params[0].decorators[0].name)
+	assert.Equal(t, []interface{}{"Basic", "Standard"}, f.params[1].typ.allowed)
+	assert.True(t, f.params[2].typ.isArray)
+	assert.True(t, f.params[2].typ.nullable)
+
+	require.Len(t, f.vars, 2)
+	require.Len(t, f.declarations, 2)
+
+	rg, ok := f.declarations[0].(*bicepResource)
+	require.True(t, ok)
+	assert.Equal(t, "Microsoft.Resources/resourceGroups", rg.typ)
+	assert.Equal(t, "2022-09-01", rg.apiVersion)
+
+	app, ok := f.declarations[1].(*bicepModule)
+	require.True(t, ok)
+	assert.Equal(t, "app.bicep", app.path)
+	assert.IsType(t, &bicepIf{}, app.body)
+
+	require.Len(t, f.outputs, 1)
+}
+
+func TestParseBicepErrors(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{"param name string = 'unterminated\n", "line 1, column 21: unterminated string"},
+		{"var x = {\n  a: \n}\n", "line 2, column 6: unexpected new line"},
+		{"resource r 'Microsoft.Foo/bar@2020-01-01' = {\n  name: 'r'\n", "line 3, column 1: unexpected end of file"},
+		{"deploy x\n", "line 1, column 1: unsupported Bicep statement 'deploy'"},
+		{"func prefixed(name string) string => 'app-${name}'\n", "line 1, column 1: unsupported Bicep statement 'func'"},
+		{"import { planSku } from 'shared.bicep'\n", "line 1, column 1: unsupported Bicep statement 'import'"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseBicep(tt.src)
+		if assert.Error(t, err, tt.src) {
+			assert.Equal(t, tt.expected, err.Error(), tt.src)
+		}
+	}
+}
+
+func TestCompileBicepExpressions(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"'a-${1 + 2}-b'", "a-3-b"},
+		{"'{literal} ${'x'}'", "{literal} x"},
+		{`'it\'s'`, "it's"},
+		{"10 % 3 * 2", int64(2)},
+		{"-5 + 2", int64(-3)},
+		{"!(1 > 2) && 2 >= 2", true},
+		{"'ABC' =~ 'abc'", true},
+		{"'a' != 'b'", true},
+		{"null ?? 'fallback'", "fallback"},
+		{"true ? 'yes' : 'no'", "yes"},
+		{"[1, 2][1]", int64(2)},
+		{"{a: 1, ...{b: 2}}", map[string]interface{}{"a": int64(1), "b": int64(2)}},
+		{"{}", map[string]interface{}{}},
+		{"any('x')", "x"},
+		{"az.resourceGroup().name", "rg-test"},
+		{"[for i in range(0, 3): i * 2]", []interface{}{int64(0), int64(2), int64(4)}},
+		{"[for (x, i) in ['a', 'b']: '${i}${x}']", []interface{}{"0a", "1b"}},
+		{"filter([1, 2, 3, 4], n => n % 2 == 0)", []interface{}{int64(2), int64(4)}},
+		{"map(items({b: 2, a: 1}), (x) => x.key)", []interface{}{"a", "b"}},
+	}
+
+	e := newTestEvaluator(t, `{"resources": []}`, nil)
+	c := &bicepCompiler{file: &bicepFile{types: map[string]*bicepType{}}, symbols: map[string]interface{}{}}
+
+	for _, tt := range tests {
+		expr, err := parseBicepExpression(bicepSource{text: tt.expr, line: 1, col: 1})
+		require.NoError(t, err, tt.expr)
+
+		s, err := c.compileExpr(expr, bicepEnv{})
+		require.NoError(t, err, tt.expr)
+
+		v, err := e.evaluateExpression("[" + s + "]")
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.expected, v, tt.expr)
+	}
+}
+
+func TestLoadBicepTemplate(t *testing.T) {
+	path := filepath.Join("testdata", "bicep", "main.bicep")
+
+	tmpl, err := loadBicepTemplate(path)
+	require.NoError(t, err)
+	assert.Equal(t, ResourceGroup, detectDeploymentScope(path))
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+	e := NewTemplateEvaluator(tmpl, deployment, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.Type+" "+r.Name)
+	}
+	assert.Equal(t, []string{
+		"Microsoft.Web/serverfarms infracost-plan-prod",
+		"Microsoft.Web/sites infracost-app-0",
+		"Microsoft.Web/sites infracost-app-1",
+		"Microsoft.Network/virtualNetworks infracost-vnet",
+		"Microsoft.Network/virtualNetworks/subnets infracost-vnet/apps",
+		"Microsoft.Network/virtualNetworks/subnets infracost-vnet/data",
+		"Microsoft.Storage/storageAccounts infracoststprod0",
+		"Microsoft.Storage/storageAccounts infracoststprod1",
+		"Microsoft.Web/sites/config infracost-app-0/appsettings",
+	}, names)
+
+	plan := rawTestValues(t, resources[0])
+	assert.Equal(t, "P1v3", plan.Get("sku.name").String())
+	assert.Equal(t, int64(2), plan.Get("sku.capacity").Int())
+	assert.Equal(t, "westeurope", plan.Get("location").String())
+	assert.Equal(t, "prod", plan.Get("tags.environment").String())
+
+	app := rawTestValues(t, resources[1])
+	assert.Equal(t, resources[0].Id, app.Get("properties.serverFarmId").String())
+
+	storage := rawTestValues(t, resources[7])
+	assert.Equal(t, "Standard_GRS", storage.Get("sku.name").String())
+
+	settings := rawTestValues(t, resources[8])
+	assert.Equal(t, resources[6].Id, settings.Get("properties.STORAGE_ID").String())
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/shared-rg/providers/Microsoft.OperationalInsights/workspaces/shared-logs", settings.Get("properties.LOGS_ID").String())
+
+	outputs := e.evaluateOutputs()
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"value": []interface{}{"infracost-app-0", "infracost-app-1"},
+	}, outputs["appNames"])
+}
+
+func TestLoadBicepTemplateUnsupportedStatement(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.bicep")
+	require.NoError(t, os.WriteFile(path, []byte("import { planSku } from 'shared.bicep'\n"), 0600))
+
+	_, err := loadBicepTemplate(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), path)
+		assert.Contains(t, err.Error(), "unsupported Bicep statement 'import'")
+	}
+}
+
+func TestLoadBicepTemplateModuleCycle(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.bicep")
+	require.NoError(t, os.WriteFile(path, []byte("module self 'main.bicep' = {\n  name: 'self'\n}\n"), 0600))
+
+	_, err := loadBicepTemplate(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "references itself")
+	}
+}
+
+func TestLoadBicepTemplateKeyVaultSecret(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.bicep"), []byte(`resource kv 'Microsoft.KeyVault/vaults@2023-02-01' existing = {
+  name: 'kv-shared'
+  scope: resourceGroup('shared-rg')
+}
+
+resource stg 'Microsoft.Storage/storageAccounts@2023-01-01' existing = {
+  name: 'stshared'
+}
+
+module sql 'sql.bicep' = {
+  name: 'sql'
+  params: {
+    adminPassword: kv.getSecret('sqlAdminPassword')
+    sas: stg.listAccountSas('2023-01-01', {signedExpiry: '2030-01-01'})
+  }
+}
+`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sql.bicep"), []byte(`@secure()
+param adminPassword string
+param sas object
+
+resource sql 'Microsoft.Sql/servers@2022-05-01-preview' = {
+  name: 'sql-app'
+  location: 'westeurope'
+  properties: {
+    administratorLogin: 'sqladmin'
+    administratorLoginPassword: adminPassword
+  }
+}
+`), 0600))
+
+	tmpl, err := loadBicepTemplate(filepath.Join(dir, "main.bicep"))
+	require.NoError(t, err)
+
+	require.Len(t, tmpl.Resources, 1)
+	params := tmpl.Resources[0].Get("properties").(map[string]interface{})["parameters"]
+	assert.Equal(t, map[string]interface{}{
+		"adminPassword": map[string]interface{}{
+			"reference": map[string]interface{}{
+				"keyVault":   map[string]interface{}{"id": "[resourceId('shared-rg', 'Microsoft.KeyVault/vaults', 'kv-shared')]"},
+				"secretName": "sqlAdminPassword",
+			},
+		},
+		"sas": map[string]interface{}{
+			"value": "[listAccountSas(resourceId('Microsoft.Storage/storageAccounts', 'stshared'), '2023-01-01', createObject('signedExpiry', '2030-01-01'))]",
+		},
+	}, params)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+	resources, err := NewTemplateEvaluator(tmpl, deployment, nil).Evaluate()
+	require.NoError(t, err)
+
+	require.Len(t, resources, 1)
+	assert.Equal(t, "Microsoft.Sql/servers", resources[0].Type)
+	assert.Equal(t, "", rawTestValues(t, resources[0]).Get("properties.administratorLoginPassword").String())
+}
+
+func TestCompileBicepGetSecretOutsideModuleParams(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.bicep")
+	require.NoError(t, os.WriteFile(path, []byte(`resource kv 'Microsoft.KeyVault/vaults@2023-02-01' existing = {
+  name: 'kv-shared'
+}
+
+output secret string = kv.getSecret('name')
+`), 0600))
+
+	_, err := loadBicepTemplate(path)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "function 'getSecret' can only be used in module params")
+	}
+}
@@ -92,8 +92,95 @@ func getGroupDeploymentArgs(templateFile string, opts *ArmDeploymentOpts) ([]str
 		"--no-pretty-print",
 	}
 
-	if opts.ParameterFile != "" {
-		args = append(args, "--parameters", opts.ParameterFile)
+	if opts.Mode != "" {
+		args = append(args, "--mode", string(opts.Mode))
+	}
+
+	if opts.SubscriptionId != "" {
+		args = append(args, "--subscription", opts.SubscriptionId)
+	}
+
+	for _, f := range opts.ParameterFiles {
+		args = append(args, "--parameters", f)
+	}
+
+	return args, nil
+}
+
+func getSubscriptionDeploymentArgs(templateFile string, opts *ArmDeploymentOpts) ([]string, error) {
+	if opts.Location == "" {
+		return nil, errors.New("Invalid location for subscription scoped deployment")
+	}
+
+	args := []string{
+		"deployment",
+		"sub",
+		"what-if",
+		"--template-file",
+		templateFile,
+		"--location",
+		opts.Location,
+		"--no-pretty-print",
+	}
+
+	if opts.SubscriptionId != "" {
+		args = append(args, "--subscription", opts.SubscriptionId)
+	}
+
+	for _, f := range opts.ParameterFiles {
+		args = append(args, "--parameters", f)
+	}
+
+	return args, nil
+}
+
+func getManagementGroupDeploymentArgs(templateFile string, opts *ArmDeploymentOpts) ([]string, error) {
+	if opts.ManagementGroupId == "" {
+		return nil, errors.New("Invalid management group ID for management group scoped deployment")
+	}
+
+	if opts.Location == "" {
+		return nil, errors.New("Invalid location for management group scoped deployment")
+	}
+
+	args := []string{
+		"deployment",
+		"mg",
+		"what-if",
+		"--template-file",
+		templateFile,
+		"--management-group-id",
+		opts.ManagementGroupId,
+		"--location",
+		opts.Location,
+		"--no-pretty-print",
+	}
+
+	for _, f := range opts.ParameterFiles {
+		args = append(args, "--parameters", f)
+	}
+
+	return args, nil
+}
+
+func getTenantDeploymentArgs(templateFile string, opts *ArmDeploymentOpts) ([]string, error) {
+	if opts.Location == "" {
+		return nil, errors.New("Invalid location for tenant scoped deployment")
+	}
+
+	args := []string{
+		"deployment",
+		"tenant",
+		"what-if",
+		"--template-file",
+		templateFile,
+		"--location",
+		opts.Location,
+		"--no-pretty-print",
+	}
+
+	for _, f := range opts.ParameterFiles {
+		args = append(args, "--parameters", f)
 	}
 
 	return args, nil
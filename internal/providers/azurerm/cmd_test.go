@@ -0,0 +1,61 @@
+package azurerm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeploymentArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		argsFunc func(string, *ArmDeploymentOpts) ([]string, error)
+		opts     *ArmDeploymentOpts
+		expected []string
+	}{
+		{
+			name:     "resource group",
+			argsFunc: getGroupDeploymentArgs,
+			opts:     &ArmDeploymentOpts{ResourceGroup: "rg", Mode: Complete, ParameterFiles: []string{"params.json"}},
+			expected: []string{"deployment", "group", "what-if", "--template-file", "main.json", "--resource-group", "rg", "--no-pretty-print", "--mode", "Complete", "--parameters", "params.json"},
+		},
+		{
+			name:     "subscription",
+			argsFunc: getSubscriptionDeploymentArgs,
+			opts:     &ArmDeploymentOpts{Location: "westeurope", SubscriptionId: "sub-id"},
+			expected: []string{"deployment", "sub", "what-if", "--template-file", "main.json", "--location", "westeurope", "--no-pretty-print", "--subscription", "sub-id"},
+		},
+		{
+			name:     "management group",
+			argsFunc: getManagementGroupDeploymentArgs,
+			opts:     &ArmDeploymentOpts{Location: "westeurope", ManagementGroupId: "mg", ParameterFiles: []string{"params.json"}},
+			expected: []string{"deployment", "mg", "what-if", "--template-file", "main.json", "--management-group-id", "mg", "--location", "westeurope", "--no-pretty-print", "--parameters", "params.json"},
+		},
+		{
+			name:     "tenant",
+			argsFunc: getTenantDeploymentArgs,
+			opts:     &ArmDeploymentOpts{Location: "westeurope"},
+			expected: []string{"deployment", "tenant", "what-if", "--template-file", "main.json", "--location", "westeurope", "--no-pretty-print"},
+		},
+	}
+
+	for _, test := range tests {
+		args, err := test.argsFunc("main.json", test.opts)
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expected, args, test.name)
+	}
+}
+
+func TestDeploymentArgsValidation(t *testing.T) {
+	_, err := getGroupDeploymentArgs("main.json", &ArmDeploymentOpts{})
+	assert.Error(t, err)
+
+	_, err = getSubscriptionDeploymentArgs("main.json", &ArmDeploymentOpts{})
+	assert.Error(t, err)
+
+	_, err = getManagementGroupDeploymentArgs("main.json", &ArmDeploymentOpts{Location: "westeurope"})
+	assert.Error(t, err)
+
+	_, err = getTenantDeploymentArgs("main.json", &ArmDeploymentOpts{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package azurerm
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// orderResources returns the resources of the template in the order ARM deploys them, so the values
+// of reference() are known when a resource is evaluated. A resource is deployed after the resources
+// in its dependsOn and the resources it refers to with reference(). Otherwise resources keep the
+// order they're declared in, which is also the order used for resources with circular dependencies.
+func (e *TemplateEvaluator) orderResources(resources []*TemplateResource) []*TemplateResource {
+	keys := make([]map[string]bool, len(resources))
+	for i, res := range resources {
+		keys[i] = e.resourceKeys(res)
+	}
+
+	deps := make([][]int, len(resources))
+	for i, res := range resources {
+		for _, ref := range e.resourceDependencies(res) {
+			for j, k := range keys {
+				if j != i && (k[ref] || k[strings.ToLower(ref)]) {
+					deps[i] = append(deps[i], j)
+				}
+			}
+		}
+	}
+
+	ordered := make([]*TemplateResource, 0, len(resources))
+	placed := make([]bool, len(resources))
+	for len(ordered) < len(resources) {
+		next := -1
+		for i := range resources {
+			if !placed[i] && allPlaced(deps[i], placed) {
+				next = i
+				break
+			}
+		}
+
+		if next == -1 {
+			var names []string
+			for i, res := range resources {
+				if !placed[i] {
+					names = append(names, describeTemplateResource(res))
+					ordered = append(ordered, res)
+				}
+			}
+			log.Warnf("Circular dependency between ARM template resources %s, evaluating them in the order they're declared in", strings.Join(names, ", "))
+			break
+		}
+
+		placed[next] = true
+		ordered = append(ordered, resources[next])
+	}
+
+	return ordered
+}
+
+func allPlaced(deps []int, placed []bool) bool {
+	for _, d := range deps {
+		if !placed[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceKeys returns the values other resources can use to depend on the resource: its symbolic
+// name, copy loop name, and the name and ID of each resource it deploys, including its child resources.
+// Names and IDs are lowercase since ARM compares them case insensitively.
+func (e *TemplateEvaluator) resourceKeys(res *TemplateResource) map[string]bool {
+	keys := make(map[string]bool)
+	if res.SymbolicName != "" {
+		keys[res.SymbolicName] = true
+	}
+
+	e.addResourceKeys(res, "", "", keys)
+
+	return keys
+}
+
+func (e *TemplateEvaluator) addResourceKeys(res *TemplateResource, parentType, parentName string, keys map[string]bool) {
+	count := int64(1)
+	var copyName string
+	copyObj, isCopy := res.Get("copy").(map[string]interface{})
+	if isCopy {
+		name, _ := getCaseInsensitive(copyObj, "name")
+		if copyName, _ = name.(string); copyName != "" {
+			keys[strings.ToLower(copyName)] = true
+		}
+
+		n, err := e.copyCount(copyObj)
+		if err != nil {
+			return
+		}
+		count = n
+	}
+
+	for i := int64(0); i < count; i++ {
+		if isCopy {
+			e.copies = append(e.copies, &copyLoop{name: copyName, index: i})
+		}
+
+		typeStr, nameStr, ok := e.resourceTypeAndName(res, parentType, parentName)
+
+		if isCopy {
+			e.copies = e.copies[:len(e.copies)-1]
+		}
+
+		if !ok {
+			continue
+		}
+
+		keys[strings.ToLower(nameStr)] = true
+		keys[strings.ToLower(typeStr+"/"+nameStr)] = true
+		if id, err := e.resourceId(typeStr, strings.Split(nameStr, "/")); err == nil {
+			keys[strings.ToLower(id)] = true
+		}
+
+		for _, child := range res.Children() {
+			e.addResourceKeys(child, typeStr, nameStr, keys)
+		}
+	}
+}
+
+// resourceTypeAndName evaluates the type and name of a resource, which are relative to the parent
+// for child resources declared inside their parent.
+func (e *TemplateEvaluator) resourceTypeAndName(res *TemplateResource, parentType, parentName string) (string, string, bool) {
+	t, err := e.evaluateValue(res.Get("type"))
+	if err != nil {
+		return "", "", false
+	}
+	n, err := e.evaluateValue(res.Get("name"))
+	if err != nil {
+		return "", "", false
+	}
+
+	typeStr, _ := t.(string)
+	nameStr, _ := n.(string)
+	if typeStr == "" || nameStr == "" {
+		return "", "", false
+	}
+
+	if parentType != "" && !strings.Contains(typeStr, "/") {
+		typeStr = parentType + "/" + typeStr
+		nameStr = parentName + "/" + nameStr
+	}
+
+	return typeStr, nameStr, true
+}
+
+// resourceDependencies returns the references to the resources that res depends on, from its
+// dependsOn and the reference() calls in its values. Child resources are evaluated with their parent,
+// so their dependencies aren't included. Resources in copy loops are evaluated as their first copy.
+// References that can't be evaluated are ignored.
+func (e *TemplateEvaluator) resourceDependencies(res *TemplateResource) []string {
+	if copyObj, ok := res.Get("copy").(map[string]interface{}); ok {
+		name, _ := getCaseInsensitive(copyObj, "name")
+		s, _ := name.(string)
+		e.copies = append(e.copies, &copyLoop{name: s})
+		defer func() { e.copies = e.copies[:len(e.copies)-1] }()
+	}
+
+	var refs []string
+
+	if dependsOn, ok := res.Get("dependsOn").([]interface{}); ok {
+		for _, d := range dependsOn {
+			if v, err := e.evaluateValue(d); err == nil {
+				if s, ok := v.(string); ok && s != "" {
+					refs = append(refs, s)
+				}
+			}
+		}
+	}
+
+	for k, v := range res.Fields {
+		switch strings.ToLower(k) {
+		case "dependson", "copy", "resources":
+			continue
+		case "properties":
+			// The template of a nested deployment is evaluated separately with its own resources
+			if props, ok := v.(map[string]interface{}); ok {
+				v = withoutKey(props, "template")
+			}
+		}
+
+		refs = append(refs, e.expressionDependencies(v)...)
+	}
+
+	return refs
+}
+
+// expressionDependencies returns the resources referred to by the expressions in v.
+func (e *TemplateEvaluator) expressionDependencies(v interface{}) []string {
+	var refs []string
+
+	switch val := v.(type) {
+	case string:
+		if !isTemplateExpression(val) {
+			return nil
+		}
+
+		s := strings.TrimSpace(val)
+		node, err := parseTemplateExpression(s[1 : len(s)-1])
+		if err != nil {
+			return nil
+		}
+
+		walkCallNodes(node, func(n *callNode) {
+			if !strings.EqualFold(n.name, "reference") || len(n.args) == 0 {
+				return
+			}
+
+			if ref, err := e.evalNode(n.args[0]); err == nil {
+				if s, ok := ref.(string); ok && s != "" {
+					refs = append(refs, s)
+				}
+			}
+		})
+	case map[string]interface{}:
+		for _, item := range val {
+			refs = append(refs, e.expressionDependencies(item)...)
+		}
+	case []interface{}:
+		for _, item := range val {
+			refs = append(refs, e.expressionDependencies(item)...)
+		}
+	}
+
+	return refs
+}
+
+func walkCallNodes(node exprNode, fn func(*callNode)) {
+	switch n := node.(type) {
+	case *callNode:
+		fn(n)
+		for _, a := range n.args {
+			walkCallNodes(a, fn)
+		}
+	case *propertyNode:
+		walkCallNodes(n.target, fn)
+	case *indexNode:
+		walkCallNodes(n.target, fn)
+		walkCallNodes(n.index, fn)
+	}
+}
@@ -0,0 +1,1093 @@
+package azurerm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// Placeholder values used when evaluating a template without access to Azure
+const (
+	defaultSubscriptionId = "00000000-0000-0000-0000-000000000000"
+	defaultTenantId       = "00000000-0000-0000-0000-000000000000"
+	defaultResourceGroup  = "resource-group"
+	defaultDeploymentName = "infracost"
+)
+
+const (
+	deploymentsResourceType    = "Microsoft.Resources/deployments"
+	resourceGroupsResourceType = "Microsoft.Resources/resourceGroups"
+)
+
+// DeploymentContext holds the values ARM would take from the deployment target
+// when evaluating functions such as resourceGroup() and subscription().
+type DeploymentContext struct {
+	Scope             DeploymentScope
+	Mode              DeploymentMode
+	SubscriptionId    string
+	TenantId          string
+	ResourceGroup     string
+	ManagementGroupId string
+	Location          string
+	DeploymentName    string
+}
+
+func NewDeploymentContext(opts *ArmDeploymentOpts) *DeploymentContext {
+	d := &DeploymentContext{
+		Scope:             opts.Scope,
+		Mode:              opts.Mode,
+		SubscriptionId:    opts.SubscriptionId,
+		TenantId:          defaultTenantId,
+		ResourceGroup:     opts.ResourceGroup,
+		ManagementGroupId: opts.ManagementGroupId,
+		Location:          opts.Location,
+		DeploymentName:    defaultDeploymentName,
+	}
+
+	if d.Scope == "" {
+		d.Scope = ResourceGroup
+	}
+	if d.SubscriptionId == "" {
+		d.SubscriptionId = defaultSubscriptionId
+	}
+	if d.ResourceGroup == "" {
+		d.ResourceGroup = defaultResourceGroup
+	}
+	if d.Location == "" {
+		d.Location = DefaultProviderRegion
+	}
+
+	return d
+}
+
+// EvaluatedResource is a template resource with all of its expressions resolved.
+// Values has the same shape as the 'after' object of a WhatIf change.
+type EvaluatedResource struct {
+	SymbolicName string
+	Type         string
+	Name         string
+	Id           string
+	// Address identifies the resource in the output. It's the type and name of the resource,
+	// e.g. "Microsoft.Web/serverfarms/plan", unless it's deployed by a copy loop, e.g. "storageCopy[0]".
+	// Resources of nested deployments are prefixed by their module path, e.g. "module.storage.".
+	Address string
+	Values  map[string]interface{}
+}
+
+// RawValues returns the evaluated resource as JSON, so it can be passed to
+// the same RFuncs that are used for WhatIf results.
+func (r *EvaluatedResource) RawValues() (gjson.Result, error) {
+	b, err := json.Marshal(r.Values)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	return gjson.ParseBytes(b), nil
+}
+
+// TemplateEvaluator resolves the parameters, variables and resources of an ARM template
+// locally, without calling the Azure what-if API.
+type TemplateEvaluator struct {
+	template        *ArmTemplate
+	deployment      *DeploymentContext
+	parameterValues map[string]interface{}
+
+	parameters map[string]interface{}
+	variables  map[string]interface{}
+	evaluating map[string]bool
+
+	resources []*EvaluatedResource
+	parent    *TemplateEvaluator
+	// modulePath is prefixed to the addresses of resources deployed by a nested deployment,
+	// e.g. "module.storage" or "module.network.module.subnets[0]"
+	modulePath string
+
+	// copies is the stack of copy loops being expanded, see copyIndex()
+	copies []*copyLoop
+	// lambdaScopes is the stack of lambda variables being evaluated, see lambdaVariables()
+	lambdaScopes []map[string]interface{}
+	// deploymentOutputs holds the outputs of nested deployments by deployment name
+	deploymentOutputs map[string]map[string]interface{}
+}
+
+type copyLoop struct {
+	name  string
+	index int64
+}
+
+// NewTemplateEvaluator creates an evaluator for template. parameterValues override
+// the defaultValue of the template parameters and may be nil.
+func NewTemplateEvaluator(template *ArmTemplate, deployment *DeploymentContext, parameterValues map[string]interface{}) *TemplateEvaluator {
+	if parameterValues == nil {
+		parameterValues = make(map[string]interface{})
+	}
+
+	return &TemplateEvaluator{
+		template:          template,
+		deployment:        deployment,
+		parameterValues:   parameterValues,
+		parameters:        make(map[string]interface{}),
+		variables:         make(map[string]interface{}),
+		evaluating:        make(map[string]bool),
+		deploymentOutputs: make(map[string]map[string]interface{}),
+	}
+}
+
+// Evaluate returns all resources declared in the template, including nested child resources, in the
+// order they're deployed in. Resources that can't be evaluated are skipped with a warning rather than
+// failing the whole template.
+func (e *TemplateEvaluator) Evaluate() ([]*EvaluatedResource, error) {
+	for name := range e.template.Parameters {
+		if _, err := e.parameter(name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, res := range e.orderResources(e.template.Resources) {
+		e.evaluateResource(res, nil)
+	}
+
+	return e.resources, nil
+}
+
+// evaluateResource evaluates a resource declaration, which can be deployed any number of
+// times depending on its copy loop and condition. Existing resources are only referenced
+// by the template, so they're not evaluated.
+func (e *TemplateEvaluator) evaluateResource(res *TemplateResource, parent *EvaluatedResource) {
+	if existing, ok := res.Get("existing").(bool); ok && existing {
+		return
+	}
+
+	copyObj, ok := res.Get("copy").(map[string]interface{})
+	if !ok {
+		e.evaluateResourceCopy(res, parent)
+		return
+	}
+
+	count, err := e.copyCount(copyObj)
+	if err != nil {
+		log.Warnf("Skipping ARM template resource %s: %s", describeTemplateResource(res), err)
+		return
+	}
+
+	name, _ := getCaseInsensitive(copyObj, "name")
+	nameStr, _ := name.(string)
+	if nameStr == "" {
+		nameStr = res.SymbolicName
+	}
+
+	for i := int64(0); i < count; i++ {
+		e.copies = append(e.copies, &copyLoop{name: nameStr, index: i})
+		e.evaluateResourceCopy(res, parent)
+		e.copies = e.copies[:len(e.copies)-1]
+	}
+}
+
+// maxCopyCount is the maximum number of iterations of a copy loop allowed by ARM.
+const maxCopyCount = 800
+
+// copyCount returns the number of iterations of a copy loop. The mode and batchSize of the loop
+// only change the order resources are deployed in, so they're validated but otherwise ignored.
+func (e *TemplateEvaluator) copyCount(copyObj map[string]interface{}) (int64, error) {
+	raw, ok := getCaseInsensitive(copyObj, "count")
+	if !ok {
+		return 0, errors.New("copy loop has no count")
+	}
+
+	v, err := e.evaluateValue(raw)
+	if err != nil {
+		return 0, errors.Wrap(err, "evaluating copy count")
+	}
+
+	count, ok := toInt(v)
+	if !ok {
+		return 0, fmt.Errorf("copy count must be an integer, got %s", typeName(v))
+	}
+	if count < 0 || count > maxCopyCount {
+		return 0, fmt.Errorf("copy count must be between 0 and %d, got %d", maxCopyCount, count)
+	}
+
+	if rawMode, ok := getCaseInsensitive(copyObj, "mode"); ok {
+		mode, err := e.evaluateValue(rawMode)
+		if err != nil {
+			return 0, errors.Wrap(err, "evaluating copy mode")
+		}
+
+		modeStr, _ := mode.(string)
+		if !strings.EqualFold(modeStr, "serial") && !strings.EqualFold(modeStr, "parallel") {
+			return 0, fmt.Errorf("copy mode must be 'serial' or 'parallel', got %v", mode)
+		}
+	}
+
+	if rawBatchSize, ok := getCaseInsensitive(copyObj, "batchSize"); ok {
+		batchSize, err := e.evaluateValue(rawBatchSize)
+		if err != nil {
+			return 0, errors.Wrap(err, "evaluating copy batchSize")
+		}
+
+		if n, ok := toInt(batchSize); !ok || n < 1 {
+			return 0, fmt.Errorf("copy batchSize must be a positive integer, got %v", batchSize)
+		}
+	}
+
+	return count, nil
+}
+
+// copyAddress returns the address of the resource being deployed by the current copy loops,
+// e.g. "vmCopy[1]" or "vmCopy[1].diskCopy[0]" for a child resource loop in a parent loop.
+func (e *TemplateEvaluator) copyAddress() string {
+	parts := make([]string, 0, len(e.copies))
+	for _, c := range e.copies {
+		parts = append(parts, fmt.Sprintf("%s[%d]", c.name, c.index))
+	}
+	return strings.Join(parts, ".")
+}
+
+func (e *TemplateEvaluator) evaluateResourceCopy(res *TemplateResource, parent *EvaluatedResource) {
+	if cond := res.Get("condition"); cond != nil {
+		v, err := e.evaluateValue(cond)
+		if err != nil {
+			log.Warnf("Skipping ARM template resource %s: evaluating condition: %s", describeTemplateResource(res), err)
+			return
+		}
+
+		if b, ok := toBool(v); ok && !b {
+			return
+		}
+	}
+
+	evaluated, err := e.evaluateResourceValues(res, parent)
+	if err != nil {
+		log.Warnf("Skipping ARM template resource %s: %s", describeTemplateResource(res), err)
+		return
+	}
+
+	if strings.EqualFold(evaluated.Type, deploymentsResourceType) {
+		nested, ok, err := e.evaluateNestedDeployment(res, evaluated)
+		if err != nil {
+			log.Warnf("Skipping nested deployment %s: %s", evaluated.Name, err)
+			return
+		}
+		if ok {
+			e.resources = append(e.resources, nested...)
+			return
+		}
+	}
+
+	e.resources = append(e.resources, evaluated)
+
+	for _, child := range res.Children() {
+		e.evaluateResource(child, evaluated)
+	}
+}
+
+func (e *TemplateEvaluator) evaluateResourceValues(res *TemplateResource, parent *EvaluatedResource) (*EvaluatedResource, error) {
+	values := make(map[string]interface{}, len(res.Fields))
+
+	for k, v := range res.Fields {
+		switch strings.ToLower(k) {
+		case "resources", "dependson", "comments", "copy", "condition", "existing":
+			continue
+		case "properties":
+			// The template of a nested deployment is evaluated separately with its own scope
+			if props, ok := v.(map[string]interface{}); ok {
+				if _, ok := getCaseInsensitive(props, "template"); ok {
+					v = withoutKey(props, "template")
+				}
+			}
+		}
+
+		evaluated, err := e.evaluateValue(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating property '%s'", k)
+		}
+		values[k] = evaluated
+	}
+
+	resourceType, _ := getCaseInsensitive(values, "type")
+	typeStr, ok := resourceType.(string)
+	if !ok || typeStr == "" {
+		return nil, errors.New("missing resource type")
+	}
+
+	name, _ := getCaseInsensitive(values, "name")
+	nameStr, ok := name.(string)
+	if !ok || nameStr == "" {
+		return nil, errors.New("missing resource name")
+	}
+
+	// Child resources declared inside their parent use a type and name relative to the parent
+	if parent != nil && !strings.Contains(typeStr, "/") {
+		typeStr = parent.Type + "/" + typeStr
+		nameStr = parent.Name + "/" + nameStr
+	}
+
+	id, err := e.resourceId(typeStr, strings.Split(nameStr, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	values["type"] = typeStr
+	values["name"] = nameStr
+	values["id"] = id
+	if _, ok := getCaseInsensitive(values, "resourceGroup"); !ok && e.deployment.Scope == ResourceGroup {
+		values["resourceGroup"] = e.deployment.ResourceGroup
+	}
+	if _, ok := getCaseInsensitive(values, "location"); !ok && strings.EqualFold(typeStr, resourceGroupsResourceType) {
+		values["location"] = e.deployment.Location
+	}
+
+	address := resourceAddress(typeStr, nameStr)
+	if _, ok := res.Get("copy").(map[string]interface{}); ok {
+		address = e.copyAddress()
+	}
+	if e.modulePath != "" {
+		address = e.modulePath + "." + address
+	}
+
+	return &EvaluatedResource{
+		SymbolicName: res.SymbolicName,
+		Type:         typeStr,
+		Name:         nameStr,
+		Id:           id,
+		Address:      address,
+		Values:       values,
+	}, nil
+}
+
+// resourceId returns the ID of a resource deployed at the scope of the current deployment.
+func (e *TemplateEvaluator) resourceId(resourceType string, names []string) (string, error) {
+	d := e.deployment
+
+	switch d.Scope {
+	case Subscription:
+		return formatSubscriptionResourceId(d.SubscriptionId, resourceType, names)
+	case ManagementGroup:
+		return formatManagementGroupResourceId(d.ManagementGroupId, resourceType, names)
+	case Tenant:
+		return formatTenantResourceId(resourceType, names)
+	}
+
+	return formatResourceGroupResourceId(d.SubscriptionId, d.ResourceGroup, resourceType, names)
+}
+
+// evaluateNestedDeployment evaluates the template of a Microsoft.Resources/deployments resource,
+// which is how subscription scoped templates (and Bicep modules) deploy resources into resource groups.
+// The template is either inline or a templateLink to a local file. It returns false if the deployment
+// can't be expanded, in which case it is kept as a single resource.
+func (e *TemplateEvaluator) evaluateNestedDeployment(res *TemplateResource, deployment *EvaluatedResource) ([]*EvaluatedResource, bool, error) {
+	evaluatedProps, _ := getCaseInsensitive(deployment.Values, "properties")
+	evaluatedPropsObj, _ := evaluatedProps.(map[string]interface{})
+
+	var template *ArmTemplate
+	outer := false
+
+	props, _ := res.Get("properties").(map[string]interface{})
+	rawTemplate, _ := getCaseInsensitive(props, "template")
+	if templateObj, ok := rawTemplate.(map[string]interface{}); ok {
+		template = newArmTemplate(templateObj)
+		template.path = e.template.path
+		outer = nestedExpressionScope(evaluatedPropsObj) != "inner"
+	} else if link, ok := getCaseInsensitive(evaluatedPropsObj, "templateLink"); ok {
+		path, ok := e.linkedFilePath(link)
+		if !ok {
+			log.Warnf("Nested deployment %s links to a template that is not a local file, its resources will not be included in the estimate", deployment.Name)
+			return nil, false, nil
+		}
+
+		for ev := e; ev != nil; ev = ev.parent {
+			if ev.template.path != "" && filepath.Clean(ev.template.path) == path {
+				return nil, true, fmt.Errorf("linked template %s references itself", path)
+			}
+		}
+
+		var err error
+		template, err = loadArmTemplate(path)
+		if err != nil {
+			return nil, true, err
+		}
+	} else {
+		log.Debugf("Nested deployment %s has no template, it will not be expanded", deployment.Name)
+		return nil, false, nil
+	}
+
+	var nested *TemplateEvaluator
+	if outer {
+		// Expressions in a template with outer scope use the parameters and variables of the parent template
+		scoped := *e.template
+		scoped.Resources = template.Resources
+		scoped.Outputs = template.Outputs
+
+		nested = NewTemplateEvaluator(&scoped, e.nestedDeploymentContext(deployment), e.parameterValues)
+		nested.parameters = e.parameters
+		nested.variables = e.variables
+	} else {
+		params, err := e.nestedDeploymentParameters(evaluatedPropsObj)
+		if err != nil {
+			return nil, true, err
+		}
+
+		nested = NewTemplateEvaluator(template, e.nestedDeploymentContext(deployment), params)
+	}
+	nested.parent = e
+	nested.modulePath = e.moduleAddress(res, deployment)
+
+	resources, err := nested.Evaluate()
+	if err != nil {
+		return nil, true, err
+	}
+
+	e.deploymentOutputs[strings.ToLower(deployment.Name)] = nested.evaluateOutputs()
+
+	return resources, true, nil
+}
+
+// nestedDeploymentParameters returns the parameter values passed to a nested deployment with
+// inner scope, from its parametersLink and parameters.
+func (e *TemplateEvaluator) nestedDeploymentParameters(props map[string]interface{}) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+
+	if link, ok := getCaseInsensitive(props, "parametersLink"); ok {
+		path, ok := e.linkedFilePath(link)
+		if !ok {
+			return nil, errors.New("parametersLink is not a local file")
+		}
+
+		linked, err := loadParametersJSONFile(path)
+		if err != nil {
+			return nil, err
+		}
+		mergeParameterValues(params, linked)
+	}
+
+	rawParams, _ := getCaseInsensitive(props, "parameters")
+	paramsObj, _ := rawParams.(map[string]interface{})
+	for name, p := range paramsObj {
+		if pObj, ok := p.(map[string]interface{}); ok {
+			if v, err := parameterValue(pObj); err == nil {
+				mergeParameterValues(params, map[string]interface{}{name: v})
+			}
+		}
+	}
+
+	return params, nil
+}
+
+// linkedFilePath returns the local path of a templateLink or parametersLink. Links with a
+// relativePath, or a uri without a scheme, are resolved against the path of the linking template.
+// Links to URLs and template specs can't be read, so they return false.
+func (e *TemplateEvaluator) linkedFilePath(link interface{}) (string, bool) {
+	linkObj, _ := link.(map[string]interface{})
+
+	var rel string
+	if v, ok := getCaseInsensitive(linkObj, "relativePath"); ok {
+		rel, _ = v.(string)
+	} else if v, ok := getCaseInsensitive(linkObj, "uri"); ok {
+		uri, _ := v.(string)
+		if strings.HasPrefix(strings.ToLower(uri), "file://") {
+			return filepath.Clean(filepath.FromSlash(uri[len("file://"):])), true
+		}
+		if strings.Contains(uri, "://") {
+			return "", false
+		}
+		rel = uri
+	}
+
+	if rel == "" {
+		return "", false
+	}
+
+	path := filepath.FromSlash(rel)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(e.template.path), path)
+	}
+
+	return filepath.Clean(path), true
+}
+
+// moduleAddress returns the module path of the resources deployed by a nested deployment,
+// which is named after the deployment, or its copy loop address if it's deployed by one.
+func (e *TemplateEvaluator) moduleAddress(res *TemplateResource, deployment *EvaluatedResource) string {
+	name := deployment.Name
+	if _, ok := res.Get("copy").(map[string]interface{}); ok {
+		name = e.copyAddress()
+	}
+
+	if e.modulePath == "" {
+		return "module." + name
+	}
+	return e.modulePath + ".module." + name
+}
+
+// evaluateOutputs returns the outputs of the template in the shape of the outputs
+// of a deployment, e.g. {"name": {"type": "string", "value": "app"}}. Outputs that
+// can't be evaluated are left out, since they're usually runtime values.
+func (e *TemplateEvaluator) evaluateOutputs() map[string]interface{} {
+	outputs := make(map[string]interface{}, len(e.template.Outputs))
+
+	for name, raw := range e.template.Outputs {
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		rawValue, _ := getCaseInsensitive(obj, "value")
+		v, err := e.evaluateValue(rawValue)
+		if err != nil {
+			log.Debugf("Could not evaluate output '%s': %s", name, err)
+			continue
+		}
+
+		outputType, _ := getCaseInsensitive(obj, "type")
+		outputs[name] = map[string]interface{}{
+			"type":  outputType,
+			"value": v,
+		}
+	}
+
+	return outputs
+}
+
+// lookupDeploymentOutputs finds the outputs of a nested deployment evaluated earlier
+// by the deployment name or resource ID.
+func (e *TemplateEvaluator) lookupDeploymentOutputs(ref string) (map[string]interface{}, bool) {
+	name := strings.ToLower(ref)
+	if idx := strings.LastIndex(name, "/microsoft.resources/deployments/"); idx >= 0 {
+		name = name[idx+len("/microsoft.resources/deployments/"):]
+	}
+
+	for ev := e; ev != nil; ev = ev.parent {
+		if outputs, ok := ev.deploymentOutputs[name]; ok {
+			return outputs, true
+		}
+	}
+
+	return nil, false
+}
+
+// nestedDeploymentContext returns the target of a nested deployment, which can be a
+// different resource group or subscription to the parent deployment.
+func (e *TemplateEvaluator) nestedDeploymentContext(deployment *EvaluatedResource) *DeploymentContext {
+	d := *e.deployment
+	d.DeploymentName = deployment.Name
+
+	if sub, ok := getCaseInsensitive(deployment.Values, "subscriptionId"); ok {
+		if s, ok := sub.(string); ok && s != "" {
+			d.SubscriptionId = s
+			if d.Scope != ResourceGroup {
+				d.Scope = Subscription
+			}
+		}
+	}
+
+	if loc, ok := getCaseInsensitive(deployment.Values, "location"); ok {
+		if l, ok := loc.(string); ok && l != "" {
+			d.Location = l
+		}
+	}
+
+	rg, _ := getCaseInsensitive(deployment.Values, "resourceGroup")
+	if s, ok := rg.(string); ok && s != "" {
+		d.Scope = ResourceGroup
+		d.ResourceGroup = s
+
+		// resourceGroup().location resolves to the location of a resource group created by the parent deployment
+		if group := e.lookupResourceGroup(s); group != nil {
+			if loc, ok := getCaseInsensitive(group.Values, "location"); ok {
+				if l, ok := loc.(string); ok && l != "" {
+					d.Location = l
+				}
+			}
+		}
+	}
+
+	return &d
+}
+
+func nestedExpressionScope(props map[string]interface{}) string {
+	opts, ok := getCaseInsensitive(props, "expressionEvaluationOptions")
+	if !ok {
+		return "outer"
+	}
+
+	optsObj, _ := opts.(map[string]interface{})
+	scope, _ := getCaseInsensitive(optsObj, "scope")
+	if s, ok := scope.(string); ok {
+		return strings.ToLower(s)
+	}
+
+	return "outer"
+}
+
+// lookupResourceGroup finds a resource group declared by this deployment or any of its parents.
+func (e *TemplateEvaluator) lookupResourceGroup(name string) *EvaluatedResource {
+	for ev := e; ev != nil; ev = ev.parent {
+		for _, r := range ev.resources {
+			if strings.EqualFold(r.Type, resourceGroupsResourceType) && strings.EqualFold(r.Name, name) {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+// lookupResource finds a resource evaluated earlier by resource ID, name or symbolic name.
+func (e *TemplateEvaluator) lookupResource(ref string) *EvaluatedResource {
+	for ev := e; ev != nil; ev = ev.parent {
+		for _, r := range ev.resources {
+			if strings.EqualFold(r.Id, ref) || strings.EqualFold(r.Name, ref) || (r.SymbolicName != "" && r.SymbolicName == ref) {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+func (e *TemplateEvaluator) parameter(name string) (interface{}, error) {
+	key := strings.ToLower(name)
+	if v, ok := e.parameters[key]; ok {
+		return v, nil
+	}
+
+	var param *TemplateParameter
+	var paramName string
+	for n, p := range e.template.Parameters {
+		if strings.EqualFold(n, name) {
+			param, paramName = p, n
+			break
+		}
+	}
+	if param == nil {
+		return nil, fmt.Errorf("parameter '%s' is not defined in the template", name)
+	}
+
+	if v, ok := getCaseInsensitive(e.parameterValues, paramName); ok {
+		if ref, ok := v.(*keyVaultReference); ok {
+			v = placeholderParameterValue(param)
+			log.Warnf("ARM template parameter '%s' references Key Vault secret '%s', using placeholder value %v", paramName, ref.SecretName, toTemplateString(v))
+		}
+		e.parameters[key] = v
+		return v, nil
+	}
+
+	guard := "parameters:" + key
+	if e.evaluating[guard] {
+		return nil, fmt.Errorf("circular reference in parameter '%s'", name)
+	}
+	e.evaluating[guard] = true
+	defer delete(e.evaluating, guard)
+
+	var value interface{}
+	if param.HasDefault {
+		v, err := e.evaluateValue(param.DefaultValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating default value of parameter '%s'", name)
+		}
+		value = v
+	} else {
+		value = placeholderParameterValue(param)
+		log.Warnf("ARM template parameter '%s' has no value or default, using placeholder value %v", paramName, toTemplateString(value))
+	}
+
+	e.parameters[key] = value
+	return value, nil
+}
+
+// placeholderParameterValue returns a value for a parameter that has not been provided,
+// preferring the first allowed value since that's usually a valid SKU or tier.
+func placeholderParameterValue(param *TemplateParameter) interface{} {
+	if len(param.AllowedValues) > 0 {
+		return param.AllowedValues[0]
+	}
+
+	switch strings.ToLower(param.Type) {
+	case "int":
+		return int64(0)
+	case "bool":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object", "secureobject":
+		return map[string]interface{}{}
+	}
+
+	return ""
+}
+
+func (e *TemplateEvaluator) variable(name string) (interface{}, error) {
+	key := strings.ToLower(name)
+	if v, ok := e.variables[key]; ok {
+		return v, nil
+	}
+
+	raw, ok := getCaseInsensitive(e.template.Variables, name)
+	copyObj, isCopy := e.variableCopy(name)
+	if !ok && !isCopy {
+		return nil, fmt.Errorf("variable '%s' is not defined in the template", name)
+	}
+
+	guard := "variables:" + key
+	if e.evaluating[guard] {
+		return nil, fmt.Errorf("circular reference in variable '%s'", name)
+	}
+	e.evaluating[guard] = true
+	defer delete(e.evaluating, guard)
+
+	var v interface{}
+	var err error
+	if ok {
+		v, err = e.evaluateValue(raw)
+	} else {
+		_, v, err = e.evaluateCopy(copyObj)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluating variable '%s'", name)
+	}
+
+	e.variables[key] = v
+	return v, nil
+}
+
+// variableCopy returns the copy loop that declares the variable name, e.g.
+// "variables": {"copy": [{"name": "disks", "count": 2, "input": {...}}]}.
+func (e *TemplateEvaluator) variableCopy(name string) (map[string]interface{}, bool) {
+	rawCopies, _ := getCaseInsensitive(e.template.Variables, "copy")
+	copies, _ := propertyCopies("copy", rawCopies)
+
+	for _, copyObj := range copies {
+		copyName, _ := getCaseInsensitive(copyObj, "name")
+		if s, ok := copyName.(string); ok && strings.EqualFold(s, name) {
+			return copyObj, true
+		}
+	}
+
+	return nil, false
+}
+
+// propertyCopies returns the copy loops of a "copy" property, which builds an array property
+// or variable for each loop from its input, e.g. the data disks of a virtual machine.
+func propertyCopies(key string, v interface{}) ([]map[string]interface{}, bool) {
+	if !strings.EqualFold(key, "copy") {
+		return nil, false
+	}
+
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	copies := make([]map[string]interface{}, 0, len(arr))
+	for _, item := range arr {
+		copyObj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if _, ok := getCaseInsensitive(copyObj, "input"); !ok {
+			return nil, false
+		}
+		copies = append(copies, copyObj)
+	}
+
+	return copies, true
+}
+
+func (e *TemplateEvaluator) evaluatePropertyCopies(copies []map[string]interface{}, out map[string]interface{}) error {
+	for _, copyObj := range copies {
+		name, values, err := e.evaluateCopy(copyObj)
+		if err != nil {
+			return err
+		}
+		out[name] = values
+	}
+
+	return nil
+}
+
+// evaluateCopy returns the name of a property or variable copy loop and the array of its evaluated input.
+func (e *TemplateEvaluator) evaluateCopy(copyObj map[string]interface{}) (string, []interface{}, error) {
+	name, _ := getCaseInsensitive(copyObj, "name")
+	nameStr, ok := name.(string)
+	if !ok || nameStr == "" {
+		return "", nil, errors.New("copy loop has no name")
+	}
+
+	count, err := e.copyCount(copyObj)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "copy loop '%s'", nameStr)
+	}
+
+	input, _ := getCaseInsensitive(copyObj, "input")
+	values := make([]interface{}, 0, count)
+
+	for i := int64(0); i < count; i++ {
+		e.copies = append(e.copies, &copyLoop{name: nameStr, index: i})
+		v, err := e.evaluateValue(input)
+		e.copies = e.copies[:len(e.copies)-1]
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "copy loop '%s'", nameStr)
+		}
+		values = append(values, v)
+	}
+
+	return nameStr, values, nil
+}
+
+// evaluateValue walks a JSON value and evaluates any template expressions it contains.
+func (e *TemplateEvaluator) evaluateValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		if !isTemplateExpression(val) {
+			return unescapeTemplateLiteral(val), nil
+		}
+		return e.evaluateExpression(val)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if copies, ok := propertyCopies(k, item); ok {
+				if err := e.evaluatePropertyCopies(copies, out); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			evaluated, err := e.evaluateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = evaluated
+		}
+
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			evaluated, err := e.evaluateValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = evaluated
+		}
+		return out, nil
+	}
+
+	return v, nil
+}
+
+func (e *TemplateEvaluator) evaluateExpression(s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	node, err := parseTemplateExpression(s[1 : len(s)-1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing expression %s", s)
+	}
+
+	v, err := e.evalNode(node)
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluating expression %s", s)
+	}
+
+	return v, nil
+}
+
+func (e *TemplateEvaluator) evalNode(node exprNode) (interface{}, error) {
+	switch n := node.(type) {
+	case *literalNode:
+		return n.value, nil
+	case *callNode:
+		return e.evalCall(n)
+	case *propertyNode:
+		target, err := e.evalNode(n.target)
+		if err != nil {
+			return nil, err
+		}
+		return accessProperty(target, n.property)
+	case *indexNode:
+		target, err := e.evalNode(n.target)
+		if err != nil {
+			return nil, err
+		}
+		idx, err := e.evalNode(n.index)
+		if err != nil {
+			return nil, err
+		}
+		return accessIndex(target, idx)
+	}
+
+	return nil, fmt.Errorf("unknown expression node %T", node)
+}
+
+func (e *TemplateEvaluator) evalCall(n *callNode) (interface{}, error) {
+	name := strings.ToLower(n.name)
+
+	// if() only evaluates the branch that is selected, since the other branch
+	// commonly references values that don't exist for this deployment.
+	if name == "if" {
+		if len(n.args) != 3 {
+			return nil, fmt.Errorf("function 'if' expects 3 arguments, got %d", len(n.args))
+		}
+		cond, err := e.evalNode(n.args[0])
+		if err != nil {
+			return nil, err
+		}
+		b, ok := toBool(cond)
+		if !ok {
+			return nil, fmt.Errorf("function 'if' expects a bool condition, got %s", typeName(cond))
+		}
+		if b {
+			return e.evalNode(n.args[1])
+		}
+		return e.evalNode(n.args[2])
+	}
+
+	if name == "lambda" {
+		return e.evalLambda(n)
+	}
+
+	fn, ok := templateFunctions[name]
+	if !ok {
+		if strings.HasPrefix(name, "list") {
+			fn = fnList
+		} else {
+			return nil, fmt.Errorf("unsupported template function '%s'", n.name)
+		}
+	}
+
+	args := make([]interface{}, len(n.args))
+	for i, a := range n.args {
+		v, err := e.evalNode(a)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	return fn(e, args)
+}
+
+// accessProperty returns the property of an object. Missing properties evaluate to null
+// instead of failing, since they're often runtime values that aren't known offline.
+func accessProperty(target interface{}, property string) (interface{}, error) {
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		v, _ := getCaseInsensitive(t, property)
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("can't access property '%s' of %s", property, typeName(target))
+}
+
+func accessIndex(target interface{}, idx interface{}) (interface{}, error) {
+	switch t := target.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		i, ok := toInt(idx)
+		if !ok {
+			return nil, fmt.Errorf("array index must be an integer, got %s", typeName(idx))
+		}
+		if i < 0 || i >= int64(len(t)) {
+			return nil, fmt.Errorf("array index %d is out of range", i)
+		}
+		return t[i], nil
+	case map[string]interface{}:
+		v, _ := getCaseInsensitive(t, toTemplateString(idx))
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("can't index into %s", typeName(target))
+}
+
+// formatProviderPath builds the '/providers/...' part of a resource ID, interleaving the
+// resource type segments with the resource names, e.g. Microsoft.Sql/servers/databases
+// with [srv, db] becomes /providers/Microsoft.Sql/servers/srv/databases/db.
+func formatProviderPath(resourceType string, names []string) (string, error) {
+	parts := strings.Split(strings.Trim(resourceType, "/"), "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid resource type '%s'", resourceType)
+	}
+
+	// A name can hold several segments, e.g. resourceId('Microsoft.Network/virtualNetworks/subnets', 'vnet/subnet')
+	var segments []string
+	for _, n := range names {
+		segments = append(segments, strings.Split(n, "/")...)
+	}
+	names = segments
+
+	types := parts[1:]
+	if len(types) != len(names) {
+		return "", fmt.Errorf("resource type '%s' expects %d name segment(s), got %d", resourceType, len(types), len(names))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("/providers/")
+	sb.WriteString(parts[0])
+	for i, t := range types {
+		sb.WriteString("/")
+		sb.WriteString(t)
+		sb.WriteString("/")
+		sb.WriteString(names[i])
+	}
+
+	return sb.String(), nil
+}
+
+func formatSubscriptionResourceId(subscriptionId, resourceType string, names []string) (string, error) {
+	// Resource groups are addressed without the provider namespace
+	if strings.EqualFold(resourceType, resourceGroupsResourceType) && len(names) == 1 {
+		return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", subscriptionId, names[0]), nil
+	}
+
+	providerPath, err := formatProviderPath(resourceType, names)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/subscriptions/%s%s", subscriptionId, providerPath), nil
+}
+
+func formatManagementGroupResourceId(managementGroupId, resourceType string, names []string) (string, error) {
+	providerPath, err := formatProviderPath(resourceType, names)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s%s", managementGroupId, providerPath), nil
+}
+
+func formatTenantResourceId(resourceType string, names []string) (string, error) {
+	return formatProviderPath(resourceType, names)
+}
+
+func formatResourceGroupResourceId(subscriptionId, resourceGroup, resourceType string, names []string) (string, error) {
+	providerPath, err := formatProviderPath(resourceType, names)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s%s", subscriptionId, resourceGroup, providerPath), nil
+}
+
+func describeTemplateResource(res *TemplateResource) string {
+	if res.SymbolicName != "" {
+		return res.SymbolicName
+	}
+
+	t, _ := res.Get("type").(string)
+	n, _ := res.Get("name").(string)
+	return fmt.Sprintf("%s '%s'", t, n)
+}
+
+func withoutKey(obj map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if !strings.EqualFold(k, key) {
+			out[k] = v
+		}
+	}
+	return out
+}
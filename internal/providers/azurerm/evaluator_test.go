@@ -0,0 +1,501 @@
+package azurerm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvaluator(t *testing.T, template string, params map[string]interface{}) *TemplateEvaluator {
+	t.Helper()
+
+	tmpl, err := parseArmTemplate([]byte(template))
+	require.NoError(t, err)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+
+	return NewTemplateEvaluator(tmpl, deployment, params)
+}
+
+func TestEvaluateExpressions(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"parameters": {
+			"name": {"type": "string", "defaultValue": "app"},
+			"count": {"type": "int", "defaultValue": 3},
+			"location": {"type": "string", "defaultValue": "[resourceGroup().location]"}
+		},
+		"variables": {
+			"planName": "[format('plan-{0}', parameters('name'))]",
+			"settings": {"sku": {"name": "P1v2"}, "zones": ["1", "2"]}
+		},
+		"resources": []
+	}`, nil)
+
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"[parameters('name')]", "app"},
+		{"[parameters('location')]", "westeurope"},
+		{"[variables('planName')]", "plan-app"},
+		{"[variables('settings').sku.name]", "P1v2"},
+		{"[variables('settings').zones[1]]", "2"},
+		{"[concat('a', '-', parameters('count'))]", "a-3"},
+		{"[concat(createArray(1), createArray(2))]", []interface{}{int64(1), int64(2)}},
+		{"[format('{0}-{1:D3}', 'vm', 7)]", "vm-007"},
+		{"[toUpper(parameters('name'))]", "APP"},
+		{"[add(parameters('count'), -1)]", int64(2)},
+		{"[if(equals(parameters('name'), 'app'), 'yes', parameters('missing'))]", "yes"},
+		{"[length(variables('settings').zones)]", int64(2)},
+		{"[resourceGroup().name]", "rg-test"},
+		{"[resourceId('Microsoft.Web/serverfarms', variables('planName'))]", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Web/serverfarms/plan-app"},
+		{"[resourceId('other-rg', 'Microsoft.Sql/servers/databases', 'srv', 'db')]", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/other-rg/providers/Microsoft.Sql/servers/srv/databases/db"},
+		{"[string(true)]", "True"},
+		{"['it''s']", "it's"},
+		{"[[not an expression]", "[not an expression]"},
+	}
+
+	for _, test := range tests {
+		actual, err := e.evaluateValue(test.expr)
+		require.NoError(t, err, test.expr)
+		assert.Equal(t, test.expected, actual, test.expr)
+	}
+}
+
+func TestEvaluateExpressionErrors(t *testing.T) {
+	e := newTestEvaluator(t, `{"variables": {"a": "[variables('b')]", "b": "[variables('a')]"}}`, nil)
+
+	tests := []string{
+		"[parameters('missing')]",
+		"[variables('a')]",
+		"[unknownFunction()]",
+		"[concat('a']",
+	}
+
+	for _, expr := range tests {
+		_, err := e.evaluateValue(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestEvaluateUtcNowAndNewGuidDefaults(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"parameters": {
+			"deployedAt": {"type": "string", "defaultValue": "[utcNow()]"},
+			"deployedOn": {"type": "string", "defaultValue": "[utcNow('yyyy-MM-dd')]"},
+			"deploymentId": {"type": "string", "defaultValue": "[newGuid()]"}
+		},
+		"resources": [
+			{
+				"type": "Microsoft.Storage/storageAccounts",
+				"name": "[concat('st', uniqueString(parameters('deploymentId')))]",
+				"sku": {"name": "Standard_LRS"},
+				"tags": {"deployedAt": "[parameters('deployedAt')]", "deployedOn": "[parameters('deployedOn')]"}
+			}
+		]
+	}`, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+
+	values := rawTestValues(t, resources[0])
+	assert.Equal(t, "Standard_LRS", values.Get("sku.name").String())
+	assert.Equal(t, "20000101T000000Z", values.Get("tags.deployedAt").String())
+	assert.Equal(t, "2000-01-01", values.Get("tags.deployedOn").String())
+
+	id, err := e.parameter("deploymentId")
+	require.NoError(t, err)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", id)
+}
+
+func TestEvaluateTemplateResources(t *testing.T) {
+	tmpl, err := loadArmTemplate(filepath.Join("testdata", "azuredeploy.group.json"))
+	require.NoError(t, err)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+
+	resources, err := NewTemplateEvaluator(tmpl, deployment, map[string]interface{}{"sku": "P1v2"}).Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	plan, err := resources[0].RawValues()
+	require.NoError(t, err)
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp", plan.Get("id").String())
+	assert.Equal(t, "westeurope", plan.Get("location").String())
+	assert.Equal(t, "P1v2", plan.Get("sku.name").String())
+
+	site, err := resources[1].RawValues()
+	require.NoError(t, err)
+	assert.Equal(t, "AzureLinuxApp-webapp", site.Get("name").String())
+	assert.Equal(t, plan.Get("id").String(), site.Get("properties.serverFarmId").String())
+}
+
+func TestEvaluateChildResources(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"resources": [
+			{
+				"type": "Microsoft.Sql/servers",
+				"name": "srv",
+				"location": "[resourceGroup().location]",
+				"resources": [
+					{"type": "databases", "name": "db", "location": "[resourceGroup().location]", "sku": {"name": "S0"}}
+				]
+			}
+		]
+	}`, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "Microsoft.Sql/servers/databases", resources[1].Type)
+	assert.Equal(t, "srv/db", resources[1].Name)
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Sql/servers/srv/databases/db", resources[1].Id)
+}
+
+func TestEvaluateDependencyOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+	}{
+		{"dependsOn", `{
+			"resources": [
+				{
+					"type": "Microsoft.Web/sites",
+					"name": "app",
+					"dependsOn": ["[resourceId('Microsoft.Web/serverfarms', 'plan')]"],
+					"properties": {"reserved": "[reference(resourceId('Microsoft.Web/serverfarms', 'plan')).reserved]"}
+				},
+				{"type": "Microsoft.Web/serverfarms", "name": "plan", "properties": {"reserved": true}}
+			]
+		}`},
+		{"implicit reference", `{
+			"resources": [
+				{
+					"type": "Microsoft.Web/sites",
+					"name": "app",
+					"properties": {"reserved": "[reference('plan').reserved]"}
+				},
+				{"type": "Microsoft.Web/serverfarms", "name": "plan", "properties": {"reserved": true}}
+			]
+		}`},
+		{"symbolic name", `{
+			"languageVersion": "2.0",
+			"resources": {
+				"app": {
+					"type": "Microsoft.Web/sites",
+					"name": "app",
+					"properties": {"reserved": "[reference('plan').reserved]"}
+				},
+				"plan": {"type": "Microsoft.Web/serverfarms", "name": "plan", "properties": {"reserved": true}}
+			}
+		}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resources, err := newTestEvaluator(t, test.template, nil).Evaluate()
+			require.NoError(t, err)
+			require.Len(t, resources, 2)
+
+			assert.Equal(t, "plan", resources[0].Name)
+			assert.Equal(t, "app", resources[1].Name)
+			assert.Equal(t, true, rawTestValues(t, resources[1]).Get("properties.reserved").Bool())
+		})
+	}
+}
+
+func TestEvaluateVirtualNetworkPeerings(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"variables": {
+			"hubId": "[resourceId('Microsoft.Network/virtualNetworks', 'hub')]",
+			"spokeId": "[resourceId('Microsoft.Network/virtualNetworks', 'spoke')]"
+		},
+		"resources": [
+			{
+				"type": "Microsoft.Network/virtualNetworks",
+				"name": "hub",
+				"properties": {"addressSpace": {"addressPrefixes": ["10.0.0.0/16"]}},
+				"resources": [
+					{
+						"type": "virtualNetworkPeerings",
+						"name": "hub-to-spoke",
+						"dependsOn": ["[variables('hubId')]", "[variables('spokeId')]"],
+						"properties": {"remoteVirtualNetwork": {"id": "[variables('spokeId')]"}}
+					}
+				]
+			},
+			{
+				"type": "Microsoft.Network/virtualNetworks",
+				"name": "spoke",
+				"properties": {"addressSpace": {"addressPrefixes": ["10.1.0.0/16"]}},
+				"resources": [
+					{
+						"type": "virtualNetworkPeerings",
+						"name": "spoke-to-hub",
+						"dependsOn": ["[variables('hubId')]", "[variables('spokeId')]"],
+						"properties": {"remoteVirtualNetwork": {"id": "[variables('hubId')]"}}
+					}
+				]
+			}
+		]
+	}`, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 4)
+
+	assert.Equal(t, "hub", resources[0].Name)
+	assert.Equal(t, "hub/hub-to-spoke", resources[1].Name)
+	assert.Equal(t, "spoke", resources[2].Name)
+	assert.Equal(t, "spoke/spoke-to-hub", resources[3].Name)
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Network/virtualNetworks/hub", rawTestValues(t, resources[3]).Get("properties.remoteVirtualNetwork.id").String())
+}
+
+func TestEvaluateCircularDependency(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"languageVersion": "2.0",
+		"resources": {
+			"a": {"type": "Microsoft.Storage/storageAccounts", "name": "a", "dependsOn": ["b"]},
+			"b": {"type": "Microsoft.Storage/storageAccounts", "name": "b", "properties": {"x": "[reference('a').x]"}},
+			"c": {"type": "Microsoft.Storage/storageAccounts", "name": "c"}
+		}
+	}`, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 3)
+
+	assert.Equal(t, "c", resources[0].Name)
+	assert.Equal(t, "a", resources[1].Name)
+	assert.Equal(t, "b", resources[2].Name)
+}
+
+func TestEvaluateCopyLoopsAndConditions(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"parameters": {
+			"zones": {"type": "array", "defaultValue": ["1", "2", "3"]}
+		},
+		"resources": [
+			{
+				"type": "Microsoft.Network/publicIPAddresses",
+				"name": "[format('pip-{0}', parameters('zones')[copyIndex()])]",
+				"condition": "[not(equals(copyIndex('pips'), 1))]",
+				"copy": {"name": "pips", "count": "[length(parameters('zones'))]"},
+				"sku": {"name": "Standard"}
+			},
+			{
+				"type": "Microsoft.Network/virtualNetworks",
+				"name": "existing-vnet",
+				"existing": true
+			},
+			{
+				"type": "Microsoft.Cache/redis",
+				"name": "cache",
+				"condition": false
+			}
+		]
+	}`, nil)
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "pip-1", resources[0].Name)
+	assert.Equal(t, "pip-3", resources[1].Name)
+	assert.Equal(t, "pips[0]", resources[0].Address)
+	assert.Equal(t, "pips[2]", resources[1].Address)
+}
+
+func TestEvaluatePropertyAndVariableCopies(t *testing.T) {
+	tmpl, err := loadArmTemplate(filepath.Join("testdata", "copy_loops.json"))
+	require.NoError(t, err)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+	e := NewTemplateEvaluator(tmpl, deployment, map[string]interface{}{"vmCount": int64(2)})
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "vm-1", resources[0].Name)
+	assert.Equal(t, "vmCopy[0]", resources[0].Address)
+	assert.Equal(t, "vm-2", resources[1].Name)
+	assert.Equal(t, "vmCopy[1]", resources[1].Address)
+
+	vm := rawTestValues(t, resources[1])
+	assert.False(t, vm.Get("properties.storageProfile.copy").Exists())
+	assert.Equal(t, `[{"createOption":"Empty","diskSizeGB":128,"lun":0},{"createOption":"Empty","diskSizeGB":256,"lun":1}]`, vm.Get("properties.storageProfile.dataDisks").Raw)
+
+	v, err := e.evaluateExpression("[variables('diskSizes')]")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(128), int64(256)}, v)
+}
+
+func TestEvaluateCopyLoopErrors(t *testing.T) {
+	tests := []struct {
+		copy     string
+		expected string
+	}{
+		{`{"name": "c", "count": 801}`, "copy count must be between 0 and 800, got 801"},
+		{`{"name": "c", "count": 2, "mode": "batch"}`, "copy mode must be 'serial' or 'parallel', got batch"},
+		{`{"name": "c", "count": 2, "mode": "serial", "batchSize": 0}`, "copy batchSize must be a positive integer, got 0"},
+	}
+
+	for _, tt := range tests {
+		e := newTestEvaluator(t, `{"resources": []}`, nil)
+
+		copyObj, err := decodeJSON([]byte(tt.copy))
+		require.NoError(t, err)
+
+		_, err = e.copyCount(copyObj.(map[string]interface{}))
+		if assert.Error(t, err, tt.copy) {
+			assert.Equal(t, tt.expected, err.Error(), tt.copy)
+		}
+	}
+}
+
+func TestEvaluateSubscriptionScopedTemplate(t *testing.T) {
+	tmpl, err := loadArmTemplate(filepath.Join("..", "..", "..", "examples", "azurerm", "landing_zone", "subscription.json"))
+	require.NoError(t, err)
+
+	scope := deploymentScopeFromSchema(tmpl.Schema)
+	assert.Equal(t, Subscription, scope)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:    scope,
+		Location: "northeurope",
+	})
+
+	resources, err := NewTemplateEvaluator(tmpl, deployment, map[string]interface{}{"environment": "prod"}).Evaluate()
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-prod-web", resources[0].Id)
+	assert.Equal(t, "northeurope", resources[0].Values["location"])
+
+	// The nested deployment is expanded into the resources it deploys to the resource group
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-prod-web/providers/Microsoft.Web/serverfarms/plan-prod", resources[1].Id)
+	assert.Equal(t, "northeurope", resources[1].Values["location"])
+	assert.Equal(t, "rg-prod-web", resources[1].Values["resourceGroup"])
+}
+
+func TestEvaluateScopeFunctions(t *testing.T) {
+	tmpl, err := parseArmTemplate([]byte(`{}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		deployment *DeploymentContext
+		expr       string
+		expected   interface{}
+	}{
+		{
+			deployment: NewDeploymentContext(&ArmDeploymentOpts{Scope: Subscription}),
+			expr:       "[resourceId('Microsoft.Authorization/policyAssignments', 'pa')]",
+			expected:   "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/policyAssignments/pa",
+		},
+		{
+			deployment: NewDeploymentContext(&ArmDeploymentOpts{Scope: Subscription}),
+			expr:       "[subscriptionResourceId('11111111-1111-1111-1111-111111111111', 'Microsoft.Resources/resourceGroups', 'rg')]",
+			expected:   "/subscriptions/11111111-1111-1111-1111-111111111111/resourceGroups/rg",
+		},
+		{
+			deployment: NewDeploymentContext(&ArmDeploymentOpts{Scope: ManagementGroup, ManagementGroupId: "mg-root"}),
+			expr:       "[managementGroupResourceId('Microsoft.Authorization/policyDefinitions', 'pd')]",
+			expected:   "/providers/Microsoft.Management/managementGroups/mg-root/providers/Microsoft.Authorization/policyDefinitions/pd",
+		},
+		{
+			deployment: NewDeploymentContext(&ArmDeploymentOpts{Scope: ManagementGroup, ManagementGroupId: "mg-root"}),
+			expr:       "[managementGroup().name]",
+			expected:   "mg-root",
+		},
+		{
+			deployment: NewDeploymentContext(&ArmDeploymentOpts{Scope: Tenant}),
+			expr:       "[tenantResourceId('Microsoft.Management/managementGroups', 'mg')]",
+			expected:   "/providers/Microsoft.Management/managementGroups/mg",
+		},
+	}
+
+	for _, test := range tests {
+		actual, err := NewTemplateEvaluator(tmpl, test.deployment, nil).evaluateValue(test.expr)
+		require.NoError(t, err, test.expr)
+		assert.Equal(t, test.expected, actual, test.expr)
+	}
+
+	_, err = NewTemplateEvaluator(tmpl, NewDeploymentContext(&ArmDeploymentOpts{Scope: Subscription}), nil).evaluateValue("[resourceGroup().location]")
+	assert.Error(t, err)
+}
+
+func TestEvaluateNestedDeployments(t *testing.T) {
+	tmpl, err := loadArmTemplate(filepath.Join("testdata", "nested", "main.json"))
+	require.NoError(t, err)
+
+	deployment := NewDeploymentContext(&ArmDeploymentOpts{
+		Scope:         ResourceGroup,
+		ResourceGroup: "rg-test",
+		Location:      "westeurope",
+	})
+	e := NewTemplateEvaluator(tmpl, deployment, map[string]interface{}{"prefix": "test"})
+
+	resources, err := e.Evaluate()
+	require.NoError(t, err)
+
+	var actual []string
+	for _, r := range resources {
+		actual = append(actual, r.Address+" "+r.Name)
+	}
+
+	assert.Equal(t, []string{
+		"module.storage.Microsoft.Storage/storageAccounts/testst testst",
+		"module.logs.Microsoft.OperationalInsights/workspaces/test-logs test-logs",
+		"module.apps[0].Microsoft.Web/serverfarms/plan-app-0 plan-app-0",
+		"module.apps[1].Microsoft.Web/serverfarms/plan-app-1 plan-app-1",
+		"Microsoft.Resources/deployments/remote remote",
+	}, actual)
+
+	plan := rawTestValues(t, resources[2])
+	assert.Equal(t, "P1v3", plan.Get("sku.name").String())
+}
+
+func TestEvaluateLinkedTemplateCycle(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"resources": [
+			{
+				"type": "Microsoft.Resources/deployments",
+				"name": "self",
+				"properties": {"templateLink": {"relativePath": "main.json"}}
+			}
+		]
+	}`), 0600))
+
+	tmpl, err := loadArmTemplate(path)
+	require.NoError(t, err)
+
+	e := NewTemplateEvaluator(tmpl, NewDeploymentContext(&ArmDeploymentOpts{}), nil)
+	res := tmpl.Resources[0]
+	evaluated, err := e.evaluateResourceValues(res, nil)
+	require.NoError(t, err)
+
+	_, _, err = e.evaluateNestedDeployment(res, evaluated)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "references itself")
+	}
+}
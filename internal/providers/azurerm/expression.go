@@ -0,0 +1,281 @@
+package azurerm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprNode is a node in the parsed tree of an ARM template expression,
+// e.g. "[format('{0}-app', parameters('name'))]".
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/template-expressions
+type exprNode interface{}
+
+type literalNode struct {
+	value interface{}
+}
+
+type callNode struct {
+	name string
+	args []exprNode
+}
+
+type propertyNode struct {
+	target   exprNode
+	property string
+}
+
+type indexNode struct {
+	target exprNode
+	index  exprNode
+}
+
+// isTemplateExpression returns true if s is a string that ARM evaluates as an
+// expression rather than a literal. Strings starting with "[[" are escaped literals.
+func isTemplateExpression(s string) bool {
+	s = strings.TrimSpace(s)
+	return len(s) >= 2 && s[0] == '[' && s[len(s)-1] == ']' && !strings.HasPrefix(s, "[[")
+}
+
+// unescapeTemplateLiteral removes the leading bracket ARM uses to escape a
+// literal string that would otherwise be evaluated as an expression.
+func unescapeTemplateLiteral(s string) string {
+	if strings.HasPrefix(s, "[[") {
+		return s[1:]
+	}
+	return s
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenLParen
+	tokenRParen
+	tokenLBracket
+	tokenRBracket
+	tokenComma
+	tokenDot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func tokenizeExpression(s string) ([]token, error) {
+	var tokens []token
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case r == '[':
+			tokens = append(tokens, token{tokenLBracket, "[", i})
+			i++
+		case r == ']':
+			tokens = append(tokens, token{tokenRBracket, "]", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokenComma, ",", i})
+			i++
+		case r == '.':
+			tokens = append(tokens, token{tokenDot, ".", i})
+			i++
+		case r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\'' {
+					// Single quotes are escaped by doubling them
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i += 2
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string literal at position %d", start)
+			}
+			tokens = append(tokens, token{tokenString, sb.String(), start})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i]), start})
+		case unicode.IsLetter(r) || r == '_' || r == '$':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i]), start})
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' at position %d", r, i)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// parseTemplateExpression parses the contents of an ARM template expression.
+// The surrounding square brackets must already be stripped.
+func parseTemplateExpression(s string) (exprNode, error) {
+	tokens, err := tokenizeExpression(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected token '%s' at position %d", p.peek().value, p.peek().pos)
+	}
+
+	return node, nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) expect(kind tokenKind, desc string) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, fmt.Errorf("expected %s at position %d", desc, t.pos)
+	}
+	return t, nil
+}
+
+func (p *exprParser) parseExpr() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenDot:
+			p.next()
+			t, err := p.expect(tokenIdent, "property name")
+			if err != nil {
+				return nil, err
+			}
+			node = &propertyNode{target: node, property: t.value}
+		case tokenLBracket:
+			p.next()
+			idx, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(tokenRBracket, "']'"); err != nil {
+				return nil, err
+			}
+			node = &indexNode{target: node, index: idx}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenString:
+		return &literalNode{value: t.value}, nil
+	case tokenNumber:
+		if strings.Contains(t.value, ".") {
+			f, err := strconv.ParseFloat(t.value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number '%s' at position %d", t.value, t.pos)
+			}
+			return &literalNode{value: f}, nil
+		}
+		i, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s' at position %d", t.value, t.pos)
+		}
+		return &literalNode{value: i}, nil
+	case tokenIdent:
+		if p.peek().kind != tokenLParen {
+			switch strings.ToLower(t.value) {
+			case "true":
+				return &literalNode{value: true}, nil
+			case "false":
+				return &literalNode{value: false}, nil
+			case "null":
+				return &literalNode{value: nil}, nil
+			}
+			return nil, fmt.Errorf("expected '(' after function name '%s' at position %d", t.value, t.pos)
+		}
+		p.next()
+
+		call := &callNode{name: t.value}
+		if p.peek().kind == tokenRParen {
+			p.next()
+			return call, nil
+		}
+
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+
+			sep := p.next()
+			if sep.kind == tokenRParen {
+				return call, nil
+			}
+			if sep.kind != tokenComma {
+				return nil, fmt.Errorf("expected ',' or ')' at position %d", sep.pos)
+			}
+		}
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	return nil, fmt.Errorf("unexpected token '%s' at position %d", t.value, t.pos)
+}
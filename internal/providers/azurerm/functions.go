@@ -0,0 +1,1523 @@
+package azurerm
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type templateFunc func(e *TemplateEvaluator, args []interface{}) (interface{}, error)
+
+// templateFunctions contains the ARM template functions that can be evaluated without calling Azure.
+// Keys are lower case since function names are not case sensitive.
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/template-functions
+var templateFunctions map[string]templateFunc
+
+func init() {
+	templateFunctions = map[string]templateFunc{
+		// Deployment functions
+		"parameters":  fnParameters,
+		"variables":   fnVariables,
+		"deployment":  fnDeployment,
+		"environment": fnEnvironment,
+
+		// Scope functions
+		"resourcegroup":   fnResourceGroup,
+		"subscription":    fnSubscription,
+		"managementgroup": fnManagementGroup,
+		"tenant":          fnTenant,
+
+		// Resource functions
+		"resourceid":                fnResourceId,
+		"subscriptionresourceid":    fnSubscriptionResourceId,
+		"managementgroupresourceid": fnManagementGroupResourceId,
+		"tenantresourceid":          fnTenantResourceId,
+		"reference":                 fnReference,
+		"extensionresourceid":       fnExtensionResourceId,
+
+		// String functions
+		"concat":       fnConcat,
+		"format":       fnFormat,
+		"tolower":      fnToLower,
+		"toupper":      fnToUpper,
+		"trim":         fnTrim,
+		"replace":      fnReplace,
+		"substring":    fnSubstring,
+		"split":        fnSplit,
+		"startswith":   fnStartsWith,
+		"endswith":     fnEndsWith,
+		"indexof":      fnIndexOf,
+		"lastindexof":  fnLastIndexOf,
+		"padleft":      fnPadLeft,
+		"uniquestring": fnUniqueString,
+		"guid":         fnGuid,
+		"newguid":      fnNewGuid,
+		"utcnow":       fnUtcNow,
+		"base64":       fnBase64,
+		"string":       fnString,
+		"uri":          fnUri,
+
+		// Array and object functions
+		"array":        fnArray,
+		"createarray":  fnCreateArray,
+		"createobject": fnCreateObject,
+		"coalesce":     fnCoalesce,
+		"contains":     fnContains,
+		"empty":        fnEmpty,
+		"first":        fnFirst,
+		"last":         fnLast,
+		"length":       fnLength,
+		"take":         fnTake,
+		"skip":         fnSkip,
+		"union":        fnUnion,
+		"range":        fnRange,
+		"json":         fnJson,
+		"items":        fnItems,
+		"join":         fnJoin,
+
+		// Lambda functions, lambda() itself is evaluated by evalCall
+		"lambdavariables": fnLambdaVariables,
+		"map":             fnMap,
+		"filter":          fnFilter,
+
+		// Comparison and logical functions
+		"equals":          fnEquals,
+		"not":             fnNot,
+		"and":             fnAnd,
+		"or":              fnOr,
+		"bool":            fnBool,
+		"true":            func(_ *TemplateEvaluator, _ []interface{}) (interface{}, error) { return true, nil },
+		"false":           func(_ *TemplateEvaluator, _ []interface{}) (interface{}, error) { return false, nil },
+		"null":            func(_ *TemplateEvaluator, _ []interface{}) (interface{}, error) { return nil, nil },
+		"greater":         fnCompare(func(c int) bool { return c > 0 }),
+		"greaterorequals": fnCompare(func(c int) bool { return c >= 0 }),
+		"less":            fnCompare(func(c int) bool { return c < 0 }),
+		"lessorequals":    fnCompare(func(c int) bool { return c <= 0 }),
+
+		// Numeric functions
+		"int":   fnInt,
+		"float": fnFloat,
+		"add":   fnArithmetic(func(a, b int64) (int64, error) { return a + b, nil }),
+		"sub":   fnArithmetic(func(a, b int64) (int64, error) { return a - b, nil }),
+		"mul":   fnArithmetic(func(a, b int64) (int64, error) { return a * b, nil }),
+		"div":   fnArithmetic(divInt),
+		"mod":   fnArithmetic(modInt),
+		"min":   fnMinMax(func(a, b int64) bool { return a < b }),
+		"max":   fnMinMax(func(a, b int64) bool { return a > b }),
+
+		"copyindex": fnCopyIndex,
+	}
+}
+
+func checkArgs(name string, args []interface{}, min, max int) error {
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		if min == max {
+			return fmt.Errorf("function '%s' expects %d argument(s), got %d", name, min, len(args))
+		}
+		return fmt.Errorf("function '%s' expects between %d and %d argument(s), got %d", name, min, max, len(args))
+	}
+	return nil
+}
+
+func stringArg(name string, args []interface{}, i int) (string, error) {
+	s, ok := args[i].(string)
+	if !ok {
+		return "", fmt.Errorf("function '%s' expects argument %d to be a string, got %s", name, i+1, typeName(args[i]))
+	}
+	return s, nil
+}
+
+func intArg(name string, args []interface{}, i int) (int64, error) {
+	n, ok := toInt(args[i])
+	if !ok {
+		return 0, fmt.Errorf("function '%s' expects argument %d to be an integer, got %s", name, i+1, typeName(args[i]))
+	}
+	return n, nil
+}
+
+func toInt(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
+
+func toBool(v interface{}) (bool, bool) {
+	switch b := v.(type) {
+	case bool:
+		return b, true
+	case string:
+		parsed, err := strconv.ParseBool(strings.ToLower(b))
+		return parsed, err == nil
+	case int64:
+		return b != 0, true
+	}
+	return false, false
+}
+
+// toTemplateString converts v to a string the same way ARM's string() function does.
+func toTemplateString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		if val {
+			return "True"
+		}
+		return "False"
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(b)
+	}
+}
+
+func fnParameters(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("parameters", args, 1, 1); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("parameters", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return e.parameter(name)
+}
+
+func fnVariables(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("variables", args, 1, 1); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("variables", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return e.variable(name)
+}
+
+func fnDeployment(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"name": e.deployment.DeploymentName,
+		"properties": map[string]interface{}{
+			"mode": string(e.deployment.Mode),
+		},
+		"location": e.deployment.Location,
+	}, nil
+}
+
+func fnEnvironment(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	return map[string]interface{}{
+		"name":            "AzureCloud",
+		"resourceManager": "https://management.azure.com/",
+		"portal":          "https://portal.azure.com",
+		"suffixes": map[string]interface{}{
+			"storage":           "core.windows.net",
+			"sqlServerHostname": ".database.windows.net",
+			"keyvaultDns":       ".vault.azure.net",
+		},
+	}, nil
+}
+
+func fnResourceGroup(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	d := e.deployment
+	if d.Scope != ResourceGroup {
+		return nil, fmt.Errorf("function 'resourceGroup' is not available in a %s scoped deployment", d.Scope)
+	}
+
+	return map[string]interface{}{
+		"id":       fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", d.SubscriptionId, d.ResourceGroup),
+		"name":     d.ResourceGroup,
+		"type":     "Microsoft.Resources/resourceGroups",
+		"location": d.Location,
+		"properties": map[string]interface{}{
+			"provisioningState": "Succeeded",
+		},
+	}, nil
+}
+
+func fnSubscription(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	d := e.deployment
+	if d.Scope == ManagementGroup || d.Scope == Tenant {
+		return nil, fmt.Errorf("function 'subscription' is not available in a %s scoped deployment", d.Scope)
+	}
+
+	return map[string]interface{}{
+		"id":             fmt.Sprintf("/subscriptions/%s", d.SubscriptionId),
+		"subscriptionId": d.SubscriptionId,
+		"tenantId":       d.TenantId,
+		"displayName":    d.SubscriptionId,
+	}, nil
+}
+
+func fnManagementGroup(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	d := e.deployment
+	if d.Scope != ManagementGroup {
+		return nil, fmt.Errorf("function 'managementGroup' is not available in a %s scoped deployment", d.Scope)
+	}
+
+	return map[string]interface{}{
+		"id":   fmt.Sprintf("/providers/Microsoft.Management/managementGroups/%s", d.ManagementGroupId),
+		"name": d.ManagementGroupId,
+		"type": "Microsoft.Management/managementGroups",
+		"properties": map[string]interface{}{
+			"displayName": d.ManagementGroupId,
+			"tenantId":    d.TenantId,
+		},
+	}, nil
+}
+
+func fnTenant(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	d := e.deployment
+	return map[string]interface{}{
+		"tenantId":    d.TenantId,
+		"displayName": d.TenantId,
+	}, nil
+}
+
+// fnResourceId implements resourceId([subscriptionId], [resourceGroupName], resourceType, resourceName1, [resourceName2], ...)
+func fnResourceId(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	strs, typeIdx, err := scopedResourceIdArgs("resourceId", args, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionId := e.deployment.SubscriptionId
+	resourceGroup := e.deployment.ResourceGroup
+	switch typeIdx {
+	case 0:
+		// Outside of a resource group deployment resourceId returns an ID at the deployment scope
+		if e.deployment.Scope != ResourceGroup {
+			return e.resourceId(strs[0], strs[1:])
+		}
+	case 1:
+		resourceGroup = strs[0]
+	case 2:
+		subscriptionId = strs[0]
+		resourceGroup = strs[1]
+	}
+
+	return formatResourceGroupResourceId(subscriptionId, resourceGroup, strs[typeIdx], strs[typeIdx+1:])
+}
+
+// fnSubscriptionResourceId implements subscriptionResourceId([subscriptionId], resourceType, resourceName1, [resourceName2], ...)
+func fnSubscriptionResourceId(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	strs, typeIdx, err := scopedResourceIdArgs("subscriptionResourceId", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionId := e.deployment.SubscriptionId
+	if typeIdx == 1 {
+		subscriptionId = strs[0]
+	}
+
+	return formatSubscriptionResourceId(subscriptionId, strs[typeIdx], strs[typeIdx+1:])
+}
+
+// fnManagementGroupResourceId implements managementGroupResourceId([managementGroupResourceId], resourceType, resourceName1, [resourceName2], ...)
+func fnManagementGroupResourceId(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	strs, typeIdx, err := scopedResourceIdArgs("managementGroupResourceId", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	managementGroupId := e.deployment.ManagementGroupId
+	if typeIdx == 1 {
+		managementGroupId = strs[0]
+	}
+
+	return formatManagementGroupResourceId(managementGroupId, strs[typeIdx], strs[typeIdx+1:])
+}
+
+// fnTenantResourceId implements tenantResourceId(resourceType, resourceName1, [resourceName2], ...)
+func fnTenantResourceId(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	strs, typeIdx, err := scopedResourceIdArgs("tenantResourceId", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatTenantResourceId(strs[typeIdx], strs[typeIdx+1:])
+}
+
+// scopedResourceIdArgs converts the arguments of a *ResourceId function to strings and returns the
+// index of the resource type, which can be preceded by at most maxScopeArgs scope arguments.
+// The resource type is the first argument with a namespace, e.g. Microsoft.Web/sites.
+func scopedResourceIdArgs(name string, args []interface{}, maxScopeArgs int) ([]string, int, error) {
+	if err := checkArgs(name, args, 2, -1); err != nil {
+		return nil, 0, err
+	}
+
+	strs := make([]string, len(args))
+	for i := range args {
+		s, err := stringArg(name, args, i)
+		if err != nil {
+			return nil, 0, err
+		}
+		strs[i] = s
+	}
+
+	for i := 0; i <= maxScopeArgs && i < len(strs)-1; i++ {
+		if strings.Contains(strs[i], "/") {
+			return strs, i, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("function '%s' could not find a resource type in its arguments", name)
+}
+
+func fnExtensionResourceId(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("extensionResourceId", args, 3, -1); err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(args))
+	for i := range args {
+		s, err := stringArg("extensionResourceId", args, i)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+
+	providerPath, err := formatProviderPath(strs[1], strs[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.TrimSuffix(strs[0], "/") + providerPath, nil
+}
+
+// fnReference returns the evaluated properties of a resource declared earlier in the template.
+// Runtime-only values, such as connection strings or hostnames, can't be known offline so
+// references to anything else evaluate to an empty object.
+func fnReference(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("reference", args, 1, 3); err != nil {
+		return nil, err
+	}
+	ref, err := stringArg("reference", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	full := false
+	if len(args) == 3 {
+		if s, ok := args[2].(string); ok && strings.EqualFold(s, "Full") {
+			full = true
+		}
+	}
+
+	res := e.lookupResource(ref)
+	if res == nil {
+		if outputs, ok := e.lookupDeploymentOutputs(ref); ok {
+			props := map[string]interface{}{"outputs": outputs}
+			if full {
+				return map[string]interface{}{"properties": props}, nil
+			}
+			return props, nil
+		}
+
+		return map[string]interface{}{}, nil
+	}
+
+	if full {
+		return res.Values, nil
+	}
+
+	if props, ok := getCaseInsensitive(res.Values, "properties"); ok {
+		return props, nil
+	}
+
+	return map[string]interface{}{}, nil
+}
+
+// fnList stands in for list* functions such as listKeys, which return secrets that are
+// never relevant for cost estimation.
+func fnList(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func fnConcat(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	if _, ok := args[0].([]interface{}); ok {
+		var out []interface{}
+		for i, a := range args {
+			arr, ok := a.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("function 'concat' expects argument %d to be an array, got %s", i+1, typeName(a))
+			}
+			out = append(out, arr...)
+		}
+		if out == nil {
+			out = []interface{}{}
+		}
+		return out, nil
+	}
+
+	var sb strings.Builder
+	for _, a := range args {
+		sb.WriteString(toTemplateString(a))
+	}
+	return sb.String(), nil
+}
+
+// fnFormat implements the .NET style composite formatting used by ARM's format() function.
+func fnFormat(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("format", args, 1, -1); err != nil {
+		return nil, err
+	}
+	f, err := stringArg("format", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	values := args[1:]
+
+	var sb strings.Builder
+	for i := 0; i < len(f); i++ {
+		c := f[i]
+		if c == '{' {
+			if i+1 < len(f) && f[i+1] == '{' {
+				sb.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(f[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("function 'format' has an unterminated placeholder in '%s'", f)
+			}
+			placeholder := f[i+1 : i+end]
+			i += end
+
+			spec := ""
+			if idx := strings.IndexByte(placeholder, ':'); idx >= 0 {
+				spec = placeholder[idx+1:]
+				placeholder = placeholder[:idx]
+			}
+			if idx := strings.IndexByte(placeholder, ','); idx >= 0 {
+				placeholder = placeholder[:idx]
+			}
+
+			n, err := strconv.Atoi(strings.TrimSpace(placeholder))
+			if err != nil || n < 0 || n >= len(values) {
+				return nil, fmt.Errorf("function 'format' has an invalid placeholder '{%s}'", placeholder)
+			}
+			sb.WriteString(formatValue(values[n], spec))
+			continue
+		}
+		if c == '}' && i+1 < len(f) && f[i+1] == '}' {
+			sb.WriteByte('}')
+			i++
+			continue
+		}
+		sb.WriteByte(c)
+	}
+
+	return sb.String(), nil
+}
+
+func formatValue(v interface{}, spec string) string {
+	if spec == "" {
+		return toTemplateString(v)
+	}
+
+	n, ok := toInt(v)
+	if !ok {
+		return toTemplateString(v)
+	}
+
+	switch strings.ToUpper(spec[:1]) {
+	case "D":
+		width, _ := strconv.Atoi(spec[1:])
+		return fmt.Sprintf("%0*d", width, n)
+	case "X":
+		width, _ := strconv.Atoi(spec[1:])
+		s := fmt.Sprintf("%0*x", width, n)
+		if spec[0] == 'X' {
+			s = strings.ToUpper(s)
+		}
+		return s
+	}
+
+	return toTemplateString(v)
+}
+
+func fnToLower(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("toLower", args, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("toLower", args, 0)
+	return strings.ToLower(s), err
+}
+
+func fnToUpper(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("toUpper", args, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("toUpper", args, 0)
+	return strings.ToUpper(s), err
+}
+
+func fnTrim(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("trim", args, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("trim", args, 0)
+	return strings.TrimSpace(s), err
+}
+
+func fnReplace(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("replace", args, 3, 3); err != nil {
+		return nil, err
+	}
+	strs := make([]string, 3)
+	for i := range strs {
+		s, err := stringArg("replace", args, i)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+	return strings.ReplaceAll(strs[0], strs[1], strs[2]), nil
+}
+
+func fnSubstring(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("substring", args, 1, 3); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("substring", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	start := int64(0)
+	if len(args) > 1 {
+		if start, err = intArg("substring", args, 1); err != nil {
+			return nil, err
+		}
+	}
+	length := int64(len(s)) - start
+	if len(args) > 2 {
+		if length, err = intArg("substring", args, 2); err != nil {
+			return nil, err
+		}
+	}
+
+	if start < 0 || length < 0 || start+length > int64(len(s)) {
+		return nil, fmt.Errorf("function 'substring' arguments are out of range for '%s'", s)
+	}
+	return s[start : start+length], nil
+}
+
+func fnSplit(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("split", args, 2, 2); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("split", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var delimiters []string
+	switch d := args[1].(type) {
+	case string:
+		delimiters = []string{d}
+	case []interface{}:
+		for _, item := range d {
+			delimiters = append(delimiters, toTemplateString(item))
+		}
+	default:
+		return nil, fmt.Errorf("function 'split' expects argument 2 to be a string or array, got %s", typeName(d))
+	}
+
+	parts := []string{s}
+	for _, delim := range delimiters {
+		var next []string
+		for _, p := range parts {
+			next = append(next, strings.Split(p, delim)...)
+		}
+		parts = next
+	}
+
+	out := make([]interface{}, len(parts))
+	for i, p := range parts {
+		out[i] = p
+	}
+	return out, nil
+}
+
+func twoStrings(name string, args []interface{}) (string, string, error) {
+	if err := checkArgs(name, args, 2, 2); err != nil {
+		return "", "", err
+	}
+	a, err := stringArg(name, args, 0)
+	if err != nil {
+		return "", "", err
+	}
+	b, err := stringArg(name, args, 1)
+	return a, b, err
+}
+
+func fnStartsWith(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings("startsWith", args)
+	return strings.HasPrefix(strings.ToLower(a), strings.ToLower(b)), err
+}
+
+func fnEndsWith(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings("endsWith", args)
+	return strings.HasSuffix(strings.ToLower(a), strings.ToLower(b)), err
+}
+
+func fnIndexOf(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings("indexOf", args)
+	return int64(strings.Index(strings.ToLower(a), strings.ToLower(b))), err
+}
+
+func fnLastIndexOf(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	a, b, err := twoStrings("lastIndexOf", args)
+	return int64(strings.LastIndex(strings.ToLower(a), strings.ToLower(b))), err
+}
+
+func fnPadLeft(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("padLeft", args, 2, 3); err != nil {
+		return nil, err
+	}
+	s := toTemplateString(args[0])
+	width, err := intArg("padLeft", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	pad := " "
+	if len(args) > 2 {
+		if pad, err = stringArg("padLeft", args, 2); err != nil {
+			return nil, err
+		}
+	}
+	for int64(len(s)) < width && pad != "" {
+		s = pad[:1] + s
+	}
+	return s, nil
+}
+
+var uniqueStringEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// fnUniqueString returns a deterministic 13 character hash of its arguments. The value
+// differs from the one Azure would generate, but is stable between runs which is all
+// that matters for naming resources when estimating costs.
+func fnUniqueString(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("uniqueString", args, 1, -1); err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = toTemplateString(a)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "-")))
+	return uniqueStringEncoding.EncodeToString(sum[:])[:13], nil
+}
+
+func fnGuid(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("guid", args, 1, -1); err != nil {
+		return nil, err
+	}
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = toTemplateString(a)
+	}
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.Join(parts, "-"))).String(), nil
+}
+
+// placeholderGuid is returned by newGuid(), since a random value would change the estimate's resource
+// names between runs.
+const placeholderGuid = "00000000-0000-0000-0000-000000000000"
+
+func fnNewGuid(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("newGuid", args, 0, 0); err != nil {
+		return nil, err
+	}
+	log.Debugf("Using placeholder value %s for newGuid()", placeholderGuid)
+	return placeholderGuid, nil
+}
+
+// placeholderTime is the time returned by utcNow(), so estimates don't change between runs.
+var placeholderTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// dotNetDateFormats maps the specifiers of .NET custom date and time format strings to Go layouts,
+// longest first so e.g. yyyy is matched before yy.
+var dotNetDateFormats = []struct{ specifier, layout string }{
+	{"yyyy", "2006"}, {"yy", "06"},
+	{"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"dddd", "Monday"}, {"ddd", "Mon"}, {"dd", "02"}, {"d", "2"},
+	{"HH", "15"}, {"hh", "03"}, {"h", "3"},
+	{"mm", "04"}, {"m", "4"},
+	{"ss", "05"}, {"s", "5"},
+	{"fff", "000"}, {"ff", "00"}, {"f", "0"},
+	{"tt", "PM"},
+}
+
+// dotNetStandardDateFormats are the .NET standard format strings that templates commonly use.
+var dotNetStandardDateFormats = map[string]string{
+	"o": "yyyy-MM-ddTHH:mm:ss.fffffffZ",
+	"O": "yyyy-MM-ddTHH:mm:ss.fffffffZ",
+	"s": "yyyy-MM-ddTHH:mm:ss",
+	"u": "yyyy-MM-dd HH:mm:ssZ",
+	"d": "M/d/yyyy",
+	"t": "h:mm tt",
+}
+
+// fnUtcNow returns placeholderTime in the .NET format of utcNow(format), which defaults to
+// yyyyMMddTHHmmssZ. ARM only allows utcNow() in parameter defaults, where it's commonly used for
+// tags and deployment names that don't affect costs.
+func fnUtcNow(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("utcNow", args, 0, 1); err != nil {
+		return nil, err
+	}
+
+	format := "yyyyMMddTHHmmssZ"
+	if len(args) == 1 {
+		s, err := stringArg("utcNow", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		if s != "" {
+			format = s
+		}
+	}
+	if f, ok := dotNetStandardDateFormats[format]; ok {
+		format = f
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(format); {
+		matched := false
+		for _, f := range dotNetDateFormats {
+			if strings.HasPrefix(format[i:], f.specifier) {
+				b.WriteString(placeholderTime.Format(f.layout))
+				i += len(f.specifier)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(format[i])
+			i++
+		}
+	}
+
+	v := b.String()
+	log.Debugf("Using placeholder value %s for utcNow()", v)
+	return v, nil
+}
+
+func fnBase64(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("base64", args, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("base64", args, 0)
+	return base64.StdEncoding.EncodeToString([]byte(s)), err
+}
+
+func fnString(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("string", args, 1, 1); err != nil {
+		return nil, err
+	}
+	return toTemplateString(args[0]), nil
+}
+
+func fnUri(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	base, rel, err := twoStrings("uri", args)
+	if err != nil {
+		return nil, err
+	}
+	if idx := strings.LastIndex(base, "/"); idx >= 0 {
+		base = base[:idx+1]
+	}
+	return base + strings.TrimPrefix(rel, "/"), nil
+}
+
+func fnArray(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("array", args, 1, 1); err != nil {
+		return nil, err
+	}
+	if arr, ok := args[0].([]interface{}); ok {
+		return arr, nil
+	}
+	return []interface{}{args[0]}, nil
+}
+
+func fnCreateArray(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	out := make([]interface{}, len(args))
+	copy(out, args)
+	return out, nil
+}
+
+func fnCreateObject(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("function 'createObject' expects an even number of arguments, got %d", len(args))
+	}
+	out := make(map[string]interface{}, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		k, err := stringArg("createObject", args, i)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = args[i+1]
+	}
+	return out, nil
+}
+
+func fnCoalesce(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	for _, a := range args {
+		if a != nil {
+			return a, nil
+		}
+	}
+	return nil, nil
+}
+
+func fnContains(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("contains", args, 2, 2); err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case string:
+		return strings.Contains(c, toTemplateString(args[1])), nil
+	case []interface{}:
+		for _, item := range c {
+			if valuesEqual(item, args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		_, ok := getCaseInsensitive(c, toTemplateString(args[1]))
+		return ok, nil
+	}
+
+	return nil, fmt.Errorf("function 'contains' expects argument 1 to be a string, array or object, got %s", typeName(args[0]))
+}
+
+func fnEmpty(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("empty", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case nil:
+		return true, nil
+	case string:
+		return c == "", nil
+	case []interface{}:
+		return len(c) == 0, nil
+	case map[string]interface{}:
+		return len(c) == 0, nil
+	}
+
+	return false, nil
+}
+
+func fnFirst(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("first", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case string:
+		if c == "" {
+			return "", nil
+		}
+		return c[:1], nil
+	case []interface{}:
+		if len(c) == 0 {
+			return nil, nil
+		}
+		return c[0], nil
+	}
+
+	return nil, fmt.Errorf("function 'first' expects a string or array, got %s", typeName(args[0]))
+}
+
+func fnLast(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("last", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case string:
+		if c == "" {
+			return "", nil
+		}
+		return c[len(c)-1:], nil
+	case []interface{}:
+		if len(c) == 0 {
+			return nil, nil
+		}
+		return c[len(c)-1], nil
+	}
+
+	return nil, fmt.Errorf("function 'last' expects a string or array, got %s", typeName(args[0]))
+}
+
+func fnLength(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("length", args, 1, 1); err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case nil:
+		return int64(0), nil
+	case string:
+		return int64(len(c)), nil
+	case []interface{}:
+		return int64(len(c)), nil
+	case map[string]interface{}:
+		return int64(len(c)), nil
+	}
+
+	return nil, fmt.Errorf("function 'length' expects a string, array or object, got %s", typeName(args[0]))
+}
+
+func fnTake(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("take", args, 2, 2); err != nil {
+		return nil, err
+	}
+	n, err := intArg("take", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case string:
+		return c[:clampIndex(n, len(c))], nil
+	case []interface{}:
+		return c[:clampIndex(n, len(c))], nil
+	}
+
+	return nil, fmt.Errorf("function 'take' expects a string or array, got %s", typeName(args[0]))
+}
+
+func fnSkip(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("skip", args, 2, 2); err != nil {
+		return nil, err
+	}
+	n, err := intArg("skip", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := args[0].(type) {
+	case string:
+		return c[clampIndex(n, len(c)):], nil
+	case []interface{}:
+		return c[clampIndex(n, len(c)):], nil
+	}
+
+	return nil, fmt.Errorf("function 'skip' expects a string or array, got %s", typeName(args[0]))
+}
+
+func clampIndex(n int64, l int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > int64(l) {
+		return l
+	}
+	return int(n)
+}
+
+func fnUnion(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("union", args, 1, -1); err != nil {
+		return nil, err
+	}
+
+	switch args[0].(type) {
+	case []interface{}:
+		var out []interface{}
+		for i, a := range args {
+			arr, ok := a.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("function 'union' expects argument %d to be an array, got %s", i+1, typeName(a))
+			}
+			for _, item := range arr {
+				found := false
+				for _, existing := range out {
+					if valuesEqual(existing, item) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					out = append(out, item)
+				}
+			}
+		}
+		if out == nil {
+			out = []interface{}{}
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{})
+		for i, a := range args {
+			obj, ok := a.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("function 'union' expects argument %d to be an object, got %s", i+1, typeName(a))
+			}
+			for k, v := range obj {
+				out[k] = v
+			}
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("function 'union' expects arrays or objects, got %s", typeName(args[0]))
+}
+
+func fnRange(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("range", args, 2, 2); err != nil {
+		return nil, err
+	}
+	start, err := intArg("range", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	count, err := intArg("range", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 || count > 10000 {
+		return nil, fmt.Errorf("function 'range' count %d is out of range", count)
+	}
+
+	out := make([]interface{}, count)
+	for i := range out {
+		out[i] = start + int64(i)
+	}
+	return out, nil
+}
+
+func fnJson(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("json", args, 1, 1); err != nil {
+		return nil, err
+	}
+	s, err := stringArg("json", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON([]byte(s))
+}
+
+func fnEquals(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("equals", args, 2, 2); err != nil {
+		return nil, err
+	}
+	return valuesEqual(args[0], args[1]), nil
+}
+
+func valuesEqual(a, b interface{}) bool {
+	if ai, ok := a.(int64); ok {
+		if bf, ok := b.(float64); ok {
+			return float64(ai) == bf
+		}
+	}
+	if af, ok := a.(float64); ok {
+		if bi, ok := b.(int64); ok {
+			return af == float64(bi)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func fnNot(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("not", args, 1, 1); err != nil {
+		return nil, err
+	}
+	b, ok := toBool(args[0])
+	if !ok {
+		return nil, fmt.Errorf("function 'not' expects a bool, got %s", typeName(args[0]))
+	}
+	return !b, nil
+}
+
+func fnAnd(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("and", args, 2, -1); err != nil {
+		return nil, err
+	}
+	for _, a := range args {
+		b, ok := toBool(a)
+		if !ok {
+			return nil, fmt.Errorf("function 'and' expects bools, got %s", typeName(a))
+		}
+		if !b {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func fnOr(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("or", args, 2, -1); err != nil {
+		return nil, err
+	}
+	for _, a := range args {
+		b, ok := toBool(a)
+		if !ok {
+			return nil, fmt.Errorf("function 'or' expects bools, got %s", typeName(a))
+		}
+		if b {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func fnBool(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("bool", args, 1, 1); err != nil {
+		return nil, err
+	}
+	b, ok := toBool(args[0])
+	if !ok {
+		return nil, fmt.Errorf("function 'bool' can't convert %s to a bool", typeName(args[0]))
+	}
+	return b, nil
+}
+
+func fnCompare(cmp func(int) bool) templateFunc {
+	return func(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+		if err := checkArgs("compare", args, 2, 2); err != nil {
+			return nil, err
+		}
+
+		if a, ok := args[0].(string); ok {
+			b, ok := args[1].(string)
+			if !ok {
+				return nil, fmt.Errorf("can't compare string with %s", typeName(args[1]))
+			}
+			return cmp(strings.Compare(a, b)), nil
+		}
+
+		a, okA := toFloat(args[0])
+		b, okB := toFloat(args[1])
+		if !okA || !okB {
+			return nil, fmt.Errorf("can't compare %s with %s", typeName(args[0]), typeName(args[1]))
+		}
+
+		c := 0
+		if a < b {
+			c = -1
+		} else if a > b {
+			c = 1
+		}
+		return cmp(c), nil
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func fnInt(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("int", args, 1, 1); err != nil {
+		return nil, err
+	}
+	return intArg("int", args, 0)
+}
+
+func fnFloat(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("float", args, 1, 1); err != nil {
+		return nil, err
+	}
+	f, ok := toFloat(args[0])
+	if !ok {
+		return nil, fmt.Errorf("function 'float' can't convert %s to a float", typeName(args[0]))
+	}
+	return f, nil
+}
+
+func divInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("function 'div' can't divide by zero")
+	}
+	return a / b, nil
+}
+
+func modInt(a, b int64) (int64, error) {
+	if b == 0 {
+		return 0, fmt.Errorf("function 'mod' can't divide by zero")
+	}
+	return a % b, nil
+}
+
+func fnArithmetic(op func(a, b int64) (int64, error)) templateFunc {
+	return func(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+		if err := checkArgs("arithmetic", args, 2, 2); err != nil {
+			return nil, err
+		}
+		a, err := intArg("arithmetic", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		b, err := intArg("arithmetic", args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return op(a, b)
+	}
+}
+
+func fnMinMax(better func(a, b int64) bool) templateFunc {
+	return func(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+		values := args
+		if len(args) == 1 {
+			if arr, ok := args[0].([]interface{}); ok {
+				values = arr
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("function 'min'/'max' expects at least one value")
+		}
+
+		var result int64 = math.MaxInt64
+		for i, v := range values {
+			n, ok := toInt(v)
+			if !ok {
+				return nil, fmt.Errorf("function 'min'/'max' expects integers, got %s", typeName(v))
+			}
+			if i == 0 || better(n, result) {
+				result = n
+			}
+		}
+		return result, nil
+	}
+}
+
+// fnCopyIndex implements copyIndex([loopName], [offset]) for the copy loop being expanded.
+func fnCopyIndex(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("copyIndex", args, 0, 2); err != nil {
+		return nil, err
+	}
+
+	var loopName string
+	if len(args) > 0 {
+		if s, ok := args[0].(string); ok {
+			loopName = s
+			args = args[1:]
+		}
+	}
+
+	var offset int64
+	if len(args) > 0 {
+		n, err := intArg("copyIndex", args, 0)
+		if err != nil {
+			return nil, err
+		}
+		offset = n
+	}
+
+	for i := len(e.copies) - 1; i >= 0; i-- {
+		if loopName == "" || strings.EqualFold(e.copies[i].name, loopName) {
+			return e.copies[i].index + offset, nil
+		}
+	}
+
+	if loopName != "" {
+		return nil, fmt.Errorf("function 'copyIndex' references unknown copy loop '%s'", loopName)
+	}
+	return nil, fmt.Errorf("function 'copyIndex' can only be used in a copy loop")
+}
+
+// templateLambda is the value of a lambda() call, its body is evaluated by the function it's passed to.
+type templateLambda struct {
+	params []string
+	body   exprNode
+}
+
+func (e *TemplateEvaluator) evalLambda(n *callNode) (interface{}, error) {
+	if len(n.args) < 2 {
+		return nil, fmt.Errorf("function 'lambda' expects at least 2 arguments, got %d", len(n.args))
+	}
+
+	l := &templateLambda{body: n.args[len(n.args)-1]}
+	for _, a := range n.args[:len(n.args)-1] {
+		lit, ok := a.(*literalNode)
+		if !ok {
+			return nil, fmt.Errorf("function 'lambda' expects its parameter names to be string literals")
+		}
+		name, ok := lit.value.(string)
+		if !ok {
+			return nil, fmt.Errorf("function 'lambda' expects its parameter names to be string literals")
+		}
+		l.params = append(l.params, name)
+	}
+
+	return l, nil
+}
+
+func (e *TemplateEvaluator) callLambda(l *templateLambda, args ...interface{}) (interface{}, error) {
+	scope := make(map[string]interface{}, len(l.params))
+	for i, p := range l.params {
+		if i < len(args) {
+			scope[strings.ToLower(p)] = args[i]
+		}
+	}
+
+	e.lambdaScopes = append(e.lambdaScopes, scope)
+	defer func() { e.lambdaScopes = e.lambdaScopes[:len(e.lambdaScopes)-1] }()
+
+	return e.evalNode(l.body)
+}
+
+func lambdaArg(name string, args []interface{}, i int) (*templateLambda, error) {
+	l, ok := args[i].(*templateLambda)
+	if !ok {
+		return nil, fmt.Errorf("function '%s' expects argument %d to be a lambda, got %s", name, i+1, typeName(args[i]))
+	}
+	return l, nil
+}
+
+func fnLambdaVariables(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("lambdaVariables", args, 1, 1); err != nil {
+		return nil, err
+	}
+	name, err := stringArg("lambdaVariables", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(e.lambdaScopes) - 1; i >= 0; i-- {
+		if v, ok := e.lambdaScopes[i][strings.ToLower(name)]; ok {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("lambda variable '%s' is not defined", name)
+}
+
+// fnMap implements map(array, lambda(item, [index], ...))
+func fnMap(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("map", args, 2, 2); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("function 'map' expects argument 1 to be an array, got %s", typeName(args[0]))
+	}
+	l, err := lambdaArg("map", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, len(arr))
+	for i, item := range arr {
+		v, err := e.callLambda(l, item, int64(i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// fnFilter implements filter(array, lambda(item, [index], ...))
+func fnFilter(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("filter", args, 2, 2); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("function 'filter' expects argument 1 to be an array, got %s", typeName(args[0]))
+	}
+	l, err := lambdaArg("filter", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []interface{}{}
+	for i, item := range arr {
+		v, err := e.callLambda(l, item, int64(i))
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := toBool(v); ok && b {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// fnItems converts an object to an array of {key, value} objects, sorted by key like ARM does.
+func fnItems(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("items", args, 1, 1); err != nil {
+		return nil, err
+	}
+	obj, ok := args[0].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("function 'items' expects an object, got %s", typeName(args[0]))
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]interface{}, len(keys))
+	for i, k := range keys {
+		out[i] = map[string]interface{}{"key": k, "value": obj[k]}
+	}
+	return out, nil
+}
+
+func fnJoin(e *TemplateEvaluator, args []interface{}) (interface{}, error) {
+	if err := checkArgs("join", args, 2, 2); err != nil {
+		return nil, err
+	}
+	arr, ok := args[0].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("function 'join' expects argument 1 to be an array, got %s", typeName(args[0]))
+	}
+	delim, err := stringArg("join", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	strs := make([]string, len(arr))
+	for i, item := range arr {
+		strs[i] = toTemplateString(item)
+	}
+	return strings.Join(strs, delim), nil
+}
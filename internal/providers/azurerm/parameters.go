@@ -0,0 +1,262 @@
+package azurerm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// keyVaultReference is the value of a parameter that references a Key Vault secret.
+// Secrets can't be read without access to Azure, so the evaluator uses a placeholder instead.
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/key-vault-parameter
+type keyVaultReference struct {
+	VaultId    string
+	SecretName string
+}
+
+func isBicepParamFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".bicepparam")
+}
+
+// loadParameterFiles reads the values of ARM parameter files (the deploymentParameters.json
+// schema) and .bicepparam files. Files are applied in order, so values in later files
+// override the values of earlier ones.
+func loadParameterFiles(paths []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	for _, path := range paths {
+		var fileValues map[string]interface{}
+		var err error
+
+		if isBicepParamFile(path) {
+			fileValues, err = loadBicepParamFile(path)
+		} else {
+			fileValues, err = loadParametersJSONFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		mergeParameterValues(values, fileValues)
+	}
+
+	return values, nil
+}
+
+// mergeParameterValues sets the values of src in dst. Parameter names aren't case sensitive,
+// so a value replaces any value of the same name in a different case.
+func mergeParameterValues(dst, src map[string]interface{}) {
+	for name, v := range src {
+		for existing := range dst {
+			if strings.EqualFold(existing, name) {
+				delete(dst, existing)
+			}
+		}
+		dst[name] = v
+	}
+}
+
+func loadParametersJSONFile(path string) (map[string]interface{}, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading ARM parameters file")
+	}
+
+	values, err := parseParametersJSON(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing ARM parameters file %s", path)
+	}
+
+	return values, nil
+}
+
+// parseParametersJSON returns the values of a parameters file. Both the full file, with the
+// values under "parameters", and the bare object of parameters that az accepts are supported.
+func parseParametersJSON(b []byte) (map[string]interface{}, error) {
+	v, err := decodeJSON(b)
+	if err != nil {
+		return nil, err
+	}
+
+	params, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("parameters file must be a JSON object")
+	}
+
+	if inner, ok := getCaseInsensitive(params, "parameters"); ok {
+		if params, ok = inner.(map[string]interface{}); !ok {
+			return nil, errors.New("parameters must be a JSON object")
+		}
+	}
+
+	values := make(map[string]interface{}, len(params))
+	for name, raw := range params {
+		if strings.HasPrefix(name, "$") || strings.EqualFold(name, "contentVersion") {
+			continue
+		}
+
+		paramObj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("parameter '%s' must be an object with a value or reference", name)
+		}
+
+		value, err := parameterValue(paramObj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parameter '%s'", name)
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// parameterValue returns the value of a parameter passed to a deployment, which is either
+// {"value": ...} or a Key Vault reference {"reference": {"keyVault": {"id": ...}, "secretName": ...}}.
+func parameterValue(param map[string]interface{}) (interface{}, error) {
+	if v, ok := getCaseInsensitive(param, "value"); ok {
+		return v, nil
+	}
+
+	raw, ok := getCaseInsensitive(param, "reference")
+	if !ok {
+		return nil, errors.New("missing value or reference")
+	}
+
+	ref, _ := raw.(map[string]interface{})
+	keyVault, _ := getCaseInsensitive(ref, "keyVault")
+	keyVaultObj, _ := keyVault.(map[string]interface{})
+	vaultId, _ := getCaseInsensitive(keyVaultObj, "id")
+	secretName, _ := getCaseInsensitive(ref, "secretName")
+
+	kv := &keyVaultReference{}
+	kv.VaultId, _ = vaultId.(string)
+	kv.SecretName, _ = secretName.(string)
+
+	return kv, nil
+}
+
+func loadBicepParamFile(path string) (map[string]interface{}, error) {
+	f, err := readBicepFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := evaluateBicepParams(f, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error evaluating Bicep parameters file %s", path)
+	}
+
+	return values, nil
+}
+
+// evaluateBicepParams evaluates the param assignments of a .bicepparam file. The values are
+// compiled the same way as Bicep expressions and can reference the vars of the file.
+func evaluateBicepParams(f *bicepFile, path string) (map[string]interface{}, error) {
+	c := &bicepCompiler{path: path, file: f, symbols: map[string]interface{}{}}
+	c.declare()
+
+	variables := make(map[string]interface{}, len(f.vars))
+	for _, v := range f.vars {
+		value, err := c.compileValue(v.value, bicepEnv{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "var '%s'", v.name)
+		}
+		variables[v.name] = value
+	}
+
+	template := newArmTemplate(map[string]interface{}{"variables": variables})
+	e := NewTemplateEvaluator(template, NewDeploymentContext(&ArmDeploymentOpts{}), nil)
+
+	values := make(map[string]interface{}, len(f.params))
+	for _, p := range f.params {
+		if p.defaultValue == nil {
+			return nil, fmt.Errorf("param '%s' has no value", p.name)
+		}
+
+		if name, args, ok := bicepGetSecretCall(p.defaultValue); ok {
+			ref, err := c.evaluateGetSecret(e, name, args)
+			if err != nil {
+				return nil, errors.Wrapf(err, "param '%s'", p.name)
+			}
+			values[p.name] = ref
+			continue
+		}
+
+		compiled, err := c.compileValue(p.defaultValue, bicepEnv{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "param '%s'", p.name)
+		}
+
+		v, err := e.evaluateValue(compiled)
+		if err != nil {
+			return nil, errors.Wrapf(err, "param '%s'", p.name)
+		}
+		values[p.name] = v
+	}
+
+	return values, nil
+}
+
+// bicepGetSecretCall returns the arguments of a call to az.getSecret(), which is how
+// .bicepparam files reference Key Vault secrets.
+func bicepGetSecretCall(expr bicepExpr) (string, []bicepExpr, bool) {
+	switch e := expr.(type) {
+	case *bicepCall:
+		if strings.EqualFold(e.name, "getSecret") {
+			return e.name, e.args, true
+		}
+	case *bicepMethodCall:
+		if ns, ok := e.target.(*bicepIdentifier); ok && ns.name == "az" && strings.EqualFold(e.name, "getSecret") {
+			return e.name, e.args, true
+		}
+	}
+	return "", nil, false
+}
+
+// evaluateGetSecret evaluates getSecret(subscriptionId, resourceGroupName, keyVaultName, secretName, [secretVersion]).
+func (c *bicepCompiler) evaluateGetSecret(e *TemplateEvaluator, name string, args []bicepExpr) (*keyVaultReference, error) {
+	if len(args) < 4 || len(args) > 5 {
+		return nil, fmt.Errorf("function '%s' expects between 4 and 5 arguments, got %d", name, len(args))
+	}
+
+	strs := make([]string, 4)
+	for i := range strs {
+		compiled, err := c.compileExpr(args[i], bicepEnv{})
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := e.evaluateExpression("[" + compiled + "]")
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = toTemplateString(v)
+	}
+
+	return &keyVaultReference{
+		VaultId:    fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s", strs[0], strs[1], strs[2]),
+		SecretName: strs[3],
+	}, nil
+}
+
+// warnUnknownParameters warns about parameter values the template doesn't declare, which
+// are usually typos or a parameters file that belongs to a different template.
+func warnUnknownParameters(template *ArmTemplate, values map[string]interface{}) {
+	for name := range values {
+		declared := false
+		for param := range template.Parameters {
+			if strings.EqualFold(param, name) {
+				declared = true
+				break
+			}
+		}
+
+		if !declared {
+			log.Warnf("ARM parameters file sets '%s', which is not a parameter of the template", name)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package azurerm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseParametersJSON(t *testing.T) {
+	values, err := loadParameterFiles([]string{filepath.Join("testdata", "parameters", "base.parameters.json")})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"webAppName":    "infracost-base",
+		"sku":           "S1",
+		"instanceCount": int64(1),
+		"adminPassword": &keyVaultReference{
+			VaultId:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-secrets/providers/Microsoft.KeyVault/vaults/kv-infracost",
+			SecretName: "adminPassword",
+		},
+	}, values)
+
+	// az also accepts the parameters object without the wrapping file
+	values, err = parseParametersJSON([]byte(`{"sku": {"value": "B1"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"sku": "B1"}, values)
+
+	_, err = parseParametersJSON([]byte(`{"parameters": {"sku": "B1"}}`))
+	assert.Error(t, err)
+}
+
+func TestLoadLayeredParameterFiles(t *testing.T) {
+	t.Setenv("INFRACOST_TEST_OWNER", "platform")
+
+	values, err := loadParameterFiles([]string{
+		filepath.Join("testdata", "parameters", "base.parameters.json"),
+		filepath.Join("testdata", "parameters", "prod.bicepparam"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"webAppName":    "infracost-prod",
+		"SKU":           "P1v3",
+		"instanceCount": int64(1),
+		"tags": map[string]interface{}{
+			"environment": "prod",
+			"owner":       "platform",
+		},
+		"adminPassword": &keyVaultReference{
+			VaultId:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-secrets/providers/Microsoft.KeyVault/vaults/kv-infracost",
+			SecretName: "prodPassword",
+		},
+	}, values)
+}
+
+func TestLoadBicepParamFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.bicepparam")
+	require.NoError(t, os.WriteFile(path, []byte("using 'main.bicep'\n\nparam owner = readEnvironmentVariable('INFRACOST_TEST_UNSET')\n"), 0600))
+
+	_, err := loadParameterFiles([]string{path})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "environment variable 'INFRACOST_TEST_UNSET' is not set")
+	}
+}
+
+func TestEvaluateParameterValues(t *testing.T) {
+	e := newTestEvaluator(t, `{
+		"parameters": {
+			"sku": {"type": "string", "defaultValue": "B1"},
+			"tier": {"type": "string", "defaultValue": "[if(equals(parameters('sku'), 'P1v3'), 'Premium', 'Basic')]"},
+			"adminPassword": {"type": "securestring", "allowedValues": ["placeholder"]}
+		},
+		"resources": []
+	}`, map[string]interface{}{
+		"SKU":           "P1v3",
+		"adminPassword": &keyVaultReference{SecretName: "adminPassword"},
+	})
+
+	tests := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"[parameters('sku')]", "P1v3"},
+		{"[parameters('tier')]", "Premium"},
+		{"[parameters('adminPassword')]", "placeholder"},
+	}
+
+	for _, tt := range tests {
+		v, err := e.evaluateExpression(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.expected, v, tt.expr)
+	}
+}
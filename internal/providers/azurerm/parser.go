@@ -2,23 +2,34 @@ package azurerm
 
 import (
 	"encoding/json"
-	"fmt"
+	"strings"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/providers/azurerm/resources"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 )
 
 type usageMap map[string]*schema.UsageData
 
 type Parser struct {
 	ctx *config.ProjectContext
+	// addresses overrides the address of resources by lowercase resource ID or lowercase
+	// type and name, which is otherwise the address of the resource, see addressFromId
+	addresses map[string]string
+	// resourceIds are the lowercase IDs of the resources by address, so resources
+	// with the same type and name in different scopes keep distinct addresses
+	resourceIds map[string]string
+	// values are the values of the resources by lowercase resource ID, which the references
+	// of resources are resolved from, see addReferences
+	values map[string]gjson.Result
 }
 
 func NewParser(ctx *config.ProjectContext) *Parser {
-	return &Parser{ctx}
+	return &Parser{ctx: ctx}
 }
 
 type ParsedWhatifChange struct {
@@ -47,6 +58,8 @@ func (p *Parser) createPartialResource(d *schema.ResourceData, u *schema.UsageDa
 			}
 		}
 
+		p.addReferences(d, registryItem.ReferenceAttributes)
+
 		if registryItem.CoreRFunc != nil {
 			coreRes := registryItem.CoreRFunc(d)
 			if coreRes != nil {
@@ -87,6 +100,20 @@ func (p *Parser) parse(j []byte, usage usageMap) ([]*ParsedWhatifChange, error)
 		return nil, errors.New("WhatIf operation was not successful")
 	}
 
+	for _, change := range whatif.Changes {
+		beforeData, err := change.Before()
+		if err != nil {
+			return nil, err
+		}
+
+		afterData, err := change.After()
+		if err != nil {
+			return nil, err
+		}
+
+		p.addValues(*firstExisting(afterData, beforeData))
+	}
+
 	for _, change := range whatif.Changes {
 		parsed, err := p.parseChange(change, usage)
 		if err != nil {
@@ -98,16 +125,46 @@ func (p *Parser) parse(j []byte, usage usageMap) ([]*ParsedWhatifChange, error)
 		}
 	}
 
-	// Recursively create WhatIfPropertyChanges
-
 	return changes, nil
 }
 
+// parseTemplateResources creates partial resources from resources evaluated locally
+// from an ARM template, see TemplateEvaluator.
+func (p *Parser) parseTemplateResources(evaluated []*EvaluatedResource, usage usageMap) ([]*schema.PartialResource, error) {
+	var partials []*schema.PartialResource
+
+	raws := make([]gjson.Result, 0, len(evaluated))
+	for _, res := range evaluated {
+		raw, err := res.RawValues()
+		if err != nil {
+			return nil, err
+		}
+
+		raws = append(raws, raw)
+	}
+	p.addValues(raws...)
+
+	for i, res := range evaluated {
+		raw := raws[i]
+		rd, err := p.parseResourceData(&raw)
+		if err != nil {
+			return nil, err
+		}
+		if res.Address != "" {
+			p.setAddress(rd, res.Id, res.Address)
+		}
+		populateUsageData(rd, res.SymbolicName, usage)
+
+		partials = append(partials, p.createPartialResource(rd, rd.UsageData))
+	}
+
+	return partials, nil
+}
+
+// parseChange maps a WhatIf change to the resource as it is before the deployment (past)
+// and after the deployment (current), based on its change type.
 // TODO: need baseresources like TF provider?
 func (p *Parser) parseChange(change *WhatifChange, usage usageMap) (*ParsedWhatifChange, error) {
-	var after *schema.PartialResource
-	var before *schema.PartialResource
-
 	beforeData, err := change.Before()
 	if err != nil {
 		return nil, err
@@ -118,33 +175,270 @@ func (p *Parser) parseChange(change *WhatifChange, usage usageMap) (*ParsedWhati
 		return nil, err
 	}
 
-	if afterData.Get("id").Exists() {
-		afterRd, err := p.parseResourceData(afterData)
-		if err != nil {
-			return nil, err
-		}
-		after = p.createPartialResource(afterRd, afterRd.UsageData)
+	parsed := &ParsedWhatifChange{
+		Delta: change.Delta,
 	}
 
-	if beforeData.Get("id").Exists() {
-		beforeRd, err := p.parseResourceData(beforeData)
-		if err != nil {
-			return nil, err
+	switch change.ChangeType {
+	case Create:
+		parsed.PartialResource, err = p.parsePartialResource(afterData, usage)
+	case Delete:
+		parsed.PartialPastResource, err = p.parsePartialResource(beforeData, usage)
+	case Ignore:
+		// Ignored resources are left untouched by an Incremental deployment, but
+		// a Complete deployment removes everything that isn't in the template.
+		parsed.PartialPastResource, err = p.parsePartialResource(beforeData, usage)
+		if err == nil && !p.isCompleteMode() {
+			parsed.PartialResource, err = p.parsePartialResource(beforeData, usage)
+		}
+	case NoChange:
+		parsed.PartialPastResource, err = p.parsePartialResource(firstExisting(beforeData, afterData), usage)
+		if err == nil {
+			parsed.PartialResource, err = p.parsePartialResource(firstExisting(afterData, beforeData), usage)
 		}
-		before = p.createPartialResource(beforeRd, beforeRd.UsageData)
+	case Unsupported:
+		parsed.PartialResource, err = p.parseUnsupportedChange(change, firstExisting(afterData, beforeData), usage)
+	default:
+		// Modify and Deploy changes, and any change types added to the API later
+		parsed.PartialPastResource, err = p.parsePartialResource(beforeData, usage)
+		if err == nil {
+			parsed.PartialResource, err = p.parsePartialResource(afterData, usage)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if change.ChangeType == Modify {
+		addPropertyChanges(parsed.PartialResource, change.Delta)
+	}
+
+	return parsed, nil
+}
+
+// addPropertyChanges records the properties changed by a Modify change in the metadata of
+// the resource, so the diff output can explain which of them changed its cost.
+func addPropertyChanges(partial *schema.PartialResource, delta []*WhatIfPropertyChange) {
+	if partial == nil || partial.ResourceData == nil {
+		return
+	}
+
+	changes := flattenPropertyChanges(delta)
+	if len(changes) == 0 {
+		return
+	}
+
+	b, err := json.Marshal(changes)
+	if err != nil {
+		log.Debugf("Could not marshal property changes of %s: %s", partial.ResourceData.Address, err)
+		return
+	}
+
+	if partial.ResourceData.Metadata == nil {
+		partial.ResourceData.Metadata = make(map[string]gjson.Result)
 	}
+	partial.ResourceData.Metadata[schema.PropertyChangesMetadataKey] = gjson.ParseBytes(b)
+}
+
+// parsePartialResource returns nil if data doesn't describe a resource, e.g. the 'before'
+// of a resource that is being created.
+func (p *Parser) parsePartialResource(data *gjson.Result, usage usageMap) (*schema.PartialResource, error) {
+	if !data.Get("id").Exists() {
+		return nil, nil
+	}
+
+	rd, err := p.parseResourceData(data)
+	if err != nil {
+		return nil, err
+	}
+	populateUsageData(rd, "", usage)
+
+	return p.createPartialResource(rd, rd.UsageData), nil
+}
 
-	return &ParsedWhatifChange{
-		PartialResource:     after,
-		PartialPastResource: before,
-		Delta:               change.Delta,
+// parseUnsupportedChange returns a skipped resource for a change WhatIf can't evaluate,
+// e.g. because a resource name depends on a runtime value.
+func (p *Parser) parseUnsupportedChange(change *WhatifChange, data *gjson.Result, usage usageMap) (*schema.PartialResource, error) {
+	armType := data.Get("type").String()
+	if armType == "" {
+		armType = resourceTypeFromId(change.ResourceId)
+	}
+
+	resourceType := resources.GetTFResourceFromAzureRMType(armType, data)
+	if resourceType == "" {
+		resourceType = armType
+	}
+
+	reason := change.UnsupportedReason
+	if reason == "" {
+		reason = "This resource is not supported by the WhatIf operation"
+	}
+
+	rd := schema.NewAzureRMResourceData(resourceType, change.ResourceId, *data)
+	p.setAddress(rd, change.ResourceId, p.address(change.ResourceId))
+	populateUsageData(rd, "", usage)
+
+	return &schema.PartialResource{
+		ResourceData: rd,
+		Resource: &schema.Resource{
+			Name:         rd.Address,
+			ResourceType: resourceType,
+			IsSkipped:    true,
+			SkipMessage:  reason,
+		},
 	}, nil
 }
 
-// TODO: This is not exhaustive yet, probably need to do something with 'Delta' and 'WhatIfPropertyChange'
-func (p *Parser) parseResourceData(data *gjson.Result) (*schema.ResourceData, error) {
-	var resData *schema.ResourceData
+// addValues records the values of resources, so other resources can refer to them.
+func (p *Parser) addValues(data ...gjson.Result) {
+	if p.values == nil {
+		p.values = make(map[string]gjson.Result)
+	}
+
+	for _, d := range data {
+		if id := d.Get("id").String(); id != "" {
+			p.values[strings.ToLower(id)] = d
+		}
+	}
+}
+
+// addReferences adds the resources that the reference attributes of d refer to, see
+// resources.ReferenceID, as its references. References to resources that aren't parsed
+// by the parser are ignored.
+func (p *Parser) addReferences(d *schema.ResourceData, attrs []string) {
+	for _, attr := range attrs {
+		id := resources.ReferenceID(d, attr)
+		if id == "" {
+			continue
+		}
+
+		data, ok := p.values[strings.ToLower(id)]
+		if !ok {
+			log.Debugf("Could not find %s of %s", attr, d.Address)
+			continue
+		}
+
+		tfType := resources.GetTFResourceFromAzureRMType(data.Get("type").String(), &data)
+		if tfType == "" {
+			tfType = data.Get("type").String()
+		}
+
+		d.AddReference(attr, schema.NewAzureRMResourceData(tfType, p.address(id), data), nil)
+	}
+}
+
+// address returns the address of the resource with the given ID.
+func (p *Parser) address(resourceId string) string {
+	if address, ok := p.addresses[strings.ToLower(resourceId)]; ok {
+		return address
+	}
+
+	address := addressFromId(resourceId)
+	if override, ok := p.addresses[strings.ToLower(address)]; ok {
+		return override
+	}
+	return address
+}
+
+// setAddress sets the address of a resource and records its full ID in the metadata, so it's
+// still in the JSON output. If another resource already has the address, the ID is used instead.
+func (p *Parser) setAddress(rd *schema.ResourceData, resourceId, address string) {
+	if p.resourceIds == nil {
+		p.resourceIds = make(map[string]string)
+	}
+
+	key := strings.ToLower(resourceId)
+	if existing, ok := p.resourceIds[address]; ok && existing != key {
+		address = resourceId
+	}
+	p.resourceIds[address] = key
+	rd.Address = address
+
+	if address == resourceId {
+		return
+	}
+
+	if rd.Metadata == nil {
+		rd.Metadata = make(map[string]gjson.Result)
+	}
+	rd.Metadata[schema.ResourceIDMetadataKey] = gjson.Result{Type: gjson.String, Str: resourceId}
+}
+
+func (p *Parser) isCompleteMode() bool {
+	return strings.EqualFold(p.ctx.ProjectConfig.ArmDeploymentMode, string(Complete))
+}
+
+func firstExisting(data ...*gjson.Result) *gjson.Result {
+	for _, d := range data {
+		if d.Get("id").Exists() {
+			return d
+		}
+	}
+	return data[len(data)-1]
+}
 
+// resourceTypeFromId returns the ARM resource type of a resource ID, e.g.
+// /subscriptions/x/resourceGroups/rg/providers/Microsoft.Sql/servers/srv/databases/db
+// returns Microsoft.Sql/servers/databases.
+func resourceTypeFromId(id string) string {
+	idx := strings.LastIndex(strings.ToLower(id), "/providers/")
+	if idx < 0 {
+		if strings.Contains(strings.ToLower(id), "/resourcegroups/") {
+			return resourceGroupsResourceType
+		}
+		return ""
+	}
+
+	parts := strings.Split(strings.Trim(id[idx+len("/providers/"):], "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	types := []string{parts[0]}
+	for i := 1; i < len(parts); i += 2 {
+		types = append(types, parts[i])
+	}
+
+	return strings.Join(types, "/")
+}
+
+// resourceAddress returns the readable address of a resource, e.g. "Microsoft.Web/serverfarms/plan"
+// or "Microsoft.Sql/servers/databases/srv/db" for a child resource.
+func resourceAddress(resourceType, name string) string {
+	return resourceType + "/" + name
+}
+
+// addressFromId returns the readable address of the resource with the given ID, see resourceAddress.
+// The ID is returned as is if it doesn't identify a resource.
+func addressFromId(id string) string {
+	resourceType := resourceTypeFromId(id)
+	if resourceType == "" {
+		return id
+	}
+
+	if strings.EqualFold(resourceType, resourceGroupsResourceType) {
+		idx := strings.LastIndex(strings.ToLower(id), "/resourcegroups/")
+		return resourceAddress(resourceType, strings.Trim(id[idx+len("/resourceGroups/"):], "/"))
+	}
+
+	idx := strings.LastIndex(strings.ToLower(id), "/providers/")
+	parts := strings.Split(strings.Trim(id[idx+len("/providers/"):], "/"), "/")
+
+	var names []string
+	for i := 2; i < len(parts); i += 2 {
+		names = append(names, parts[i])
+	}
+	if len(names) == 0 {
+		return id
+	}
+
+	return resourceAddress(resourceType, strings.Join(names, "/"))
+}
+
+// parseResourceData returns the resource data of an ARM resource. ARM types that can't be mapped
+// to a Terraform resource keep their ARM type, so they're reported as not supported.
+func (p *Parser) parseResourceData(data *gjson.Result) (*schema.ResourceData, error) {
 	armType := data.Get("type")
 	resId := data.Get("id")
 	if !armType.Exists() || !resId.Exists() {
@@ -153,10 +447,37 @@ func (p *Parser) parseResourceData(data *gjson.Result) (*schema.ResourceData, er
 
 	tfType := resources.GetTFResourceFromAzureRMType(armType.Str, data)
 	if len(tfType) == 0 {
-		return nil, errors.New(fmt.Sprintf("Could not convert AzureRM type '%s' to TF type", armType.Str))
+		log.Debugf("Could not convert AzureRM type '%s' to TF type", armType.Str)
+		tfType = armType.Str
 	}
 
-	resData = schema.NewAzureRMResourceData(tfType, resId.Str, *data)
+	values, err := p.withDefaultRegion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := schema.NewAzureRMResourceData(tfType, resId.Str, values)
+	p.setAddress(rd, resId.Str, p.address(resId.Str))
+
+	return rd, nil
+}
+
+// withDefaultRegion sets the region of the deployment on the values of a resource, which
+// util.LookupRegion falls back to for resources without a location, e.g. child resources.
+func (p *Parser) withDefaultRegion(data *gjson.Result) (gjson.Result, error) {
+	if data.Get("region").Exists() {
+		return *data, nil
+	}
+
+	region := p.ctx.ProjectConfig.ArmLocation
+	if region == "" {
+		region = DefaultProviderRegion
+	}
+
+	raw, err := sjson.Set(data.Raw, "region", region)
+	if err != nil {
+		return gjson.Result{}, errors.Wrapf(err, "Failed to set default region of %s", data.Get("id").Str)
+	}
 
-	return resData, nil
+	return gjson.Parse(raw), nil
 }
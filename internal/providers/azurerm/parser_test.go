@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/infracost/infracost/internal/config"
@@ -13,6 +14,7 @@ import (
 	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
 )
 
 type ResourceTests struct {
@@ -26,14 +28,14 @@ func TestParseWhatifJson(t *testing.T) {
 			filePath: "./testdata/what_if.json",
 			expected: []*schema.Resource{
 				{
-					Name:         "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/rg",
+					Name:         "Microsoft.Resources/resourceGroups/rg",
 					ResourceType: "azurerm_resource_group",
 					IsSkipped:    true,
 					NoPrice:      true,
 					SkipMessage:  "Free resource",
 				},
 				{
-					Name:         "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/my-resource-group/providers/Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp",
+					Name:         "Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp",
 					ResourceType: "azurerm_app_service_plan",
 					IsSkipped:    false,
 					NoPrice:      false,
@@ -45,7 +47,7 @@ func TestParseWhatifJson(t *testing.T) {
 					},
 				},
 				{
-					Name:         "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/my-resource-group/providers/Microsoft.Web/sites/AzureLinuxApp-webapp",
+					Name:         "Microsoft.Web/sites/AzureLinuxApp-webapp",
 					ResourceType: "azurerm_windows_web_app",
 					IsSkipped:    true,
 					NoPrice:      true,
@@ -137,3 +139,263 @@ func TestParseWhatifJson(t *testing.T) {
 		}
 	}
 }
+
+func TestParseWhatifChangeTypes(t *testing.T) {
+	testFile, err := os.ReadFile("./testdata/what_if_change_types.json")
+	if err != nil {
+		t.Fatalf("Error reading test whatif: " + err.Error())
+	}
+
+	type expectedChange struct {
+		hasPast    bool
+		hasCurrent bool
+	}
+
+	tests := []struct {
+		mode     string
+		expected map[string]expectedChange
+	}{
+		{
+			mode: "Incremental",
+			expected: map[string]expectedChange{
+				"asp-create":      {hasPast: false, hasCurrent: true},
+				"asp-delete":      {hasPast: true, hasCurrent: false},
+				"asp-modify":      {hasPast: true, hasCurrent: true},
+				"asp-nochange":    {hasPast: true, hasCurrent: true},
+				"asp-ignore":      {hasPast: true, hasCurrent: true},
+				"asp-deploy":      {hasPast: true, hasCurrent: true},
+				"asp-unsupported": {hasPast: false, hasCurrent: true},
+			},
+		},
+		{
+			mode: "Complete",
+			expected: map[string]expectedChange{
+				"asp-ignore": {hasPast: true, hasCurrent: false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{ArmDeploymentMode: test.mode}, log.Fields{})
+		parser := NewParser(ctx)
+
+		changes, err := parser.parse(testFile, schema.NewEmptyUsageMap())
+		if err != nil {
+			t.Fatalf(errors.Wrap(err, "Error parsing WhatIf data").Error())
+		}
+
+		for _, change := range changes {
+			res := change.PartialResource
+			if res == nil {
+				res = change.PartialPastResource
+			}
+			name := res.ResourceData.Address[strings.LastIndex(res.ResourceData.Address, "/")+1:]
+
+			expected, ok := test.expected[name]
+			if !ok {
+				continue
+			}
+
+			assert.Equal(t, expected.hasPast, change.PartialPastResource != nil, "%s (%s): past resource", name, test.mode)
+			assert.Equal(t, expected.hasCurrent, change.PartialResource != nil, "%s (%s): current resource", name, test.mode)
+		}
+	}
+}
+
+func TestParseWhatifUnsupportedChange(t *testing.T) {
+	testFile, err := os.ReadFile("./testdata/what_if_change_types.json")
+	if err != nil {
+		t.Fatalf("Error reading test whatif: " + err.Error())
+	}
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	changes, err := NewParser(ctx).parse(testFile, schema.NewEmptyUsageMap())
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Error parsing WhatIf data").Error())
+	}
+
+	unsupported := changes[len(changes)-1].PartialResource
+	resource := schema.BuildResource(unsupported, nil)
+
+	assert.Equal(t, "azurerm_app_service_plan", resource.ResourceType)
+	assert.True(t, resource.IsSkipped)
+	assert.Equal(t, "Changes to the resource declared at 'properties.name' are not supported because the name depends on a runtime value.", resource.SkipMessage)
+}
+
+func TestParseWhatifPropertyChanges(t *testing.T) {
+	testFile, err := os.ReadFile("./testdata/what_if_change_types.json")
+	if err != nil {
+		t.Fatalf("Error reading test whatif: " + err.Error())
+	}
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	changes, err := NewParser(ctx).parse(testFile, schema.NewEmptyUsageMap())
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Error parsing WhatIf data").Error())
+	}
+
+	for _, change := range changes {
+		if change.PartialResource == nil || change.PartialPastResource == nil {
+			continue
+		}
+
+		metadata := change.PartialResource.ResourceData.Metadata
+		if !strings.HasSuffix(change.PartialResource.ResourceData.Address, "/asp-modify") {
+			assert.NotContains(t, metadata, schema.PropertyChangesMetadataKey, change.PartialResource.ResourceData.Address)
+			continue
+		}
+
+		resource := schema.BuildResource(change.PartialResource, nil)
+
+		var actual []schema.PropertyChange
+		err := json.Unmarshal([]byte(resource.Metadata[schema.PropertyChangesMetadataKey].Raw), &actual)
+		assert.NoError(t, err)
+
+		expected := []schema.PropertyChange{
+			{Path: "sku.name", Before: "S1", After: "P1v2"},
+			{Path: "properties.ipRules[0].value", Before: "10.0.0.0/24", After: "10.1.0.0/24"},
+		}
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestParseWhatifResourceTypes(t *testing.T) {
+	testFile, err := os.ReadFile("./testdata/what_if_resource_types.json")
+	if err != nil {
+		t.Fatalf("Error reading test whatif: " + err.Error())
+	}
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{ArmLocation: "northeurope"}, log.Fields{})
+	changes, err := NewParser(ctx).parse(testFile, schema.NewEmptyUsageMap())
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Error parsing WhatIf data").Error())
+	}
+
+	tests := map[string]struct {
+		resourceType  string
+		isSkipped     bool
+		noPrice       bool
+		skipMessage   string
+		costComponent string
+		region        string
+		subResources  []string
+	}{
+		"stgrs":         {resourceType: "azurerm_storage_account", costComponent: "Capacity", region: "westeurope"},
+		"sql-db":        {resourceType: "azurerm_mssql_database", costComponent: "Compute (serverless, GP_S_Gen5_4)", region: "westeurope"},
+		"sql-pooled-db": {resourceType: "azurerm_mssql_database", isSkipped: true, noPrice: true, skipMessage: "Databases in an elastic pool are priced by the pool"},
+		"www":           {resourceType: "azurerm_dns_a_record", costComponent: "DNS queries (first 1B)", region: "Zone 1"},
+		"vnet":          {resourceType: "azurerm_virtual_network", isSkipped: true, noPrice: true, skipMessage: "Free resource"},
+		"vm":            {resourceType: "azurerm_linux_virtual_machine", costComponent: "Instance usage (pay as you go, Standard_D2s_v3)", region: "westeurope", subResources: []string{"os_disk"}},
+		"redis":         {resourceType: "azurerm_redis_cache", costComponent: "Cache usage (Premium_P1, 2 nodes)", region: "westeurope"},
+		"kv":            {resourceType: "azurerm_key_vault", isSkipped: true, noPrice: true, skipMessage: "Free resource"},
+		"key":           {resourceType: "azurerm_key_vault_key", costComponent: "Secrets operations", region: "westeurope"},
+		"cosmos":        {resourceType: "azurerm_cosmosdb_account", isSkipped: true, noPrice: true, skipMessage: "Free resource"},
+		"cosmos-db":     {resourceType: "azurerm_cosmosdb_sql_database", costComponent: "Provisioned throughput", region: "westeurope"},
+		"vgw":           {resourceType: "azurerm_virtual_network_gateway", costComponent: "VPN gateway (VpnGw1)", region: "westeurope"},
+		"vgw-conn":      {resourceType: "azurerm_virtual_network_gateway_connection", costComponent: "VPN gateway (VpnGw1)", region: "westeurope"},
+		"lb":            {resourceType: "azurerm_lb", costComponent: "Data processed", region: "Global", subResources: []string{"http", "https", "outbound"}},
+		"widget":        {resourceType: "Microsoft.Contoso/widgets", isSkipped: true, skipMessage: "This resource is not currently supported"},
+	}
+
+	assert.Len(t, changes, len(tests))
+
+	for _, change := range changes {
+		address := change.PartialResource.ResourceData.Address
+		name := address[strings.LastIndex(address, "/")+1:]
+		expected := tests[name]
+
+		resource := schema.BuildResource(change.PartialResource, nil)
+
+		assert.Equal(t, expected.resourceType, change.PartialResource.ResourceData.Type, name)
+		assert.Equal(t, expected.isSkipped, resource.IsSkipped, name)
+		assert.Equal(t, expected.noPrice, resource.NoPrice, name)
+		assert.Equal(t, expected.skipMessage, resource.SkipMessage, name)
+
+		var subResources []string
+		for _, sub := range resource.SubResources {
+			subResources = append(subResources, sub.Name)
+		}
+		assert.Equal(t, expected.subResources, subResources, name)
+
+		if expected.costComponent == "" {
+			continue
+		}
+
+		var found *schema.CostComponent
+		for _, c := range resource.CostComponents {
+			if strings.HasPrefix(c.Name, expected.costComponent) {
+				found = c
+				break
+			}
+		}
+
+		if assert.NotNil(t, found, "%s: cost component %s", name, expected.costComponent) {
+			assert.Equal(t, expected.region, *found.ProductFilter.Region, name)
+		}
+	}
+}
+
+func TestParseWhatifAddresses(t *testing.T) {
+	testFile, err := os.ReadFile("./testdata/what_if.json")
+	if err != nil {
+		t.Fatalf("Error reading test whatif: " + err.Error())
+	}
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	parser := NewParser(ctx)
+	parser.addresses = map[string]string{
+		"/subscriptions/00000000-0000-0000-0000-000000000001/resourcegroups/my-resource-group/providers/microsoft.web/sites/azurelinuxapp-webapp": "siteCopy[0]",
+	}
+
+	changes, err := parser.parse(testFile, schema.NewEmptyUsageMap())
+	if err != nil {
+		t.Fatalf(errors.Wrap(err, "Error parsing WhatIf data").Error())
+	}
+
+	resourceIds := make(map[string]string)
+	for _, change := range changes {
+		if change.PartialResource != nil {
+			rd := change.PartialResource.ResourceData
+			resourceIds[rd.Address] = rd.Metadata[schema.ResourceIDMetadataKey].String()
+		}
+	}
+
+	// The full ID of the resources is kept in their metadata
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/my-resource-group/providers/Microsoft.Web/sites/AzureLinuxApp-webapp", resourceIds["siteCopy[0]"])
+	assert.Equal(t, "/subscriptions/00000000-0000-0000-0000-000000000001/resourceGroups/my-resource-group/providers/Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp", resourceIds["Microsoft.Web/serverfarms/AppServicePlan-AzureLinuxApp"])
+}
+
+func TestAddressFromId(t *testing.T) {
+	tests := map[string]string{
+		"/subscriptions/sub/resourceGroups/rg":                                                  "Microsoft.Resources/resourceGroups/rg",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan":         "Microsoft.Web/serverfarms/plan",
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Sql/servers/srv/databases/db": "Microsoft.Sql/servers/databases/srv/db",
+		"/subscriptions/sub/providers/Microsoft.Resources/deployments/main":                     "Microsoft.Resources/deployments/main",
+		"/subscriptions/sub": "/subscriptions/sub",
+	}
+
+	for id, expected := range tests {
+		assert.Equal(t, expected, addressFromId(id), id)
+	}
+}
+
+func TestParserDuplicateAddresses(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	parser := NewParser(ctx)
+
+	first := "/subscriptions/sub/resourceGroups/rg-a/providers/Microsoft.Web/serverfarms/plan"
+	second := "/subscriptions/sub/resourceGroups/rg-b/providers/Microsoft.Web/serverfarms/plan"
+
+	var addresses []string
+	for _, id := range []string{first, second, first} {
+		data := gjson.Parse(fmt.Sprintf(`{"id": %q, "type": "Microsoft.Web/serverfarms", "name": "plan"}`, id))
+		rd, err := parser.parseResourceData(&data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addresses = append(addresses, rd.Address)
+	}
+
+	// Resources with the same type and name in different resource groups are addressed by their ID
+	assert.Equal(t, []string{"Microsoft.Web/serverfarms/plan", second, "Microsoft.Web/serverfarms/plan"}, addresses)
+}
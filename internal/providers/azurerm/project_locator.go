@@ -0,0 +1,243 @@
+package azurerm
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+// maxTemplateSearchDepth is how many directories deep FindTemplateProjects looks for templates.
+const maxTemplateSearchDepth = 10
+
+// TemplateProject is an ARM template or Bicep file that is deployed on its own, i.e. it isn't
+// only used as a Bicep module or linked template, together with the parameters file it's deployed with.
+type TemplateProject struct {
+	Path           string
+	ParametersFile string
+}
+
+// FindTemplateProjects walks the directory tree at root for ARM templates and Bicep files and returns
+// a TemplateProject for each pair of a template and one of its parameter files. Parameter files are
+// paired with a template by naming convention, e.g. main.bicep with main.bicepparam, main.prod.bicepparam,
+// main.parameters.json and main.parameters.prod.json, or by the using declaration of a .bicepparam file.
+// Templates without any parameter files are returned on their own.
+func FindTemplateProjects(root string) []TemplateProject {
+	l := &templateLocator{
+		referenced: make(map[string]bool),
+	}
+	l.walk(root, 0)
+	sort.Strings(l.templates)
+
+	var projects []TemplateProject
+	for _, path := range l.templates {
+		if l.referenced[path] {
+			log.Debugf("Skipping %s as it is used as a Bicep module or linked template", path)
+			continue
+		}
+
+		if l.isCompiledBicep(path) {
+			log.Debugf("Skipping %s as it is compiled from a Bicep file", path)
+			continue
+		}
+
+		paramFiles := l.parameterFiles(path)
+		if len(paramFiles) == 0 {
+			projects = append(projects, TemplateProject{Path: path})
+			continue
+		}
+
+		for _, paramFile := range paramFiles {
+			projects = append(projects, TemplateProject{Path: path, ParametersFile: paramFile})
+		}
+	}
+
+	return projects
+}
+
+type templateLocator struct {
+	templates      []string
+	jsonParamFiles []string
+	bicepParams    map[string]string
+	// referenced are the templates used as Bicep modules or linked templates
+	referenced map[string]bool
+}
+
+func (l *templateLocator) walk(dir string, level int) {
+	if level >= maxTemplateSearchDepth {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Debugf("Could not read directory %s: %s", dir, err)
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		if entry.IsDir() {
+			if !strings.HasPrefix(name, ".") && name != "node_modules" {
+				l.walk(path, level+1)
+			}
+			continue
+		}
+
+		switch {
+		case isBicepFile(path):
+			l.addBicepFile(path)
+		case isBicepParamFile(path):
+			l.addBicepParamFile(path)
+		case strings.EqualFold(filepath.Ext(path), ".json"):
+			l.addJSONFile(path)
+		}
+	}
+}
+
+func (l *templateLocator) addBicepFile(path string) {
+	f, err := readBicepFile(path)
+	if err != nil {
+		log.Debugf("Skipping %s: %s", path, err)
+		return
+	}
+
+	l.templates = append(l.templates, path)
+
+	for _, decl := range f.declarations {
+		m, ok := decl.(*bicepModule)
+		if !ok || isBicepRegistryModule(m.path) {
+			continue
+		}
+		l.referenced[filepath.Join(filepath.Dir(path), filepath.FromSlash(m.path))] = true
+	}
+}
+
+func (l *templateLocator) addBicepParamFile(path string) {
+	if l.bicepParams == nil {
+		l.bicepParams = make(map[string]string)
+	}
+
+	var using string
+	if f, err := readBicepFile(path); err == nil && f.using != "" {
+		using = filepath.Join(filepath.Dir(path), filepath.FromSlash(f.using))
+	}
+	l.bicepParams[path] = using
+}
+
+func (l *templateLocator) addJSONFile(path string) {
+	b, err := os.ReadFile(path)
+	if err != nil || !gjson.ValidBytes(b) {
+		return
+	}
+
+	schema := strings.ToLower(gjson.GetBytes(b, "$schema").String())
+	switch {
+	case strings.Contains(schema, "deploymentparameters.json"):
+		l.jsonParamFiles = append(l.jsonParamFiles, path)
+	case strings.Contains(schema, "deploymenttemplate.json"):
+		l.templates = append(l.templates, path)
+
+		v, err := decodeJSON(b)
+		if err != nil {
+			return
+		}
+
+		for _, link := range findTemplateLinks(v) {
+			l.referenced[filepath.Join(filepath.Dir(path), filepath.FromSlash(link))] = true
+		}
+	}
+}
+
+// findTemplateLinks returns the local paths of the templateLinks in a template, which are
+// either a relativePath or a uri without a scheme.
+func findTemplateLinks(v interface{}) []string {
+	var links []string
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if strings.EqualFold(k, "templateLink") {
+				linkObj, _ := item.(map[string]interface{})
+				for _, key := range []string{"relativePath", "uri"} {
+					link, _ := getCaseInsensitive(linkObj, key)
+					if s, ok := link.(string); ok && s != "" && !isTemplateExpression(s) && !strings.Contains(s, "://") {
+						links = append(links, s)
+					}
+				}
+				continue
+			}
+			links = append(links, findTemplateLinks(item)...)
+		}
+	case []interface{}:
+		for _, item := range val {
+			links = append(links, findTemplateLinks(item)...)
+		}
+	}
+
+	return links
+}
+
+// isCompiledBicep returns true for a JSON template that was built from a Bicep file next to it,
+// e.g. main.json from main.bicep, since it would otherwise be counted twice.
+func (l *templateLocator) isCompiledBicep(path string) bool {
+	if isBicepFile(path) {
+		return false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil || gjson.GetBytes(b, "metadata._generator.name").String() != "bicep" {
+		return false
+	}
+
+	bicepPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".bicep"
+	for _, t := range l.templates {
+		if t == bicepPath {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parameterFiles returns the parameter files for the template at path, sorted by path.
+func (l *templateLocator) parameterFiles(path string) []string {
+	dir := filepath.Dir(path)
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	var files []string
+
+	for _, p := range l.jsonParamFiles {
+		if filepath.Dir(p) != dir {
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		if name == stem+".parameters" || strings.HasPrefix(name, stem+".parameters.") ||
+			(strings.HasPrefix(name, stem+".") && strings.HasSuffix(name, ".parameters")) {
+			files = append(files, p)
+		}
+	}
+
+	for p, using := range l.bicepParams {
+		if using != "" {
+			if using == path {
+				files = append(files, p)
+			}
+			continue
+		}
+
+		name := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		if filepath.Dir(p) == dir && (name == stem || strings.HasPrefix(name, stem+".")) {
+			files = append(files, p)
+		}
+	}
+
+	sort.Strings(files)
+
+	return files
+}
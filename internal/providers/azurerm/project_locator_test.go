@@ -0,0 +1,37 @@
+package azurerm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTemplateProjects(t *testing.T) {
+	root := filepath.Join("testdata", "repo")
+	web := filepath.Join(root, "apps", "web")
+	infra := filepath.Join(root, "infra")
+
+	projects := FindTemplateProjects(root)
+
+	// Modules, linked templates, compiled Bicep and files in dot and node_modules directories are skipped
+	assert.Equal(t, []TemplateProject{
+		{Path: filepath.Join(web, "main.bicep"), ParametersFile: filepath.Join(web, "main.bicepparam")},
+		{Path: filepath.Join(web, "main.bicep"), ParametersFile: filepath.Join(web, "main.prod.bicepparam")},
+		{Path: filepath.Join(infra, "azuredeploy.json"), ParametersFile: filepath.Join(infra, "azuredeploy.parameters.json")},
+	}, projects)
+}
+
+func TestFindTemplateProjectsWithoutParameters(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.bicep"), []byte("param name string = 'test'\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.parameters.json"), []byte(`{
+  "$schema": "https://schema.management.azure.com/schemas/2019-04-01/deploymentParameters.json#",
+  "parameters": {}
+}`), 0600))
+
+	assert.Equal(t, []TemplateProject{{Path: filepath.Join(dir, "main.bicep")}}, FindTemplateProjects(dir))
+	assert.Empty(t, FindTemplateProjects(t.TempDir()))
+}
@@ -0,0 +1,81 @@
+package azurerm
+
+import (
+	"os"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/ui"
+	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// AzureRMResourceListProvider costs the resources of the JSON output of `az resource list`, e.g. to
+// generate an Infracost JSON baseline of the deployed resources for infracost diff --compare-to.
+type AzureRMResourceListProvider struct {
+	ctx  *config.ProjectContext
+	Path string
+}
+
+func NewResourceListJsonProvider(ctx *config.ProjectContext) *AzureRMResourceListProvider {
+	return &AzureRMResourceListProvider{
+		ctx:  ctx,
+		Path: ctx.ProjectConfig.Path,
+	}
+}
+
+func (p *AzureRMResourceListProvider) Type() string {
+	return "azurerm_resource_list_json"
+}
+
+func (p *AzureRMResourceListProvider) DisplayType() string {
+	return "Azure resource list JSON"
+}
+
+func (p *AzureRMResourceListProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ArmSubscriptionId = p.ctx.ProjectConfig.ArmSubscriptionId
+	metadata.ArmResourceGroup = p.ctx.ProjectConfig.ArmResourceGroup
+}
+
+func (p *AzureRMResourceListProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	spinner := ui.NewSpinner("Extracting only cost-related params from resource list", ui.SpinnerOptions{
+		EnableLogging: p.ctx.RunContext.Config.IsLogging(),
+		NoColor:       p.ctx.RunContext.Config.NoColor,
+		Indent:        "  ",
+	})
+	defer spinner.Fail()
+
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading Azure resource list JSON file")
+	}
+
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+	parser := NewParser(p.ctx)
+
+	data := resourceListValues(gjson.ParseBytes(b))
+	parser.addValues(data...)
+	for i := range data {
+		partial, err := parser.parsePartialResource(&data[i], usage)
+		if err != nil {
+			return []*schema.Project{project}, errors.Wrap(err, "Error parsing Azure resource list JSON file")
+		}
+
+		if partial != nil {
+			project.PartialResources = append(project.PartialResources, partial)
+		}
+	}
+
+	spinner.Success()
+
+	return []*schema.Project{project}, nil
+}
@@ -0,0 +1,45 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getActiveDirectoryDomainServiceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_active_directory_domain_service",
+		RFunc: NewActiveDirectoryDomainService,
+	}
+}
+
+func NewActiveDirectoryDomainService(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ActiveDirectoryDomainService{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		SKU:     d.GetStringOrDefault("properties.sku", "Enterprise"),
+	}
+	r.PopulateUsage(u)
+	res := r.BuildResource()
+
+	// The first replica set is the domain service itself, ARM declares the other replica sets in its
+	// properties rather than as azurerm_active_directory_domain_service_replica_set resources.
+	replicaSets := d.Get("properties.replicaSets").Array()
+	for i := 1; i < len(replicaSets); i++ {
+		region := r.Region
+		if location := replicaSets[i].Get("location").String(); location != "" {
+			region = util.ToAzureCLIName(location)
+		}
+
+		replicaSet := &azure.ActiveDirectoryDomainServiceReplicaSet{
+			Address:            fmt.Sprintf("replica_set[%d]", i),
+			Region:             region,
+			DomainServiceIDSKU: r.SKU,
+		}
+		res.SubResources = append(res.SubResources, replicaSet.BuildResource())
+	}
+
+	return res
+}
@@ -0,0 +1,27 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getAPIManagementRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_api_management",
+		RFunc: NewAPIManagement,
+	}
+}
+
+func NewAPIManagement(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	// ARM splits the SKU into a name and capacity, the struct expects the Terraform format, e.g. Developer_1
+	r := &azure.APIManagement{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		SKUName: fmt.Sprintf("%s_%d", d.Get("sku.name").String(), d.Get("sku.capacity").Int()),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,29 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getAppServiceCertificateOrderRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_app_service_certificate_order",
+		RFunc: NewAppServiceCertificateOrder,
+	}
+}
+
+func NewAppServiceCertificateOrder(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	productType := "Standard"
+	if strings.Contains(strings.ToLower(d.Get("properties.productType").String()), "wildcard") {
+		productType = "WildCard"
+	}
+
+	r := &azure.AppServiceCertificateOrder{
+		Address:     d.Address,
+		ProductType: productType,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
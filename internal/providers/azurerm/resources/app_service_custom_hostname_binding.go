@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getAppServiceCustomHostnameBindingRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_app_service_custom_hostname_binding",
+		RFunc: NewAppServiceCustomHostnameBinding,
+	}
+}
+
+func NewAppServiceCustomHostnameBinding(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AppServiceCustomHostnameBinding{
+		Address:  d.Address,
+		Region:   util.LookupRegion(d, []string{}),
+		SSLState: d.Get("properties.sslState").String(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
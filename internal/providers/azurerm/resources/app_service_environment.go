@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/tidwall/gjson"
+)
+
+// appServiceEnvironmentTiers maps the front end size of an App Service Environment to its Isolated pricing tier
+var appServiceEnvironmentTiers = map[string]string{
+	"small":          "I1",
+	"standard_d1_v2": "I1",
+	"medium":         "I2",
+	"standard_d2_v2": "I2",
+	"large":          "I3",
+	"standard_d3_v2": "I3",
+}
+
+func getAppServiceEnvironmentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_app_service_environment",
+		RFunc: NewAppServiceEnvironment,
+	}
+}
+
+func NewAppServiceEnvironment(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AppServiceEnvironment{
+		Address:     d.Address,
+		Region:      util.LookupRegion(d, []string{}),
+		PricingTier: appServiceEnvironmentTiers[strings.ToLower(d.Get("properties.multiSize").String())],
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+// mapAppServiceEnvironmentTfResource maps ASEv3 separately, it has no stamp fee and is priced per plan instead.
+func mapAppServiceEnvironmentTfResource(rawResource *gjson.Result) string {
+	if strings.EqualFold(rawResource.Get("kind").Str, "ASEV3") {
+		return "azurerm_app_service_environment_v3"
+	}
+
+	return "azurerm_app_service_environment"
+}
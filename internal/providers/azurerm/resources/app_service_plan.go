@@ -18,7 +18,7 @@ func NewAppServicePlan(d *schema.ResourceData, u *schema.UsageData) *schema.Reso
 		Address:     d.Address,
 		Region:      util.LookupRegion(d, []string{}),
 		SKUSize:     d.Get("sku.name").String(),
-		SKUCapacity: d.Get("skuCapacity.default").Int(),
+		SKUCapacity: d.GetInt64OrDefault("sku.capacity", d.Get("skuCapacity.default").Int()),
 		Kind:        d.Get("kind").String(),
 	}
 	r.PopulateUsage(u)
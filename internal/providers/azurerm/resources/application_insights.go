@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getApplicationInsightsRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_application_insights",
+		RFunc: NewApplicationInsights,
+	}
+}
+
+func NewApplicationInsights(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ApplicationInsights{
+		Address:         d.Address,
+		Region:          util.LookupRegion(d, []string{}),
+		RetentionInDays: d.GetInt64OrDefault("properties.RetentionInDays", 90),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
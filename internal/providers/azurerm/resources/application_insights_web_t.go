@@ -0,0 +1,25 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getApplicationInsightsWebTestRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_application_insights_web_test",
+		RFunc: NewApplicationInsightsWebTest,
+	}
+}
+
+func NewApplicationInsightsWebTest(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ApplicationInsightsWebTest{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		Enabled: d.Get("properties.Enabled").Bool(),
+		Kind:    d.Get("properties.Kind").String(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
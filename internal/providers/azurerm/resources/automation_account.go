@@ -0,0 +1,59 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getAutomationAccountRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_automation_account",
+		RFunc: NewAutomationAccount,
+	}
+}
+
+func NewAutomationAccount(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AutomationAccount{Address: d.Address, Region: util.LookupRegion(d, []string{})}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getAutomationDSCConfigurationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_automation_dsc_configuration",
+		RFunc: NewAutomationDSCConfiguration,
+	}
+}
+
+func NewAutomationDSCConfiguration(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AutomationDSCConfiguration{Address: d.Address, Region: util.LookupRegion(d, []string{})}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getAutomationDSCNodeConfigurationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_automation_dsc_nodeconfiguration",
+		RFunc: NewAutomationDSCNodeConfiguration,
+	}
+}
+
+func NewAutomationDSCNodeConfiguration(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AutomationDSCNodeConfiguration{Address: d.Address, Region: util.LookupRegion(d, []string{})}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getAutomationJobScheduleRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_automation_job_schedule",
+		RFunc: NewAutomationJobSchedule,
+	}
+}
+
+func NewAutomationJobSchedule(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.AutomationJobSchedule{Address: d.Address, Region: util.LookupRegion(d, []string{})}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,25 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getContainerRegistryRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_container_registry",
+		RFunc: NewContainerRegistry,
+		Notes: []string{"Geo-replications are declared as separate Microsoft.ContainerRegistry/registries/replications resources and aren't priced."},
+	}
+}
+
+func NewContainerRegistry(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ContainerRegistry{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		SKU:     d.Get("sku.name").String(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,20 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getDataFactoryRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_data_factory",
+		RFunc: NewDataFactory,
+	}
+}
+
+func NewDataFactory(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.DataFactory{Address: d.Address, Region: util.LookupRegion(d, []string{})}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,103 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/tidwall/gjson"
+)
+
+// Integration runtimes are a single ARM type, Terraform has a resource for each kind of runtime.
+func mapDataFactoryIntegrationRuntimeTfResource(rawResource *gjson.Result) string {
+	if strings.EqualFold(rawResource.Get("properties.type").Str, "SelfHosted") {
+		return "azurerm_data_factory_integration_runtime_self_hosted"
+	}
+
+	if rawResource.Get("properties.typeProperties.ssisProperties").Exists() {
+		return "azurerm_data_factory_integration_runtime_azure_ssis"
+	}
+
+	return "azurerm_data_factory_integration_runtime_azure"
+}
+
+func getDataFactoryIntegrationRuntimeAzureRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_data_factory_integration_runtime_azure",
+		RFunc: NewDataFactoryIntegrationRuntimeAzure,
+	}
+}
+
+func NewDataFactoryIntegrationRuntimeAzure(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	compute := d.GetStringOrDefault("properties.typeProperties.computeProperties.dataFlowProperties.computeType", "General")
+
+	computeType := map[string]string{
+		"general":          "general",
+		"computeoptimized": "compute_optimized",
+		"memoryoptimized":  "memory_optimized",
+	}[strings.ToLower(compute)]
+
+	r := &azure.DataFactoryIntegrationRuntimeAzure{
+		Address:     d.Address,
+		Region:      dataFactoryIntegrationRuntimeRegion(d),
+		Cores:       d.GetInt64OrDefault("properties.typeProperties.computeProperties.dataFlowProperties.coreCount", 8),
+		ComputeType: computeType,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getDataFactoryIntegrationRuntimeAzureSSISRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_data_factory_integration_runtime_azure_ssis",
+		RFunc: NewDataFactoryIntegrationRuntimeAzureSSIS,
+	}
+}
+
+func NewDataFactoryIntegrationRuntimeAzureSSIS(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	licenseType := d.GetStringOrDefault("properties.typeProperties.ssisProperties.licenseType", "LicenseIncluded")
+	edition := d.GetStringOrDefault("properties.typeProperties.ssisProperties.edition", "Standard")
+
+	nodeSize := d.Get("properties.typeProperties.computeProperties.nodeSize").String()
+	instanceType := strings.ReplaceAll(nodeSize, "Standard_", "")
+	instanceType = strings.ReplaceAll(instanceType, "_", " ")
+
+	r := &azure.DataFactoryIntegrationRuntimeAzureSSIS{
+		Address:         d.Address,
+		Region:          dataFactoryIntegrationRuntimeRegion(d),
+		Enterprise:      strings.EqualFold(edition, "Enterprise"),
+		LicenseIncluded: strings.EqualFold(licenseType, "LicenseIncluded"),
+		Instances:       d.GetInt64OrDefault("properties.typeProperties.computeProperties.numberOfNodes", 1),
+		InstanceType:    instanceType,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getDataFactoryIntegrationRuntimeSelfHostedRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_data_factory_integration_runtime_self_hosted",
+		RFunc: NewDataFactoryIntegrationRuntimeSelfHosted,
+	}
+}
+
+func NewDataFactoryIntegrationRuntimeSelfHosted(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.DataFactoryIntegrationRuntimeSelfHosted{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+// dataFactoryIntegrationRuntimeRegion returns the region the runtime's compute runs in,
+// 'AutoResolve' means it runs in the region of the data factory.
+func dataFactoryIntegrationRuntimeRegion(d *schema.ResourceData) string {
+	location := d.Get("properties.typeProperties.computeProperties.location").String()
+	if location != "" && !strings.EqualFold(location, "AutoResolve") {
+		return util.ToAzureCLIName(location)
+	}
+
+	return util.LookupRegion(d, []string{})
+}
@@ -0,0 +1,18 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+var dataFactoryTriggerTypes = map[string]string{
+	"blobeventstrigger":     "azurerm_data_factory_trigger_blob_event",
+	"customeventstrigger":   "azurerm_data_factory_trigger_custom_event",
+	"scheduletrigger":       "azurerm_data_factory_trigger_schedule",
+	"tumblingwindowtrigger": "azurerm_data_factory_tumbling_window",
+}
+
+func mapDataFactoryTriggerTfResource(rawResource *gjson.Result) string {
+	return dataFactoryTriggerTypes[strings.ToLower(rawResource.Get("properties.type").String())]
+}
@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getDatabricksWorkspaceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_databricks_workspace",
+		RFunc: NewDatabricksWorkspace,
+	}
+}
+
+func NewDatabricksWorkspace(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.DatabricksWorkspace{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		SKU:     d.Get("sku.name").String(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
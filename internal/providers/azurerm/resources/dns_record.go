@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// dnsRecord is implemented by the public and private DNS record structs, which are all
+// priced by the number of queries.
+type dnsRecord interface {
+	PopulateUsage(u *schema.UsageData)
+	BuildResource() *schema.Resource
+}
+
+// dnsRecordRegistryItems returns a registry item for each record type. Records are child resources
+// of the (global) DNS zone, so the region falls back to the region of the deployment.
+func dnsRecordRegistryItems() []*schema.RegistryItem {
+	records := map[string]func(address, region string) dnsRecord{
+		"azurerm_dns_a_record": func(address, region string) dnsRecord {
+			return &azure.DNSARecord{Address: address, Region: region}
+		},
+		"azurerm_dns_aaaa_record": func(address, region string) dnsRecord {
+			return &azure.DNSAAAARecord{Address: address, Region: region}
+		},
+		"azurerm_dns_caa_record": func(address, region string) dnsRecord {
+			return &azure.DNSCAARecord{Address: address, Region: region}
+		},
+		"azurerm_dns_cname_record": func(address, region string) dnsRecord {
+			return &azure.DNSCNameRecord{Address: address, Region: region}
+		},
+		"azurerm_dns_mx_record": func(address, region string) dnsRecord {
+			return &azure.DNSMXRecord{Address: address, Region: region}
+		},
+		"azurerm_dns_ns_record": func(address, region string) dnsRecord {
+			return &azure.DNSNSRecord{Address: address, Region: region}
+		},
+		"azurerm_dns_ptr_record": func(address, region string) dnsRecord {
+			return &azure.DNSPtrRecord{Address: address, Region: region}
+		},
+		"azurerm_dns_srv_record": func(address, region string) dnsRecord {
+			return &azure.DNSSrvRecord{Address: address, Region: region}
+		},
+		"azurerm_dns_txt_record": func(address, region string) dnsRecord {
+			return &azure.DNSTxtRecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_a_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSARecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_aaaa_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSAAAARecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_cname_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSCNameRecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_mx_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSMXRecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_ptr_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSPTRRecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_srv_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSSRVRecord{Address: address, Region: region}
+		},
+		"azurerm_private_dns_txt_record": func(address, region string) dnsRecord {
+			return &azure.PrivateDNSTXTRecord{Address: address, Region: region}
+		},
+	}
+
+	items := make([]*schema.RegistryItem, 0, len(records))
+	for name, newRecord := range records {
+		newRecord := newRecord
+		items = append(items, &schema.RegistryItem{
+			Name: name,
+			RFunc: func(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+				r := newRecord(d.Address, util.LookupRegion(d, []string{}))
+				r.PopulateUsage(u)
+				return r.BuildResource()
+			},
+		})
+	}
+
+	return items
+}
@@ -0,0 +1,38 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getExpressRouteGatewayRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_express_route_gateway",
+		RFunc: NewExpressRouteGateway,
+	}
+}
+
+func NewExpressRouteGateway(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ExpressRouteGateway{
+		Address:    d.Address,
+		Region:     util.LookupRegion(d, []string{}),
+		ScaleUnits: d.GetInt64OrDefault("properties.autoScaleConfiguration.bounds.min", 1),
+	}
+	return r.BuildResource()
+}
+
+func getExpressRouteConnectionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_express_route_connection",
+		RFunc: NewExpressRouteConnection,
+	}
+}
+
+func NewExpressRouteConnection(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.ExpressRouteConnection{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+	}
+	return r.BuildResource()
+}
@@ -0,0 +1,113 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// flexibleServerTiers maps the ARM tier of MySQL and PostgreSQL flexible servers
+// to the prefix used in the Terraform SKU names, e.g. GP_Standard_D2ds_v4.
+var flexibleServerTiers = map[string]string{
+	"burstable":       "b",
+	"generalpurpose":  "gp",
+	"memoryoptimized": "mo",
+}
+
+type flexibleServerSku struct {
+	sku          string
+	tier         string
+	instanceType string
+	version      string
+}
+
+// parseFlexibleServerSku parses an ARM SKU, e.g. {"name": "Standard_D2ds_v4", "tier": "GeneralPurpose"}.
+func parseFlexibleServerSku(d *schema.ResourceData) (flexibleServerSku, bool) {
+	tier, ok := flexibleServerTiers[strings.ToLower(d.Get("sku.tier").String())]
+	if !ok {
+		log.Warnf("Unrecognised flexible server tier for resource %s: %s", d.Address, d.Get("sku.tier").String())
+		return flexibleServerSku{}, false
+	}
+
+	name := d.Get("sku.name").String()
+	s := strings.Split(name, "_")
+	if len(s) < 2 || len(s) > 3 {
+		log.Warnf("Unrecognised flexible server SKU format for resource %s: %s", d.Address, name)
+		return flexibleServerSku{}, false
+	}
+
+	parsed := flexibleServerSku{
+		sku:          fmt.Sprintf("%s_%s", strings.ToUpper(tier), name),
+		tier:         tier,
+		instanceType: s[1],
+	}
+	if len(s) > 2 {
+		parsed.version = s[2]
+	}
+
+	return parsed, true
+}
+
+func getMySQLFlexibleServerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_mysql_flexible_server",
+		RFunc: NewMySQLFlexibleServer,
+	}
+}
+
+func NewMySQLFlexibleServer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	sku, ok := parseFlexibleServerSku(d)
+	if !ok {
+		return nil
+	}
+
+	iops := d.Get("properties.storage.iops").Int()
+	if d.Get("properties.storage.autoIoScaling").String() == "Enabled" {
+		iops = 0
+	}
+
+	r := &azure.MySQLFlexibleServer{
+		Address:         d.Address,
+		Region:          util.LookupRegion(d, []string{}),
+		SKU:             sku.sku,
+		Tier:            sku.tier,
+		InstanceType:    sku.instanceType,
+		InstanceVersion: sku.version,
+		Storage:         d.Get("properties.storage.storageSizeGB").Int(),
+		IOPS:            iops,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getPostgreSQLFlexibleServerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_postgresql_flexible_server",
+		RFunc: NewPostgreSQLFlexibleServer,
+	}
+}
+
+func NewPostgreSQLFlexibleServer(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	sku, ok := parseFlexibleServerSku(d)
+	if !ok {
+		return nil
+	}
+
+	r := &azure.PostgreSQLFlexibleServer{
+		Address:         d.Address,
+		Region:          util.LookupRegion(d, []string{}),
+		SKU:             sku.sku,
+		Tier:            sku.tier,
+		InstanceType:    sku.instanceType,
+		InstanceVersion: sku.version,
+		// The struct expects MB like the Terraform storage_mb attribute
+		Storage: d.GetInt64OrDefault("properties.storage.storageSizeGB", 32) * 1024,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,62 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getFrontdoorRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_frontdoor",
+		RFunc: NewFrontdoor,
+	}
+}
+
+func NewFrontdoor(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	routingRules := 0
+	for _, rule := range d.Get("properties.routingRules").Array() {
+		if !strings.EqualFold(rule.Get("properties.enabledState").String(), "Disabled") {
+			routingRules++
+		}
+	}
+
+	r := &azure.Frontdoor{
+		Address:       d.Address,
+		Region:        frontdoorZone(util.LookupRegion(d, []string{})),
+		FrontendHosts: len(d.Get("properties.frontendEndpoints").Array()),
+		RoutingRules:  routingRules,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getFrontdoorFirewallPolicyRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_frontdoor_firewall_policy",
+		RFunc: NewFrontdoorFirewallPolicy,
+	}
+}
+
+func NewFrontdoorFirewallPolicy(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.FrontdoorFirewallPolicy{
+		Address:         d.Address,
+		Region:          frontdoorZone(util.LookupRegion(d, []string{})),
+		CustomRules:     len(d.Get("properties.customRules.rules").Array()),
+		ManagedRulesets: len(d.Get("properties.managedRules.managedRuleSets").Array()),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+// frontdoorZone returns the billing zone that Front Door is priced by in a region.
+func frontdoorZone(region string) string {
+	if strings.HasPrefix(strings.ToLower(region), "usgov") {
+		return "US Gov Zone 1"
+	}
+
+	return tfazure.RegionToZone(region)
+}
@@ -0,0 +1,19 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+var hdInsightClusterKinds = map[string]string{
+	"hadoop":          "azurerm_hdinsight_hadoop_cluster",
+	"hbase":           "azurerm_hdinsight_hbase_cluster",
+	"interactivehive": "azurerm_hdinsight_interactive_query_cluster",
+	"kafka":           "azurerm_hdinsight_kafka_cluster",
+	"spark":           "azurerm_hdinsight_spark_cluster",
+}
+
+func mapHDInsightClusterTfResource(rawResource *gjson.Result) string {
+	return hdInsightClusterKinds[strings.ToLower(rawResource.Get("properties.clusterDefinition.kind").String())]
+}
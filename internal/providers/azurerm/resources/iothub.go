@@ -0,0 +1,42 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getIoTHubRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_iothub",
+		RFunc: NewIoTHub,
+	}
+}
+
+func NewIoTHub(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.IoTHub{
+		Address:  d.Address,
+		Region:   util.LookupRegion(d, []string{}),
+		Sku:      d.Get("sku.name").String(),
+		Capacity: d.GetInt64OrDefault("sku.capacity", 1),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getIoTHubDPSRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_iothub_dps",
+		RFunc: NewIoTHubDPS,
+	}
+}
+
+func NewIoTHubDPS(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.IoTHubDPS{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		Sku:     d.Get("sku.name").String(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,43 @@
+package resources
+
+import (
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// getLoadBalancerRegistryItem prices the load balancing and outbound rules of a load balancer as its
+// subresources, ARM declares them in its properties rather than as azurerm_lb_rule and
+// azurerm_lb_outbound_rule resources.
+func getLoadBalancerRegistryItem() *schema.RegistryItem {
+	lb := newTerraformResourceRegistryItem(tfazure.GetAzureRMLoadBalancerRegistryItem(), loadBalancerAttributes)
+	rule := newTerraformResourceRegistryItem(tfazure.GetAzureRMLoadBalancerRuleRegistryItem(), noTerraformAttributes)
+	outboundRule := newTerraformResourceRegistryItem(tfazure.GetAzureRMLoadBalancerOutboundRuleRegistryItem(), noTerraformAttributes)
+
+	return &schema.RegistryItem{
+		Name:  lb.Name,
+		Notes: lb.Notes,
+		RFunc: func(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+			r := lb.RFunc(d, u)
+			if r == nil || r.IsSkipped {
+				return r
+			}
+
+			for _, rules := range []struct {
+				path string
+				item *schema.RegistryItem
+			}{
+				{"properties.loadBalancingRules", rule},
+				{"properties.outboundRules", outboundRule},
+			} {
+				for _, lbRule := range d.Get(rules.path).Array() {
+					ruleData := schema.NewAzureRMResourceData(rules.item.Name, lbRule.Get("name").String(), d.RawValues)
+					if sub := rules.item.RFunc(ruleData, nil); sub != nil {
+						r.SubResources = append(r.SubResources, sub)
+					}
+				}
+			}
+
+			return r
+		},
+	}
+}
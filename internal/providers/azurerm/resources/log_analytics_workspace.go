@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// logAnalyticsWorkspaceSkus normalises the casing of SKU names, ARM accepts them in any case
+var logAnalyticsWorkspaceSkus = map[string]string{
+	"free":                "Free",
+	"pergb2018":           "PerGB2018",
+	"capacityreservation": "CapacityReservation",
+	"pernode":             "PerNode",
+	"premium":             "Premium",
+	"standalone":          "Standalone",
+	"standard":            "Standard",
+	"lacluster":           "LACluster",
+}
+
+func getLogAnalyticsWorkspaceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_log_analytics_workspace",
+		RFunc: NewLogAnalyticsWorkspace,
+		Notes: []string{"Microsoft Sentinel is declared as a separate solution resource and isn't detected."},
+	}
+}
+
+func NewLogAnalyticsWorkspace(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	sku := d.GetStringOrDefault("properties.sku.name", "PerGB2018")
+	if s, ok := logAnalyticsWorkspaceSkus[strings.ToLower(sku)]; ok {
+		sku = s
+	}
+
+	r := &azure.LogAnalyticsWorkspace{
+		Address:                       d.Address,
+		Region:                        util.LookupRegion(d, []string{}),
+		SKU:                           sku,
+		ReservationCapacityInGBPerDay: d.Get("properties.sku.capacityReservationLevel").Int(),
+		RetentionInDays:               d.GetInt64OrDefault("properties.retentionInDays", 30),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/infracost/infracost/internal/schema"
@@ -22,25 +23,391 @@ func defaultArmToTfMapper(tfResource string) ArmToTfMapper {
 	}
 }
 
-// TODO: see providers/terraform/azure/registry.go
-// Use ASP/Function app as testing resources
-var Registry []*schema.RegistryItem = []*schema.RegistryItem{
+// Registry contains the resources that are priced from an ARM resource, they use the same
+// structs in internal/resources/azure as providers/terraform/azure/registry.go, or the Terraform
+// RFunc for resources that are priced inline, see terraformResourceRegistryItems.
+var Registry []*schema.RegistryItem = append(append([]*schema.RegistryItem{
+	getActiveDirectoryDomainServiceRegistryItem(),
+	getAPIManagementRegistryItem(),
+	getAppServiceCertificateOrderRegistryItem(),
+	getAppServiceCustomHostnameBindingRegistryItem(),
+	getAppServiceEnvironmentRegistryItem(),
 	getAppServicePlanRegistryItem(),
-}
-
+	getApplicationInsightsRegistryItem(),
+	getApplicationInsightsWebTestRegistryItem(),
+	getAutomationAccountRegistryItem(),
+	getAutomationDSCConfigurationRegistryItem(),
+	getAutomationDSCNodeConfigurationRegistryItem(),
+	getAutomationJobScheduleRegistryItem(),
+	getContainerRegistryRegistryItem(),
+	getDataFactoryRegistryItem(),
+	getDataFactoryIntegrationRuntimeAzureRegistryItem(),
+	getDataFactoryIntegrationRuntimeAzureSSISRegistryItem(),
+	getDataFactoryIntegrationRuntimeSelfHostedRegistryItem(),
+	getDatabricksWorkspaceRegistryItem(),
+	getExpressRouteConnectionRegistryItem(),
+	getExpressRouteGatewayRegistryItem(),
+	getFrontdoorFirewallPolicyRegistryItem(),
+	getFrontdoorRegistryItem(),
+	getIoTHubRegistryItem(),
+	getIoTHubDPSRegistryItem(),
+	getLogAnalyticsWorkspaceRegistryItem(),
+	getMySQLFlexibleServerRegistryItem(),
+	getPointToSiteVPNGatewayRegistryItem(),
+	getPostgreSQLFlexibleServerRegistryItem(),
+	getSQLDatabaseRegistryItem(),
+	getSQLManagedInstanceRegistryItem(),
+	getStorageAccountRegistryItem(),
+	getVirtualHubRegistryItem(),
+	getVirtualNetworkPeeringRegistryItem(),
+	getVPNGatewayRegistryItem(),
+	getVPNGatewayConnectionRegistryItem(),
+}, dnsRecordRegistryItems()...), terraformResourceRegistryItems()...)
+
+// azureRMToTerraformResourceMap maps ARM resource types to the Terraform resource they're priced as.
+// Types that are priced by Terraform but not in the Registry yet are mapped too, so they're reported
+// as not supported rather than unknown.
 var azureRMToTerraformResourceMap = ArmToTfResourceMapperMap{
-	"Microsoft.Resources/resourceGroups": defaultArmToTfMapper("azurerm_resource_group"),
-	"Microsoft.Web/serverfarms":          defaultArmToTfMapper("azurerm_app_service_plan"),
-	"Microsoft.Web/sites":                mapWebAppTfResource,
+	// Azure Active Directory Domain Services
+	"Microsoft.AAD/domainServices": defaultArmToTfMapper("azurerm_active_directory_domain_service"),
+
+	// Azure Api Management
+	"Microsoft.ApiManagement/service":               defaultArmToTfMapper("azurerm_api_management"),
+	"Microsoft.ApiManagement/service/apis":          defaultArmToTfMapper("azurerm_api_management_api"),
+	"Microsoft.ApiManagement/service/apis/policies": defaultArmToTfMapper("azurerm_api_management_api_policy"),
+	"Microsoft.ApiManagement/service/backends":      defaultArmToTfMapper("azurerm_api_management_backend"),
+	"Microsoft.ApiManagement/service/groups":        defaultArmToTfMapper("azurerm_api_management_group"),
+	"Microsoft.ApiManagement/service/loggers":       defaultArmToTfMapper("azurerm_api_management_logger"),
+	"Microsoft.ApiManagement/service/namedValues":   defaultArmToTfMapper("azurerm_api_management_named_value"),
+	"Microsoft.ApiManagement/service/policies":      defaultArmToTfMapper("azurerm_api_management_policy"),
+	"Microsoft.ApiManagement/service/products":      defaultArmToTfMapper("azurerm_api_management_product"),
+	"Microsoft.ApiManagement/service/subscriptions": defaultArmToTfMapper("azurerm_api_management_subscription"),
+	"Microsoft.ApiManagement/service/users":         defaultArmToTfMapper("azurerm_api_management_user"),
+
+	// Azure App Service
+	"Microsoft.CertificateRegistration/certificateOrders": defaultArmToTfMapper("azurerm_app_service_certificate_order"),
+	"Microsoft.Web/certificates":                          defaultArmToTfMapper("azurerm_app_service_certificate"),
+	"Microsoft.Web/hostingEnvironments":                   mapAppServiceEnvironmentTfResource,
+	"Microsoft.Web/serverfarms":                           defaultArmToTfMapper("azurerm_app_service_plan"),
+	"Microsoft.Web/sites":                                 mapWebAppTfResource,
+	"Microsoft.Web/sites/hostNameBindings":                defaultArmToTfMapper("azurerm_app_service_custom_hostname_binding"),
+	"Microsoft.Web/sites/slots":                           defaultArmToTfMapper("azurerm_app_service_slot"),
+
+	// Azure Application Insights
+	"Microsoft.Insights/components": defaultArmToTfMapper("azurerm_application_insights"),
+	"Microsoft.Insights/webtests":   defaultArmToTfMapper("azurerm_application_insights_web_test"),
+
+	// Azure Attestation
+	"Microsoft.Attestation/attestationProviders": defaultArmToTfMapper("azurerm_attestation_provider"),
+
+	// Azure Automation
+	"Microsoft.Automation/automationAccounts":                    defaultArmToTfMapper("azurerm_automation_account"),
+	"Microsoft.Automation/automationAccounts/certificates":       defaultArmToTfMapper("azurerm_automation_certificate"),
+	"Microsoft.Automation/automationAccounts/configurations":     defaultArmToTfMapper("azurerm_automation_dsc_configuration"),
+	"Microsoft.Automation/automationAccounts/connections":        defaultArmToTfMapper("azurerm_automation_connection"),
+	"Microsoft.Automation/automationAccounts/credentials":        defaultArmToTfMapper("azurerm_automation_credential"),
+	"Microsoft.Automation/automationAccounts/jobSchedules":       defaultArmToTfMapper("azurerm_automation_job_schedule"),
+	"Microsoft.Automation/automationAccounts/modules":            defaultArmToTfMapper("azurerm_automation_module"),
+	"Microsoft.Automation/automationAccounts/nodeConfigurations": defaultArmToTfMapper("azurerm_automation_dsc_nodeconfiguration"),
+	"Microsoft.Automation/automationAccounts/runbooks":           defaultArmToTfMapper("azurerm_automation_runbook"),
+	"Microsoft.Automation/automationAccounts/schedules":          defaultArmToTfMapper("azurerm_automation_schedule"),
+
+	// Azure Base
+	"Microsoft.Authorization/locks":                    defaultArmToTfMapper("azurerm_management_lock"),
+	"Microsoft.Authorization/policyAssignments":        defaultArmToTfMapper("azurerm_policy_assignment"),
+	"Microsoft.Authorization/policyDefinitions":        defaultArmToTfMapper("azurerm_policy_definition"),
+	"Microsoft.Authorization/policySetDefinitions":     defaultArmToTfMapper("azurerm_policy_set_definition"),
+	"Microsoft.Authorization/roleAssignments":          defaultArmToTfMapper("azurerm_role_assignment"),
+	"Microsoft.Authorization/roleDefinitions":          defaultArmToTfMapper("azurerm_role_definition"),
+	"Microsoft.Blueprint/blueprintAssignments":         defaultArmToTfMapper("azurerm_blueprint_assignment"),
+	"Microsoft.Management/managementGroups":            defaultArmToTfMapper("azurerm_management_group"),
+	"Microsoft.ManagedIdentity/userAssignedIdentities": defaultArmToTfMapper("azurerm_user_assigned_identity"),
+	"Microsoft.Portal/dashboards":                      defaultArmToTfMapper("azurerm_dashboard"),
+	"Microsoft.Resources/deployments":                  mapTemplateDeploymentTfResource,
+	"Microsoft.Resources/resourceGroups":               defaultArmToTfMapper("azurerm_resource_group"),
+	"Microsoft.Solutions/applicationDefinitions":       defaultArmToTfMapper("azurerm_managed_application_definition"),
+	"Microsoft.Solutions/applications":                 defaultArmToTfMapper("azurerm_managed_application"),
+
+	// Azure CDN
+	"Microsoft.Cdn/profiles":           defaultArmToTfMapper("azurerm_cdn_profile"),
+	"Microsoft.Cdn/profiles/endpoints": defaultArmToTfMapper("azurerm_cdn_endpoint"),
+
+	// Azure Compute
+	"Microsoft.Compute/availabilitySets":         defaultArmToTfMapper("azurerm_availability_set"),
+	"Microsoft.Compute/disks":                    defaultArmToTfMapper("azurerm_managed_disk"),
+	"Microsoft.Compute/images":                   defaultArmToTfMapper("azurerm_image"),
+	"Microsoft.Compute/proximityPlacementGroups": defaultArmToTfMapper("azurerm_proximity_placement_group"),
+	"Microsoft.Compute/snapshots":                defaultArmToTfMapper("azurerm_snapshot"),
+	"Microsoft.Compute/sshPublicKeys":            defaultArmToTfMapper("azurerm_ssh_public_key"),
+	"Microsoft.Compute/virtualMachines":          mapVirtualMachineTfResource,
+	"Microsoft.Compute/virtualMachineScaleSets":  mapVirtualMachineScaleSetTfResource,
+
+	// Azure Container Registry
+	"Microsoft.ContainerRegistry/registries":           defaultArmToTfMapper("azurerm_container_registry"),
+	"Microsoft.ContainerRegistry/registries/scopeMaps": defaultArmToTfMapper("azurerm_container_registry_scope_map"),
+	"Microsoft.ContainerRegistry/registries/tokens":    defaultArmToTfMapper("azurerm_container_registry_token"),
+	"Microsoft.ContainerRegistry/registries/webhooks":  defaultArmToTfMapper("azurerm_container_registry_webhook"),
+
+	// Azure CosmosDB
+	"Microsoft.DocumentDB/databaseAccounts":                                          defaultArmToTfMapper("azurerm_cosmosdb_account"),
+	"Microsoft.DocumentDB/databaseAccounts/cassandraKeyspaces":                       defaultArmToTfMapper("azurerm_cosmosdb_cassandra_keyspace"),
+	"Microsoft.DocumentDB/databaseAccounts/cassandraKeyspaces/tables":                defaultArmToTfMapper("azurerm_cosmosdb_cassandra_table"),
+	"Microsoft.DocumentDB/databaseAccounts/gremlinDatabases":                         defaultArmToTfMapper("azurerm_cosmosdb_gremlin_database"),
+	"Microsoft.DocumentDB/databaseAccounts/gremlinDatabases/graphs":                  defaultArmToTfMapper("azurerm_cosmosdb_gremlin_graph"),
+	"Microsoft.DocumentDB/databaseAccounts/mongodbDatabases":                         defaultArmToTfMapper("azurerm_cosmosdb_mongo_database"),
+	"Microsoft.DocumentDB/databaseAccounts/mongodbDatabases/collections":             defaultArmToTfMapper("azurerm_cosmosdb_mongo_collection"),
+	"Microsoft.DocumentDB/databaseAccounts/sqlDatabases":                             defaultArmToTfMapper("azurerm_cosmosdb_sql_database"),
+	"Microsoft.DocumentDB/databaseAccounts/sqlDatabases/containers":                  defaultArmToTfMapper("azurerm_cosmosdb_sql_container"),
+	"Microsoft.DocumentDB/databaseAccounts/sqlDatabases/containers/storedProcedures": defaultArmToTfMapper("azurerm_cosmosdb_sql_stored_procedure"),
+	"Microsoft.DocumentDB/databaseAccounts/sqlDatabases/containers/triggers":         defaultArmToTfMapper("azurerm_cosmosdb_sql_trigger"),
+	"Microsoft.DocumentDB/databaseAccounts/tables":                                   defaultArmToTfMapper("azurerm_cosmosdb_table"),
+
+	// Azure Data Factory
+	"Microsoft.DataFactory/factories":                     defaultArmToTfMapper("azurerm_data_factory"),
+	"Microsoft.DataFactory/factories/dataflows":           defaultArmToTfMapper("azurerm_data_factory_data_flow"),
+	"Microsoft.DataFactory/factories/datasets":            defaultArmToTfMapper("azurerm_data_factory_custom_dataset"),
+	"Microsoft.DataFactory/factories/integrationRuntimes": mapDataFactoryIntegrationRuntimeTfResource,
+	"Microsoft.DataFactory/factories/linkedservices":      defaultArmToTfMapper("azurerm_data_factory_linked_custom_service"),
+	"Microsoft.DataFactory/factories/pipelines":           defaultArmToTfMapper("azurerm_data_factory_pipeline"),
+	"Microsoft.DataFactory/factories/triggers":            mapDataFactoryTriggerTfResource,
+
+	// Azure Database
+	"Microsoft.DBforMariaDB/servers":                           defaultArmToTfMapper("azurerm_mariadb_server"),
+	"Microsoft.DBforMariaDB/servers/configurations":            defaultArmToTfMapper("azurerm_mariadb_configuration"),
+	"Microsoft.DBforMariaDB/servers/firewallRules":             defaultArmToTfMapper("azurerm_mariadb_firewall_rule"),
+	"Microsoft.DBforMySQL/flexibleServers":                     defaultArmToTfMapper("azurerm_mysql_flexible_server"),
+	"Microsoft.DBforMySQL/flexibleServers/configurations":      defaultArmToTfMapper("azurerm_mysql_flexible_server_configuration"),
+	"Microsoft.DBforMySQL/flexibleServers/databases":           defaultArmToTfMapper("azurerm_mysql_flexible_database"),
+	"Microsoft.DBforMySQL/flexibleServers/firewallRules":       defaultArmToTfMapper("azurerm_mysql_flexible_server_firewall_rule"),
+	"Microsoft.DBforMySQL/servers":                             defaultArmToTfMapper("azurerm_mysql_server"),
+	"Microsoft.DBforMySQL/servers/firewallRules":               defaultArmToTfMapper("azurerm_mysql_firewall_rule"),
+	"Microsoft.DBforPostgreSQL/flexibleServers":                defaultArmToTfMapper("azurerm_postgresql_flexible_server"),
+	"Microsoft.DBforPostgreSQL/flexibleServers/configurations": defaultArmToTfMapper("azurerm_postgresql_flexible_server_configuration"),
+	"Microsoft.DBforPostgreSQL/flexibleServers/databases":      defaultArmToTfMapper("azurerm_postgresql_flexible_server_database"),
+	"Microsoft.DBforPostgreSQL/flexibleServers/firewallRules":  defaultArmToTfMapper("azurerm_postgresql_flexible_server_firewall_rule"),
+	"Microsoft.DBforPostgreSQL/servers":                        defaultArmToTfMapper("azurerm_postgresql_server"),
+	"Microsoft.DBforPostgreSQL/servers/configurations":         defaultArmToTfMapper("azurerm_postgresql_configuration"),
+	"Microsoft.DBforPostgreSQL/servers/firewallRules":          defaultArmToTfMapper("azurerm_postgresql_firewall_rule"),
+
+	// Azure Databricks
+	"Microsoft.Databricks/workspaces": defaultArmToTfMapper("azurerm_databricks_workspace"),
+
+	// Azure DNS
+	"Microsoft.Network/dnsZones":                            defaultArmToTfMapper("azurerm_dns_zone"),
+	"Microsoft.Network/dnsZones/A":                          defaultArmToTfMapper("azurerm_dns_a_record"),
+	"Microsoft.Network/dnsZones/AAAA":                       defaultArmToTfMapper("azurerm_dns_aaaa_record"),
+	"Microsoft.Network/dnsZones/CAA":                        defaultArmToTfMapper("azurerm_dns_caa_record"),
+	"Microsoft.Network/dnsZones/CNAME":                      defaultArmToTfMapper("azurerm_dns_cname_record"),
+	"Microsoft.Network/dnsZones/MX":                         defaultArmToTfMapper("azurerm_dns_mx_record"),
+	"Microsoft.Network/dnsZones/NS":                         defaultArmToTfMapper("azurerm_dns_ns_record"),
+	"Microsoft.Network/dnsZones/PTR":                        defaultArmToTfMapper("azurerm_dns_ptr_record"),
+	"Microsoft.Network/dnsZones/SRV":                        defaultArmToTfMapper("azurerm_dns_srv_record"),
+	"Microsoft.Network/dnsZones/TXT":                        defaultArmToTfMapper("azurerm_dns_txt_record"),
+	"Microsoft.Network/privateDnsZones":                     defaultArmToTfMapper("azurerm_private_dns_zone"),
+	"Microsoft.Network/privateDnsZones/A":                   defaultArmToTfMapper("azurerm_private_dns_a_record"),
+	"Microsoft.Network/privateDnsZones/AAAA":                defaultArmToTfMapper("azurerm_private_dns_aaaa_record"),
+	"Microsoft.Network/privateDnsZones/CNAME":               defaultArmToTfMapper("azurerm_private_dns_cname_record"),
+	"Microsoft.Network/privateDnsZones/MX":                  defaultArmToTfMapper("azurerm_private_dns_mx_record"),
+	"Microsoft.Network/privateDnsZones/PTR":                 defaultArmToTfMapper("azurerm_private_dns_ptr_record"),
+	"Microsoft.Network/privateDnsZones/SRV":                 defaultArmToTfMapper("azurerm_private_dns_srv_record"),
+	"Microsoft.Network/privateDnsZones/TXT":                 defaultArmToTfMapper("azurerm_private_dns_txt_record"),
+	"Microsoft.Network/privateDnsZones/virtualNetworkLinks": defaultArmToTfMapper("azurerm_private_dns_zone_virtual_network_link"),
+
+	// Azure Event Hub
+	"Microsoft.EventHub/namespaces":                              defaultArmToTfMapper("azurerm_eventhub_namespace"),
+	"Microsoft.EventHub/namespaces/authorizationRules":           defaultArmToTfMapper("azurerm_eventhub_namespace_authorization_rule"),
+	"Microsoft.EventHub/namespaces/eventhubs":                    defaultArmToTfMapper("azurerm_eventhub"),
+	"Microsoft.EventHub/namespaces/eventhubs/authorizationRules": defaultArmToTfMapper("azurerm_eventhub_authorization_rule"),
+	"Microsoft.EventHub/namespaces/eventhubs/consumergroups":     defaultArmToTfMapper("azurerm_eventhub_consumer_group"),
+
+	// Azure Front Door
+	"Microsoft.Network/frontDoors":                              defaultArmToTfMapper("azurerm_frontdoor"),
+	"Microsoft.Network/frontDoors/rulesEngines":                 defaultArmToTfMapper("azurerm_frontdoor_rules_engine"),
+	"Microsoft.Network/FrontDoorWebApplicationFirewallPolicies": defaultArmToTfMapper("azurerm_frontdoor_firewall_policy"),
+
+	// Azure HDInsight
+	"Microsoft.HDInsight/clusters": mapHDInsightClusterTfResource,
+
+	// Azure IoT
+	"Microsoft.Devices/IotHubs":                                  defaultArmToTfMapper("azurerm_iothub"),
+	"Microsoft.Devices/IotHubs/certificates":                     defaultArmToTfMapper("azurerm_iothub_certificate"),
+	"Microsoft.Devices/IotHubs/eventHubEndpoints/ConsumerGroups": defaultArmToTfMapper("azurerm_iothub_consumer_group"),
+	"Microsoft.Devices/provisioningServices":                     defaultArmToTfMapper("azurerm_iothub_dps"),
+	"Microsoft.Devices/provisioningServices/certificates":        defaultArmToTfMapper("azurerm_iothub_dps_certificate"),
+
+	// Azure Key Vault
+	"Microsoft.KeyVault/managedHSMs":           defaultArmToTfMapper("azurerm_key_vault_managed_hardware_security_module"),
+	"Microsoft.KeyVault/vaults":                defaultArmToTfMapper("azurerm_key_vault"),
+	"Microsoft.KeyVault/vaults/accessPolicies": defaultArmToTfMapper("azurerm_key_vault_access_policy"),
+	"Microsoft.KeyVault/vaults/keys":           defaultArmToTfMapper("azurerm_key_vault_key"),
+	"Microsoft.KeyVault/vaults/secrets":        defaultArmToTfMapper("azurerm_key_vault_secret"),
+
+	// Azure Kubernetes Service
+	"Microsoft.ContainerService/managedClusters":            defaultArmToTfMapper("azurerm_kubernetes_cluster"),
+	"Microsoft.ContainerService/managedClusters/agentPools": defaultArmToTfMapper("azurerm_kubernetes_cluster_node_pool"),
+
+	// Azure Lighthouse (Delegated Resoure Management)
+	"Microsoft.ManagedServices/registrationAssignments": defaultArmToTfMapper("azurerm_lighthouse_assignment"),
+	"Microsoft.ManagedServices/registrationDefinitions": defaultArmToTfMapper("azurerm_lighthouse_definition"),
+
+	// Azure Log Analytics
+	"Microsoft.OperationalInsights/workspaces":                defaultArmToTfMapper("azurerm_log_analytics_workspace"),
+	"Microsoft.OperationalInsights/workspaces/dataExports":    defaultArmToTfMapper("azurerm_log_analytics_data_export_rule"),
+	"Microsoft.OperationalInsights/workspaces/linkedServices": defaultArmToTfMapper("azurerm_log_analytics_linked_service"),
+	"Microsoft.OperationalInsights/workspaces/savedSearches":  defaultArmToTfMapper("azurerm_log_analytics_saved_search"),
+	"Microsoft.OperationsManagement/solutions":                defaultArmToTfMapper("azurerm_log_analytics_solution"),
+
+	// Azure Logic Apps
+	"Microsoft.Logic/integrationServiceEnvironments": defaultArmToTfMapper("azurerm_integration_service_environment"),
+
+	// Azure Networking
+	"Microsoft.Network/applicationGateways":                          defaultArmToTfMapper("azurerm_application_gateway"),
+	"Microsoft.Network/applicationSecurityGroups":                    defaultArmToTfMapper("azurerm_application_security_group"),
+	"Microsoft.Network/azureFirewalls":                               defaultArmToTfMapper("azurerm_firewall"),
+	"Microsoft.Network/bastionHosts":                                 defaultArmToTfMapper("azurerm_bastion_host"),
+	"Microsoft.Network/connections":                                  defaultArmToTfMapper("azurerm_virtual_network_gateway_connection"),
+	"Microsoft.Network/expressRouteGateways":                         defaultArmToTfMapper("azurerm_express_route_gateway"),
+	"Microsoft.Network/expressRouteGateways/expressRouteConnections": defaultArmToTfMapper("azurerm_express_route_connection"),
+	"Microsoft.Network/firewallPolicies":                             defaultArmToTfMapper("azurerm_firewall_policy"),
+	"Microsoft.Network/firewallPolicies/ruleCollectionGroups":        defaultArmToTfMapper("azurerm_firewall_policy_rule_collection_group"),
+	"Microsoft.Network/loadBalancers":                                defaultArmToTfMapper("azurerm_lb"),
+	"Microsoft.Network/loadBalancers/backendAddressPools":            defaultArmToTfMapper("azurerm_lb_backend_address_pool"),
+	"Microsoft.Network/loadBalancers/inboundNatRules":                defaultArmToTfMapper("azurerm_lb_nat_rule"),
+	"Microsoft.Network/localNetworkGateways":                         defaultArmToTfMapper("azurerm_local_network_gateway"),
+	"Microsoft.Network/natGateways":                                  defaultArmToTfMapper("azurerm_nat_gateway"),
+	"Microsoft.Network/networkInterfaces":                            defaultArmToTfMapper("azurerm_network_interface"),
+	"Microsoft.Network/networkSecurityGroups":                        defaultArmToTfMapper("azurerm_network_security_group"),
+	"Microsoft.Network/networkSecurityGroups/securityRules":          defaultArmToTfMapper("azurerm_network_security_rule"),
+	"Microsoft.Network/privateEndpoints":                             defaultArmToTfMapper("azurerm_private_endpoint"),
+	"Microsoft.Network/privateLinkServices":                          defaultArmToTfMapper("azurerm_private_link_service"),
+	"Microsoft.Network/publicIPAddresses":                            defaultArmToTfMapper("azurerm_public_ip"),
+	"Microsoft.Network/publicIPPrefixes":                             defaultArmToTfMapper("azurerm_public_ip_prefix"),
+	"Microsoft.Network/virtualNetworkGateways":                       defaultArmToTfMapper("azurerm_virtual_network_gateway"),
+	"Microsoft.Network/virtualNetworks":                              defaultArmToTfMapper("azurerm_virtual_network"),
+	"Microsoft.Network/virtualNetworks/subnets":                      defaultArmToTfMapper("azurerm_subnet"),
+	"Microsoft.Network/virtualNetworks/virtualNetworkPeerings":       defaultArmToTfMapper("azurerm_virtual_network_peering"),
+
+	// Azure Notification Hub
+	"Microsoft.NotificationHubs/namespaces":                  defaultArmToTfMapper("azurerm_notification_hub_namespace"),
+	"Microsoft.NotificationHubs/namespaces/notificationHubs": defaultArmToTfMapper("azurerm_notification_hub"),
+
+	// Azure Redis
+	"Microsoft.Cache/Redis":               defaultArmToTfMapper("azurerm_redis_cache"),
+	"Microsoft.Cache/Redis/firewallRules": defaultArmToTfMapper("azurerm_redis_firewall_rule"),
+	"Microsoft.Cache/Redis/linkedServers": defaultArmToTfMapper("azurerm_redis_linked_server"),
+
+	// Azure Search
+	"Microsoft.Search/searchServices": defaultArmToTfMapper("azurerm_search_service"),
+
+	// Azure Sentinel
+	"Microsoft.SecurityInsights/dataConnectors": mapSentinelDataConnectorTfResource,
+
+	// Azure SQL
+	"Microsoft.Sql/managedInstances":            defaultArmToTfMapper("azurerm_sql_managed_instance"),
+	"Microsoft.Sql/servers":                     defaultArmToTfMapper("azurerm_sql_server"),
+	"Microsoft.Sql/servers/databases":           defaultArmToTfMapper("azurerm_mssql_database"),
+	"Microsoft.Sql/servers/elasticPools":        defaultArmToTfMapper("azurerm_mssql_elasticpool"),
+	"Microsoft.Sql/servers/firewallRules":       defaultArmToTfMapper("azurerm_mssql_firewall_rule"),
+	"Microsoft.Sql/servers/virtualNetworkRules": defaultArmToTfMapper("azurerm_sql_virtual_network_rule"),
+
+	// Azure Storage
+	"Microsoft.Storage/storageAccounts":                         defaultArmToTfMapper("azurerm_storage_account"),
+	"Microsoft.Storage/storageAccounts/blobServices/containers": defaultArmToTfMapper("azurerm_storage_container"),
+	"Microsoft.Storage/storageAccounts/inventoryPolicies":       defaultArmToTfMapper("azurerm_storage_blob_inventory_policy"),
+	"Microsoft.Storage/storageAccounts/managementPolicies":      defaultArmToTfMapper("azurerm_storage_management_policy"),
+
+	// Azure Synapse Analytics
+	"Microsoft.Synapse/privateLinkHubs":          defaultArmToTfMapper("azurerm_synapse_private_link_hub"),
+	"Microsoft.Synapse/workspaces":               defaultArmToTfMapper("azurerm_synapse_workspace"),
+	"Microsoft.Synapse/workspaces/bigDataPools":  defaultArmToTfMapper("azurerm_synapse_spark_pool"),
+	"Microsoft.Synapse/workspaces/firewallRules": defaultArmToTfMapper("azurerm_synapse_firewall_rule"),
+	"Microsoft.Synapse/workspaces/sqlPools":      defaultArmToTfMapper("azurerm_synapse_sql_pool"),
+
+	// Azure Virtual Desktop
+	"Microsoft.DesktopVirtualization/applicationGroups":              defaultArmToTfMapper("azurerm_virtual_desktop_application_group"),
+	"Microsoft.DesktopVirtualization/applicationGroups/applications": defaultArmToTfMapper("azurerm_virtual_desktop_application"),
+	"Microsoft.DesktopVirtualization/hostPools":                      defaultArmToTfMapper("azurerm_virtual_desktop_host_pool"),
+	"Microsoft.DesktopVirtualization/workspaces":                     defaultArmToTfMapper("azurerm_virtual_desktop_workspace"),
+
+	// Azure WAN
+	"Microsoft.Network/p2sVpnGateways":             defaultArmToTfMapper("azurerm_point_to_site_vpn_gateway"),
+	"Microsoft.Network/virtualHubs":                defaultArmToTfMapper("azurerm_virtual_hub"),
+	"Microsoft.Network/virtualWans":                defaultArmToTfMapper("azurerm_virtual_wan"),
+	"Microsoft.Network/vpnGateways":                defaultArmToTfMapper("azurerm_vpn_gateway"),
+	"Microsoft.Network/vpnGateways/vpnConnections": defaultArmToTfMapper("azurerm_vpn_gateway_connection"),
 }
 
-// TODO: Terraform resources and azure resources don't map 1-on-1 to eachother
-// Use this function as a stub for later exceptions
+// GetTFResourceFromAzureRMType returns the Terraform resource an ARM resource is priced as, or an
+// empty string if the ARM type isn't known. ARM resource types are case-insensitive.
 func GetTFResourceFromAzureRMType(armResource string, rawResource *gjson.Result) string {
-	mapper := azureRMToTerraformResourceMap[armResource]
+	mapper, ok := azureRMToTerraformResourceMap[armResource]
+	if !ok {
+		for armType, m := range azureRMToTerraformResourceMap {
+			if strings.EqualFold(armType, armResource) {
+				mapper, ok = m, true
+				break
+			}
+		}
+	}
+
+	if !ok {
+		return ""
+	}
 	return mapper(rawResource)
 }
 
+const (
+	// ParentReferenceAttribute is the reference attribute of a child resource to its parent,
+	// e.g. the vault of a Microsoft.KeyVault/vaults/keys resource.
+	ParentReferenceAttribute = "#parent"
+	// RootReferenceAttribute is the reference attribute of a child resource to its top-level
+	// resource, e.g. the account of a Microsoft.DocumentDB/databaseAccounts/sqlDatabases/containers resource.
+	RootReferenceAttribute = "#root"
+)
+
+// ReferenceID returns the ID of the resource that the reference attribute of an ARM resource refers
+// to. Reference attributes are the paths of resource IDs in its values, e.g. properties.serverFarmId,
+// or ParentReferenceAttribute and RootReferenceAttribute.
+func ReferenceID(d *schema.ResourceData, attr string) string {
+	id := d.Get("id").String()
+
+	switch attr {
+	case ParentReferenceAttribute, RootReferenceAttribute:
+		idx := strings.LastIndex(strings.ToLower(id), "/providers/")
+		if idx < 0 {
+			return ""
+		}
+
+		// The parts are the namespace followed by pairs of types and names
+		parts := strings.Split(strings.Trim(id[idx+len("/providers/"):], "/"), "/")
+		if len(parts) < 5 {
+			return ""
+		}
+
+		n := len(parts) - 2
+		if attr == RootReferenceAttribute {
+			n = 3
+		}
+		return id[:idx] + "/providers/" + strings.Join(parts[:n], "/")
+	}
+
+	return d.Get(attr).String()
+}
+
+// terraformOnlyResources are the Terraform resources with a price that aren't in the Registry, with the
+// reason why. Other Terraform resources with a price are priced from ARM resources too.
+var terraformOnlyResources = map[string]string{
+	"azurerm_active_directory_domain_service_replica_set": "ARM declares replica sets in the properties of the domain service, they're priced as its subresources",
+	"azurerm_app_service_certificate_binding":             "ARM declares certificate bindings as Microsoft.Web/sites/hostNameBindings, priced as azurerm_app_service_custom_hostname_binding",
+	"azurerm_data_factory_integration_runtime_managed":    "Deprecated by Terraform, ARM managed runtimes are priced as azurerm_data_factory_integration_runtime_azure or azurerm_data_factory_integration_runtime_azure_ssis",
+	"azurerm_key_vault_certificate":                       "Key Vault certificates can't be deployed with ARM",
+	"azurerm_lb_outbound_rule":                            "ARM declares outbound rules in the properties of the load balancer, they're priced as its subresources",
+	"azurerm_lb_rule":                                     "ARM declares load balancing rules in the properties of the load balancer, they're priced as its subresources",
+	"azurerm_sql_database":                                "Deprecated by Terraform, ARM databases are priced as azurerm_mssql_database",
+	"azurerm_virtual_machine":                             "Deprecated by Terraform, ARM VMs are priced as azurerm_linux_virtual_machine or azurerm_windows_virtual_machine",
+	"azurerm_virtual_machine_scale_set":                   "Deprecated by Terraform, ARM scale sets are priced as azurerm_linux_virtual_machine_scale_set or azurerm_windows_virtual_machine_scale_set",
+}
+
 var FreeResources = []string{
 	// Azure Api Management
 	"azurerm_api_management_api",
@@ -113,6 +480,10 @@ var FreeResources = []string{
 
 	// Azure Base
 	"azurerm_resource_group",
+	"azurerm_resource_group_template_deployment",
+	"azurerm_subscription_template_deployment",
+	"azurerm_management_group_template_deployment",
+	"azurerm_tenant_template_deployment",
 	"azurerm_resource_provider_registration",
 	"azurerm_subscription",
 	"azurerm_role_assignment",
@@ -250,6 +621,7 @@ var FreeResources = []string{
 	"azurerm_log_analytics_linked_service",
 	"azurerm_log_analytics_linked_storage_account",
 	"azurerm_log_analytics_saved_search",
+	"azurerm_log_analytics_solution",
 	"azurerm_log_analytics_storage_insights",
 
 	// Azure Management
@@ -303,6 +675,14 @@ var FreeResources = []string{
 	"azurerm_sentinel_alert_rule_fusion",
 	"azurerm_sentinel_alert_rule_ms_security_incident",
 	"azurerm_sentinel_alert_rule_scheduled",
+	"azurerm_sentinel_data_connector_aws_cloud_trail",
+	"azurerm_sentinel_data_connector_azure_active_directory",
+	"azurerm_sentinel_data_connector_azure_advanced_threat_protection",
+	"azurerm_sentinel_data_connector_azure_security_center",
+	"azurerm_sentinel_data_connector_microsoft_cloud_app_security",
+	"azurerm_sentinel_data_connector_microsoft_defender_advanced_threat_protection",
+	"azurerm_sentinel_data_connector_office_365",
+	"azurerm_sentinel_data_connector_threat_intelligence",
 
 	// Azure SQL
 	"azurerm_sql_server",
@@ -0,0 +1,45 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestRegistryCoversTerraformResources(t *testing.T) {
+	registryMap := GetRegistryMap()
+
+	for _, item := range tfazure.ResourceRegistry {
+		if item.NoPrice {
+			continue
+		}
+
+		if _, ok := terraformOnlyResources[item.Name]; ok {
+			assert.NotContains(t, *registryMap, item.Name, "%s is priced from ARM resources but listed as Terraform only", item.Name)
+			continue
+		}
+
+		assert.Contains(t, *registryMap, item.Name, "%s isn't priced from ARM resources, add it to the Registry or to terraformOnlyResources", item.Name)
+	}
+}
+
+func TestReferenceID(t *testing.T) {
+	d := schema.NewAzureRMResourceData("azurerm_cosmosdb_sql_container", "container", gjson.Parse(`{
+		"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.DocumentDB/databaseAccounts/account/sqlDatabases/db/containers/container",
+		"properties": {"serverFarmId": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan"}
+	}`))
+
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.DocumentDB/databaseAccounts/account/sqlDatabases/db", ReferenceID(d, ParentReferenceAttribute))
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.DocumentDB/databaseAccounts/account", ReferenceID(d, RootReferenceAttribute))
+	assert.Equal(t, "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan", ReferenceID(d, "properties.serverFarmId"))
+
+	topLevel := schema.NewAzureRMResourceData("azurerm_key_vault", "vault", gjson.Parse(`{
+		"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.KeyVault/vaults/vault"
+	}`))
+	assert.Equal(t, "", ReferenceID(topLevel, ParentReferenceAttribute))
+	assert.Equal(t, "", ReferenceID(topLevel, RootReferenceAttribute))
+}
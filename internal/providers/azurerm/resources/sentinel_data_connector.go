@@ -0,0 +1,22 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+var sentinelDataConnectorKinds = map[string]string{
+	"amazonwebservicescloudtrail":               "azurerm_sentinel_data_connector_aws_cloud_trail",
+	"azureactivedirectory":                      "azurerm_sentinel_data_connector_azure_active_directory",
+	"azureadvancedthreatprotection":             "azurerm_sentinel_data_connector_azure_advanced_threat_protection",
+	"azuresecuritycenter":                       "azurerm_sentinel_data_connector_azure_security_center",
+	"microsoftcloudappsecurity":                 "azurerm_sentinel_data_connector_microsoft_cloud_app_security",
+	"microsoftdefenderadvancedthreatprotection": "azurerm_sentinel_data_connector_microsoft_defender_advanced_threat_protection",
+	"office365":          "azurerm_sentinel_data_connector_office_365",
+	"threatintelligence": "azurerm_sentinel_data_connector_threat_intelligence",
+}
+
+func mapSentinelDataConnectorTfResource(rawResource *gjson.Result) string {
+	return sentinelDataConnectorKinds[strings.ToLower(rawResource.Get("kind").String())]
+}
@@ -0,0 +1,122 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+const gbInBytes = 1073741824.0
+
+var (
+	sqlTierMapping = map[string]string{
+		"GP":   "General Purpose",
+		"GP_S": "General Purpose - Serverless",
+		"HS":   "Hyperscale",
+		"BC":   "Business Critical",
+	}
+
+	sqlFamilyMapping = map[string]string{
+		"Gen5": "Compute Gen5",
+		"Gen4": "Compute Gen4",
+		"M":    "Compute M Series",
+	}
+
+	// sqlDTUServiceObjectives maps the DTU capacity of the Standard and Premium tiers
+	// to the service objective they're priced by.
+	sqlDTUServiceObjectives = map[string]map[int64]string{
+		"standard": {10: "S0", 20: "S1", 50: "S2", 100: "S3", 200: "S4", 400: "S6", 800: "S7", 1600: "S9", 3000: "S12"},
+		"premium":  {125: "P1", 250: "P2", 500: "P4", 1000: "P6", 1750: "P11", 4000: "P15"},
+	}
+)
+
+func getSQLDatabaseRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_mssql_database",
+		RFunc: NewSQLDatabase,
+	}
+}
+
+// NewSQLDatabase prices a database by its ARM SKU, which is either a vCore SKU,
+// e.g. {"name": "GP_Gen5", "capacity": 2}, or a DTU SKU, e.g. {"name": "S0"} or {"name": "Standard", "capacity": 10}.
+func NewSQLDatabase(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	if strings.EqualFold(d.Get("sku.name").String(), "ElasticPool") || d.Get("properties.elasticPoolId").String() != "" {
+		return &schema.Resource{
+			Name:        d.Address,
+			NoPrice:     true,
+			IsSkipped:   true,
+			SkipMessage: "Databases in an elastic pool are priced by the pool",
+		}
+	}
+
+	var maxSizeGB *float64
+	if maxBytes := d.Get("properties.maxSizeBytes").Float(); maxBytes > 0 {
+		val := maxBytes / gbInBytes
+		maxSizeGB = &val
+	}
+
+	var readReplicas *int64
+	if d.Get("properties.highAvailabilityReplicaCount").Exists() {
+		val := d.Get("properties.highAvailabilityReplicaCount").Int()
+		readReplicas = &val
+	}
+
+	r := &azure.SQLDatabase{
+		Address:          d.Address,
+		Region:           util.LookupRegion(d, []string{}),
+		LicenceType:      d.GetStringOrDefault("properties.licenseType", "LicenseIncluded"),
+		MaxSizeGB:        maxSizeGB,
+		ReadReplicaCount: readReplicas,
+		ZoneRedundant:    d.Get("properties.zoneRedundant").Bool(),
+	}
+
+	name := d.GetStringOrDefault("sku.name", "GP_Gen5")
+	if tier, family, ok := parseSQLVCoreSku(name); ok {
+		cores := d.GetInt64OrDefault("sku.capacity", 2)
+		r.SKU = fmt.Sprintf("%s_%d", name, cores)
+		r.Tier = tier
+		r.Family = family
+		r.Cores = &cores
+	} else {
+		r.SKU = sqlDTUServiceObjective(name, d.Get("sku.capacity").Int())
+	}
+
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+// parseSQLVCoreSku parses vCore SKU names, e.g. GP_Gen5 or GP_S_Gen5.
+func parseSQLVCoreSku(name string) (string, string, bool) {
+	s := strings.Split(name, "_")
+	if len(s) < 2 {
+		return "", "", false
+	}
+
+	tier, ok := sqlTierMapping[strings.Join(s[:len(s)-1], "_")]
+	if !ok {
+		return "", "", false
+	}
+
+	family, ok := sqlFamilyMapping[s[len(s)-1]]
+	if !ok {
+		return "", "", false
+	}
+
+	return tier, family, true
+}
+
+func sqlDTUServiceObjective(name string, capacity int64) string {
+	if objectives, ok := sqlDTUServiceObjectives[strings.ToLower(name)]; ok {
+		if objective, ok := objectives[capacity]; ok {
+			return objective
+		}
+
+		// Fall back to the lowest service objective of the tier
+		return map[string]string{"standard": "S0", "premium": "P1"}[strings.ToLower(name)]
+	}
+
+	return name
+}
@@ -0,0 +1,44 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// sqlManagedInstanceBackupRedundancy maps the ARM backup storage redundancy to the storage account type
+var sqlManagedInstanceBackupRedundancy = map[string]string{
+	"local":   "LRS",
+	"zone":    "ZRS",
+	"geo":     "RA-GRS",
+	"geozone": "RA-GZRS",
+}
+
+func getSQLManagedInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_sql_managed_instance",
+		RFunc: NewSQLManagedInstance,
+	}
+}
+
+func NewSQLManagedInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	storageAccountType, ok := sqlManagedInstanceBackupRedundancy[strings.ToLower(d.Get("properties.requestedBackupStorageRedundancy").String())]
+	if !ok {
+		// Geo-redundant backup storage is the default
+		storageAccountType = "RA-GRS"
+	}
+
+	r := &azure.SQLManagedInstance{
+		Address:            d.Address,
+		Region:             util.LookupRegion(d, []string{}),
+		SKU:                d.GetStringOrDefault("sku.name", "GP_Gen5"),
+		Cores:              d.GetInt64OrDefault("properties.vCores", 8),
+		LicenceType:        d.GetStringOrDefault("properties.licenseType", "LicenseIncluded"),
+		StorageSizeInGb:    d.GetInt64OrDefault("properties.storageSizeInGB", 32),
+		StorageAccountType: storageAccountType,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
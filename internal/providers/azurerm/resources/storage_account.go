@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getStorageAccountRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_storage_account",
+		RFunc: NewStorageAccount,
+	}
+}
+
+// NewStorageAccount splits the ARM SKU name, e.g. Standard_RAGRS, into the account tier and replication type.
+func NewStorageAccount(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	var accountTier, accountReplicationType string
+
+	s := strings.SplitN(d.GetStringOrDefault("sku.name", "Standard_LRS"), "_", 2)
+	accountTier = s[0]
+	if len(s) > 1 {
+		accountReplicationType = s[1]
+	}
+
+	switch strings.ToLower(accountReplicationType) {
+	case "ragrs":
+		accountReplicationType = "RA-GRS"
+	case "ragzrs":
+		accountReplicationType = "RA-GZRS"
+	}
+
+	r := &azure.StorageAccount{
+		Address:                d.Address,
+		Region:                 util.LookupRegion(d, []string{}),
+		AccessTier:             d.GetStringOrDefault("properties.accessTier", "Hot"),
+		AccountKind:            d.GetStringOrDefault("kind", "StorageV2"),
+		AccountReplicationType: accountReplicationType,
+		AccountTier:            accountTier,
+		NFSv3:                  d.Get("properties.isNfsV3Enabled").Bool(),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
@@ -0,0 +1,24 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// mapTemplateDeploymentTfResource maps a nested deployment to the Terraform resource for its scope,
+// which can be told apart from the deployment's resource ID.
+func mapTemplateDeploymentTfResource(rawResource *gjson.Result) string {
+	id := strings.ToLower(rawResource.Get("id").Str)
+
+	switch {
+	case strings.Contains(id, "/resourcegroups/"):
+		return "azurerm_resource_group_template_deployment"
+	case strings.HasPrefix(id, "/providers/microsoft.management/managementgroups/"):
+		return "azurerm_management_group_template_deployment"
+	case strings.HasPrefix(id, "/subscriptions/"):
+		return "azurerm_subscription_template_deployment"
+	}
+
+	return "azurerm_tenant_template_deployment"
+}
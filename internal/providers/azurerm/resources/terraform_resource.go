@@ -0,0 +1,499 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// terraformAttributes converts the values of an ARM resource to the attributes of a Terraform resource.
+type terraformAttributes func(d *schema.ResourceData) map[string]interface{}
+
+// terraformResourceRegistryItems returns the resources that are priced inline by providers/terraform/azure,
+// rather than by a struct in internal/resources/azure. They're priced by the Terraform RFunc, with the
+// ARM values converted to the attributes that the RFunc reads.
+func terraformResourceRegistryItems() []*schema.RegistryItem {
+	return []*schema.RegistryItem{
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMAppFunctionRegistryItem(), noTerraformAttributes,
+			terraformReference{attribute: "app_service_plan_id", armAttribute: "properties.serverFarmId", attributes: appServicePlanAttributes}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMAppIntegrationServiceEnvironmentRegistryItem(), integrationServiceEnvironmentAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMApplicationGatewayRegistryItem(), applicationGatewayAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMBastionHostRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCDNEndpointRegistryItem(), cdnEndpointAttributes,
+			terraformReference{attribute: "profile_name", armAttribute: ParentReferenceAttribute, attributes: cdnProfileAttributes}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbCassandraKeyspaceRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbCassandraTableRegistryItem(), cosmosDBAttributes,
+			terraformReference{attribute: "cassandra_keyspace_id", armAttribute: ParentReferenceAttribute, attributes: noTerraformAttributes, references: []terraformReference{cosmosDBAccountReference}}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbGremlinDatabaseRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbGremlinGraphRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbMongoCollectionRegistryItem(), cosmosDBAttributes,
+			terraformReference{attribute: "database_name", armAttribute: ParentReferenceAttribute, attributes: noTerraformAttributes, references: []terraformReference{cosmosDBAccountReference}}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbMongoDatabaseRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbSQLContainerRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbSQLDatabaseRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMCosmosdbTableRegistryItem(), cosmosDBAttributes, cosmosDBAccountReference),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMDNSPrivateZoneRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMDNSZoneRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMEventHubsNamespaceRegistryItem(), eventHubsNamespaceAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMFirewallRegistryItem(), firewallAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMHDInsightHadoopClusterRegistryItem(), hdInsightClusterAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMHDInsightHBaseClusterRegistryItem(), hdInsightClusterAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMHDInsightInteractiveQueryClusterRegistryItem(), hdInsightClusterAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMHDInsightKafkaClusterRegistryItem(), hdInsightClusterAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMHDInsightSparkClusterRegistryItem(), hdInsightClusterAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMKeyVaultKeyRegistryItem(), keyVaultKeyAttributes,
+			terraformReference{attribute: "key_vault_id", armAttribute: ParentReferenceAttribute, attributes: keyVaultAttributes}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMKeyVaultManagedHSMRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMKubernetesClusterNodePoolRegistryItem(), kubernetesClusterNodePoolAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMKubernetesClusterRegistryItem(), kubernetesClusterAttributes),
+		newVirtualMachineRegistryItem(tfazure.GetAzureRMLinuxVirtualMachineRegistryItem()),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMLinuxVirtualMachineScaleSetRegistryItem(), virtualMachineScaleSetAttributes),
+		getLoadBalancerRegistryItem(),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMManagedDiskRegistryItem(), managedDiskAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMMariaDBServerRegistryItem(), singleServerAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMMySQLServerRegistryItem(), singleServerAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMAppNATGatewayRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMNotificationHubNamespaceRegistryItem(), notificationHubNamespaceAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMPostgreSQLServerRegistryItem(), singleServerAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMPrivateEndpointRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMPublicIPPrefixRegistryItem(), noTerraformAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMPublicIPRegistryItem(), publicIPAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMRedisCacheRegistryItem(), redisCacheAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMSearchServiceRegistryItem(), searchServiceAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMSynapseSparkPoolRegistryItem(), synapseSparkPoolAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMSynapseSQLPoolRegistryItem(), synapseSQLPoolAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMSynapseWorkspacRegistryItem(), synapseWorkspaceAttributes),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMVirtualNetworkGatewayConnectionRegistryItem(), virtualNetworkGatewayConnectionAttributes,
+			terraformReference{attribute: "virtual_network_gateway_id", armAttribute: "properties.virtualNetworkGateway1.id", attributes: virtualNetworkGatewayAttributes}),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMVirtualNetworkGatewayRegistryItem(), virtualNetworkGatewayAttributes),
+		newVirtualMachineRegistryItem(tfazure.GetAzureRMWindowsVirtualMachineRegistryItem()),
+		newTerraformResourceRegistryItem(tfazure.GetAzureRMWindowsVirtualMachineScaleSetRegistryItem(), virtualMachineScaleSetAttributes),
+	}
+}
+
+// terraformReference converts the resource that a reference attribute of an ARM resource refers to,
+// see ReferenceID, to the resource that a reference attribute of the Terraform resource refers to.
+type terraformReference struct {
+	// attribute is the reference attribute of the Terraform resource, e.g. key_vault_id
+	attribute string
+	// armAttribute is the reference attribute of the ARM resource, e.g. ParentReferenceAttribute
+	armAttribute string
+	attributes   terraformAttributes
+	// references are the references of the referenced Terraform resource, which are resolved from
+	// the reference attributes of the ARM resource too, e.g. the account of a Cosmos DB table's keyspace
+	references []terraformReference
+}
+
+func newTerraformResourceRegistryItem(item *schema.RegistryItem, attributes terraformAttributes, references ...terraformReference) *schema.RegistryItem {
+	armAttributes := referenceArmAttributes(references)
+
+	return &schema.RegistryItem{
+		Name:                item.Name,
+		Notes:               item.Notes,
+		ReferenceAttributes: armAttributes,
+		RFunc: func(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+			values := attributes(d)
+			values["location"] = util.LookupRegion(d, armAttributes)
+
+			tfData := terraformResourceData(item.Name, d.Address, values)
+			if tfData == nil {
+				return nil
+			}
+			addTerraformReferences(d, tfData, references)
+
+			return item.RFunc(tfData, u)
+		},
+	}
+}
+
+func referenceArmAttributes(references []terraformReference) []string {
+	var attrs []string
+	for _, ref := range references {
+		attrs = append(attrs, ref.armAttribute)
+		attrs = append(attrs, referenceArmAttributes(ref.references)...)
+	}
+
+	return attrs
+}
+
+// addTerraformReferences adds the references of the ARM resource d to the Terraform resource tfData.
+func addTerraformReferences(d, tfData *schema.ResourceData, references []terraformReference) {
+	for _, ref := range references {
+		for _, armRef := range d.References(ref.armAttribute) {
+			values := ref.attributes(armRef)
+			setAttribute(values, "location", armRef.Get("location"))
+
+			refData := terraformResourceData(armRef.Type, armRef.Address, values)
+			if refData == nil {
+				continue
+			}
+			addTerraformReferences(d, refData, ref.references)
+
+			tfData.AddReference(ref.attribute, refData, nil)
+		}
+	}
+}
+
+func terraformResourceData(resourceType, address string, values map[string]interface{}) *schema.ResourceData {
+	b, err := json.Marshal(values)
+	if err != nil {
+		log.Debugf("Could not convert %s to Terraform attributes: %s", address, err)
+		return nil
+	}
+
+	return schema.NewAzureRMResourceData(resourceType, address, gjson.ParseBytes(b))
+}
+
+func noTerraformAttributes(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// setAttribute sets the attribute to the value at path, if it exists.
+func setAttribute(attrs map[string]interface{}, attr string, value gjson.Result) {
+	if value.Exists() && value.Type != gjson.Null {
+		attrs[attr] = value.Value()
+	}
+}
+
+func osDiskAttributes(osDisk gjson.Result) []map[string]interface{} {
+	disk := map[string]interface{}{
+		"storage_account_type": "Standard_LRS",
+	}
+	setAttribute(disk, "storage_account_type", osDisk.Get("managedDisk.storageAccountType"))
+	setAttribute(disk, "disk_size_gb", osDisk.Get("diskSizeGB"))
+
+	return []map[string]interface{}{disk}
+}
+
+func virtualMachineAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"os_disk": osDiskAttributes(d.Get("properties.storageProfile.osDisk")),
+		"additional_capabilities": []map[string]interface{}{
+			{"ultra_ssd_enabled": d.Get("properties.additionalCapabilities.ultraSSDEnabled").Bool()},
+		},
+	}
+	setAttribute(attrs, "size", d.Get("properties.hardwareProfile.vmSize"))
+	setAttribute(attrs, "license_type", d.Get("properties.licenseType"))
+
+	return attrs
+}
+
+func virtualMachineScaleSetAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"instances": d.GetInt64OrDefault("sku.capacity", 1),
+		"os_disk":   osDiskAttributes(d.Get("properties.virtualMachineProfile.storageProfile.osDisk")),
+		"additional_capabilities": []map[string]interface{}{
+			{"ultra_ssd_enabled": d.Get("properties.additionalCapabilities.ultraSSDEnabled").Bool()},
+		},
+	}
+	setAttribute(attrs, "sku", d.Get("sku.name"))
+	setAttribute(attrs, "license_type", d.Get("properties.virtualMachineProfile.licenseType"))
+
+	return attrs
+}
+
+func managedDiskAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"storage_account_type": d.GetStringOrDefault("sku.name", "Standard_LRS"),
+	}
+	setAttribute(attrs, "disk_size_gb", d.Get("properties.diskSizeGB"))
+	setAttribute(attrs, "disk_iops_read_write", d.Get("properties.diskIOPSReadWrite"))
+	setAttribute(attrs, "disk_mbps_read_write", d.Get("properties.diskMBpsReadWrite"))
+
+	return attrs
+}
+
+func redisCacheAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku_name", d.Get("properties.sku.name"))
+	setAttribute(attrs, "family", d.Get("properties.sku.family"))
+	setAttribute(attrs, "capacity", d.Get("properties.sku.capacity"))
+	setAttribute(attrs, "replicas_per_master", d.Get("properties.replicasPerMaster"))
+	setAttribute(attrs, "shard_count", d.Get("properties.shardCount"))
+
+	return attrs
+}
+
+func publicIPAttributes(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"allocation_method": d.GetStringOrDefault("properties.publicIPAllocationMethod", "Dynamic"),
+		"sku":               d.GetStringOrDefault("sku.name", "Basic"),
+	}
+}
+
+// kubernetesClusterAttributes prices the first agent pool profile as the default node pool, other
+// profiles are usually deployed as agentPools resources.
+func kubernetesClusterAttributes(d *schema.ResourceData) map[string]interface{} {
+	skuTier := d.GetStringOrDefault("sku.tier", "Free")
+	if !strings.EqualFold(skuTier, "free") {
+		// The uptime SLA is called the Paid tier by older API versions and the Terraform provider
+		skuTier = "Paid"
+	}
+
+	attrs := map[string]interface{}{
+		"sku_tier":                         skuTier,
+		"default_node_pool":                []map[string]interface{}{agentPoolAttributes(d.Get("properties.agentPoolProfiles.0"))},
+		"http_application_routing_enabled": d.Get("properties.addonProfiles.httpApplicationRouting.enabled").Bool(),
+	}
+
+	if lbSku := d.Get("properties.networkProfile.loadBalancerSku"); lbSku.Exists() {
+		attrs["network_profile"] = []map[string]interface{}{{"load_balancer_sku": lbSku.String()}}
+	}
+
+	return attrs
+}
+
+func kubernetesClusterNodePoolAttributes(d *schema.ResourceData) map[string]interface{} {
+	return agentPoolAttributes(d.Get("properties"))
+}
+
+func agentPoolAttributes(pool gjson.Result) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "vm_size", pool.Get("vmSize"))
+	setAttribute(attrs, "node_count", pool.Get("count"))
+	setAttribute(attrs, "min_count", pool.Get("minCount"))
+	setAttribute(attrs, "os_disk_type", pool.Get("osDiskType"))
+	setAttribute(attrs, "os_disk_size_gb", pool.Get("osDiskSizeGB"))
+
+	return attrs
+}
+
+func firewallAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku_tier", d.Get("properties.sku.tier"))
+
+	if d.Get("properties.virtualHub.id").Exists() {
+		attrs["virtual_hub"] = []map[string]interface{}{{"virtual_hub_id": d.Get("properties.virtualHub.id").String()}}
+	}
+
+	return attrs
+}
+
+func integrationServiceEnvironmentAttributes(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"sku_name": fmt.Sprintf("%s_%d", d.GetStringOrDefault("sku.name", "Developer"), d.Get("sku.capacity").Int()),
+	}
+}
+
+func notificationHubNamespaceAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku_name", d.Get("sku.name"))
+
+	return attrs
+}
+
+func searchServiceAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku", d.Get("sku.name"))
+	setAttribute(attrs, "partition_count", d.Get("properties.partitionCount"))
+	setAttribute(attrs, "replica_count", d.Get("properties.replicaCount"))
+
+	return attrs
+}
+
+// singleServerAttributes converts MySQL, PostgreSQL and MariaDB single servers, whose ARM SKU
+// names, e.g. GP_Gen5_2, are the same as the Terraform ones.
+func singleServerAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{
+		"storage_mb":                   d.GetInt64OrDefault("properties.storageProfile.storageMB", 5120),
+		"geo_redundant_backup_enabled": strings.EqualFold(d.Get("properties.storageProfile.geoRedundantBackup").String(), "enabled"),
+	}
+	setAttribute(attrs, "sku_name", d.Get("sku.name"))
+
+	return attrs
+}
+
+func synapseWorkspaceAttributes(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"managed_virtual_network_enabled": strings.EqualFold(d.Get("properties.managedVirtualNetwork").String(), "default"),
+	}
+}
+
+func virtualNetworkGatewayAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku", d.Get("properties.sku.name"))
+
+	return attrs
+}
+
+func virtualNetworkGatewayConnectionAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "type", d.Get("properties.connectionType"))
+
+	return attrs
+}
+
+func appServicePlanAttributes(d *schema.ResourceData) map[string]interface{} {
+	sku := map[string]interface{}{}
+	setAttribute(sku, "tier", d.Get("sku.tier"))
+	setAttribute(sku, "size", d.Get("sku.size"))
+
+	attrs := map[string]interface{}{
+		"sku": []map[string]interface{}{sku},
+	}
+	setAttribute(attrs, "kind", d.Get("kind"))
+
+	return attrs
+}
+
+func applicationGatewayAttributes(d *schema.ResourceData) map[string]interface{} {
+	sku := map[string]interface{}{}
+	setAttribute(sku, "name", d.Get("properties.sku.name"))
+	setAttribute(sku, "capacity", d.Get("properties.sku.capacity"))
+
+	attrs := map[string]interface{}{
+		"sku": []map[string]interface{}{sku},
+	}
+
+	if minCapacity := d.Get("properties.autoscaleConfiguration.minCapacity"); minCapacity.Exists() {
+		attrs["autoscale_configuration"] = []map[string]interface{}{{"min_capacity": minCapacity.Int()}}
+	}
+
+	return attrs
+}
+
+func cdnProfileAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku", d.Get("sku.name"))
+
+	return attrs
+}
+
+func cdnEndpointAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "delivery_rule", d.Get("properties.deliveryPolicy.rules"))
+
+	return attrs
+}
+
+// cosmosDBAccountReference is the account of Cosmos DB databases, and of their containers, which is
+// the top-level resource of both.
+var cosmosDBAccountReference = terraformReference{attribute: "account_name", armAttribute: RootReferenceAttribute, attributes: cosmosDBAccountAttributes}
+
+func cosmosDBAccountAttributes(d *schema.ResourceData) map[string]interface{} {
+	var geoLocations []map[string]interface{}
+	for _, l := range d.Get("properties.locations").Array() {
+		geoLocations = append(geoLocations, map[string]interface{}{
+			"location":          util.ToAzureCLIName(l.Get("locationName").String()),
+			"failover_priority": l.Get("failoverPriority").Int(),
+			"zone_redundant":    l.Get("isZoneRedundant").Bool(),
+		})
+	}
+
+	backup := map[string]interface{}{
+		"type": d.GetStringOrDefault("properties.backupPolicy.type", "Periodic"),
+	}
+	setAttribute(backup, "interval_in_minutes", d.Get("properties.backupPolicy.periodicModeProperties.backupIntervalInMinutes"))
+	setAttribute(backup, "retention_in_hours", d.Get("properties.backupPolicy.periodicModeProperties.backupRetentionIntervalInHours"))
+
+	attrs := map[string]interface{}{
+		"geo_location": geoLocations,
+		"backup":       []map[string]interface{}{backup},
+	}
+	setAttribute(attrs, "enable_multiple_write_locations", d.Get("properties.enableMultipleWriteLocations"))
+	setAttribute(attrs, "analytical_storage_enabled", d.Get("properties.enableAnalyticalStorage"))
+
+	return attrs
+}
+
+// cosmosDBAttributes converts the throughput of Cosmos DB databases and containers, which is
+// shared by the containers of a database if it's set on the database.
+func cosmosDBAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "throughput", d.Get("properties.options.throughput"))
+
+	if maxThroughput := d.Get("properties.options.autoscaleSettings.maxThroughput"); maxThroughput.Exists() {
+		attrs["autoscale_settings"] = []map[string]interface{}{{"max_throughput": maxThroughput.Int()}}
+	}
+
+	return attrs
+}
+
+func eventHubsNamespaceAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku", d.Get("sku.name"))
+	setAttribute(attrs, "capacity", d.Get("sku.capacity"))
+	setAttribute(attrs, "dedicated_cluster_id", d.Get("properties.clusterArmId"))
+
+	return attrs
+}
+
+// hdInsightRoles are the Terraform blocks of the roles of HDInsight clusters by ARM role name.
+var hdInsightRoles = map[string]string{
+	"headnode":      "head_node",
+	"workernode":    "worker_node",
+	"zookeepernode": "zookeeper_node",
+	"edgenode":      "edge_node",
+}
+
+func hdInsightClusterAttributes(d *schema.ResourceData) map[string]interface{} {
+	roles := map[string]interface{}{}
+	for _, r := range d.Get("properties.computeProfile.roles").Array() {
+		block, ok := hdInsightRoles[strings.ToLower(r.Get("name").String())]
+		if !ok {
+			continue
+		}
+
+		role := map[string]interface{}{}
+		setAttribute(role, "vm_size", r.Get("hardwareProfile.vmSize"))
+		setAttribute(role, "target_instance_count", r.Get("targetInstanceCount"))
+		setAttribute(role, "number_of_disks_per_node", r.Get("dataDisksGroups.0.disksPerNode"))
+		roles[block] = []map[string]interface{}{role}
+	}
+
+	attrs := map[string]interface{}{
+		"roles": []map[string]interface{}{roles},
+	}
+	setAttribute(attrs, "tier", d.Get("properties.tier"))
+
+	return attrs
+}
+
+func keyVaultAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku_name", d.Get("properties.sku.name"))
+
+	return attrs
+}
+
+func keyVaultKeyAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "key_type", d.Get("properties.kty"))
+	setAttribute(attrs, "key_size", d.Get("properties.keySize"))
+
+	return attrs
+}
+
+func loadBalancerAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku", d.Get("sku.name"))
+
+	return attrs
+}
+
+func synapseSparkPoolAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "node_size", d.Get("properties.nodeSize"))
+
+	if d.Get("properties.autoScale.enabled").Bool() {
+		attrs["auto_scale"] = []map[string]interface{}{{"min_node_count": d.Get("properties.autoScale.minNodeCount").Int()}}
+	} else {
+		setAttribute(attrs, "node_count", d.Get("properties.nodeCount"))
+	}
+
+	return attrs
+}
+
+func synapseSQLPoolAttributes(d *schema.ResourceData) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	setAttribute(attrs, "sku_name", d.Get("sku.name"))
+
+	return attrs
+}
@@ -0,0 +1,97 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// mapVirtualMachineTfResource maps a VM to the Linux or Windows resource by the OS of its image.
+func mapVirtualMachineTfResource(rawResource *gjson.Result) string {
+	if isLinuxVirtualMachine(rawResource.Get("properties")) {
+		return "azurerm_linux_virtual_machine"
+	}
+
+	return "azurerm_windows_virtual_machine"
+}
+
+// mapVirtualMachineScaleSetTfResource maps a scale set to the Linux or Windows resource by the OS of
+// its VM profile.
+func mapVirtualMachineScaleSetTfResource(rawResource *gjson.Result) string {
+	if isLinuxVirtualMachine(rawResource.Get("properties.virtualMachineProfile")) {
+		return "azurerm_linux_virtual_machine_scale_set"
+	}
+
+	return "azurerm_windows_virtual_machine_scale_set"
+}
+
+func isLinuxVirtualMachine(profile gjson.Result) bool {
+	if profile.Get("osProfile.linuxConfiguration").Exists() {
+		return true
+	}
+
+	return strings.EqualFold(profile.Get("storageProfile.osDisk.osType").String(), "linux")
+}
+
+// newVirtualMachineRegistryItem prices a VM with the Terraform resource and adds the data disks that
+// the VM creates. In Terraform these are separate azurerm_managed_disk resources, so they're priced
+// with the costs of a managed disk.
+func newVirtualMachineRegistryItem(item *schema.RegistryItem) *schema.RegistryItem {
+	vm := newTerraformResourceRegistryItem(item, virtualMachineAttributes)
+	rFunc := vm.RFunc
+
+	vm.RFunc = func(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+		r := rFunc(d, u)
+		if r == nil {
+			return nil
+		}
+
+		r.SubResources = append(r.SubResources, dataDiskSubResources(d)...)
+		return r
+	}
+
+	return vm
+}
+
+// dataDiskSubResources returns a managed disk for each data disk the VM creates. Disks that are
+// attached with createOption Attach are existing managed disks, which are priced as their own resource.
+func dataDiskSubResources(d *schema.ResourceData) []*schema.Resource {
+	managedDisk := tfazure.GetAzureRMManagedDiskRegistryItem()
+	region := util.LookupRegion(d, nil)
+
+	var subResources []*schema.Resource
+	for _, disk := range d.Get("properties.storageProfile.dataDisks").Array() {
+		createOption := disk.Get("createOption").String()
+		if !strings.EqualFold(createOption, "Empty") && !strings.EqualFold(createOption, "FromImage") {
+			continue
+		}
+
+		attrs := map[string]interface{}{
+			"location":             region,
+			"storage_account_type": "Standard_LRS",
+		}
+		setAttribute(attrs, "storage_account_type", disk.Get("managedDisk.storageAccountType"))
+		setAttribute(attrs, "disk_size_gb", disk.Get("diskSizeGB"))
+
+		diskData := terraformResourceData(managedDisk.Name, "storage_data_disk", attrs)
+		if diskData == nil {
+			continue
+		}
+
+		r := managedDisk.RFunc(diskData, nil)
+		if r == nil {
+			continue
+		}
+
+		subResources = append(subResources, &schema.Resource{
+			Name:           "storage_data_disk",
+			CostComponents: r.CostComponents,
+		})
+	}
+
+	return subResources
+}
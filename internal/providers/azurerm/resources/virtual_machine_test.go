@@ -0,0 +1,48 @@
+package resources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func TestVirtualMachineDataDisks(t *testing.T) {
+	item, ok := (*GetRegistryMap())["azurerm_linux_virtual_machine"]
+	require.True(t, ok)
+
+	d := schema.NewAzureRMResourceData("azurerm_linux_virtual_machine", "Microsoft.Compute/virtualMachines/vm", gjson.Parse(`{
+		"location": "westeurope",
+		"properties": {
+			"hardwareProfile": {"vmSize": "Standard_D2s_v3"},
+			"osProfile": {"linuxConfiguration": {}},
+			"storageProfile": {
+				"osDisk": {"createOption": "FromImage", "managedDisk": {"storageAccountType": "Premium_LRS"}},
+				"dataDisks": [
+					{"lun": 0, "createOption": "Empty", "diskSizeGB": 256, "managedDisk": {"storageAccountType": "Premium_LRS"}},
+					{"lun": 1, "createOption": "FromImage", "diskSizeGB": 1024},
+					{"lun": 2, "createOption": "Attach", "managedDisk": {"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/disks/existing"}}
+				]
+			}
+		}
+	}`))
+
+	r := item.RFunc(d, nil)
+	require.NotNil(t, r)
+
+	var names []string
+	for _, sub := range r.SubResources {
+		for _, c := range sub.CostComponents {
+			names = append(names, sub.Name+": "+c.Name)
+		}
+	}
+	assert.Equal(t, []string{
+		"os_disk: Storage (P4, LRS)",
+		"storage_data_disk: Storage (P15, LRS)",
+		"storage_data_disk: Storage (S30, LRS)",
+		"storage_data_disk: Disk operations",
+	}, names)
+}
@@ -0,0 +1,46 @@
+package resources
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	tfazure "github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getVirtualNetworkPeeringRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_virtual_network_peering",
+		RFunc: NewVirtualNetworkPeering,
+		Notes: []string{"Peerings are assumed to be within the region of the deployment, the location of the remote network isn't known."},
+	}
+}
+
+func NewVirtualNetworkPeering(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	region := util.LookupRegion(d, []string{})
+	zone := virtualNetworkPeeringZone(region)
+
+	r := &azure.VirtualNetworkPeering{
+		Address:           d.Address,
+		SourceRegion:      region,
+		DestinationRegion: region,
+		SourceZone:        zone,
+		DestinationZone:   zone,
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func virtualNetworkPeeringZone(region string) string {
+	switch {
+	case strings.HasPrefix(strings.ToLower(region), "usgov"):
+		return "US Gov Zone 1"
+	case strings.HasPrefix(strings.ToLower(region), "germany"):
+		return "DE Zone 1"
+	case strings.HasPrefix(strings.ToLower(region), "china"):
+		return "CN Zone 1"
+	}
+
+	return tfazure.RegionToZone(region)
+}
@@ -0,0 +1,75 @@
+package resources
+
+import (
+	"github.com/infracost/infracost/internal/providers/azurerm/util"
+	"github.com/infracost/infracost/internal/resources/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func getVirtualHubRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_virtual_hub",
+		RFunc: NewVirtualHub,
+	}
+}
+
+func NewVirtualHub(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.VirtualHub{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+		SKU:     d.GetStringOrDefault("properties.sku", "Basic"),
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getVPNGatewayRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_vpn_gateway",
+		RFunc: NewVPNGateway,
+	}
+}
+
+func NewVPNGateway(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.VPNGateway{
+		Address:    d.Address,
+		Region:     util.LookupRegion(d, []string{}),
+		ScaleUnits: d.GetInt64OrDefault("properties.vpnGatewayScaleUnit", 1),
+		Type:       "S2S",
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getPointToSiteVPNGatewayRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_point_to_site_vpn_gateway",
+		RFunc: NewPointToSiteVPNGateway,
+	}
+}
+
+func NewPointToSiteVPNGateway(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.VPNGateway{
+		Address:    d.Address,
+		Region:     util.LookupRegion(d, []string{}),
+		ScaleUnits: d.GetInt64OrDefault("properties.vpnGatewayScaleUnit", 1),
+		Type:       "P2S",
+	}
+	r.PopulateUsage(u)
+	return r.BuildResource()
+}
+
+func getVPNGatewayConnectionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "azurerm_vpn_gateway_connection",
+		RFunc: NewVPNGatewayConnection,
+	}
+}
+
+func NewVPNGatewayConnection(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	r := &azure.VPNGatewayConnection{
+		Address: d.Address,
+		Region:  util.LookupRegion(d, []string{}),
+	}
+	return r.BuildResource()
+}
@@ -8,7 +8,12 @@ import (
 
 func mapWebAppTfResource(rawResource *gjson.Result) string {
 	kind := strings.ToLower(rawResource.Get("kind").Str)
-	if kind == "linux" {
+	if strings.Contains(kind, "functionapp") {
+		return "azurerm_function_app"
+	}
+
+	// Linux apps have kinds like "app,linux" or "app,linux,container"
+	if strings.Contains(kind, "linux") {
 		return "azurerm_linux_web_app"
 	}
 
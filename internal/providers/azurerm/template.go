@@ -0,0 +1,251 @@
+package azurerm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ArmTemplate is the in-memory model of an ARM deployment template.
+// Values are kept as generic JSON values (see normalizeJSONValue) so template
+// expressions can be evaluated anywhere within them.
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/syntax
+type ArmTemplate struct {
+	Schema          string
+	ContentVersion  string
+	LanguageVersion string
+	Parameters      map[string]*TemplateParameter
+	Variables       map[string]interface{}
+	Resources       []*TemplateResource
+	Outputs         map[string]interface{}
+
+	// path is the file the template was loaded from, which relative templateLinks are resolved against
+	path string
+}
+
+type TemplateParameter struct {
+	Type          string
+	DefaultValue  interface{}
+	HasDefault    bool
+	AllowedValues []interface{}
+}
+
+// TemplateResource is a resource declaration as written in the template,
+// before any expressions have been evaluated.
+type TemplateResource struct {
+	// SymbolicName is only set for languageVersion 2.0 templates, which declare resources as an object
+	SymbolicName string
+	Fields       map[string]interface{}
+}
+
+func (r *TemplateResource) Get(key string) interface{} {
+	v, _ := getCaseInsensitive(r.Fields, key)
+	return v
+}
+
+// Children returns the child resources declared in the 'resources' property of this resource.
+func (r *TemplateResource) Children() []*TemplateResource {
+	return parseTemplateResources(r.Get("resources"))
+}
+
+func loadArmTemplate(path string) (*ArmTemplate, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading ARM template file")
+	}
+
+	t, err := parseArmTemplate(b)
+	if err != nil {
+		return nil, err
+	}
+	t.path = path
+
+	return t, nil
+}
+
+func parseArmTemplate(b []byte) (*ArmTemplate, error) {
+	raw, err := decodeJSON(b)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing ARM template JSON")
+	}
+
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("ARM template must be a JSON object")
+	}
+
+	return newArmTemplate(obj), nil
+}
+
+func newArmTemplate(obj map[string]interface{}) *ArmTemplate {
+	t := &ArmTemplate{
+		Parameters: make(map[string]*TemplateParameter),
+		Variables:  make(map[string]interface{}),
+		Outputs:    make(map[string]interface{}),
+	}
+
+	if v, ok := getCaseInsensitive(obj, "$schema"); ok {
+		t.Schema, _ = v.(string)
+	}
+	if v, ok := getCaseInsensitive(obj, "contentVersion"); ok {
+		t.ContentVersion, _ = v.(string)
+	}
+	if v, ok := getCaseInsensitive(obj, "languageVersion"); ok {
+		t.LanguageVersion, _ = v.(string)
+	}
+
+	if v, ok := getCaseInsensitive(obj, "parameters"); ok {
+		if params, ok := v.(map[string]interface{}); ok {
+			for name, p := range params {
+				t.Parameters[name] = newTemplateParameter(p)
+			}
+		}
+	}
+
+	if v, ok := getCaseInsensitive(obj, "variables"); ok {
+		if vars, ok := v.(map[string]interface{}); ok {
+			t.Variables = vars
+		}
+	}
+
+	if v, ok := getCaseInsensitive(obj, "outputs"); ok {
+		if outputs, ok := v.(map[string]interface{}); ok {
+			t.Outputs = outputs
+		}
+	}
+
+	if v, ok := getCaseInsensitive(obj, "resources"); ok {
+		t.Resources = parseTemplateResources(v)
+	}
+
+	return t
+}
+
+func newTemplateParameter(v interface{}) *TemplateParameter {
+	p := &TemplateParameter{}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return p
+	}
+
+	if t, ok := getCaseInsensitive(obj, "type"); ok {
+		p.Type, _ = t.(string)
+	}
+	if d, ok := getCaseInsensitive(obj, "defaultValue"); ok {
+		p.DefaultValue = d
+		p.HasDefault = true
+	}
+	if a, ok := getCaseInsensitive(obj, "allowedValues"); ok {
+		p.AllowedValues, _ = a.([]interface{})
+	}
+
+	return p
+}
+
+// parseTemplateResources accepts resources declared either as an array (languageVersion 1.0)
+// or as an object keyed by symbolic name (languageVersion 2.0).
+func parseTemplateResources(v interface{}) []*TemplateResource {
+	var resources []*TemplateResource
+
+	switch r := v.(type) {
+	case []interface{}:
+		for _, item := range r {
+			if fields, ok := item.(map[string]interface{}); ok {
+				resources = append(resources, &TemplateResource{Fields: fields})
+			}
+		}
+	case map[string]interface{}:
+		// Sort the symbolic names so the output order is stable
+		names := make([]string, 0, len(r))
+		for name := range r {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if fields, ok := r[name].(map[string]interface{}); ok {
+				resources = append(resources, &TemplateResource{SymbolicName: name, Fields: fields})
+			}
+		}
+	}
+
+	return resources
+}
+
+// decodeJSON decodes b into generic JSON values, keeping integers as int64
+// so they format the same way ARM would in string functions.
+func decodeJSON(b []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return normalizeJSONValue(v), nil
+}
+
+func normalizeJSONValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = normalizeJSONValue(item)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = normalizeJSONValue(item)
+		}
+		return val
+	}
+
+	return v
+}
+
+// getCaseInsensitive looks up key in obj, falling back to a case-insensitive
+// match since ARM property names are not case sensitive.
+func getCaseInsensitive(obj map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := obj[key]; ok {
+		return v, true
+	}
+
+	for k, v := range obj {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case int64, float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
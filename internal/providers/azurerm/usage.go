@@ -0,0 +1,84 @@
+package azurerm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// populateUsageData sets the usage of an ARM resource from the usage file. Resources are matched,
+// from most to least specific, by:
+//
+//   - their address, e.g. "vmCopy[0]" or the resource ID,
+//   - their resource ID, ignoring case,
+//   - their symbolic name in a languageVersion 2.0 template or Bicep file,
+//   - a wildcard for all resources of a copy loop, e.g. "vmCopy[*]",
+//   - a pattern where * matches any characters, e.g. "/subscriptions/*/resourceGroups/rg-prod/providers/Microsoft.Storage/storageAccounts/*".
+//
+// The usage of the resource type, e.g. "azurerm_storage_account", fills in any values that aren't set.
+func populateUsageData(d *schema.ResourceData, symbolicName string, usage usageMap) {
+	if len(usage) == 0 {
+		return
+	}
+
+	id := d.Get("id").String()
+
+	ud := matchResourceUsage(usage, d.Address, id, symbolicName)
+	d.UsageData = ud.Merge(usage[d.Type])
+}
+
+func matchResourceUsage(usage usageMap, address, id, symbolicName string) *schema.UsageData {
+	if ud := usage[address]; ud != nil {
+		return ud
+	}
+
+	if id != "" {
+		for key, ud := range usage {
+			if strings.EqualFold(key, id) {
+				return ud
+			}
+		}
+	}
+
+	if symbolicName != "" {
+		if ud := usage[symbolicName]; ud != nil {
+			return ud
+		}
+	}
+
+	if strings.HasSuffix(address, "]") {
+		lastIndexOfOpenBracket := strings.LastIndex(address, "[")
+
+		if ud := usage[fmt.Sprintf("%s[*]", address[:lastIndexOfOpenBracket])]; ud != nil {
+			return ud
+		}
+	}
+
+	// The longest matching pattern is the most specific
+	var match *schema.UsageData
+	var matchLen int
+	for key, ud := range usage {
+		if !strings.Contains(key, "*") || strings.HasSuffix(key, "[*]") || len(key) <= matchLen {
+			continue
+		}
+
+		pattern := usageKeyPattern(key)
+		if pattern.MatchString(address) || (id != "" && pattern.MatchString(id)) {
+			match, matchLen = ud, len(key)
+		}
+	}
+
+	return match
+}
+
+// usageKeyPattern returns a case insensitive regexp for a usage key where * matches any characters.
+func usageKeyPattern(key string) *regexp.Regexp {
+	parts := strings.Split(key, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile("(?i)^" + strings.Join(parts, ".*") + "$")
+}
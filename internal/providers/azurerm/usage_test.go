@@ -0,0 +1,61 @@
+package azurerm
+
+import (
+	"testing"
+
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestPopulateUsageData(t *testing.T) {
+	usage := schema.NewUsageMap(map[string]interface{}{
+		"azurerm_storage_account": map[string]interface{}{
+			"storage_gb":               100,
+			"monthly_write_operations": 1000,
+		},
+		"vmCopy[1]":   map[string]interface{}{"monthly_hrs": 100},
+		"vmCopy[*]":   map[string]interface{}{"monthly_hrs": 200},
+		"logsStorage": map[string]interface{}{"storage_gb": 300},
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg-test/providers/microsoft.storage/storageaccounts/exact": map[string]interface{}{
+			"storage_gb": 400,
+		},
+		"/subscriptions/*/resourceGroups/rg-test/providers/Microsoft.Storage/storageAccounts/*":     map[string]interface{}{"storage_gb": 500},
+		"/subscriptions/*/resourceGroups/rg-test/providers/Microsoft.Storage/storageAccounts/data*": map[string]interface{}{"storage_gb": 600},
+	})
+
+	storageId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Storage/storageAccounts/"
+
+	tests := []struct {
+		name         string
+		resourceType string
+		address      string
+		id           string
+		symbolicName string
+		key          string
+		expected     int64
+	}{
+		{"address", "azurerm_linux_virtual_machine", "vmCopy[1]", "", "", "monthly_hrs", 100},
+		{"copy wildcard", "azurerm_linux_virtual_machine", "vmCopy[0]", "", "", "monthly_hrs", 200},
+		{"symbolic name", "azurerm_storage_account", storageId + "logs", storageId + "logs", "logsStorage", "storage_gb", 300},
+		{"resource ID ignoring case", "azurerm_storage_account", storageId + "exact", storageId + "exact", "", "storage_gb", 400},
+		{"pattern", "azurerm_storage_account", storageId + "other", storageId + "other", "", "storage_gb", 500},
+		{"most specific pattern", "azurerm_storage_account", "module.data." + storageId + "data01", storageId + "data01", "", "storage_gb", 600},
+		{"resource type defaults", "azurerm_storage_account", storageId + "other", storageId + "other", "", "monthly_write_operations", 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := schema.NewAzureRMResourceData(tt.resourceType, tt.address, gjson.Parse(`{"id": "`+tt.id+`"}`))
+			populateUsageData(d, tt.symbolicName, usage)
+
+			if assert.NotNil(t, d.UsageData) {
+				assert.Equal(t, tt.expected, d.UsageData.Get(tt.key).Int())
+			}
+		})
+	}
+
+	d := schema.NewAzureRMResourceData("azurerm_service_plan", "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test/providers/Microsoft.Web/serverfarms/plan", gjson.Parse(`{}`))
+	populateUsageData(d, "", usage)
+	assert.Nil(t, d.UsageData)
+}
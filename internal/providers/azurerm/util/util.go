@@ -31,8 +31,9 @@ func ToAzureCLIName(location string) string {
 
 func LookupRegion(d *schema.ResourceData, parentResourceKeys []string) string {
 	// First check for a location set directly on a resource
+	// ARM uses 'global' for resources that aren't deployed to a region, e.g. DNS zones and Front Doors
 	location := d.Get("location").String()
-	if location != "" && !strings.Contains(location, "mock") {
+	if location != "" && !strings.Contains(location, "mock") && !strings.EqualFold(location, "global") {
 		return ToAzureCLIName(location)
 	}
 
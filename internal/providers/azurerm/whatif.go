@@ -2,29 +2,41 @@ package azurerm
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/infracost/infracost/internal/schema"
 	"github.com/tidwall/gjson"
 )
 
 type ChangeType string
 type PropertyChangeType string
 
+// See: https://learn.microsoft.com/en-us/azure/azure-resource-manager/templates/deploy-what-if#change-types
 const (
-	Create      ChangeType = "Create"
-	Delete      ChangeType = "Delete"
-	Deploy                 = "Deploy"
-	Ignore                 = "Ignore"
-	Modify                 = "Modify"
-	NoChange               = "NoChange"
-	Unsupported            = "Unsupported"
+	// The resource doesn't exist but is defined in the template
+	Create ChangeType = "Create"
+	// The resource exists but isn't defined in the template, only returned in Complete mode
+	Delete ChangeType = "Delete"
+	// The resource exists and is defined in the template, it will be redeployed
+	Deploy ChangeType = "Deploy"
+	// The resource exists but isn't defined in the template, it won't be touched in Incremental mode
+	Ignore ChangeType = "Ignore"
+	// The resource exists, is defined in the template and its properties will change
+	Modify ChangeType = "Modify"
+	// The resource exists, is defined in the template and its properties won't change
+	NoChange ChangeType = "NoChange"
+	// The resource isn't supported by WhatIf, see UnsupportedReason
+	Unsupported ChangeType = "Unsupported"
 )
 
 const (
 	PropCreate   PropertyChangeType = "Create"
 	PropDelete   PropertyChangeType = "Delete"
-	PropArray                       = "Array"
-	PropModify                      = "Modify"
-	PropNoEffect                    = "NoEffect"
+	PropArray    PropertyChangeType = "Array"
+	PropModify   PropertyChangeType = "Modify"
+	PropNoEffect PropertyChangeType = "NoEffect"
 )
 
 // Struct for deserializing the JSON response of a whatif call
@@ -106,3 +118,58 @@ type ErrorAdditionalInfo struct {
 	Info string `json:"info"`
 	Type string `json:"type"`
 }
+
+// flattenPropertyChanges walks the delta of a WhatIf change and returns the leaf properties
+// whose values change, with paths relative to the resource, e.g. sku.name or properties.ipRules[0].value.
+// Changes that have no effect on the deployed resource, and tags, which never affect cost, are left out.
+func flattenPropertyChanges(delta []*WhatIfPropertyChange) []schema.PropertyChange {
+	var changes []schema.PropertyChange
+
+	for _, d := range delta {
+		changes = append(changes, flattenPropertyChange(d, "")...)
+	}
+
+	return changes
+}
+
+func flattenPropertyChange(change *WhatIfPropertyChange, parentPath string) []schema.PropertyChange {
+	if change == nil || change.PropertyChangeType == PropNoEffect {
+		return nil
+	}
+
+	path := joinPropertyPath(parentPath, change.Path)
+	if path == "tags" || strings.HasPrefix(path, "tags.") {
+		return nil
+	}
+
+	if len(change.Children) > 0 {
+		var changes []schema.PropertyChange
+		for _, child := range change.Children {
+			changes = append(changes, flattenPropertyChange(child, path)...)
+		}
+		return changes
+	}
+
+	before, _ := change.Before()
+	after, _ := change.After()
+
+	return []schema.PropertyChange{{
+		Path:   path,
+		Before: before.Value(),
+		After:  after.Value(),
+	}}
+}
+
+// joinPropertyPath joins the path of a child property change to its parent. WhatIf uses the
+// array index as the path of array items and the relative property name for anything else.
+func joinPropertyPath(parentPath, path string) string {
+	if parentPath == "" || strings.HasPrefix(path, parentPath+".") {
+		return path
+	}
+
+	if _, err := strconv.Atoi(path); err == nil {
+		return fmt.Sprintf("%s[%s]", parentPath, path)
+	}
+
+	return parentPath + "." + path
+}
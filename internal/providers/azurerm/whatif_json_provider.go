@@ -2,11 +2,13 @@ package azurerm
 
 import (
 	"os"
+	"strings"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
 	"github.com/infracost/infracost/internal/ui"
 	"github.com/pkg/errors"
+	"github.com/tidwall/gjson"
 )
 
 // TODO: AzureRM doesn't have a concept of a 'Project', needs its own config.ProjectContext object
@@ -15,6 +17,8 @@ type AzureRMWhatifProvider struct {
 	Path                 string
 	includePastResources bool
 	content              []byte
+	// addresses of the resources by lowercase resource ID, see ArmTemplateProvider.templateAddresses
+	addresses map[string]string
 }
 
 func NewWhatifJsonProvider(ctx *config.ProjectContext, includePastResources bool) *AzureRMWhatifProvider {
@@ -42,8 +46,54 @@ func (p *AzureRMWhatifProvider) DisplayType() string {
 	return "Azure Resource Manager WhatIf JSON"
 }
 
+// AddMetadata records the deployment of the WhatIf result. The subscription and resource group
+// are taken from the IDs of the changed resources if they all share one, and otherwise from the config.
 func (p *AzureRMWhatifProvider) AddMetadata(metadata *schema.ProjectMetadata) {
-	// no op
+	cfg := p.ctx.ProjectConfig
+
+	metadata.ArmDeploymentScope = cfg.ArmDeploymentScope
+	metadata.ArmSubscriptionId = cfg.ArmSubscriptionId
+	metadata.ArmResourceGroup = cfg.ArmResourceGroup
+	metadata.ArmLocation = cfg.ArmLocation
+
+	var ids []string
+	for _, change := range gjson.GetBytes(p.content, "changes").Array() {
+		ids = append(ids, change.Get("resourceId").String())
+	}
+
+	if sub := commonIdSegment(ids, "subscriptions"); sub != "" {
+		metadata.ArmSubscriptionId = sub
+	}
+	if rg := commonIdSegment(ids, "resourceGroups"); rg != "" {
+		metadata.ArmResourceGroup = rg
+	}
+}
+
+// commonIdSegment returns the value following the given segment, e.g. the name of the resource group
+// for "resourceGroups", if it's the same in all the IDs.
+func commonIdSegment(ids []string, segment string) string {
+	var common string
+
+	for _, id := range ids {
+		parts := strings.Split(strings.Trim(id, "/"), "/")
+
+		var value string
+		for i := 0; i < len(parts)-1; i++ {
+			if strings.EqualFold(parts[i], segment) {
+				value = parts[i+1]
+				break
+			}
+		}
+
+		if value == "" || (common != "" && !strings.EqualFold(common, value)) {
+			return ""
+		}
+		if common == "" {
+			common = value
+		}
+	}
+
+	return common
 }
 
 func (p *AzureRMWhatifProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
@@ -77,6 +127,7 @@ func (p *AzureRMWhatifProvider) LoadResources(usage map[string]*schema.UsageData
 
 	project := schema.NewProject(name, metadata)
 	parser := NewParser(p.ctx)
+	parser.addresses = p.addresses
 
 	// TODO: pastResources are ??, check what they are in Azure context
 	whatIfResources, err := parser.parse(p.content, usage)
@@ -85,7 +136,7 @@ func (p *AzureRMWhatifProvider) LoadResources(usage map[string]*schema.UsageData
 	}
 
 	for _, res := range whatIfResources {
-		if res.PartialPastResource != nil {
+		if res.PartialPastResource != nil && p.includePastResources {
 			project.PartialPastResources = append(project.PartialPastResources, res.PartialPastResource)
 		}
 		if res.PartialResource != nil {
@@ -26,4 +26,43 @@ func TestWhatIfJsonProvider(t *testing.T) {
 	// Ensure all resources in the whatif are returned from the provider
 	assert.Equal(t, 3, len(project[0].PartialResources))
 	assert.Equal(t, 0, len(project[0].PartialPastResources))
+
+	// The resources are in different resource groups of the same subscription
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", project[0].Metadata.ArmSubscriptionId)
+	assert.Equal(t, "", project[0].Metadata.ArmResourceGroup)
+}
+
+func TestCommonIdSegment(t *testing.T) {
+	ids := []string{
+		"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/serverfarms/plan",
+		"/subscriptions/sub/resourcegroups/RG/providers/Microsoft.Web/sites/app",
+	}
+
+	assert.Equal(t, "sub", commonIdSegment(ids, "subscriptions"))
+	assert.Equal(t, "rg", commonIdSegment(ids, "resourceGroups"))
+	assert.Equal(t, "", commonIdSegment(append(ids, "/subscriptions/sub"), "resourceGroups"))
+	assert.Equal(t, "", commonIdSegment(nil, "subscriptions"))
+}
+
+func TestResourceListJsonProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	ctx.ProjectConfig.Path = filepath.Join("testdata", "baseline", "resource_list.json")
+
+	provider := NewResourceListJsonProvider(ctx)
+	project, err := provider.LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	if err != nil {
+		t.Fatalf("Error loading resources: " + err.Error())
+	}
+
+	assert.Equal(t, 3, len(project[0].PartialResources))
+	assert.Equal(t, 0, len(project[0].PartialPastResources))
+	assert.Equal(t, "Microsoft.Web/serverFarms/AppServicePlan-AzureLinuxApp", project[0].PartialResources[0].ResourceData.Address)
+}
+
+func TestIsResourceListJSON(t *testing.T) {
+	assert.True(t, IsResourceListJSON([]byte(`[{"id": "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app", "type": "Microsoft.Web/sites"}]`)))
+	assert.True(t, IsResourceListJSON([]byte(`{"value": [{"id": "/subscriptions/sub/resourceGroups/rg", "type": "Microsoft.Resources/resourceGroups"}]}`)))
+	assert.False(t, IsResourceListJSON([]byte(`[]`)))
+	assert.False(t, IsResourceListJSON([]byte(`[{"name": "app"}]`)))
+	assert.False(t, IsResourceListJSON([]byte(`{"changes": [], "status": "Succeeded"}`)))
 }
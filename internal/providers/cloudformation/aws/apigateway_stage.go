@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v4/cloudformation/apigateway"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetAPIGatewayStageRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ApiGateway::Stage",
+		RFunc: NewAPIGatewayStage,
+	}
+}
+
+func NewAPIGatewayStage(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*apigateway.Stage)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	cacheClusterSize, _ := strconv.ParseFloat(cfr.CacheClusterSize, 64)
+
+	a := &aws.APIGatewayStage{
+		Address:          d.Address,
+		Region:           d.Get("region").String(),
+		CacheClusterSize: cacheClusterSize,
+		CacheEnabled:     cfr.CacheClusterEnabled,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
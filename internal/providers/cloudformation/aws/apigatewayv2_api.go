@@ -0,0 +1,44 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/apigatewayv2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetAPIGatewayV2APIRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ApiGatewayV2::Api",
+		RFunc: NewAPIGatewayV2API,
+	}
+}
+
+func NewAPIGatewayV2API(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*apigatewayv2.Api)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.APIGatewayV2API{
+		Address:      d.Address,
+		Region:       d.Get("region").String(),
+		ProtocolType: cfr.ProtocolType,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+
+	// Tags of API Gateway v2 are a JSON object rather than a list of key/value pairs
+	resource.Tags = make(map[string]string)
+	if t, ok := cfr.Tags.(map[string]interface{}); ok {
+		for k, v := range t {
+			resource.Tags[k] = fmt.Sprint(v)
+		}
+	}
+
+	return resource
+}
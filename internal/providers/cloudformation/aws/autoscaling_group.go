@@ -0,0 +1,235 @@
+package aws
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/autoscaling"
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetAutoscalingGroupRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::AutoScaling::AutoScalingGroup",
+		Notes: []string{
+			"Launch templates referenced by name or from other stacks are not supported.",
+		},
+		RFunc: NewAutoscalingGroup,
+		ReferenceAttributes: []string{
+			"LaunchConfigurationName",
+			"LaunchTemplate.LaunchTemplateId",
+			"MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification.LaunchTemplateId",
+		},
+	}
+}
+
+func NewAutoscalingGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*autoscaling.AutoScalingGroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.AutoscalingGroup{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+		Name:    cfr.AutoScalingGroupName,
+	}
+
+	var instanceCount int64
+	if cfr.DesiredCapacity != "" {
+		instanceCount, _ = strconv.ParseInt(cfr.DesiredCapacity, 10, 64)
+	} else {
+		instanceCount, _ = strconv.ParseInt(cfr.MinSize, 10, 64)
+		if instanceCount == 0 {
+			log.Debugf("Using instance count 1 for %s since no DesiredCapacity or non-zero MinSize is set. To override this set the instance_count attribute for this resource in the Infracost usage file.", a.Address)
+			instanceCount = 1
+		}
+	}
+
+	if refs := d.References("LaunchConfigurationName"); len(refs) > 0 {
+		if lc, ok := refs[0].CFResource.(*autoscaling.LaunchConfiguration); ok {
+			a.LaunchConfiguration = newLaunchConfiguration(refs[0].Address, lc, a.Region, instanceCount)
+		}
+	} else if refs := d.References("LaunchTemplate.LaunchTemplateId"); len(refs) > 0 {
+		if lt, ok := refs[0].CFResource.(*ec2.LaunchTemplate); ok {
+			onDemandPercentageAboveBaseCount := int64(100)
+			if isSpotLaunchTemplate(lt) {
+				onDemandPercentageAboveBaseCount = 0
+			}
+
+			a.LaunchTemplate = newLaunchTemplate(refs[0].Address, lt, a.Region, "", instanceCount, 0, onDemandPercentageAboveBaseCount)
+		}
+	} else if refs := d.References("MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification.LaunchTemplateId"); len(refs) > 0 {
+		if lt, ok := refs[0].CFResource.(*ec2.LaunchTemplate); ok {
+			a.LaunchTemplate = newMixedInstancesLaunchTemplate(refs[0].Address, lt, a.Region, instanceCount, cfr.MixedInstancesPolicy)
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	if resource != nil {
+		resource.Tags = make(map[string]string)
+		for _, tag := range cfr.Tags {
+			resource.Tags[tag.Key] = tag.Value
+		}
+	}
+
+	return resource
+}
+
+func newLaunchConfiguration(address string, cfr *autoscaling.LaunchConfiguration, region string, instanceCount int64) *aws.LaunchConfiguration {
+	purchaseOption := "on_demand"
+	if cfr.SpotPrice != "" {
+		purchaseOption = "spot"
+	}
+
+	a := &aws.LaunchConfiguration{
+		Address:          address,
+		Region:           region,
+		AMI:              cfr.ImageId,
+		InstanceCount:    intPtr(instanceCount),
+		Tenancy:          cfr.PlacementTenancy,
+		PurchaseOption:   purchaseOption,
+		InstanceType:     cfr.InstanceType,
+		EBSOptimized:     cfr.EbsOptimized,
+		EnableMonitoring: cfr.InstanceMonitoring,
+		RootBlockDevice: &aws.EBSVolume{
+			Address: "root_block_device",
+			Region:  region,
+		},
+	}
+
+	for _, mapping := range cfr.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+
+		volume := &aws.EBSVolume{
+			Region: region,
+			Type:   mapping.Ebs.VolumeType,
+			IOPS:   int64(mapping.Ebs.Iops),
+		}
+
+		if mapping.Ebs.VolumeSize > 0 {
+			volume.Size = intPtr(int64(mapping.Ebs.VolumeSize))
+		}
+
+		if rootDeviceNames[mapping.DeviceName] {
+			volume.Address = "root_block_device"
+			a.RootBlockDevice = volume
+			continue
+		}
+
+		volume.Address = fmt.Sprintf("ebs_block_device[%d]", len(a.EBSBlockDevices))
+		a.EBSBlockDevices = append(a.EBSBlockDevices, volume)
+	}
+
+	return a
+}
+
+// newLaunchTemplate returns the launch template with the instance type overridden by instanceType, if
+// it's set, since autoscaling groups and EKS node groups can override the type of the template.
+func newLaunchTemplate(address string, cfr *ec2.LaunchTemplate, region string, instanceType string, instanceCount, onDemandBaseCount, onDemandPercentageAboveBaseCount int64) *aws.LaunchTemplate {
+	a := &aws.LaunchTemplate{
+		Address:                          address,
+		Region:                           region,
+		InstanceCount:                    intPtr(instanceCount),
+		OnDemandBaseCount:                onDemandBaseCount,
+		OnDemandPercentageAboveBaseCount: onDemandPercentageAboveBaseCount,
+		InstanceType:                     instanceType,
+	}
+
+	data := cfr.LaunchTemplateData
+	if data == nil {
+		return a
+	}
+
+	a.AMI = data.ImageId
+	a.EBSOptimized = data.EbsOptimized
+
+	if a.InstanceType == "" {
+		a.InstanceType = data.InstanceType
+	}
+	if data.Placement != nil {
+		a.Tenancy = data.Placement.Tenancy
+	}
+	if data.Monitoring != nil {
+		a.EnableMonitoring = data.Monitoring.Enabled
+	}
+	if data.CreditSpecification != nil {
+		a.CPUCredits = data.CreditSpecification.CpuCredits
+	}
+	if len(data.ElasticInferenceAccelerators) > 0 {
+		a.ElasticInferenceAcceleratorType = &data.ElasticInferenceAccelerators[0].Type
+	}
+
+	for _, mapping := range data.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+
+		volume := &aws.EBSVolume{
+			Address: fmt.Sprintf("block_device_mapping[%d]", len(a.EBSBlockDevices)),
+			Region:  region,
+			Type:    mapping.Ebs.VolumeType,
+			IOPS:    int64(mapping.Ebs.Iops),
+		}
+
+		if mapping.Ebs.VolumeSize > 0 {
+			volume.Size = intPtr(int64(mapping.Ebs.VolumeSize))
+		}
+
+		a.EBSBlockDevices = append(a.EBSBlockDevices, volume)
+	}
+
+	return a
+}
+
+func newMixedInstancesLaunchTemplate(address string, cfr *ec2.LaunchTemplate, region string, capacity int64, policy *autoscaling.AutoScalingGroup_MixedInstancesPolicy) *aws.LaunchTemplate {
+	instanceType, instanceCount := getInstanceTypeAndCount(policy, capacity)
+
+	onDemandBaseCount := int64(0)
+	onDemandPercentageAboveBaseCount := int64(100)
+	// goformation can't tell an unset percentage from 0, so 0 is taken to mean the group is all spot
+	// instances above the base capacity when the distribution is set.
+	if dist := policy.InstancesDistribution; dist != nil {
+		onDemandBaseCount = int64(dist.OnDemandBaseCapacity)
+		onDemandPercentageAboveBaseCount = int64(dist.OnDemandPercentageAboveBaseCapacity)
+	}
+
+	return newLaunchTemplate(address, cfr, region, instanceType, instanceCount, onDemandBaseCount, onDemandPercentageAboveBaseCount)
+}
+
+// getInstanceTypeAndCount returns the instance type of the first override of the mixed instances
+// policy and the number of instances needed for the capacity with the weight of the override.
+func getInstanceTypeAndCount(policy *autoscaling.AutoScalingGroup_MixedInstancesPolicy, capacity int64) (string, int64) {
+	if policy.LaunchTemplate == nil || len(policy.LaunchTemplate.Overrides) == 0 {
+		return "", capacity
+	}
+
+	override := policy.LaunchTemplate.Overrides[0]
+
+	weightedCapacity := int64(1)
+	if override.WeightedCapacity != "" {
+		weightedCapacity, _ = strconv.ParseInt(override.WeightedCapacity, 10, 64)
+	}
+
+	if weightedCapacity == 0 {
+		return override.InstanceType, 0
+	}
+
+	return override.InstanceType, decimal.NewFromInt(capacity).Div(decimal.NewFromInt(weightedCapacity)).Ceil().IntPart()
+}
+
+func isSpotLaunchTemplate(cfr *ec2.LaunchTemplate) bool {
+	data := cfr.LaunchTemplateData
+	return data != nil && data.InstanceMarketOptions != nil && strings.EqualFold(data.InstanceMarketOptions.MarketType, "spot")
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/cloudfront"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetCloudfrontDistributionRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::CloudFront::Distribution",
+		RFunc: NewCloudfrontDistribution,
+	}
+}
+
+func NewCloudfrontDistribution(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*cloudfront.Distribution)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.CloudfrontDistribution{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+
+	if config := cfr.DistributionConfig; config != nil {
+		if len(config.Origins) > 0 && config.Origins[0].OriginShield != nil {
+			a.IsOriginShieldEnabled = config.Origins[0].OriginShield.Enabled
+			a.OriginShieldRegion = config.Origins[0].OriginShield.OriginShieldRegion
+		}
+
+		a.IsSSLSupportMethodVIP = config.ViewerCertificate != nil && config.ViewerCertificate.SslSupportMethod == "vip"
+		a.HasLoggingConfigBucket = config.Logging != nil && config.Logging.Bucket != ""
+		a.HasFieldLevelEncryptionID = config.DefaultCacheBehavior != nil && config.DefaultCacheBehavior.FieldLevelEncryptionId != ""
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
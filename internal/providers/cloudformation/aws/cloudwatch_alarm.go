@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/cloudwatch"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetCloudwatchAlarmRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::CloudWatch::Alarm",
+		RFunc: NewCloudwatchAlarm,
+	}
+}
+
+func NewCloudwatchAlarm(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*cloudwatch.Alarm)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	metricCount := int64(1)
+	period := int64(cfr.Period)
+
+	// Alarms on metric math expressions are charged for each metric in the expression
+	if len(cfr.Metrics) > 0 {
+		metricCount = 0
+		for _, m := range cfr.Metrics {
+			if m.MetricStat == nil {
+				continue
+			}
+
+			metricCount++
+
+			if period == 0 {
+				period = int64(m.MetricStat.Period)
+			}
+		}
+	}
+
+	a := &aws.CloudwatchMetricAlarm{
+		Address:            d.Address,
+		Region:             d.Get("region").String(),
+		ComparisonOperator: cfr.ComparisonOperator,
+		Metrics:            metricCount,
+		Period:             period,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/docdb"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetDocDBClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::DocDB::DBCluster",
+		RFunc: NewDocDBCluster,
+	}
+}
+
+func NewDocDBCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*docdb.DBCluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.DocDBCluster{
+		Address:               d.Address,
+		Region:                d.Get("region").String(),
+		BackupRetentionPeriod: int64(cfr.BackupRetentionPeriod),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/docdb"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetDocDBClusterInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::DocDB::DBInstance",
+		RFunc: NewDocDBClusterInstance,
+	}
+}
+
+func NewDocDBClusterInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*docdb.DBInstance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.DocDBClusterInstance{
+		Address:       d.Address,
+		Region:        d.Get("region").String(),
+		InstanceClass: cfr.DBInstanceClass,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEC2ClientVPNEndpointRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::ClientVpnEndpoint",
+		RFunc: NewEC2ClientVPNEndpoint,
+	}
+}
+
+func GetEC2ClientVPNNetworkAssociationRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::ClientVpnTargetNetworkAssociation",
+		RFunc: NewEC2ClientVPNNetworkAssociation,
+	}
+}
+
+func NewEC2ClientVPNEndpoint(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.ClientVpnEndpoint)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.EC2ClientVPNEndpoint{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = make(map[string]string)
+	for _, spec := range cfr.TagSpecifications {
+		for k, v := range mapTags(spec.Tags) {
+			resource.Tags[k] = v
+		}
+	}
+
+	return resource
+}
+
+func NewEC2ClientVPNNetworkAssociation(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	a := &aws.EC2ClientVPNNetworkAssociation{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEIPRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::EIP",
+		Notes: []string{
+			"Elastic IPs are assumed to be associated with an instance, NAT gateway or network interface.",
+		},
+		RFunc: NewEIP,
+	}
+}
+
+func NewEIP(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.EIP)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	// Templates usually associate Elastic IPs with the AllocationId of a NAT gateway or an
+	// AWS::EC2::EIPAssociation rather than the InstanceId, so they're treated as allocated.
+	a := &aws.EIP{
+		Address:   d.Address,
+		Region:    d.Get("region").String(),
+		Allocated: true,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+// rootDeviceNames are the device names of the root volumes of the Amazon Linux, Windows and Ubuntu
+// AMIs. Templates can override the root volume with a block device mapping using these names.
+var rootDeviceNames = map[string]bool{
+	"/dev/xvda":  true,
+	"/dev/sda1":  true,
+	"/dev/nvme0": true,
+}
+
+func GetEC2InstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::EC2::Instance",
+		Notes: []string{
+			"Costs associated with marketplace AMIs are not supported.",
+			"Properties of launch templates are not used.",
+		},
+		RFunc: NewEC2Instance,
+	}
+}
+
+func NewEC2Instance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.Instance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	var cpuCredits string
+	if cfr.CreditSpecification != nil {
+		cpuCredits = cfr.CreditSpecification.CPUCredits
+	}
+
+	a := &aws.Instance{
+		Address:          d.Address,
+		Region:           region,
+		Tenancy:          cfr.Tenancy,
+		PurchaseOption:   "on_demand",
+		AMI:              cfr.ImageId,
+		InstanceType:     cfr.InstanceType,
+		EBSOptimized:     cfr.EbsOptimized,
+		EnableMonitoring: cfr.Monitoring,
+		CPUCredits:       cpuCredits,
+		HasHost:          cfr.HostId != "",
+		RootBlockDevice: &aws.EBSVolume{
+			Address: "root_block_device",
+			Region:  region,
+		},
+	}
+
+	if len(cfr.ElasticInferenceAccelerators) > 0 {
+		a.ElasticInferenceAcceleratorType = &cfr.ElasticInferenceAccelerators[0].Type
+	}
+
+	for _, mapping := range cfr.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+
+		volume := &aws.EBSVolume{
+			Region: region,
+			Type:   mapping.Ebs.VolumeType,
+			IOPS:   int64(mapping.Ebs.Iops),
+		}
+
+		if mapping.Ebs.VolumeSize > 0 {
+			volume.Size = intPtr(int64(mapping.Ebs.VolumeSize))
+		}
+
+		if rootDeviceNames[mapping.DeviceName] {
+			volume.Address = "root_block_device"
+			a.RootBlockDevice = volume
+			continue
+		}
+
+		volume.Address = fmt.Sprintf("ebs_block_device[%d]", len(a.EBSBlockDevices))
+		a.EBSBlockDevices = append(a.EBSBlockDevices, volume)
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEC2TransitGatewayVpcAttachmentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::TransitGatewayAttachment",
+		RFunc: NewEC2TransitGatewayVpcAttachment,
+	}
+}
+
+func GetEC2TransitGatewayPeeringAttachmentRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::TransitGatewayPeeringAttachment",
+		RFunc: NewEC2TransitGatewayPeeringAttachment,
+	}
+}
+
+// NewEC2TransitGatewayVpcAttachment prices the attachment in the region of the stack, since the
+// transit gateway and VPC of an attachment have to be in the region it's created in.
+func NewEC2TransitGatewayVpcAttachment(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.TransitGatewayAttachment)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.Ec2TransitGatewayVpcAttachment{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
+
+func NewEC2TransitGatewayPeeringAttachment(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.TransitGatewayPeeringAttachment)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.EC2TransitGatewayPeeringAttachment{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,39 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetVPCEndpointRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EC2::VPCEndpoint",
+		RFunc: NewVPCEndpoint,
+	}
+}
+
+func NewVPCEndpoint(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ec2.VPCEndpoint)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	// Interface endpoints have a network interface in each subnet
+	interfaces := int64(1)
+	if len(cfr.SubnetIds) > 0 {
+		interfaces = int64(len(cfr.SubnetIds))
+	}
+
+	a := &aws.VPCEndpoint{
+		Address:    d.Address,
+		Region:     d.Get("region").String(),
+		Type:       cfr.VpcEndpointType,
+		Interfaces: intPtr(interfaces),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,136 @@
+package aws
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ecs"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+func GetECSServiceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ECS::Service",
+		RFunc: NewECSService,
+		ReferenceAttributes: []string{
+			"Cluster",
+			"TaskDefinition",
+		},
+	}
+}
+
+func NewECSService(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ecs.Service)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.ECSService{
+		Address:      d.Address,
+		Region:       d.Get("region").String(),
+		LaunchType:   calcLaunchType(d, cfr),
+		DesiredCount: int64(cfr.DesiredCount),
+	}
+
+	for _, ref := range d.References("TaskDefinition") {
+		if taskDefinition, ok := ref.CFResource.(*ecs.TaskDefinition); ok {
+			a.MemoryGB = parseVCPUMemoryString(taskDefinition.Memory)
+			a.VCPU = parseVCPUMemoryString(taskDefinition.Cpu)
+			if len(taskDefinition.InferenceAccelerators) > 0 {
+				a.InferenceAcceleratorDeviceType = taskDefinition.InferenceAccelerators[0].DeviceType
+			}
+			break
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
+
+// calcLaunchType determines the launch type of the service using the following precedence:
+//  1. LaunchType of the service
+//  2. CapacityProviderStrategy of the service
+//  3. DefaultCapacityProviderStrategy of the cluster
+//  4. CapacityProviders of the cluster
+func calcLaunchType(d *schema.ResourceData, cfr *ecs.Service) string {
+	if cfr.LaunchType != "" {
+		return cfr.LaunchType
+	}
+
+	launchType := ""
+	for _, s := range cfr.CapacityProviderStrategy {
+		if lt := capacityProviderLaunchType(s.CapacityProvider, s.Base, s.Weight); lt == "FARGATE" {
+			return lt
+		} else if lt != "" {
+			launchType = lt
+		}
+	}
+	if launchType != "" {
+		return launchType
+	}
+
+	for _, ref := range d.References("Cluster") {
+		cluster, ok := ref.CFResource.(*ecs.Cluster)
+		if !ok {
+			continue
+		}
+
+		if len(cluster.DefaultCapacityProviderStrategy) == 0 {
+			// since there are no default strategies, look for a directly set fargate capacity provider
+			for _, provider := range cluster.CapacityProviders {
+				if provider == "FARGATE" {
+					return "FARGATE"
+				}
+			}
+		}
+
+		for _, s := range cluster.DefaultCapacityProviderStrategy {
+			if lt := capacityProviderLaunchType(s.CapacityProvider, s.Base, s.Weight); lt == "FARGATE" {
+				return lt
+			} else if lt != "" {
+				launchType = lt
+			}
+		}
+	}
+
+	return launchType
+}
+
+func capacityProviderLaunchType(provider string, base, weight int) string {
+	if base <= 0 && weight <= 0 {
+		return ""
+	}
+
+	if strings.HasPrefix(strings.ToUpper(provider), "FARGATE") {
+		return "FARGATE"
+	}
+
+	return "EC2"
+}
+
+// parseVCPUMemoryString returns the number of vCPUs or GB of memory of a task definition, which are
+// either in CPU units and MiB or strings like "1 vCPU" and "2 GB".
+func parseVCPUMemoryString(rawValue string) float64 {
+	var quantity float64
+
+	noSpaceString := strings.ReplaceAll(rawValue, " ", "")
+
+	reg := regexp.MustCompile(`(?i)vcpu|gb`)
+	if reg.MatchString(noSpaceString) {
+		quantity, _ = strconv.ParseFloat(reg.ReplaceAllString(noSpaceString, ""), 64)
+	} else {
+		quantity, _ = strconv.ParseFloat(noSpaceString, 64)
+		quantity /= 1024.0
+	}
+
+	return quantity
+}
@@ -0,0 +1,45 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/efs"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEFSFileSystemRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EFS::FileSystem",
+		RFunc: NewEFSFileSystem,
+	}
+}
+
+func NewEFSFileSystem(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*efs.FileSystem)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.EFSFileSystem{
+		Address:                     d.Address,
+		Region:                      d.Get("region").String(),
+		HasLifecyclePolicy:          len(cfr.LifecyclePolicies) > 0,
+		AvailabilityZoneName:        cfr.AvailabilityZoneName,
+		ProvisionedThroughputInMBps: cfr.ProvisionedThroughputInMibps,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = efsTags(cfr.FileSystemTags)
+
+	return resource
+}
+
+func efsTags(cfTags []efs.FileSystem_ElasticFileSystemTag) map[string]string {
+	mapped := make(map[string]string)
+	for _, tag := range cfTags {
+		mapped[tag.Key] = tag.Value
+	}
+	return mapped
+}
@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/eks"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetEKSClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::Cluster",
+		RFunc: NewEKSCluster,
+	}
+}
+
+func NewEKSCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	_, ok := d.CFResource.(*eks.Cluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.EKSCluster{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/eks"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetNewEKSFargateProfileItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::FargateProfile",
+		RFunc: NewEKSFargateProfile,
+	}
+}
+
+func NewEKSFargateProfile(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*eks.FargateProfile)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.EKSFargateProfile{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
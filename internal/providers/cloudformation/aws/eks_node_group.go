@@ -0,0 +1,92 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/eks"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+var defaultEKSInstanceType = "t3.medium"
+
+func GetNewEKSNodeGroupItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::EKS::Nodegroup",
+		RFunc: NewEKSNodeGroup,
+		ReferenceAttributes: []string{
+			"LaunchTemplate.Id",
+		},
+	}
+}
+
+func NewEKSNodeGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*eks.Nodegroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+
+	var instanceCount int64
+	if cfr.ScalingConfig != nil {
+		instanceCount = int64(cfr.ScalingConfig.DesiredSize)
+	}
+
+	diskSize := float64(20)
+	if cfr.DiskSize > 0 {
+		diskSize = cfr.DiskSize
+	}
+
+	a := &aws.EKSNodeGroup{
+		Address:       d.Address,
+		Region:        region,
+		Name:          cfr.NodegroupName,
+		ClusterName:   cfr.ClusterName,
+		InstanceCount: intPtr(instanceCount),
+		DiskSize:      diskSize,
+	}
+
+	// The instance types in the node group override any in the launch template
+	var instanceType string
+	if len(cfr.InstanceTypes) > 0 {
+		instanceType = strings.ToLower(cfr.InstanceTypes[0])
+	}
+
+	var launchTemplate *ec2.LaunchTemplate
+	var launchTemplateAddress string
+	if refs := d.References("LaunchTemplate.Id"); len(refs) > 0 {
+		launchTemplate, _ = refs[0].CFResource.(*ec2.LaunchTemplate)
+		launchTemplateAddress = refs[0].Address
+	}
+
+	if launchTemplate != nil {
+		onDemandPercentageAboveBaseCount := int64(100)
+		if isSpotLaunchTemplate(launchTemplate) {
+			onDemandPercentageAboveBaseCount = 0
+		}
+
+		a.LaunchTemplate = newLaunchTemplate(launchTemplateAddress, launchTemplate, region, instanceType, instanceCount, 0, onDemandPercentageAboveBaseCount)
+		if a.LaunchTemplate.InstanceType == "" {
+			a.LaunchTemplate.InstanceType = defaultEKSInstanceType
+		}
+	} else {
+		if instanceType == "" {
+			instanceType = defaultEKSInstanceType
+		}
+		a.InstanceType = instanceType
+		a.PurchaseOption = strings.ToLower(cfr.CapacityType)
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	if resource != nil {
+		resource.Tags = mapUntypedTags(cfr.Tags)
+	}
+
+	return resource
+}
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticache"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetElastiCacheClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElastiCache::CacheCluster",
+		RFunc: NewElastiCacheCluster,
+	}
+}
+
+func NewElastiCacheCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticache.CacheCluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.ElastiCacheCluster{
+		Address:                d.Address,
+		Region:                 d.Get("region").String(),
+		NodeType:               cfr.CacheNodeType,
+		Engine:                 cfr.Engine,
+		CacheNodes:             int64(cfr.NumCacheNodes),
+		SnapshotRetentionLimit: int64(cfr.SnapshotRetentionLimit),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticache"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetElastiCacheReplicationGroupItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElastiCache::ReplicationGroup",
+		RFunc: NewElastiCacheReplicationGroup,
+	}
+}
+
+func NewElastiCacheReplicationGroup(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticache.ReplicationGroup)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	cacheClusters := int64(cfr.NumCacheClusters)
+	if cacheClusters == 0 {
+		cacheClusters = 1
+	}
+
+	clusterNodeGroups := int64(cfr.NumNodeGroups)
+	clusterReplicasPerNodeGroup := int64(cfr.ReplicasPerNodeGroup)
+	// The node groups can be configured individually instead, in which case the replicas of the first
+	// node group are used for all of them
+	if clusterNodeGroups == 0 && len(cfr.NodeGroupConfiguration) > 0 {
+		clusterNodeGroups = int64(len(cfr.NodeGroupConfiguration))
+		if clusterReplicasPerNodeGroup == 0 {
+			clusterReplicasPerNodeGroup = int64(cfr.NodeGroupConfiguration[0].ReplicaCount)
+		}
+	}
+
+	a := &aws.ElastiCacheReplicationGroup{
+		Address:                     d.Address,
+		Region:                      d.Get("region").String(),
+		NodeType:                    cfr.CacheNodeType,
+		Engine:                      cfr.Engine,
+		CacheClusters:               cacheClusters,
+		ClusterNodeGroups:           clusterNodeGroups,
+		ClusterReplicasPerNodeGroup: clusterReplicasPerNodeGroup,
+		SnapshotRetentionLimit:      int64(cfr.SnapshotRetentionLimit),
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
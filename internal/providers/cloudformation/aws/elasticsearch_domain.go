@@ -0,0 +1,129 @@
+package aws
+
+import (
+	"encoding/json"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/cloudformation/elasticsearch"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+	"github.com/tidwall/gjson"
+)
+
+func GetElasticsearchDomainRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Elasticsearch::Domain",
+		RFunc: NewElasticsearchDomain,
+	}
+}
+
+// GetOpenSearchDomainRegistryItem returns the registry item of OpenSearch domains, which are
+// Elasticsearch domains with a new name. goformation doesn't know the type, so it's read from the
+// properties of the custom resource it's parsed as.
+func GetOpenSearchDomainRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::OpenSearchService::Domain",
+		RFunc: NewOpenSearchDomain,
+	}
+}
+
+func NewElasticsearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticsearch.Domain)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.ElasticsearchDomain{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+	}
+
+	if config := cfr.ElasticsearchClusterConfig; config != nil {
+		a.ClusterInstanceType = config.InstanceType
+		a.ClusterDedicatedMasterEnabled = config.DedicatedMasterEnabled
+		a.ClusterDedicatedMasterType = config.DedicatedMasterType
+		a.ClusterWarmEnabled = config.WarmEnabled
+		a.ClusterWarmType = config.WarmType
+
+		if config.InstanceCount > 0 {
+			a.ClusterInstanceCount = intPtr(int64(config.InstanceCount))
+		}
+		if config.DedicatedMasterCount > 0 {
+			a.ClusterDedicatedMasterCount = intPtr(int64(config.DedicatedMasterCount))
+		}
+		if config.WarmCount > 0 {
+			a.ClusterWarmCount = intPtr(int64(config.WarmCount))
+		}
+	}
+
+	if ebs := cfr.EBSOptions; ebs != nil {
+		a.EBSEnabled = ebs.EBSEnabled
+		a.EBSVolumeType = ebs.VolumeType
+
+		if ebs.VolumeSize > 0 {
+			a.EBSVolumeSize = floatPtr(float64(ebs.VolumeSize))
+		}
+		if ebs.Iops > 0 {
+			a.EBSIOPS = floatPtr(float64(ebs.Iops))
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
+
+func NewOpenSearchDomain(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*cloudformation.CustomResource)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	b, err := json.Marshal(cfr.Properties)
+	if err != nil {
+		log.Warnf("Skipping resource %s as its properties could not be read: %s", d.Address, err)
+		return nil
+	}
+	properties := gjson.ParseBytes(b)
+
+	a := &aws.ElasticsearchDomain{
+		Address:                       d.Address,
+		Region:                        d.Get("region").String(),
+		ClusterInstanceType:           properties.Get("ClusterConfig.InstanceType").String(),
+		ClusterDedicatedMasterEnabled: properties.Get("ClusterConfig.DedicatedMasterEnabled").Bool(),
+		ClusterDedicatedMasterType:    properties.Get("ClusterConfig.DedicatedMasterType").String(),
+		ClusterWarmEnabled:            properties.Get("ClusterConfig.WarmEnabled").Bool(),
+		ClusterWarmType:               properties.Get("ClusterConfig.WarmType").String(),
+		EBSEnabled:                    properties.Get("EBSOptions.EBSEnabled").Bool(),
+		EBSVolumeType:                 properties.Get("EBSOptions.VolumeType").String(),
+	}
+
+	if v := properties.Get("ClusterConfig.InstanceCount"); v.Exists() {
+		a.ClusterInstanceCount = intPtr(v.Int())
+	}
+	if v := properties.Get("ClusterConfig.DedicatedMasterCount"); v.Exists() {
+		a.ClusterDedicatedMasterCount = intPtr(v.Int())
+	}
+	if v := properties.Get("ClusterConfig.WarmCount"); v.Exists() {
+		a.ClusterWarmCount = intPtr(v.Int())
+	}
+	if v := properties.Get("EBSOptions.VolumeSize"); v.Exists() {
+		a.EBSVolumeSize = floatPtr(v.Float())
+	}
+	if v := properties.Get("EBSOptions.Iops"); v.Exists() {
+		a.EBSIOPS = floatPtr(v.Float())
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapUntypedTags(properties.Get("Tags").Value())
+
+	return resource
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/elasticloadbalancingv2"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetLBRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::ElasticLoadBalancingV2::LoadBalancer",
+		RFunc: NewLB,
+	}
+}
+
+func NewLB(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*elasticloadbalancingv2.LoadBalancer)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	loadBalancerType := cfr.Type
+	if loadBalancerType == "" {
+		loadBalancerType = "application"
+	}
+
+	a := &aws.LB{
+		Address:          d.Address,
+		Region:           d.Get("region").String(),
+		LoadBalancerType: loadBalancerType,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
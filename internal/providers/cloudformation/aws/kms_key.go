@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/kms"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetKMSKeyRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::KMS::Key",
+		RFunc: NewKMSKey,
+	}
+}
+
+func NewKMSKey(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*kms.Key)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.KMSKey{
+		Address:               d.Address,
+		Region:                d.Get("region").String(),
+		CustomerMasterKeySpec: cfr.KeySpec,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/amazonmq"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetMQBrokerRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::AmazonMQ::Broker",
+		RFunc: NewMQBroker,
+	}
+}
+
+func NewMQBroker(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*amazonmq.Broker)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.MQBroker{
+		Address:          d.Address,
+		Region:           d.Get("region").String(),
+		EngineType:       cfr.EngineType,
+		HostInstanceType: cfr.HostInstanceType,
+		StorageType:      cfr.StorageType,
+		DeploymentMode:   cfr.DeploymentMode,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = make(map[string]string)
+	for _, tag := range cfr.Tags {
+		resource.Tags[tag.Key] = tag.Value
+	}
+
+	return resource
+}
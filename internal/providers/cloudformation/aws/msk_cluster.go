@@ -0,0 +1,43 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/msk"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetMSKClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::MSK::Cluster",
+		RFunc: NewMSKCluster,
+	}
+}
+
+func NewMSKCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*msk.Cluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.MSKCluster{
+		Address:     d.Address,
+		Region:      d.Get("region").String(),
+		BrokerNodes: int64(cfr.NumberOfBrokerNodes),
+	}
+
+	if info := cfr.BrokerNodeGroupInfo; info != nil {
+		a.BrokerNodeInstanceType = info.InstanceType
+		if info.StorageInfo != nil && info.StorageInfo.EBSStorageInfo != nil {
+			a.BrokerNodeEBSVolumeSize = int64(info.StorageInfo.EBSStorageInfo.VolumeSize)
+		}
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapUntypedTags(cfr.Tags)
+
+	return resource
+}
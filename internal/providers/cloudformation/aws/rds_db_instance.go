@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"strconv"
+
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetRDSDBInstanceRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::RDS::DBInstance",
+		RFunc: NewRDSDBInstance,
+	}
+}
+
+func NewRDSDBInstance(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*rds.DBInstance)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	region := d.Get("region").String()
+	piEnabled := cfr.EnablePerformanceInsights
+	piLongTerm := piEnabled && cfr.PerformanceInsightsRetentionPeriod > 7
+
+	// Instances of Aurora clusters don't have their own storage, it's priced by the AWS::RDS::DBCluster
+	if cfr.DBClusterIdentifier != "" {
+		a := &aws.RDSClusterInstance{
+			Address:                              d.Address,
+			Region:                               region,
+			InstanceClass:                        cfr.DBInstanceClass,
+			Engine:                               cfr.Engine,
+			PerformanceInsightsEnabled:           piEnabled,
+			PerformanceInsightsLongTermRetention: piLongTerm,
+		}
+		a.PopulateUsage(u)
+
+		resource := a.BuildResource()
+		resource.Tags = mapTags(cfr.Tags)
+
+		return resource
+	}
+
+	a := &aws.DBInstance{
+		Address:                              d.Address,
+		Region:                               region,
+		InstanceClass:                        cfr.DBInstanceClass,
+		Engine:                               cfr.Engine,
+		MultiAZ:                              cfr.MultiAZ,
+		LicenseModel:                         cfr.LicenseModel,
+		BackupRetentionPeriod:                int64(cfr.BackupRetentionPeriod),
+		IOPS:                                 float64(cfr.Iops),
+		StorageType:                          cfr.StorageType,
+		PerformanceInsightsEnabled:           piEnabled,
+		PerformanceInsightsLongTermRetention: piLongTerm,
+	}
+
+	// AllocatedStorage is a string in the CloudFormation schema
+	if storage, err := strconv.ParseFloat(cfr.AllocatedStorage, 64); err == nil {
+		a.AllocatedStorageGB = floatPtr(storage)
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
@@ -0,0 +1,40 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/redshift"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetRedshiftClusterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Redshift::Cluster",
+		RFunc: NewRedshiftCluster,
+	}
+}
+
+func NewRedshiftCluster(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*redshift.Cluster)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.RedshiftCluster{
+		Address:  d.Address,
+		Region:   d.Get("region").String(),
+		NodeType: cfr.NodeType,
+	}
+
+	if cfr.NumberOfNodes > 0 {
+		a.Nodes = intPtr(int64(cfr.NumberOfNodes))
+	}
+
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
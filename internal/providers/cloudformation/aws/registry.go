@@ -3,83 +3,174 @@ package aws
 import "github.com/infracost/infracost/internal/schema"
 
 var ResourceRegistry []*schema.RegistryItem = []*schema.RegistryItem{
-	// GetAPIGatewayRestAPIRegistryItem(),
-	// GetAPIGatewayStageRegistryItem(),
-	// GetAPIGatewayv2ApiRegistryItem(),
-	// GetAutoscalingGroupRegistryItem(),
+	GetAPIGatewayRestAPIRegistryItem(),
+	GetAPIGatewayStageRegistryItem(),
+	GetAPIGatewayV2APIRegistryItem(),
+	GetAutoscalingGroupRegistryItem(),
 	// GetACMCertificate(),
 	// GetACMPCACertificateAuthorityRegistryItem(),
-	// GetCloudfrontDistributionRegistryItem(),
+	GetCloudfrontDistributionRegistryItem(),
 	// GetCloudwatchDashboardRegistryItem(),
 	// GetCloudwatchEventBusItem(),
-	// GetCloudwatchLogGroupItem(),
-	// GetCloudwatchMetricAlarmRegistryItem(),
+	GetCloudwatchLogGroupRegistryItem(),
+	GetCloudwatchAlarmRegistryItem(),
 	// GetCodebuildProjectRegistryItem(),
 	// GetConfigRuleItem(),
 	// GetConfigurationRecorderItem(),
 	// GetConfigOrganizationCustomRuleItem(),
 	// GetConfigOrganizationManagedRuleItem(),
 	// getDataTransferRegistryItem(),
-	// GetDBInstanceRegistryItem(),
+	GetRDSDBInstanceRegistryItem(),
 	// GetDMSRegistryItem(),
-	// GetDocDBClusterInstanceRegistryItem(),
-	// GetDocDBClusterRegistryItem(),
+	GetDocDBClusterInstanceRegistryItem(),
+	GetDocDBClusterRegistryItem(),
 	// GetDocDBClusterSnapshotRegistryItem(),
 	// GetDXConnectionRegistryItem(),
 	// GetDXGatewayAssociationRegistryItem(),
 	GetDynamoDBTableRegistryItem(),
 	// GetEBSSnapshotCopyRegistryItem(),
 	// GetEBSSnapshotRegistryItem(),
-	// GetEBSVolumeRegistryItem(),
-	// GetEC2ClientVPNEndpointRegistryItem(),
-	// GetEC2ClientVPNNetworkAssociationRegistryItem(),
+	GetEBSVolumeRegistryItem(),
+	GetEC2ClientVPNEndpointRegistryItem(),
+	GetEC2ClientVPNNetworkAssociationRegistryItem(),
 	// GetEC2TrafficMirroSessionRegistryItem(),
-	// GetEC2TransitGatewayPeeringAttachmentRegistryItem(),
-	// GetEC2TransitGatewayVpcAttachmentRegistryItem(),
-	// GetECRRegistryItem(),
-	// GetECSServiceRegistryItem(),
-	// GetEFSFileSystemRegistryItem(),
-	// GetEIPRegistryItem(),
-	// GetElastiCacheClusterItem(),
-	// GetElastiCacheReplicationGroupItem(),
-	// GetElasticsearchDomainRegistryItem(),
-	// GetELBRegistryItem(),
+	GetEC2TransitGatewayPeeringAttachmentRegistryItem(),
+	GetEC2TransitGatewayVpcAttachmentRegistryItem(),
+	GetECRRepositoryRegistryItem(),
+	GetECSServiceRegistryItem(),
+	GetEFSFileSystemRegistryItem(),
+	GetEIPRegistryItem(),
+	GetElastiCacheClusterRegistryItem(),
+	GetElastiCacheReplicationGroupItem(),
+	GetElasticsearchDomainRegistryItem(),
+	GetELBRegistryItem(),
 	// GetFSXWindowsFSRegistryItem(),
-	// GetInstanceRegistryItem(),
-	// GetLambdaFunctionRegistryItem(),
-	// GetLBRegistryItem(),
+	GetEC2InstanceRegistryItem(),
+	GetLambdaFunctionRegistryItem(),
+	GetLBRegistryItem(),
 	// GetLightsailInstanceRegistryItem(),
-	// GetMSKClusterRegistryItem(),
+	GetMSKClusterRegistryItem(),
 	// GetALBRegistryItem(),
-	// GetMQBrokerRegistryItem(),
-	// GetNATGatewayRegistryItem(),
-	// GetRDSClusterRegistryItem(),
-	// GetRDSClusterInstanceRegistryItem(),
-	// GetRedshiftClusterRegistryItem(),
+	GetMQBrokerRegistryItem(),
+	GetNATGatewayRegistryItem(),
+	GetOpenSearchDomainRegistryItem(),
+	GetRDSDBClusterRegistryItem(),
+	GetRedshiftClusterRegistryItem(),
 	// GetRoute53HealthCheck(),
 	// GetRoute53ResolverEndpointRegistryItem(),
-	// GetRoute53RecordRegistryItem(),
-	// GetRoute53ZoneRegistryItem(),
-	// GetS3BucketRegistryItem(),
+	GetRoute53RecordRegistryItem(),
+	GetRoute53HostedZoneRegistryItem(),
+	GetS3BucketRegistryItem(),
 	// GetS3BucketAnalyticsConfigurationRegistryItem(),
 	// GetS3BucketInventoryRegistryItem(),
-	// GetSecretsManagerSecret(),
+	GetSecretsManagerSecretRegistryItem(),
 	// GetSSMActivationRegistryItem(),
-	// GetSSMParameterRegistryItem(),
-	// GetSNSTopicRegistryItem(),
+	GetSSMParameterRegistryItem(),
+	GetSNSTopicRegistryItem(),
 	// GetSNSTopicSubscriptionRegistryItem(),
-	// GetSQSQueueRegistryItem(),
-	// GetNewEKSNodeGroupItem(),
-	// GetNewEKSFargateProfileItem(),
-	// GetNewEKSClusterItem(),
-	// GetNewKMSKeyRegistryItem(),
+	GetSQSQueueRegistryItem(),
+	GetSFnStateMachineRegistryItem(),
+	GetNewEKSNodeGroupItem(),
+	GetNewEKSFargateProfileItem(),
+	GetEKSClusterRegistryItem(),
+	GetKMSKeyRegistryItem(),
 	// GetNewKMSExternalKeyRegistryItem(),
-	// GetVPNConnectionRegistryItem(),
-	// GetVpcEndpointRegistryItem(),
+	GetVPNConnectionRegistryItem(),
+	GetVPCEndpointRegistryItem(),
 }
 
 // FreeResources grouped alphabetically
 var FreeResources = []string{
+	// AWS API Gateway
+	"AWS::ApiGateway::Deployment",
+	"AWS::ApiGateway::Method",
+	"AWS::ApiGateway::Resource",
+	"AWS::ApiGatewayV2::Integration",
+	"AWS::ApiGatewayV2::Route",
+	"AWS::ApiGatewayV2::Stage",
+
+	// AWS Auto Scaling
+	"AWS::AutoScaling::LaunchConfiguration",
+	"AWS::AutoScaling::LifecycleHook",
+	"AWS::AutoScaling::ScalingPolicy",
+	"AWS::AutoScaling::ScheduledAction",
+
+	// AWS CDK
+	"AWS::CDK::Metadata",
+
+	// AWS Cloudwatch
+	"AWS::Logs::MetricFilter",
+	"AWS::Logs::SubscriptionFilter",
+
+	// AWS EC2 & VPC
+	"AWS::EC2::EIPAssociation",
+	"AWS::EC2::InternetGateway",
+	"AWS::EC2::LaunchTemplate",
+	"AWS::EC2::Route",
+	"AWS::EC2::RouteTable",
+	"AWS::EC2::SecurityGroup",
+	"AWS::EC2::SecurityGroupEgress",
+	"AWS::EC2::SecurityGroupIngress",
+	"AWS::EC2::Subnet",
+	"AWS::EC2::SubnetRouteTableAssociation",
+	"AWS::EC2::VolumeAttachment",
+	"AWS::EC2::VPC",
+	"AWS::EC2::VPCGatewayAttachment",
+
+	// AWS EC2 Client VPN & Transit Gateway
+	"AWS::EC2::ClientVpnAuthorizationRule",
+	"AWS::EC2::ClientVpnRoute",
+	"AWS::EC2::CustomerGateway",
+	"AWS::EC2::TransitGateway",
+	"AWS::EC2::TransitGatewayRoute",
+	"AWS::EC2::TransitGatewayRouteTable",
+	"AWS::EC2::TransitGatewayRouteTableAssociation",
+	"AWS::EC2::TransitGatewayRouteTablePropagation",
+	"AWS::EC2::VPNConnectionRoute",
+	"AWS::EC2::VPNGateway",
+	"AWS::EC2::VPNGatewayRoutePropagation",
+
+	// AWS ECS
+	"AWS::ECS::Cluster",
+	"AWS::ECS::TaskDefinition",
+
+	// AWS Elastic Load Balancing
+	"AWS::ElasticLoadBalancingV2::Listener",
+	"AWS::ElasticLoadBalancingV2::ListenerRule",
+	"AWS::ElasticLoadBalancingV2::TargetGroup",
+
+	// AWS IAM
+	"AWS::IAM::InstanceProfile",
+	"AWS::IAM::ManagedPolicy",
+	"AWS::IAM::Policy",
+	"AWS::IAM::Role",
+	"AWS::IAM::User",
+
+	// AWS Others
+	"AWS::AmazonMQ::Configuration",
+	"AWS::DocDB::DBClusterParameterGroup",
+	"AWS::DocDB::DBSubnetGroup",
+	"AWS::ElastiCache::SubnetGroup",
+	"AWS::KMS::Alias",
+	"AWS::Lambda::Alias",
+	"AWS::Lambda::EventSourceMapping",
+	"AWS::Lambda::LayerVersion",
+	"AWS::Lambda::Permission",
+	"AWS::Lambda::Version",
+	"AWS::MSK::Configuration",
+	"AWS::RDS::DBClusterParameterGroup",
+	"AWS::RDS::DBParameterGroup",
+	"AWS::RDS::DBSubnetGroup",
+	"AWS::S3::BucketPolicy",
+	"AWS::SNS::Subscription",
+	"AWS::SNS::TopicPolicy",
+	"AWS::SQS::QueuePolicy",
+	"AWS::SSM::Association",
+	"AWS::SSM::MaintenanceWindow",
+	"AWS::SSM::MaintenanceWindowTarget",
+	"AWS::SSM::MaintenanceWindowTask",
+	"AWS::SSM::PatchBaseline",
+
 	// AWS Certificate Manager
 	"aws_acm_certificate_validation",
 
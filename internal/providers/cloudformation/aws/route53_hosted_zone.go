@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetRoute53HostedZoneRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::Route53::HostedZone",
+		RFunc: NewRoute53HostedZone,
+	}
+}
+
+func NewRoute53HostedZone(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*route53.HostedZone)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.Route53Zone{
+		Address: d.Address,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = make(map[string]string)
+	for _, tag := range cfr.HostedZoneTags {
+		resource.Tags[tag.Key] = tag.Value
+	}
+
+	return resource
+}
@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/route53"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetRoute53RecordRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name: "AWS::Route53::RecordSet",
+		Notes: []string{
+			"Queries of alias records are assumed to be for AWS resources, which are free.",
+		},
+		RFunc: NewRoute53Record,
+	}
+}
+
+func NewRoute53Record(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*route53.RecordSet)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.Route53Record{
+		Address: d.Address,
+		IsAlias: cfr.AliasTarget != nil,
+	}
+	a.PopulateUsage(u)
+
+	return a.BuildResource()
+}
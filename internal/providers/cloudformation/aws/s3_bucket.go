@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/awslabs/goformation/v4/cloudformation/s3"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+var s3StorageClassNames = map[string]string{
+	"STANDARD":            "standard",
+	"INTELLIGENT_TIERING": "intelligent_tiering",
+	"STANDARD_IA":         "standard_infrequent_access",
+	"ONEZONE_IA":          "one_zone_infrequent_access",
+	"GLACIER":             "glacier_flexible_retrieval",
+	"DEEP_ARCHIVE":        "glacier_deep_archive",
+}
+
+func GetS3BucketRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::S3::Bucket",
+		RFunc: NewS3Bucket,
+	}
+}
+
+func NewS3Bucket(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*s3.Bucket)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	objTagsEnabled := false
+
+	// Always add the standard storage class
+	lifecycleStorageClassMap := map[string]bool{
+		"standard": true,
+	}
+
+	addStorageClass := func(name string) {
+		if storageClass := s3StorageClassNames[name]; storageClass != "" {
+			lifecycleStorageClassMap[storageClass] = true
+		}
+	}
+
+	if cfr.LifecycleConfiguration != nil {
+		for _, rule := range cfr.LifecycleConfiguration.Rules {
+			if rule.Status != "Enabled" {
+				continue
+			}
+
+			if len(rule.TagFilters) > 0 {
+				objTagsEnabled = true
+			}
+
+			if rule.Transition != nil {
+				addStorageClass(rule.Transition.StorageClass)
+			}
+
+			for _, t := range rule.Transitions {
+				addStorageClass(t.StorageClass)
+			}
+
+			if rule.NoncurrentVersionTransition != nil {
+				addStorageClass(rule.NoncurrentVersionTransition.StorageClass)
+			}
+
+			for _, t := range rule.NoncurrentVersionTransitions {
+				addStorageClass(t.StorageClass)
+			}
+		}
+	}
+
+	lifecycleStorageClasses := make([]string, 0, len(lifecycleStorageClassMap))
+	for storageClass := range lifecycleStorageClassMap {
+		lifecycleStorageClasses = append(lifecycleStorageClasses, storageClass)
+	}
+	sort.Strings(lifecycleStorageClasses)
+
+	a := &aws.S3Bucket{
+		Address:                 d.Address,
+		Region:                  d.Get("region").String(),
+		Name:                    cfr.BucketName,
+		ObjectTagsEnabled:       objTagsEnabled,
+		LifecycleStorageClasses: lifecycleStorageClasses,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
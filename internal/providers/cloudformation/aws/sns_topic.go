@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sns"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetSNSTopicRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SNS::Topic",
+		RFunc: NewSNSTopic,
+	}
+}
+
+func NewSNSTopic(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sns.Topic)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	var resource *schema.Resource
+	if cfr.FifoTopic {
+		// Only the subscriptions defined on the topic are counted, not AWS::SNS::Subscription resources
+		a := &aws.SNSFIFOTopic{
+			Address:       d.Address,
+			Region:        d.Get("region").String(),
+			Subscriptions: int64(len(cfr.Subscription)),
+		}
+		a.PopulateUsage(u)
+		resource = a.BuildResource()
+	} else {
+		a := &aws.SNSTopic{
+			Address: d.Address,
+			Region:  d.Get("region").String(),
+		}
+		a.PopulateUsage(u)
+		resource = a.BuildResource()
+	}
+
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
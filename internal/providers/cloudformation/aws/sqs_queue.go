@@ -0,0 +1,35 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/sqs"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetSQSQueueRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SQS::Queue",
+		RFunc: NewSQSQueue,
+	}
+}
+
+func NewSQSQueue(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*sqs.Queue)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.SQSQueue{
+		Address:   d.Address,
+		Region:    d.Get("region").String(),
+		FifoQueue: cfr.FifoQueue,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = mapTags(cfr.Tags)
+
+	return resource
+}
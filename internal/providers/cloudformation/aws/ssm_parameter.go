@@ -0,0 +1,37 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/ssm"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetSSMParameterRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::SSM::Parameter",
+		RFunc: NewSSMParameter,
+	}
+}
+
+func NewSSMParameter(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*ssm.Parameter)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.SSMParameter{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+		Tier:    cfr.Tier,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	if resource != nil {
+		resource.Tags = mapUntypedTags(cfr.Tags)
+	}
+
+	return resource
+}
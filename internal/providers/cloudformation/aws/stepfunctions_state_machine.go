@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"github.com/awslabs/goformation/v4/cloudformation/stepfunctions"
+	"github.com/infracost/infracost/internal/resources/aws"
+	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
+)
+
+func GetSFnStateMachineRegistryItem() *schema.RegistryItem {
+	return &schema.RegistryItem{
+		Name:  "AWS::StepFunctions::StateMachine",
+		RFunc: NewSFnStateMachine,
+	}
+}
+
+func NewSFnStateMachine(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
+	cfr, ok := d.CFResource.(*stepfunctions.StateMachine)
+	if !ok {
+		log.Warnf("Skipping resource %s as it did not have the expected type (got %T)", d.Address, d.CFResource)
+		return nil
+	}
+
+	a := &aws.SFnStateMachine{
+		Address: d.Address,
+		Region:  d.Get("region").String(),
+		Type:    cfr.StateMachineType,
+	}
+	a.PopulateUsage(u)
+
+	resource := a.BuildResource()
+	resource.Tags = make(map[string]string)
+	for _, tag := range cfr.Tags {
+		resource.Tags[tag.Key] = tag.Value
+	}
+
+	return resource
+}
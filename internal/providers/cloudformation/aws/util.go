@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"fmt"
+
 	"github.com/awslabs/goformation/v4/cloudformation/tags"
 )
 
@@ -11,3 +13,32 @@ func mapTags(cfTags []tags.Tag) map[string]string {
 	}
 	return mapped
 }
+
+func intPtr(i int64) *int64 {
+	return &i
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+// mapUntypedTags maps the tags of the resources that goformation doesn't type, which are either an
+// object of tags or a list of Key and Value pairs.
+func mapUntypedTags(cfTags interface{}) map[string]string {
+	mapped := make(map[string]string)
+
+	switch t := cfTags.(type) {
+	case map[string]interface{}:
+		for k, v := range t {
+			mapped[k] = fmt.Sprint(v)
+		}
+	case []interface{}:
+		for _, tag := range t {
+			if m, ok := tag.(map[string]interface{}); ok {
+				mapped[fmt.Sprint(m["Key"])] = fmt.Sprint(m["Value"])
+			}
+		}
+	}
+
+	return mapped
+}
@@ -0,0 +1,148 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	cdkManifestFile      = "manifest.json"
+	cdkStackArtifactType = "aws:cloudformation:stack"
+	// Nested cloud assemblies are created for the stages of a CDK app
+	cdkAssemblyArtifactType = "cdk:cloud-assembly"
+)
+
+// cdkManifest is the manifest.json of a CDK cloud assembly, e.g. the cdk.out directory created by
+// `cdk synth`.
+// See: https://github.com/aws/aws-cdk/tree/main/packages/aws-cdk-lib/cloud-assembly-schema
+type cdkManifest struct {
+	Version   string                  `json:"version"`
+	Artifacts map[string]*cdkArtifact `json:"artifacts"`
+}
+
+type cdkArtifact struct {
+	Type        string `json:"type"`
+	Environment string `json:"environment"`
+	DisplayName string `json:"displayName"`
+	Properties  struct {
+		TemplateFile  string            `json:"templateFile"`
+		Parameters    map[string]string `json:"parameters"`
+		StackName     string            `json:"stackName"`
+		DirectoryName string            `json:"directoryName"`
+	} `json:"properties"`
+}
+
+// CDKStack is a stack of a CDK cloud assembly.
+type CDKStack struct {
+	// Name is the display name of the stack, which includes the path of its stage
+	Name         string
+	TemplateFile string
+	// Region is empty if the stack is environment-agnostic
+	Region     string
+	Parameters map[string]string
+}
+
+// IsCDKAssembly returns true if path is a CDK cloud assembly directory or its manifest.json.
+func IsCDKAssembly(path string) bool {
+	stacks, err := FindCDKStacks(path)
+	return err == nil && len(stacks) > 0
+}
+
+// FindCDKStacks returns the stacks of a CDK cloud assembly, including the stacks of its nested
+// assemblies. path is the cloud assembly directory or its manifest.json.
+func FindCDKStacks(path string) ([]CDKStack, error) {
+	manifestPath := path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		manifestPath = filepath.Join(path, cdkManifestFile)
+	} else if filepath.Base(path) != cdkManifestFile {
+		return nil, errors.Errorf("%s is not a CDK cloud assembly manifest", path)
+	}
+
+	return readCDKManifest(manifestPath, "")
+}
+
+func readCDKManifest(manifestPath string, stage string) ([]CDKStack, error) {
+	b, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading CDK cloud assembly manifest")
+	}
+
+	var manifest cdkManifest
+	err = json.Unmarshal(b, &manifest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing CDK cloud assembly manifest %s", manifestPath)
+	}
+
+	if manifest.Version == "" || manifest.Artifacts == nil {
+		return nil, errors.Errorf("%s is not a CDK cloud assembly manifest", manifestPath)
+	}
+
+	dir := filepath.Dir(manifestPath)
+
+	ids := make([]string, 0, len(manifest.Artifacts))
+	for id := range manifest.Artifacts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var stacks []CDKStack
+	for _, id := range ids {
+		artifact := manifest.Artifacts[id]
+
+		switch artifact.Type {
+		case cdkStackArtifactType:
+			if artifact.Properties.TemplateFile == "" {
+				continue
+			}
+
+			name := artifact.DisplayName
+			if name == "" {
+				name = id
+			}
+			if stage != "" && !strings.HasPrefix(name, stage+"/") {
+				name = stage + "/" + name
+			}
+
+			stacks = append(stacks, CDKStack{
+				Name:         name,
+				TemplateFile: filepath.Join(dir, artifact.Properties.TemplateFile),
+				Region:       cdkEnvironmentRegion(artifact.Environment),
+				Parameters:   artifact.Properties.Parameters,
+			})
+		case cdkAssemblyArtifactType:
+			if artifact.Properties.DirectoryName == "" {
+				continue
+			}
+
+			nestedStage := artifact.DisplayName
+			if nestedStage == "" {
+				nestedStage = id
+			}
+
+			nested, err := readCDKManifest(filepath.Join(dir, artifact.Properties.DirectoryName, cdkManifestFile), nestedStage)
+			if err != nil {
+				return nil, err
+			}
+
+			stacks = append(stacks, nested...)
+		}
+	}
+
+	return stacks, nil
+}
+
+// cdkEnvironmentRegion returns the region of an environment in the format aws://account/region,
+// or an empty string if the stack can be deployed to any region.
+func cdkEnvironmentRegion(environment string) string {
+	parts := strings.Split(strings.TrimPrefix(environment, "aws://"), "/")
+	if len(parts) != 2 || parts[1] == "unknown-region" {
+		return ""
+	}
+
+	return parts[1]
+}
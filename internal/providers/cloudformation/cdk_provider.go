@@ -0,0 +1,104 @@
+package cloudformation
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// CDKProvider costs every stack of a CDK cloud assembly. Each stack template is loaded as its own
+// project by a TemplateProvider.
+type CDKProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewCDKProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &CDKProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *CDKProvider) Type() string {
+	return "cloudformation_cdk"
+}
+
+func (p *CDKProvider) DisplayType() string {
+	return "AWS CDK cloud assembly"
+}
+
+func (p *CDKProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *CDKProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	stacks, err := FindCDKStacks(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stacks) == 0 {
+		return nil, fmt.Errorf("No CDK stacks found at path %s, run `cdk synth` to create the cloud assembly", p.Path)
+	}
+
+	var projects []*schema.Project
+	for _, stack := range stacks {
+		ctx := config.NewProjectContext(p.ctx.RunContext, p.stackProjectConfig(stack), log.Fields{})
+
+		provider := NewTemplateProvider(ctx, p.includePastResources)
+		loaded, err := provider.LoadResources(usage)
+		if err != nil {
+			log.Warnf("Error loading CDK stack %s: %s", stack.Name, err)
+			metadata := config.DetectProjectMetadata(ctx.ProjectConfig.Path)
+			metadata.Type = p.Type()
+			name := ctx.ProjectConfig.Name
+			if name == "" {
+				name = metadata.GenerateProjectName(ctx.RunContext.VCSMetadata.Remote, ctx.RunContext.IsCloudEnabled())
+			}
+			loaded = schema.AddProjectError(loaded, name, metadata, err)
+		}
+
+		for _, project := range loaded {
+			project.Metadata.Type = p.Type()
+			p.AddMetadata(project.Metadata)
+		}
+
+		projects = append(projects, loaded...)
+	}
+
+	return projects, nil
+}
+
+// stackProjectConfig returns the config of a stack project, which inherits the config of the cloud
+// assembly. The region of the environment of the stack overrides cloudformation_region, and the
+// parameters of the config file override the parameters of the stack.
+func (p *CDKProvider) stackProjectConfig(stack CDKStack) *config.Project {
+	projectCfg := *p.ctx.ProjectConfig
+	projectCfg.Path = stack.TemplateFile
+
+	if stack.Region != "" {
+		projectCfg.CloudFormationRegion = stack.Region
+	}
+
+	params := make(map[string]string, len(stack.Parameters)+len(projectCfg.CloudFormationParameters))
+	for k, v := range stack.Parameters {
+		params[k] = v
+	}
+	for k, v := range projectCfg.CloudFormationParameters {
+		params[k] = v
+	}
+	projectCfg.CloudFormationParameters = params
+
+	projectCfg.Name = stack.Name
+	if p.ctx.ProjectConfig.Name != "" {
+		projectCfg.Name = p.ctx.ProjectConfig.Name + "/" + stack.Name
+	}
+
+	return &projectCfg
+}
@@ -0,0 +1,75 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func TestFindCDKStacks(t *testing.T) {
+	dir := filepath.Join("testdata", "cdk.out")
+
+	stacks, err := FindCDKStacks(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []CDKStack{
+		{
+			Name:         "AppStack",
+			TemplateFile: filepath.Join(dir, "AppStack.template.json"),
+			Parameters:   map[string]string{"QueueName": "orders"},
+		},
+		{
+			Name:         "Prod/DatabaseStack",
+			TemplateFile: filepath.Join(dir, "assembly-Prod", "ProdDatabaseStack1A2B3C4D.template.json"),
+			Region:       "eu-west-2",
+		},
+	}, stacks)
+
+	// The manifest can be used instead of the directory
+	fromManifest, err := FindCDKStacks(filepath.Join(dir, "manifest.json"))
+	require.NoError(t, err)
+	assert.Equal(t, stacks, fromManifest)
+
+	assert.False(t, IsCDKAssembly(filepath.Join("testdata", "template.yml")))
+	assert.False(t, IsCDKAssembly("testdata"))
+}
+
+func TestCDKProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:                 filepath.Join("testdata", "cdk.out"),
+		Name:                 "app",
+		CloudFormationRegion: "eu-west-1",
+	}, log.Fields{})
+
+	provider := NewCDKProvider(ctx, false)
+	projects, err := provider.LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	require.NoError(t, err)
+	require.Len(t, projects, 2)
+
+	app := projects[0]
+	assert.Equal(t, "app/AppStack", app.Name)
+	assert.Equal(t, "cloudformation_cdk", app.Metadata.Type)
+	require.Len(t, app.Resources, 2)
+	for _, r := range app.Resources {
+		if r.Name == "CDKMetadata" {
+			assert.True(t, r.NoPrice)
+			continue
+		}
+
+		assert.False(t, r.IsSkipped)
+		// Environment-agnostic stacks use the region of the project
+		assert.Equal(t, "eu-west-1", *r.CostComponents[0].ProductFilter.Region)
+	}
+
+	prod := projects[1]
+	assert.Equal(t, "app/Prod/DatabaseStack", prod.Name)
+	require.Len(t, prod.Resources, 1)
+	assert.False(t, prod.Resources[0].IsSkipped)
+	assert.Equal(t, "eu-west-2", *prod.Resources[0].CostComponents[0].ProductFilter.Region)
+}
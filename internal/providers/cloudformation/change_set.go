@@ -0,0 +1,84 @@
+package cloudformation
+
+import (
+	"encoding/json"
+)
+
+type ChangeAction string
+
+// See: https://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_ResourceChange.html
+const (
+	// The resource is added to the stack
+	ChangeAdd ChangeAction = "Add"
+	// The properties of the resource change, which can replace it
+	ChangeModify ChangeAction = "Modify"
+	// The resource is removed from the stack
+	ChangeRemove ChangeAction = "Remove"
+	// An existing resource is imported into the stack
+	ChangeImport ChangeAction = "Import"
+	// The change can't be determined until the change set is executed, e.g. nested stacks
+	ChangeDynamic ChangeAction = "Dynamic"
+)
+
+// ChangeSet is the output of `aws cloudformation describe-change-set`.
+// See: https://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_DescribeChangeSet.html
+type ChangeSet struct {
+	ChangeSetId   string         `json:"ChangeSetId"`
+	ChangeSetName string         `json:"ChangeSetName"`
+	StackName     string         `json:"StackName"`
+	Status        string         `json:"Status"`
+	Parameters    []cliParameter `json:"Parameters"`
+	Changes       []*Change      `json:"Changes"`
+}
+
+type Change struct {
+	Type           string          `json:"Type"`
+	ResourceChange *ResourceChange `json:"ResourceChange"`
+}
+
+type ResourceChange struct {
+	Action             ChangeAction `json:"Action"`
+	LogicalResourceId  string       `json:"LogicalResourceId"`
+	PhysicalResourceId string       `json:"PhysicalResourceId"`
+	ResourceType       string       `json:"ResourceType"`
+}
+
+// IsChangeSetJSON returns true if b is the output of `aws cloudformation describe-change-set`.
+func IsChangeSetJSON(b []byte) bool {
+	var cs ChangeSet
+	err := json.Unmarshal(b, &cs)
+	if err != nil {
+		return false
+	}
+
+	return cs.Changes != nil && (cs.ChangeSetId != "" || cs.ChangeSetName != "")
+}
+
+// parameterValues returns the values of the parameters of the change set. Values of NoEcho
+// parameters are masked, so they're left to the defaults of the template.
+func (c *ChangeSet) parameterValues() map[string]string {
+	values := make(map[string]string)
+
+	for _, p := range c.Parameters {
+		if p.UsePreviousValue || p.ParameterValue == "****" {
+			continue
+		}
+
+		values[p.ParameterKey] = p.ParameterValue
+	}
+
+	return values
+}
+
+// resourceChanges returns the changes of the resources of the stack by logical ID.
+func (c *ChangeSet) resourceChanges() map[string]*ResourceChange {
+	changes := make(map[string]*ResourceChange)
+
+	for _, change := range c.Changes {
+		if change.Type == "Resource" && change.ResourceChange != nil {
+			changes[change.ResourceChange.LogicalResourceId] = change.ResourceChange
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,167 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ChangeSetProvider prices the changes of a CloudFormation change set. The change set doesn't
+// contain the properties of the resources, so the cost diff comes from the proposed template and
+// the current template of the stack if it's set. The change set is only used for its parameters
+// and to find the resources that are added or removed when there's no current template.
+type ChangeSetProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewChangeSetProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &ChangeSetProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *ChangeSetProvider) Type() string {
+	return "cloudformation_change_set_json"
+}
+
+func (p *ChangeSetProvider) DisplayType() string {
+	return "CloudFormation change set JSON"
+}
+
+func (p *ChangeSetProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *ChangeSetProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation change set JSON file")
+	}
+
+	var changeSet ChangeSet
+	err = json.Unmarshal(b, &changeSet)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error parsing CloudFormation change set JSON file")
+	}
+
+	cfg := p.ctx.ProjectConfig
+	if cfg.CloudFormationTemplateFile == "" {
+		return []*schema.Project{}, errors.New("cloudformation_template_file must be set to the proposed template of the CloudFormation change set")
+	}
+
+	// Values of the parameters in the config file override the values of the change set
+	params := changeSet.parameterValues()
+	configParams, err := loadParameters(cfg)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+	for k, v := range configParams {
+		params[k] = v
+	}
+
+	parser := NewParser(p.ctx)
+	proposed, err := loadTemplate(cfg.CloudFormationTemplateFile, parser.region(), params)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
+	}
+
+	var current *cloudformation.Template
+	if cfg.CloudFormationCurrentTemplateFile != "" {
+		current, err = loadTemplate(cfg.CloudFormationCurrentTemplateFile, parser.region(), params)
+		if err != nil {
+			return []*schema.Project{}, errors.Wrap(err, "Error reading current CloudFormation template file")
+		}
+	}
+
+	metadata := config.DetectProjectMetadata(cfg.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := cfg.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	_, project.Resources, err = parser.parseTemplate(proposed, cfg.CloudFormationTemplateFile, usage)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
+	}
+
+	if p.includePastResources {
+		project.PastResources, err = p.pastResources(parser, &changeSet, proposed, current, usage)
+		if err != nil {
+			return []*schema.Project{project}, errors.Wrap(err, "Error parsing current CloudFormation template file")
+		}
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+// pastResources returns the resources of the stack before the change set is executed. Without the
+// current template, resources that are modified are assumed to have the properties of the proposed
+// template, so only added and removed resources change the cost. Resources that are replaced are
+// priced like modified resources since they keep their logical ID.
+func (p *ChangeSetProvider) pastResources(parser *Parser, changeSet *ChangeSet, proposed *cloudformation.Template, current *cloudformation.Template, usage map[string]*schema.UsageData) ([]*schema.Resource, error) {
+	if current != nil {
+		_, resources, err := parser.parseTemplate(current, p.ctx.ProjectConfig.CloudFormationCurrentTemplateFile, usage)
+		return resources, err
+	}
+
+	changes := changeSet.resourceChanges()
+
+	var modified []string
+	for name, change := range changes {
+		if change.Action == ChangeModify {
+			modified = append(modified, name)
+		}
+	}
+
+	if len(modified) > 0 {
+		sort.Strings(modified)
+		log.Warnf("CloudFormation resources %s are modified by the change set, set cloudformation_current_template_file to include the cost of their changes", strings.Join(modified, ", "))
+	}
+
+	past := cloudformation.NewTemplate()
+	for name, r := range proposed.Resources {
+		if change, ok := changes[name]; ok && change.Action == ChangeAdd {
+			continue
+		}
+
+		past.Resources[name] = r
+	}
+
+	_, resources, err := parser.parseTemplate(past, p.ctx.ProjectConfig.CloudFormationTemplateFile, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, change := range changes {
+		if change.Action != ChangeRemove {
+			continue
+		}
+
+		log.Warnf("CloudFormation resource %s is removed by the change set, set cloudformation_current_template_file to include its cost", name)
+		resources = append(resources, &schema.Resource{
+			Name:         name,
+			ResourceType: change.ResourceType,
+			Tags:         map[string]string{},
+			IsSkipped:    true,
+			SkipMessage:  "This resource is removed by the change set and isn't in the proposed template",
+		})
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,88 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func loadChangeSetProject(t *testing.T, currentTemplate string) *schema.Project {
+	t.Helper()
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:                              filepath.Join("testdata", "change_set.json"),
+		CloudFormationTemplateFile:        filepath.Join("testdata", "change_set_proposed.yml"),
+		CloudFormationCurrentTemplateFile: currentTemplate,
+	}, log.Fields{})
+
+	projects, err := NewChangeSetProvider(ctx, true).LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	return projects[0]
+}
+
+func resourcesByName(resources []*schema.Resource) map[string]*schema.Resource {
+	m := make(map[string]*schema.Resource)
+	for _, r := range resources {
+		m[r.Name] = r
+	}
+
+	return m
+}
+
+func TestChangeSetProvider(t *testing.T) {
+	project := loadChangeSetProject(t, "")
+
+	resources := resourcesByName(project.Resources)
+	assert.Len(t, resources, 3)
+	// The parameters of the change set are used for the proposed template
+	assert.Contains(t, resources["WebServer"].CostComponents[0].Name, "m5.xlarge")
+	assert.Contains(t, resources, "Cache")
+
+	// Without the current template, added resources are excluded from the past resources and
+	// removed resources can't be priced
+	past := resourcesByName(project.PastResources)
+	assert.Len(t, past, 3)
+	assert.NotContains(t, past, "Cache")
+	assert.Contains(t, past["WebServer"].CostComponents[0].Name, "m5.xlarge")
+	assert.True(t, past["NatGateway"].IsSkipped)
+	assert.Equal(t, "AWS::EC2::NatGateway", past["NatGateway"].ResourceType)
+}
+
+func TestChangeSetProviderCurrentTemplate(t *testing.T) {
+	project := loadChangeSetProject(t, filepath.Join("testdata", "change_set_current.json"))
+
+	past := resourcesByName(project.PastResources)
+	assert.Len(t, past, 3)
+	assert.NotContains(t, past, "Cache")
+	assert.Contains(t, past["WebServer"].CostComponents[0].Name, "t3.medium")
+	assert.False(t, past["NatGateway"].IsSkipped)
+
+	resources := resourcesByName(project.Resources)
+	assert.Contains(t, resources["WebServer"].CostComponents[0].Name, "m5.xlarge")
+	assert.NotContains(t, resources, "NatGateway")
+}
+
+func TestChangeSetProviderRequiresTemplate(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: filepath.Join("testdata", "change_set.json"),
+	}, log.Fields{})
+
+	_, err := NewChangeSetProvider(ctx, true).LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	assert.EqualError(t, err, "cloudformation_template_file must be set to the proposed template of the CloudFormation change set")
+}
+
+func TestIsChangeSetJSON(t *testing.T) {
+	assert.True(t, IsChangeSetJSON([]byte(`{"ChangeSetName": "cs", "Changes": []}`)))
+	// WhatIf results have the same field names in lowercase
+	assert.False(t, IsChangeSetJSON([]byte(`{"status": "Succeeded", "changes": []}`)))
+	assert.False(t, IsChangeSetJSON([]byte(`AWSTemplateFormatVersion: "2010-09-09"`)))
+}
@@ -0,0 +1,240 @@
+package cloudformation
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/goformation/v4/cloudformation"
+	cfnstack "github.com/awslabs/goformation/v4/cloudformation/cloudformation"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// maxNestedStackDepth is the maximum depth of nested stacks that are parsed, CloudFormation allows
+// at most 5 levels but StackSets and SAM applications add more.
+const maxNestedStackDepth = 10
+
+// stackContext is the stack that the resources of a template are created in. Nested stacks and the
+// stack instances of StackSets have their own region, address and template path.
+type stackContext struct {
+	// templatePath is the path of the template, which relative TemplateURLs are resolved from
+	templatePath string
+	region       string
+	// address is the address of the resource of the nested stack, it's empty for the root stack
+	address string
+	// templatePaths are the templates of the parent stacks, to stop templates that nest themselves
+	templatePaths []string
+}
+
+func (s *stackContext) resourceAddress(name string) string {
+	if s.address == "" {
+		return name
+	}
+
+	return s.address + "." + name
+}
+
+func (s *stackContext) child(address string, templatePath string, region string) *stackContext {
+	templatePaths := append([]string{}, s.templatePaths...)
+	if s.templatePath != "" {
+		templatePaths = append(templatePaths, s.templatePath)
+	}
+
+	return &stackContext{
+		templatePath:  templatePath,
+		region:        region,
+		address:       address,
+		templatePaths: templatePaths,
+	}
+}
+
+// childTemplatePath returns the path of a local TemplateURL, relative to the template of the stack.
+// An empty string is returned for templates in S3 or on the web, which aren't read.
+func (s *stackContext) childTemplatePath(templateURL string) string {
+	if templateURL == "" || strings.Contains(templateURL, "://") {
+		return ""
+	}
+
+	path := templateURL
+	if !filepath.IsAbs(path) && s.templatePath != "" {
+		path = filepath.Join(filepath.Dir(s.templatePath), path)
+	}
+
+	return path
+}
+
+// checkNesting returns an error if the template can't be nested in the stack.
+func (s *stackContext) checkNesting(templatePath string) error {
+	if len(s.templatePaths) >= maxNestedStackDepth {
+		return fmt.Errorf("stacks are nested more than %d levels deep", maxNestedStackDepth)
+	}
+
+	for _, p := range append(s.templatePaths, s.templatePath) {
+		if p != "" && filepath.Clean(p) == filepath.Clean(templatePath) {
+			return fmt.Errorf("template %s nests itself", templatePath)
+		}
+	}
+
+	return nil
+}
+
+// parseNestedStack returns the resources of the template of an AWS::CloudFormation::Stack, with the
+// parameters of the stack. Nested stacks whose template isn't a local file are skipped.
+func (p *Parser) parseNestedStack(r *cfnstack.Stack, address string, stack *stackContext, usage map[string]*schema.UsageData) []*schema.Resource {
+	templatePath := stack.childTemplatePath(r.TemplateURL)
+	if templatePath == "" {
+		return []*schema.Resource{skippedStack(address, r.AWSCloudFormationType(), "The template of the nested stack isn't a local file. Package the template with relative TemplateURLs to include its resources.")}
+	}
+
+	// Parameters that refer to the outputs of other stacks can't be evaluated, so the defaults of the
+	// template are used for them
+	params := make(map[string]string)
+	for k, v := range r.Parameters {
+		if v != "" {
+			params[k] = v
+		}
+	}
+
+	child := stack.child(address, templatePath, stack.region)
+	t, err := p.loadChildTemplate(child, stack, params, nil)
+	if err != nil {
+		log.Warnf("Skipping nested CloudFormation stack %s: %s", address, err)
+		return []*schema.Resource{skippedStack(address, r.AWSCloudFormationType(), fmt.Sprintf("The template of the nested stack could not be read: %s", err))}
+	}
+
+	return p.parseResources(t, child, usage)
+}
+
+// parseStackSet returns the resources of a stack instance of an AWS::CloudFormation::StackSet in each
+// target account and region. The targets are set with cloudformation_stack_sets in the config file,
+// or are read from the StackInstancesGroup of the StackSet.
+func (p *Parser) parseStackSet(r *cfnstack.StackSet, name string, address string, stack *stackContext, usage map[string]*schema.UsageData) []*schema.Resource {
+	instances := p.stackSetInstances(r, name)
+	if len(instances) == 0 {
+		return []*schema.Resource{skippedStack(address, r.AWSCloudFormationType(), "The StackSet has no target accounts and regions, set them with cloudformation_stack_sets in the config file.")}
+	}
+
+	templatePath := stack.childTemplatePath(r.TemplateURL)
+	if r.TemplateBody == "" && templatePath == "" {
+		return []*schema.Resource{skippedStack(address, r.AWSCloudFormationType(), "The template of the StackSet isn't inline or a local file.")}
+	}
+
+	if r.TemplateBody != "" {
+		// Inline templates can't be nested in themselves, and their TemplateURLs are relative to
+		// the template of the parent
+		templatePath = stack.templatePath
+	}
+
+	params := make(map[string]string)
+	for _, param := range r.Parameters {
+		params[param.ParameterKey] = param.ParameterValue
+	}
+
+	var resources []*schema.Resource
+
+	// Refs to AWS::Region are evaluated when the template is loaded, so it's loaded for each region
+	templates := make(map[string]*cloudformation.Template)
+
+	for _, instance := range instances {
+		child := stack.child(fmt.Sprintf("%s[%q]", address, instance.account+"/"+instance.region), templatePath, instance.region)
+
+		t, ok := templates[instance.region]
+		if !ok {
+			var err error
+			t, err = p.loadChildTemplate(child, stack, params, []byte(r.TemplateBody))
+			if err != nil {
+				log.Warnf("Skipping CloudFormation StackSet %s: %s", address, err)
+				return []*schema.Resource{skippedStack(address, r.AWSCloudFormationType(), fmt.Sprintf("The template of the StackSet could not be read: %s", err))}
+			}
+
+			templates[instance.region] = t
+		}
+
+		resources = append(resources, p.parseResources(t, child, usage)...)
+	}
+
+	return resources
+}
+
+// loadChildTemplate loads the template of a nested stack or StackSet, which is inline if body is set.
+func (p *Parser) loadChildTemplate(child *stackContext, parent *stackContext, params map[string]string, body []byte) (*cloudformation.Template, error) {
+	if len(body) > 0 {
+		if len(parent.templatePaths) >= maxNestedStackDepth {
+			return nil, fmt.Errorf("stacks are nested more than %d levels deep", maxNestedStackDepth)
+		}
+
+		// YAML is a superset of JSON, so the body can be read as YAML whatever its format
+		return loadTemplateBody(body, false, child.region, params)
+	}
+
+	if err := parent.checkNesting(child.templatePath); err != nil {
+		return nil, err
+	}
+
+	return loadTemplate(child.templatePath, child.region, params)
+}
+
+type stackInstance struct {
+	account string
+	region  string
+}
+
+// stackSetInstances returns the account and region of each stack instance of a StackSet.
+func (p *Parser) stackSetInstances(r *cfnstack.StackSet, name string) []stackInstance {
+	var instances []stackInstance
+	seen := make(map[stackInstance]bool)
+
+	add := func(accounts []string, regions []string) {
+		for _, account := range accounts {
+			for _, region := range regions {
+				instance := stackInstance{account: account, region: region}
+				if !seen[instance] {
+					seen[instance] = true
+					instances = append(instances, instance)
+				}
+			}
+		}
+	}
+
+	if p.ctx.ProjectConfig != nil {
+		if targets, ok := p.ctx.ProjectConfig.CloudFormationStackSets[name]; ok && targets != nil {
+			add(targets.Accounts, targets.Regions)
+			return instances
+		}
+	}
+
+	for _, group := range r.StackInstancesGroup {
+		if group.DeploymentTargets == nil {
+			continue
+		}
+
+		if len(group.DeploymentTargets.Accounts) == 0 && len(group.DeploymentTargets.OrganizationalUnitIds) > 0 {
+			log.Warnf("CloudFormation StackSet %s is deployed to organizational units, set its accounts with cloudformation_stack_sets in the config file", name)
+			continue
+		}
+
+		add(group.DeploymentTargets.Accounts, group.Regions)
+	}
+
+	sort.SliceStable(instances, func(i, j int) bool {
+		if instances[i].account != instances[j].account {
+			return instances[i].account < instances[j].account
+		}
+		return instances[i].region < instances[j].region
+	})
+
+	return instances
+}
+
+func skippedStack(address string, resourceType string, message string) *schema.Resource {
+	return &schema.Resource{
+		Name:         address,
+		ResourceType: resourceType,
+		Tags:         map[string]string{},
+		IsSkipped:    true,
+		SkipMessage:  message,
+	}
+}
@@ -0,0 +1,122 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func loadNestedTestProject(t *testing.T, projectCfg *config.Project) map[string]*schema.Resource {
+	t.Helper()
+
+	projectCfg.Path = filepath.Join("testdata", "nested", "parent.yml")
+	ctx := config.NewProjectContext(config.EmptyRunContext(), projectCfg, log.Fields{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	resources := make(map[string]*schema.Resource)
+	for _, r := range projects[0].Resources {
+		resources[r.Name] = r
+	}
+
+	return resources
+}
+
+func TestNestedStacks(t *testing.T) {
+	resources := loadNestedTestProject(t, &config.Project{CloudFormationRegion: "us-east-1"})
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	assert.Equal(t, []string{
+		`Baseline["111111111111/eu-west-1"].AuditLogs`,
+		`Baseline["111111111111/us-east-1"].AuditLogs`,
+		`Baseline["222222222222/eu-west-1"].AuditLogs`,
+		`Baseline["222222222222/us-east-1"].AuditLogs`,
+		"Monitoring",
+		"Network.Endpoints.S3Endpoint",
+		"Network.NatGatewayA",
+		"Network.NatGatewayB",
+	}, names)
+
+	// The parameters of the nested stack are passed to its template
+	assert.Equal(t, map[string]string{"Environment": "dev"}, resources["Network.NatGatewayA"].Tags)
+	assert.False(t, resources["Network.NatGatewayB"].IsSkipped)
+	assert.False(t, resources["Network.Endpoints.S3Endpoint"].IsSkipped)
+
+	// Templates in S3 aren't read
+	assert.True(t, resources["Monitoring"].IsSkipped)
+
+	// Each stack instance of the StackSet is priced in its region
+	logs := resources[`Baseline["222222222222/eu-west-1"].AuditLogs`]
+	assert.False(t, logs.IsSkipped)
+	assert.Equal(t, "eu-west-1", *logs.CostComponents[0].ProductFilter.Region)
+}
+
+func TestStackSetConfigTargets(t *testing.T) {
+	resources := loadNestedTestProject(t, &config.Project{
+		CloudFormationStackSets: map[string]*config.CloudFormationStackSet{
+			"Baseline": {
+				Accounts: []string{"prod", "staging", "dev"},
+				Regions:  []string{"ap-southeast-2"},
+			},
+		},
+	})
+
+	var instances []string
+	for name := range resources {
+		if r := resources[name]; r.ResourceType == "AWS::Logs::LogGroup" {
+			instances = append(instances, name)
+			assert.Equal(t, "ap-southeast-2", *r.CostComponents[0].ProductFilter.Region)
+		}
+	}
+
+	assert.Len(t, instances, 3)
+}
+
+func TestStackSetInlineNestedStacks(t *testing.T) {
+	projectCfg := &config.Project{Path: filepath.Join("testdata", "nested", "stack_set.yml")}
+	ctx := config.NewProjectContext(config.EmptyRunContext(), projectCfg, log.Fields{})
+
+	projects, err := NewTemplateProvider(ctx, false).LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	// The TemplateURLs of the inline template are relative to the parent for every stack instance,
+	// including those whose template was loaded for an earlier instance in the same region
+	var names []string
+	for _, r := range projects[0].Resources {
+		assert.False(t, r.IsSkipped, r.Name)
+		names = append(names, r.Name)
+	}
+	sort.Strings(names)
+
+	assert.Equal(t, []string{
+		`Endpoints["111111111111/us-east-1"].Endpoints.S3Endpoint`,
+		`Endpoints["222222222222/us-east-1"].Endpoints.S3Endpoint`,
+	}, names)
+}
+
+func TestStackContextNesting(t *testing.T) {
+	stack := &stackContext{templatePath: filepath.Join("testdata", "nested", "parent.yml")}
+
+	assert.Equal(t, filepath.Join("testdata", "nested", "network.yml"), stack.childTemplatePath("./network.yml"))
+	assert.Equal(t, "", stack.childTemplatePath("s3://bucket/network.yml"))
+
+	child := stack.child("Network", filepath.Join("testdata", "nested", "network.yml"), "us-east-1")
+	assert.Error(t, child.checkNesting(filepath.Join("testdata", "nested", "parent.yml")))
+	assert.NoError(t, child.checkNesting(filepath.Join("testdata", "nested", "endpoints", "endpoints.yml")))
+}
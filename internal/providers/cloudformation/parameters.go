@@ -0,0 +1,92 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+// cliParameter is a parameter in the format of the --parameters option of the AWS CLI.
+type cliParameter struct {
+	ParameterKey     string `json:"ParameterKey"`
+	ParameterValue   string `json:"ParameterValue"`
+	UsePreviousValue bool   `json:"UsePreviousValue"`
+}
+
+// loadParameters returns the values of the parameters of the project. Values of the parameters
+// in the config file override the values of the parameters file.
+func loadParameters(projectCfg *config.Project) (map[string]string, error) {
+	values := make(map[string]string)
+
+	if projectCfg.CloudFormationParametersFile != "" {
+		fileValues, err := loadParametersFile(projectCfg.CloudFormationParametersFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range fileValues {
+			values[k] = v
+		}
+	}
+
+	for k, v := range projectCfg.CloudFormationParameters {
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+// loadParametersFile reads a parameters file in the format of the --parameters option of the AWS
+// CLI, or the template configuration files of CodePipeline ({"Parameters": {"Key": "Value"}}).
+func loadParametersFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error reading CloudFormation parameters file")
+	}
+
+	values, err := parseParameters(b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error parsing CloudFormation parameters file %s", path)
+	}
+
+	return values, nil
+}
+
+func parseParameters(b []byte) (map[string]string, error) {
+	values := make(map[string]string)
+
+	var params []cliParameter
+	if err := json.Unmarshal(b, &params); err == nil {
+		for _, p := range params {
+			if p.ParameterKey == "" {
+				return nil, fmt.Errorf("parameter without a ParameterKey")
+			}
+
+			// The previous values of the stack aren't known, so the default value is used
+			if p.UsePreviousValue {
+				continue
+			}
+
+			values[p.ParameterKey] = p.ParameterValue
+		}
+
+		return values, nil
+	}
+
+	var templateConfig struct {
+		Parameters map[string]interface{} `json:"Parameters"`
+	}
+	if err := json.Unmarshal(b, &templateConfig); err != nil {
+		return nil, errors.New("expected a list of ParameterKey and ParameterValue objects or an object with Parameters")
+	}
+
+	for k, v := range templateConfig.Parameters {
+		values[k] = fmt.Sprint(v)
+	}
+
+	return values, nil
+}
@@ -1,13 +1,17 @@
 package cloudformation
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/awslabs/goformation/v4/cloudformation"
+	cfnstack "github.com/awslabs/goformation/v4/cloudformation/cloudformation"
 
 	"github.com/infracost/infracost/internal/config"
 	"github.com/infracost/infracost/internal/schema"
+	log "github.com/sirupsen/logrus"
 	"github.com/tidwall/gjson"
 )
 
@@ -59,33 +63,113 @@ func (p *Parser) createResource(d *schema.ResourceData, u *schema.UsageData) *sc
 	}
 }
 
-func (p *Parser) parseTemplate(t *cloudformation.Template, usage map[string]*schema.UsageData) ([]*schema.Resource, []*schema.Resource, error) {
+// parseTemplate returns the resources of the template at templatePath, including the resources of
+// its nested stacks and StackSets.
+func (p *Parser) parseTemplate(t *cloudformation.Template, templatePath string, usage map[string]*schema.UsageData) ([]*schema.Resource, []*schema.Resource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
 	var resources []*schema.Resource
 	resources = append(resources, baseResources...)
 
+	stack := &stackContext{
+		templatePath: templatePath,
+		region:       p.region(),
+	}
+	resources = append(resources, p.parseResources(t, stack, usage)...)
+
+	return resources, resources, nil
+}
+
+func (p *Parser) parseResources(t *cloudformation.Template, stack *stackContext, usage map[string]*schema.UsageData) []*schema.Resource {
+	var resources []*schema.Resource
+
 	for name, d := range t.Resources {
+		address := stack.resourceAddress(name)
+
+		switch r := d.(type) {
+		case *cfnstack.Stack:
+			resources = append(resources, p.parseNestedStack(r, address, stack, usage)...)
+			continue
+		case *cfnstack.StackSet:
+			resources = append(resources, p.parseStackSet(r, name, address, stack, usage)...)
+			continue
+		}
+
 		tags := map[string]string{} // TODO: Where do I get tags?
 		var usageData *schema.UsageData
 
-		if ud := usage[name]; ud != nil {
+		if ud := usage[address]; ud != nil {
 			usageData = ud
-		} else if strings.HasSuffix(name, "]") {
-			lastIndexOfOpenBracket := strings.LastIndex(name, "[")
+		} else if strings.HasSuffix(address, "]") {
+			lastIndexOfOpenBracket := strings.LastIndex(address, "[")
 
-			if arrayUsageData := usage[fmt.Sprintf("%s[*]", name[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
+			if arrayUsageData := usage[fmt.Sprintf("%s[*]", address[:lastIndexOfOpenBracket])]; arrayUsageData != nil {
 				usageData = arrayUsageData
 			}
 		}
-		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", name, tags, d)
+		resourceData := schema.NewCFResourceData(d.AWSCloudFormationType(), "aws", address, tags, d)
+		resourceData.Set("region", stack.region)
+		p.addReferences(resourceData, t, stack)
 
 		if r := p.createResource(resourceData, usageData); r != nil {
 			resources = append(resources, r)
 		}
 	}
 
-	return resources, resources, nil
+	return resources
+}
+
+// addReferences adds the resources of the template that the reference attributes of the resource
+// refer to. Refs to resources are evaluated to their logical ID, so the reference attributes are the
+// paths of the properties that contain the logical IDs.
+func (p *Parser) addReferences(d *schema.ResourceData, t *cloudformation.Template, stack *stackContext) {
+	registryItem, ok := (*GetResourceRegistryMap())[d.Type]
+	if !ok || len(registryItem.ReferenceAttributes) == 0 {
+		return
+	}
+
+	b, err := json.Marshal(d.CFResource)
+	if err != nil {
+		log.Debugf("Could not read the references of CloudFormation resource %s: %s", d.Address, err)
+		return
+	}
+	properties := gjson.GetBytes(b, "Properties")
+
+	for _, attr := range registryItem.ReferenceAttributes {
+		name := properties.Get(attr).String()
+		if name == "" {
+			continue
+		}
+
+		r, ok := t.Resources[name]
+		if !ok {
+			continue
+		}
+
+		refData := schema.NewCFResourceData(r.AWSCloudFormationType(), "aws", stack.resourceAddress(name), map[string]string{}, r)
+		refData.Set("region", stack.region)
+		d.AddReference(attr, refData, nil)
+	}
+}
+
+// region returns the AWS region the template is deployed to. Templates don't contain their region
+// since it's chosen when the stack is created.
+func (p *Parser) region() string {
+	if p.ctx.RunContext != nil && p.ctx.RunContext.Config != nil && p.ctx.RunContext.Config.AWSOverrideRegion != "" {
+		return p.ctx.RunContext.Config.AWSOverrideRegion
+	}
+
+	if p.ctx.ProjectConfig != nil && p.ctx.ProjectConfig.CloudFormationRegion != "" {
+		return p.ctx.ProjectConfig.CloudFormationRegion
+	}
+
+	for _, k := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+
+	return "us-east-1"
 }
 
 func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schema.Resource {
@@ -106,5 +190,5 @@ func (p *Parser) loadUsageFileResources(u map[string]*schema.UsageData) []*schem
 }
 
 func isAwsChina(d *schema.ResourceData) bool {
-	return strings.HasPrefix(d.Type, "aws_") && strings.HasPrefix(d.Get("region").String(), "cn-")
+	return (strings.HasPrefix(d.Type, "aws_") || strings.HasPrefix(d.Type, "AWS::")) && strings.HasPrefix(d.Get("region").String(), "cn-")
 }
@@ -0,0 +1,353 @@
+package cloudformation
+
+import (
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const serverlessTransform = "AWS::Serverless-2016-10-31"
+
+// globalResourceTypes are the SAM resource types that the sections of Globals apply to.
+// See: https://docs.aws.amazon.com/serverless-application-model/latest/developerguide/sam-specification-template-anatomy-globals.html
+var globalResourceTypes = map[string]string{
+	"Function":    "AWS::Serverless::Function",
+	"Api":         "AWS::Serverless::Api",
+	"HttpApi":     "AWS::Serverless::HttpApi",
+	"SimpleTable": "AWS::Serverless::SimpleTable",
+}
+
+// serverlessFunctionProperties are the properties of AWS::Serverless::Function that have the same
+// name and format in AWS::Lambda::Function.
+var serverlessFunctionProperties = []string{
+	"Architectures",
+	"DeadLetterConfig",
+	"Description",
+	"Environment",
+	"EphemeralStorage",
+	"FileSystemConfigs",
+	"FunctionName",
+	"Handler",
+	"KmsKeyArn",
+	"Layers",
+	"MemorySize",
+	"PackageType",
+	"ReservedConcurrentExecutions",
+	"Runtime",
+	"Timeout",
+	"VpcConfig",
+}
+
+// hasServerlessTransform returns true if the template uses the AWS SAM transform.
+func hasServerlessTransform(raw map[string]interface{}) bool {
+	switch t := raw["Transform"].(type) {
+	case string:
+		return t == serverlessTransform
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s == serverlessTransform {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expandServerlessResources replaces the AWS SAM resources of the template with the CloudFormation
+// resources that the SAM transform creates, so they're priced like any other resource. Only the
+// resources that affect the cost are created, e.g. the deployments and stages of APIs are left out.
+// See: https://docs.aws.amazon.com/serverless-application-model/latest/developerguide/sam-specification-generated-resources.html
+func expandServerlessResources(raw map[string]interface{}) {
+	resources, ok := raw["Resources"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	applyServerlessGlobals(raw, resources)
+
+	// Sort the names so the implicit APIs are the same for every run
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		resource, ok := resources[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		properties, _ := resource["Properties"].(map[string]interface{})
+		if properties == nil {
+			properties = map[string]interface{}{}
+		}
+
+		switch resource["Type"] {
+		case "AWS::Serverless::Function":
+			expandServerlessFunction(resources, name, resource, properties)
+		case "AWS::Serverless::Api":
+			resource["Type"] = "AWS::ApiGateway::RestApi"
+			resource["Properties"] = pickProperties(properties, "Name", "Description")
+			setTags(resource, properties["Tags"])
+		case "AWS::Serverless::HttpApi":
+			resource["Type"] = "AWS::ApiGatewayV2::Api"
+			api := pickProperties(properties, "Name", "Description")
+			api["ProtocolType"] = "HTTP"
+			if tags, ok := properties["Tags"]; ok {
+				api["Tags"] = tags
+			}
+			resource["Properties"] = api
+		case "AWS::Serverless::SimpleTable":
+			resource["Type"] = "AWS::DynamoDB::Table"
+			resource["Properties"] = simpleTableProperties(properties)
+			setTags(resource, properties["Tags"])
+		case "AWS::Serverless::StateMachine":
+			resource["Type"] = "AWS::StepFunctions::StateMachine"
+			stateMachine := map[string]interface{}{}
+			if v, ok := properties["Name"]; ok {
+				stateMachine["StateMachineName"] = v
+			}
+			if v, ok := properties["Type"]; ok {
+				stateMachine["StateMachineType"] = v
+			}
+			resource["Properties"] = stateMachine
+			setTags(resource, properties["Tags"])
+		case "AWS::Serverless::LayerVersion":
+			resource["Type"] = "AWS::Lambda::LayerVersion"
+			layer := pickProperties(properties, "CompatibleRuntimes", "Description", "LicenseInfo")
+			if v, ok := properties["LayerName"]; ok {
+				layer["LayerName"] = v
+			}
+			resource["Properties"] = layer
+		case "AWS::Serverless::Application":
+			resource["Type"] = "AWS::CloudFormation::Stack"
+			stack := pickProperties(properties, "Parameters", "NotificationARNs", "TimeoutInMinutes")
+			if location, ok := properties["Location"].(string); ok {
+				stack["TemplateURL"] = location
+			} else {
+				log.Debugf("AWS SAM application %s is from the Serverless Application Repository, its resources aren't included", name)
+			}
+			resource["Properties"] = stack
+		}
+	}
+
+	delete(raw, "Globals")
+}
+
+// applyServerlessGlobals adds the properties of the Globals section to the SAM resources that don't
+// set them. Lists are appended to and maps are merged, like the SAM transform does.
+func applyServerlessGlobals(raw map[string]interface{}, resources map[string]interface{}) {
+	globals, ok := raw["Globals"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	for section, resourceType := range globalResourceTypes {
+		values, ok := globals[section].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		for _, r := range resources {
+			resource, ok := r.(map[string]interface{})
+			if !ok || resource["Type"] != resourceType {
+				continue
+			}
+
+			properties, ok := resource["Properties"].(map[string]interface{})
+			if !ok {
+				properties = map[string]interface{}{}
+				resource["Properties"] = properties
+			}
+
+			for k, v := range values {
+				properties[k] = mergeGlobalValue(properties[k], v)
+			}
+		}
+	}
+}
+
+func mergeGlobalValue(value interface{}, global interface{}) interface{} {
+	if value == nil {
+		return global
+	}
+
+	switch g := global.(type) {
+	case []interface{}:
+		if v, ok := value.([]interface{}); ok {
+			// The global list is copied since it's merged into each function, and appending to it
+			// directly would share its spare capacity between them
+			return append(append([]interface{}{}, g...), v...)
+		}
+	case map[string]interface{}:
+		if v, ok := value.(map[string]interface{}); ok {
+			merged := make(map[string]interface{}, len(g)+len(v))
+			for k, gv := range g {
+				merged[k] = gv
+			}
+			for k, vv := range v {
+				merged[k] = mergeGlobalValue(vv, g[k])
+			}
+			return merged
+		}
+	}
+
+	return value
+}
+
+// expandServerlessFunction converts an AWS::Serverless::Function to an AWS::Lambda::Function and
+// adds its execution role and the resources of its events.
+func expandServerlessFunction(resources map[string]interface{}, name string, resource map[string]interface{}, properties map[string]interface{}) {
+	resource["Type"] = "AWS::Lambda::Function"
+
+	function := pickProperties(properties, serverlessFunctionProperties...)
+	if tracing, ok := properties["Tracing"]; ok {
+		function["TracingConfig"] = map[string]interface{}{"Mode": tracing}
+	}
+	if _, ok := properties["ImageUri"]; ok {
+		function["PackageType"] = "Image"
+	}
+
+	if role, ok := properties["Role"]; ok {
+		function["Role"] = role
+	} else {
+		roleName := name + "Role"
+		resources[roleName] = map[string]interface{}{
+			"Type":       "AWS::IAM::Role",
+			"Properties": map[string]interface{}{},
+		}
+		function["Role"] = map[string]interface{}{"Fn::GetAtt": []interface{}{roleName, "Arn"}}
+	}
+
+	resource["Properties"] = function
+	setTags(resource, properties["Tags"])
+
+	events, _ := properties["Events"].(map[string]interface{})
+
+	eventNames := make([]string, 0, len(events))
+	for eventName := range events {
+		eventNames = append(eventNames, eventName)
+	}
+	sort.Strings(eventNames)
+
+	for _, eventName := range eventNames {
+		event, ok := events[eventName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		eventProperties, _ := event["Properties"].(map[string]interface{})
+		eventType, _ := event["Type"].(string)
+
+		switch eventType {
+		case "Api":
+			// Events without a RestApiId use the API that SAM creates for the template
+			if _, ok := eventProperties["RestApiId"]; !ok {
+				addImplicitResource(resources, "ServerlessRestApi", "AWS::ApiGateway::RestApi", nil)
+			}
+		case "HttpApi":
+			if _, ok := eventProperties["ApiId"]; !ok {
+				addImplicitResource(resources, "ServerlessHttpApi", "AWS::ApiGatewayV2::Api", map[string]interface{}{"ProtocolType": "HTTP"})
+			}
+		case "SQS", "Kinesis", "DynamoDB", "MSK", "MQ":
+			resources[name+eventName] = map[string]interface{}{
+				"Type":       "AWS::Lambda::EventSourceMapping",
+				"Properties": map[string]interface{}{},
+			}
+		case "SNS":
+			resources[name+eventName] = map[string]interface{}{
+				"Type":       "AWS::SNS::Subscription",
+				"Properties": map[string]interface{}{},
+			}
+		default:
+			log.Debugf("Ignoring %s event %s of AWS SAM function %s", eventType, eventName, name)
+		}
+	}
+}
+
+// addImplicitResource adds a resource that SAM creates once for the template, e.g. the API of the
+// Api events of the functions.
+func addImplicitResource(resources map[string]interface{}, name string, resourceType string, properties map[string]interface{}) {
+	if _, ok := resources[name]; ok {
+		return
+	}
+
+	if properties == nil {
+		properties = map[string]interface{}{}
+	}
+
+	resources[name] = map[string]interface{}{
+		"Type":       resourceType,
+		"Properties": properties,
+	}
+}
+
+// simpleTableProperties returns the properties of the DynamoDB table of an AWS::Serverless::SimpleTable.
+// SimpleTables have a single primary key, which is a string named id by default.
+func simpleTableProperties(properties map[string]interface{}) map[string]interface{} {
+	keyName := interface{}("id")
+	keyType := interface{}("S")
+
+	if primaryKey, ok := properties["PrimaryKey"].(map[string]interface{}); ok {
+		if v, ok := primaryKey["Name"]; ok {
+			keyName = v
+		}
+		if s, ok := primaryKey["Type"].(string); ok && s != "" {
+			keyType = strings.ToUpper(s[:1])
+		}
+	}
+
+	table := pickProperties(properties, "TableName", "SSESpecification")
+	table["KeySchema"] = []interface{}{
+		map[string]interface{}{"AttributeName": keyName, "KeyType": "HASH"},
+	}
+	table["AttributeDefinitions"] = []interface{}{
+		map[string]interface{}{"AttributeName": keyName, "AttributeType": keyType},
+	}
+
+	if throughput, ok := properties["ProvisionedThroughput"]; ok {
+		table["BillingMode"] = "PROVISIONED"
+		table["ProvisionedThroughput"] = throughput
+	} else {
+		table["BillingMode"] = "PAY_PER_REQUEST"
+	}
+
+	return table
+}
+
+func pickProperties(properties map[string]interface{}, names ...string) map[string]interface{} {
+	picked := make(map[string]interface{})
+
+	for _, name := range names {
+		if v, ok := properties[name]; ok {
+			picked[name] = v
+		}
+	}
+
+	return picked
+}
+
+// setTags sets the SAM tags of a resource, which are a map, as a list of key/value pairs.
+func setTags(resource map[string]interface{}, tags interface{}) {
+	m, ok := tags.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	list := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, map[string]interface{}{"Key": k, "Value": m[k]})
+	}
+
+	properties := resource["Properties"].(map[string]interface{})
+	properties["Tags"] = list
+}
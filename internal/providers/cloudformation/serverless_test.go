@@ -0,0 +1,68 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/dynamodb"
+	"github.com/awslabs/goformation/v4/cloudformation/lambda"
+	"github.com/awslabs/goformation/v4/cloudformation/stepfunctions"
+	"github.com/awslabs/goformation/v4/cloudformation/tags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTemplateServerless(t *testing.T) {
+	template, err := loadTemplate(filepath.Join("testdata", "sam.yml"), "us-east-1", nil)
+	require.NoError(t, err)
+
+	types := make(map[string]string)
+	for name, r := range template.Resources {
+		types[name] = r.AWSCloudFormationType()
+	}
+
+	assert.Equal(t, map[string]string{
+		"ApiFunction":        "AWS::Lambda::Function",
+		"ApiFunctionRole":    "AWS::IAM::Role",
+		"ServerlessRestApi":  "AWS::ApiGateway::RestApi",
+		"ServerlessHttpApi":  "AWS::ApiGatewayV2::Api",
+		"WorkerFunction":     "AWS::Lambda::Function",
+		"WorkerFunctionJobs": "AWS::Lambda::EventSourceMapping",
+		"JobQueue":           "AWS::SQS::Queue",
+		"Orders":             "AWS::DynamoDB::Table",
+		"Workflow":           "AWS::StepFunctions::StateMachine",
+	}, types)
+
+	// The properties of the Globals are added to the functions, and the tags are merged
+	function := template.Resources["ApiFunction"].(*lambda.Function)
+	assert.Equal(t, 512, function.MemorySize)
+	assert.Equal(t, "python3.9", function.Runtime)
+	assert.Equal(t, 10, function.Timeout)
+	assert.Equal(t, []tags.Tag{{Key: "Service", Value: "api"}, {Key: "Team", Value: "payments"}}, function.Tags)
+
+	table := template.Resources["Orders"].(*dynamodb.Table)
+	assert.Equal(t, "PROVISIONED", table.BillingMode)
+	assert.Equal(t, int64(5), table.ProvisionedThroughput.ReadCapacityUnits)
+	assert.Equal(t, "N", table.AttributeDefinitions[0].AttributeType)
+
+	stateMachine := template.Resources["Workflow"].(*stepfunctions.StateMachine)
+	assert.Equal(t, "EXPRESS", stateMachine.StateMachineType)
+}
+
+func TestMergeGlobalValue(t *testing.T) {
+	assert.Equal(t, "local", mergeGlobalValue("local", "global"))
+	assert.Equal(t, "global", mergeGlobalValue(nil, "global"))
+	assert.Equal(t, []interface{}{"a", "b"}, mergeGlobalValue([]interface{}{"b"}, []interface{}{"a"}))
+	assert.Equal(t,
+		map[string]interface{}{"A": "global", "B": "local"},
+		mergeGlobalValue(map[string]interface{}{"B": "local"}, map[string]interface{}{"A": "global", "B": "global"}),
+	)
+
+	// Lists merged into different functions don't share the spare capacity of the global list
+	global := make([]interface{}, 3, 4)
+	copy(global, []interface{}{"a", "b", "c"})
+	first := mergeGlobalValue([]interface{}{"A"}, global)
+	second := mergeGlobalValue([]interface{}{"B"}, global)
+	assert.Equal(t, []interface{}{"a", "b", "c", "A"}, first)
+	assert.Equal(t, []interface{}{"a", "b", "c", "B"}, second)
+}
@@ -0,0 +1,397 @@
+package cloudformation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/awslabs/goformation/v4"
+	"github.com/awslabs/goformation/v4/cloudformation"
+	"github.com/awslabs/goformation/v4/intrinsics"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// goformation isn't threadsafe since it registers the YAML tags of the intrinsic functions globally,
+// so templates are read one at a time.
+// See: https://github.com/awslabs/goformation/issues/363
+var goformationMux = &sync.Mutex{}
+
+// OpenTemplate reads a CloudFormation template without evaluating its parameters. Resources of types
+// that goformation doesn't know are read as custom resources.
+func OpenTemplate(path string) (*cloudformation.Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	goformationMux.Lock()
+	defer goformationMux.Unlock()
+
+	raw, err := readTemplate(b, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if resources, ok := raw["Resources"].(map[string]interface{}); ok {
+		renameUnknownTypes(resources)
+	}
+
+	b, err = json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return goformation.ParseJSON(b)
+}
+
+// loadTemplate reads a CloudFormation template and evaluates its intrinsic functions with the
+// parameter values and the region of the stack. Resources whose condition is false are removed,
+// like CloudFormation does when it creates the stack, and AWS SAM resources are expanded.
+func loadTemplate(path string, region string, params map[string]string) (*cloudformation.Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadTemplateBody(b, strings.EqualFold(filepath.Ext(path), ".json"), region, params)
+}
+
+// loadTemplateBody is loadTemplate for a template that isn't in a file, e.g. the inline template of a
+// StackSet.
+func loadTemplateBody(b []byte, isJSON bool, region string, params map[string]string) (*cloudformation.Template, error) {
+	goformationMux.Lock()
+	defer goformationMux.Unlock()
+
+	raw, err := readTemplate(b, isJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	setParameterValues(raw, params)
+	if hasServerlessTransform(raw) {
+		expandServerlessResources(raw)
+	}
+	resourceConditions := removeResourceConditions(raw)
+
+	b, err = json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluate the intrinsic functions, then convert the values of the properties to the types of the
+	// resources before they're parsed
+	b, err = intrinsics.ProcessJSON(b, &intrinsics.ProcessorOptions{
+		EvaluateConditions: true,
+		IntrinsicHandlerOverrides: map[string]intrinsics.IntrinsicHandler{
+			"Ref":     refHandler(region),
+			"Fn::Sub": subHandler(region),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var processed map[string]interface{}
+	err = json.Unmarshal(b, &processed)
+	if err != nil {
+		return nil, err
+	}
+
+	var unknownTypes map[string]string
+	if resources, ok := processed["Resources"].(map[string]interface{}); ok {
+		coerceProperties(resources)
+		unknownTypes = renameUnknownTypes(resources)
+	}
+
+	b, err = json.Marshal(processed)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := goformation.ParseJSONWithOptions(b, &intrinsics.ProcessorOptions{NoProcess: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error parsing CloudFormation resources")
+	}
+
+	for name, resourceType := range unknownTypes {
+		if r, ok := t.Resources[name].(*cloudformation.CustomResource); ok {
+			r.Type = resourceType
+		}
+	}
+
+	for name, condition := range resourceConditions {
+		v, ok := t.Conditions[condition].(bool)
+		if !ok {
+			log.Debugf("Condition %s of CloudFormation resource %s could not be evaluated, including the resource", condition, name)
+			continue
+		}
+
+		if !v {
+			log.Debugf("Excluding CloudFormation resource %s since its condition %s is false", name, condition)
+			delete(t.Resources, name)
+		}
+	}
+
+	return t, nil
+}
+
+// readTemplate converts the template to JSON without evaluating it, so the parameters and conditions
+// can be set up before the intrinsic functions are evaluated. The output of `aws cloudformation
+// get-template` is also accepted, which has the template in its TemplateBody.
+func readTemplate(b []byte, isJSON bool) (map[string]interface{}, error) {
+	opts := &intrinsics.ProcessorOptions{NoProcess: true}
+
+	var err error
+	if isJSON {
+		b, err = intrinsics.ProcessJSON(b, opts)
+	} else {
+		b, err = intrinsics.ProcessYAML(b, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	err = json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, err
+	}
+
+	switch body := raw["TemplateBody"].(type) {
+	case map[string]interface{}:
+		return body, nil
+	case string:
+		// YAML is a superset of JSON, so the body can be read as YAML whatever its format
+		return readTemplate([]byte(body), false)
+	}
+
+	return raw, nil
+}
+
+// setParameterValues sets the values of the parameters as their defaults, which is what the
+// intrinsic functions evaluate Refs to parameters with.
+func setParameterValues(raw map[string]interface{}, values map[string]string) {
+	parameters, _ := raw["Parameters"].(map[string]interface{})
+
+	for name, value := range values {
+		parameter, ok := parameters[name].(map[string]interface{})
+		if !ok {
+			log.Warnf("Ignoring value of CloudFormation parameter %s since it's not defined in the template", name)
+			continue
+		}
+
+		var v interface{} = value
+		// Conditions compare Number parameters with numbers in the template
+		if parameter["Type"] == "Number" {
+			if f, err := strconv.ParseFloat(value, 64); err == nil {
+				v = f
+			}
+		}
+
+		parameter["Default"] = v
+	}
+
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if parameter, ok := parameters[name].(map[string]interface{}); ok {
+			if _, ok := parameter["Default"]; !ok {
+				log.Debugf("CloudFormation parameter %s has no value, set it with cloudformation_parameters or cloudformation_parameters_file in the config file", name)
+			}
+		}
+	}
+}
+
+// removeResourceConditions removes the conditions of the resources so they aren't replaced with
+// the value of the condition when the template is evaluated, and returns them by resource name.
+func removeResourceConditions(raw map[string]interface{}) map[string]string {
+	conditions := make(map[string]string)
+
+	resources, _ := raw["Resources"].(map[string]interface{})
+	for name, r := range resources {
+		resource, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition, ok := resource["Condition"].(string); ok {
+			conditions[name] = condition
+			delete(resource, "Condition")
+		}
+	}
+
+	return conditions
+}
+
+// renameUnknownTypes prefixes the types of the resources that goformation doesn't know with Custom::
+// so they're read as custom resources, e.g. AWS::CDK::Metadata or types newer than goformation.
+// goformation fails to read the template otherwise. It returns the original types by resource name.
+func renameUnknownTypes(resources map[string]interface{}) map[string]string {
+	resourceTypes := cloudformation.AllResources()
+	unknownTypes := make(map[string]string)
+
+	for name, r := range resources {
+		resource, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		typeName, _ := resource["Type"].(string)
+		if _, ok := resourceTypes[typeName]; ok || typeName == "" || strings.HasPrefix(typeName, "Custom::") {
+			continue
+		}
+
+		unknownTypes[name] = typeName
+		resource["Type"] = "Custom::" + typeName
+	}
+
+	return unknownTypes
+}
+
+// refHandler resolves Refs to parameters and pseudo parameters. AWS::Region is the region of the
+// project rather than the placeholder of goformation. Refs to resources are resolved to the logical ID
+// of the resource so the parser can find the resources that are referenced.
+func refHandler(region string) intrinsics.IntrinsicHandler {
+	return func(name string, input interface{}, template interface{}) interface{} {
+		s, ok := input.(string)
+		if ok && s == "AWS::Region" {
+			return region
+		}
+
+		if t, isMap := template.(map[string]interface{}); ok && isMap {
+			if resources, isMap := t["Resources"].(map[string]interface{}); isMap {
+				if _, isResource := resources[s]; isResource {
+					return s
+				}
+			}
+		}
+
+		return intrinsics.Ref(name, input, template)
+	}
+}
+
+// subHandler resolves Fn::Subs like goformation, but with AWS::Region as the region of the project.
+// goformation resolves the variables of Fn::Sub with its own Ref rather than the Ref override, so the
+// region is substituted before the rest of the string is resolved.
+func subHandler(region string) intrinsics.IntrinsicHandler {
+	return func(name string, input interface{}, template interface{}) interface{} {
+		switch val := input.(type) {
+		case string:
+			input = strings.ReplaceAll(val, "${AWS::Region}", region)
+		case []interface{}:
+			if len(val) > 0 {
+				if src, ok := val[0].(string); ok {
+					substituted := make([]interface{}, len(val))
+					copy(substituted, val)
+					substituted[0] = strings.ReplaceAll(src, "${AWS::Region}", region)
+					input = substituted
+				}
+			}
+		}
+
+		return intrinsics.FnSub(name, input, template)
+	}
+}
+
+// coerceProperties converts the values of the properties of the resources to the types of the
+// goformation resources. Parameter values are strings in CloudFormation and can be used for
+// properties of any type, but goformation requires the JSON types to match.
+func coerceProperties(resources map[string]interface{}) {
+	resourceTypes := cloudformation.AllResources()
+
+	for _, r := range resources {
+		resource, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		typeName, _ := resource["Type"].(string)
+		resourceType, ok := resourceTypes[typeName]
+		if !ok {
+			continue
+		}
+
+		if properties, ok := resource["Properties"]; ok {
+			resource["Properties"] = coerceValue(properties, reflect.TypeOf(resourceType))
+		}
+	}
+}
+
+func coerceValue(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		switch x := v.(type) {
+		case float64:
+			return strconv.FormatFloat(x, 'f', -1, 64)
+		case bool:
+			return strconv.FormatBool(x)
+		case map[string]interface{}:
+			// Documents such as the TemplateBody of a StackSet can be written as YAML objects
+			if b, err := json.Marshal(x); err == nil {
+				return string(b)
+			}
+		}
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		}
+	case reflect.Bool:
+		if s, ok := v.(string); ok {
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+	case reflect.Slice:
+		// Refs to CommaDelimitedList parameters are comma separated strings
+		if s, ok := v.(string); ok && t.Elem().Kind() == reflect.String {
+			return strings.Split(s, ",")
+		}
+
+		if a, ok := v.([]interface{}); ok {
+			for i := range a {
+				a[i] = coerceValue(a[i], t.Elem())
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				m[k] = coerceValue(m[k], t.Elem())
+			}
+		}
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+
+			if fv, ok := m[name]; ok && fv != nil {
+				m[name] = coerceValue(fv, field.Type)
+			}
+		}
+	}
+
+	return v
+}
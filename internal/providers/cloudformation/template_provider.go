@@ -1,7 +1,6 @@
 package cloudformation
 
 import (
-	"github.com/awslabs/goformation/v4"
 	"github.com/pkg/errors"
 
 	"github.com/infracost/infracost/internal/config"
@@ -35,7 +34,13 @@ func (p *TemplateProvider) AddMetadata(metadata *schema.ProjectMetadata) {
 }
 
 func (p *TemplateProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
-	template, err := goformation.Open(p.Path)
+	params, err := loadParameters(p.ctx.ProjectConfig)
+	if err != nil {
+		return []*schema.Project{}, err
+	}
+
+	parser := NewParser(p.ctx)
+	template, err := loadTemplate(p.Path, parser.region(), params)
 	if err != nil {
 		return []*schema.Project{}, errors.Wrap(err, "Error reading CloudFormation template file")
 	}
@@ -49,8 +54,7 @@ func (p *TemplateProvider) LoadResources(usage map[string]*schema.UsageData) ([]
 	}
 
 	project := schema.NewProject(name, metadata)
-	parser := NewParser(p.ctx)
-	pastResources, resources, err := parser.parseTemplate(template, usage)
+	pastResources, resources, err := parser.parseTemplate(template, p.Path, usage)
 	if err != nil {
 		return []*schema.Project{project}, errors.Wrap(err, "Error parsing CloudFormation template file")
 	}
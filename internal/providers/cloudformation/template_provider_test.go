@@ -0,0 +1,78 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func TestTemplateProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:                 filepath.Join("testdata", "template.yml"),
+		CloudFormationRegion: "eu-west-1",
+	}, log.Fields{})
+
+	provider := NewTemplateProvider(ctx, false)
+	projects, err := provider.LoadResources(usage.NewBlankUsageFile().ToUsageDataMap())
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	resources := make(map[string]*schema.Resource)
+	for _, r := range projects[0].Resources {
+		resources[r.Name] = r
+	}
+
+	for _, name := range []string{"WebServer", "Database", "Function", "Bucket", "NatGateway", "AutoScalingGroup", "Service", "SearchDomain"} {
+		require.Contains(t, resources, name)
+		assert.False(t, resources[name].IsSkipped, "%s should be supported", name)
+		assert.True(t, len(resources[name].CostComponents) > 0 || len(resources[name].SubResources) > 0, "%s should have costs", name)
+	}
+
+	assert.True(t, resources["Role"].NoPrice)
+	assert.True(t, resources["TransitGateway"].NoPrice)
+	assert.True(t, resources["Stream"].IsSkipped)
+	assert.False(t, resources["Stream"].NoPrice)
+
+	// The region of the project is used for the prices
+	instance := resources["WebServer"]
+	assert.Equal(t, "eu-west-1", *instance.CostComponents[0].ProductFilter.Region)
+	assert.Equal(t, map[string]string{"Environment": "prod"}, instance.Tags)
+
+	// The /dev/xvda mapping is the root volume, the others are additional volumes
+	require.Len(t, instance.SubResources, 2)
+	assert.Equal(t, "root_block_device", instance.SubResources[0].Name)
+	assert.Equal(t, "ebs_block_device[0]", instance.SubResources[1].Name)
+
+	// Refs to resources are resolved, so the launch template and task definition are used
+	group := resources["AutoScalingGroup"]
+	require.Len(t, group.SubResources, 1)
+	assert.Equal(t, "LaunchTemplate", group.SubResources[0].Name)
+	assert.Contains(t, group.SubResources[0].CostComponents[0].Name, "t3.large")
+	assert.Equal(t, "1460", group.SubResources[0].CostComponents[0].MonthlyQuantity.String())
+
+	service := resources["Service"]
+	require.Len(t, service.CostComponents, 2)
+	assert.Equal(t, "4", service.CostComponents[0].HourlyQuantity.String())
+	assert.Equal(t, "2", service.CostComponents[1].HourlyQuantity.String())
+}
+
+func TestParserRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{}, log.Fields{})
+	assert.Equal(t, "us-east-1", NewParser(ctx).region())
+
+	t.Setenv("AWS_DEFAULT_REGION", "ap-southeast-2")
+	assert.Equal(t, "ap-southeast-2", NewParser(ctx).region())
+
+	ctx.ProjectConfig.CloudFormationRegion = "eu-central-1"
+	assert.Equal(t, "eu-central-1", NewParser(ctx).region())
+}
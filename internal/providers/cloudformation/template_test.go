@@ -0,0 +1,103 @@
+package cloudformation
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/goformation/v4/cloudformation/ec2"
+	"github.com/awslabs/goformation/v4/cloudformation/rds"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+)
+
+func TestLoadTemplateDefaults(t *testing.T) {
+	tmpl, err := loadTemplate(filepath.Join("testdata", "conditions.yml"), "us-east-1", nil)
+	require.NoError(t, err)
+
+	instance, err := tmpl.GetEC2InstanceWithName("WebServer")
+	require.NoError(t, err)
+	assert.Equal(t, "t3.micro", instance.InstanceType)
+	assert.Equal(t, "ami-11111111", instance.ImageId)
+	assert.False(t, instance.Monitoring)
+
+	db, err := tmpl.GetRDSDBInstanceWithName("Database")
+	require.NoError(t, err)
+	assert.Equal(t, "db.t3.micro", db.DBInstanceClass)
+	// Number parameters are converted to the type of the property
+	assert.Equal(t, "20", db.AllocatedStorage)
+	assert.False(t, db.MultiAZ)
+
+	// Resources whose condition is false are excluded
+	assert.NotContains(t, tmpl.Resources, "ProdCache")
+	assert.Contains(t, tmpl.Resources, "DevQueue")
+
+	endpoint, err := tmpl.GetEC2VPCEndpointWithName("Endpoint")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"subnet-1", "subnet-2"}, endpoint.SubnetIds)
+}
+
+func TestLoadTemplateParameters(t *testing.T) {
+	params, err := loadParameters(&config.Project{
+		CloudFormationParametersFile: filepath.Join("testdata", "parameters.json"),
+		CloudFormationParameters:     map[string]string{"StorageSize": "1000"},
+	})
+	require.NoError(t, err)
+	// Values in the config file override the values of the parameters file, and previous values
+	// use the default of the template
+	assert.Equal(t, map[string]string{"Environment": "prod", "StorageSize": "1000"}, params)
+
+	tmpl, err := loadTemplate(filepath.Join("testdata", "conditions.yml"), "eu-west-1", params)
+	require.NoError(t, err)
+
+	instance, err := tmpl.GetEC2InstanceWithName("WebServer")
+	require.NoError(t, err)
+	assert.Equal(t, "m5.xlarge", instance.InstanceType)
+	assert.Equal(t, "ami-22222222", instance.ImageId)
+	assert.True(t, instance.Monitoring)
+
+	db, ok := tmpl.Resources["Database"].(*rds.DBInstance)
+	require.True(t, ok)
+	assert.Equal(t, "db.m5.large", db.DBInstanceClass)
+	assert.Equal(t, "1000", db.AllocatedStorage)
+	assert.True(t, db.MultiAZ)
+
+	assert.Contains(t, tmpl.Resources, "ProdCache")
+	assert.NotContains(t, tmpl.Resources, "DevQueue")
+
+	_, ok = tmpl.Resources["Endpoint"].(*ec2.VPCEndpoint)
+	assert.True(t, ok)
+}
+
+func TestLoadTemplateSubRegion(t *testing.T) {
+	tmpl, err := loadTemplateBody([]byte(`
+Resources:
+  WebServer:
+    Type: AWS::EC2::Instance
+    Properties:
+      InstanceType: t3.micro
+      ImageId: !Sub "${AWS::Region}-ami"
+      KeyName: !Sub
+        - "${AWS::Region}-${Suffix}"
+        - Suffix: key
+`), false, "eu-west-1", nil)
+	require.NoError(t, err)
+
+	instance, err := tmpl.GetEC2InstanceWithName("WebServer")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1-ami", instance.ImageId)
+	assert.Equal(t, "eu-west-1-key", instance.KeyName)
+}
+
+func TestParseParameters(t *testing.T) {
+	values, err := parseParameters([]byte(`{"Parameters": {"Environment": "prod", "StorageSize": 500}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Environment": "prod", "StorageSize": "500"}, values)
+
+	_, err = parseParameters([]byte(`[{"ParameterValue": "prod"}]`))
+	assert.EqualError(t, err, "parameter without a ParameterKey")
+
+	_, err = parseParameters([]byte(`"prod"`))
+	assert.Error(t, err)
+}
@@ -7,9 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
-	"sync"
+	"strings"
 
-	"github.com/awslabs/goformation/v4"
 	"github.com/tidwall/gjson"
 
 	"github.com/infracost/infracost/internal/config"
@@ -17,6 +16,8 @@ import (
 	"github.com/infracost/infracost/internal/logging"
 	"github.com/infracost/infracost/internal/providers/azurerm"
 	"github.com/infracost/infracost/internal/providers/cloudformation"
+	"github.com/infracost/infracost/internal/providers/kubernetes"
+	"github.com/infracost/infracost/internal/providers/pulumi"
 	"github.com/infracost/infracost/internal/providers/terraform"
 	"github.com/infracost/infracost/internal/schema"
 )
@@ -91,6 +92,12 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 		return terraform.NewStateJSONProvider(ctx, includePastResources), nil
 	case "cloudformation":
 		return cloudformation.NewTemplateProvider(ctx, includePastResources), nil
+	case "cloudformation_change_set_json":
+		return cloudformation.NewChangeSetProvider(ctx, includePastResources), nil
+	case "cloudformation_cdk":
+		return cloudformation.NewCDKProvider(ctx, includePastResources), nil
+	case "pulumi_preview_json":
+		return pulumi.NewPreviewJSONProvider(ctx, includePastResources), nil
 	case "azurerm_whatif_json":
 		return azurerm.NewWhatifJsonProvider(ctx, includePastResources), nil
 	case "azurerm_template_json":
@@ -99,6 +106,12 @@ func Detect(ctx *config.ProjectContext, includePastResources bool) (schema.Provi
 			return nil, err
 		}
 		return provider, nil
+	case "azurerm_template_dir":
+		return azurerm.NewArmTemplateDirProvider(ctx, includePastResources), nil
+	case "azurerm_resource_list_json":
+		return azurerm.NewResourceListJsonProvider(ctx), nil
+	case "kubernetes_manifest":
+		return kubernetes.NewManifestProvider(ctx), nil
 	}
 
 	return nil, fmt.Errorf("could not detect path type for '%s'", path)
@@ -127,10 +140,23 @@ func validateProjectForHCL(ctx *config.ProjectContext, path string) error {
 }
 
 func DetectProjectType(path string, forceCLI bool) string {
+	// Change sets are checked before WhatIf results, which have the same field names in lowercase
+	if isCloudFormationChangeSet(path) {
+		return "cloudformation_change_set_json"
+	}
+
+	if isPulumiPreviewJSON(path) {
+		return "pulumi_preview_json"
+	}
+
 	if isCloudFormationTemplate(path) {
 		return "cloudformation"
 	}
 
+	if cloudformation.IsCDKAssembly(path) {
+		return "cloudformation_cdk"
+	}
+
 	if isTerraformPlanJSON(path) {
 		return "terraform_plan_json"
 	}
@@ -154,10 +180,22 @@ func DetectProjectType(path string, forceCLI bool) string {
 		return "azurerm_whatif_json"
 	}
 
+	if isAzureRMResourceList(path) {
+		return "azurerm_resource_list_json"
+	}
+
 	if isAzureRMTemplate(path) || isBicepTemplate(path) {
 		return "azurerm_template_json"
 	}
 
+	if isAzureRMTemplateDir(path) {
+		return "azurerm_template_dir"
+	}
+
+	if isKubernetesManifest(path) {
+		return "kubernetes_manifest"
+	}
+
 	if forceCLI {
 		return "terraform_cli"
 	}
@@ -234,6 +272,15 @@ func isAzureRMWhatifTemplate(path string) bool {
 	return valid
 }
 
+func isAzureRMResourceList(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return azurerm.IsResourceListJSON(b)
+}
+
 func isBicepTemplate(path string) bool {
 	return filepath.Ext(path) == ".bicep"
 }
@@ -250,7 +297,7 @@ func isAzureRMTemplate(path string) bool {
 		return false
 	}
 
-	pattern := "https://schema\\.management\\.azure\\.com/schemas/\\d{4}-\\d{2}-\\d{2}/deploymentTemplate\\.json"
+	pattern := "https://schema\\.management\\.azure\\.com/schemas/\\d{4}-\\d{2}-\\d{2}/(deploymentTemplate|subscriptionDeploymentTemplate|managementGroupDeploymentTemplate|tenantDeploymentTemplate)\\.json"
 	matched, err := regexp.Match(pattern, []byte(schema.Str))
 	if err != nil {
 		return false
@@ -259,6 +306,48 @@ func isAzureRMTemplate(path string) bool {
 	return matched
 }
 
+// isAzureRMTemplateDir returns true for a directory that contains ARM templates or Bicep files
+// but no Terraform files, e.g. a Bicep monorepo.
+func isAzureRMTemplateDir(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+
+	if hasNestedTerraformFiles(path, 5) {
+		return false
+	}
+
+	return len(azurerm.FindTemplateProjects(path)) > 0
+}
+
+// hasNestedTerraformFiles returns true if path or its subdirectories contain Terraform files. Hidden
+// directories and node_modules, which can contain the Terraform files of packages, are skipped.
+func hasNestedTerraformFiles(path string, maxDepth int) bool {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() {
+			if strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json") || name == "terragrunt.hcl" {
+				return true
+			}
+			continue
+		}
+
+		if maxDepth > 0 && !strings.HasPrefix(name, ".") && name != "node_modules" {
+			if hasNestedTerraformFiles(filepath.Join(path, name), maxDepth-1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func isTerraformPlan(path string) bool {
 	r, err := zip.OpenReader(path)
 	if err != nil {
@@ -309,15 +398,26 @@ func isTerragruntNestedDir(path string, maxDepth int) bool {
 	return false
 }
 
-// goformation lib is not threadsafe, so we run this check synchronously
-// See: https://github.com/awslabs/goformation/issues/363
-var cfMux = &sync.Mutex{}
+func isCloudFormationChangeSet(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return cloudformation.IsChangeSetJSON(b)
+}
 
-func isCloudFormationTemplate(path string) bool {
-	cfMux.Lock()
-	defer cfMux.Unlock()
+func isPulumiPreviewJSON(path string) bool {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
 
-	template, err := goformation.Open(path)
+	return pulumi.IsPreviewJSON(b)
+}
+
+func isCloudFormationTemplate(path string) bool {
+	template, err := cloudformation.OpenTemplate(path)
 	if err != nil {
 		return false
 	}
@@ -328,3 +428,18 @@ func isCloudFormationTemplate(path string) bool {
 
 	return false
 }
+
+// isKubernetesManifest returns true for a file of Kubernetes objects, or a directory that contains
+// them but no Terraform files.
+func isKubernetesManifest(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() && hasNestedTerraformFiles(path, 5) {
+		return false
+	}
+
+	return kubernetes.IsManifest(path)
+}
@@ -2,6 +2,7 @@ package providers
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/infracost/infracost/internal/config"
@@ -38,6 +39,21 @@ func TestAzureRmProviderDetection(t *testing.T) {
 			Expected: "azurerm_bicep_template",
 			Config:   "../../examples/azurerm/web_app/infracost-config.yml",
 		},
+		{
+			Path:     "../../examples/azurerm/landing_zone/subscription.json",
+			Expected: "azurerm_template_json",
+			Config:   "../../examples/azurerm/landing_zone/infracost-config.yml",
+		},
+		{
+			Path:     "./azurerm/testdata/baseline/resource_list.json",
+			Expected: "azurerm_resource_list_json",
+			Config:   "../../examples/azurerm/web_app/infracost-config.yml",
+		},
+		{
+			Path:     "./azurerm/testdata/repo",
+			Expected: "azurerm_template_dir",
+			Config:   "../../examples/azurerm/web_app/infracost-config.yml",
+		},
 	}
 
 	for _, test := range tests {
@@ -66,3 +82,28 @@ func TestAzureRmProviderDetection(t *testing.T) {
 		}(test.Path, test.Expected)
 	}
 }
+
+func TestCloudFormationProviderDetection(t *testing.T) {
+	assert.Equal(t, "cloudformation", DetectProjectType("./cloudformation/testdata/template.yml", false))
+	assert.Equal(t, "cloudformation_change_set_json", DetectProjectType("./cloudformation/testdata/change_set.json", false))
+	assert.Equal(t, "cloudformation", DetectProjectType("./cloudformation/testdata/sam.yml", false))
+	assert.Equal(t, "cloudformation_cdk", DetectProjectType("./cloudformation/testdata/cdk.out", false))
+	assert.Equal(t, "cloudformation_cdk", DetectProjectType("./cloudformation/testdata/cdk.out/manifest.json", false))
+	assert.Equal(t, "pulumi_preview_json", DetectProjectType("./pulumi/testdata/preview.json", false))
+}
+
+func TestKubernetesProviderDetection(t *testing.T) {
+	assert.Equal(t, "kubernetes_manifest", DetectProjectType("./kubernetes/testdata/aks", false))
+	assert.Equal(t, "kubernetes_manifest", DetectProjectType("./kubernetes/testdata/eks.yaml", false))
+}
+
+func TestHasNestedTerraformFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "node_modules", "pkg"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "node_modules", "pkg", "main.tf"), []byte{}, 0600))
+	assert.False(t, hasNestedTerraformFiles(dir, 5))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "infra"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "infra", "main.tf"), []byte{}, 0600))
+	assert.True(t, hasNestedTerraformFiles(dir, 5))
+}
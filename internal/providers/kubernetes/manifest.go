@@ -0,0 +1,345 @@
+package kubernetes
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// maxManifestDirDepth is how deep directories are searched for manifests.
+const maxManifestDirDepth = 5
+
+// ObjectMeta is the metadata of a Kubernetes object.
+type ObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+func (m ObjectMeta) namespace() string {
+	if m.Namespace == "" {
+		return "default"
+	}
+
+	return m.Namespace
+}
+
+// key returns the namespace and name of the object, which is unique for objects of the same kind.
+func (m ObjectMeta) key() string {
+	return m.namespace() + "/" + m.Name
+}
+
+type object struct {
+	APIVersion string     `yaml:"apiVersion"`
+	Kind       string     `yaml:"kind"`
+	Metadata   ObjectMeta `yaml:"metadata"`
+}
+
+type StorageClass struct {
+	Metadata    ObjectMeta        `yaml:"metadata"`
+	Provisioner string            `yaml:"provisioner"`
+	Parameters  map[string]string `yaml:"parameters"`
+}
+
+type PersistentVolumeClaim struct {
+	Metadata ObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		// StorageClassName is nil if the claim uses the default storage class, and empty if it's
+		// bound to a volume that isn't dynamically provisioned
+		StorageClassName *string              `yaml:"storageClassName"`
+		Resources        ResourceRequirements `yaml:"resources"`
+	} `yaml:"spec"`
+}
+
+type Service struct {
+	Metadata ObjectMeta `yaml:"metadata"`
+	Spec     struct {
+		Type              string `yaml:"type"`
+		LoadBalancerClass string `yaml:"loadBalancerClass"`
+		Ports             []struct {
+			Name     string `yaml:"name"`
+			Port     int64  `yaml:"port"`
+			Protocol string `yaml:"protocol"`
+		} `yaml:"ports"`
+	} `yaml:"spec"`
+}
+
+// Workload is a Deployment, StatefulSet, DaemonSet, ReplicaSet, ReplicationController or Pod.
+type Workload struct {
+	Kind     string
+	Metadata ObjectMeta
+	// Replicas is the number of pods, it's 1 for Pods and the number of pods on each node for DaemonSets
+	Replicas             int64
+	Pod                  PodSpec
+	VolumeClaimTemplates []*PersistentVolumeClaim
+}
+
+type PodSpec struct {
+	Containers []struct {
+		Name      string               `yaml:"name"`
+		Resources ResourceRequirements `yaml:"resources"`
+	} `yaml:"containers"`
+}
+
+// ResourceRequirements are the requests and limits of a container, or the storage request of a
+// PersistentVolumeClaim. The values are Kubernetes quantities, e.g. 500m or 10Gi.
+type ResourceRequirements struct {
+	Requests map[string]string `yaml:"requests"`
+	Limits   map[string]string `yaml:"limits"`
+}
+
+// request returns the request of a resource, which defaults to its limit if only the limit is set.
+func (r ResourceRequirements) request(name string) string {
+	if v, ok := r.Requests[name]; ok {
+		return v
+	}
+
+	return r.Limits[name]
+}
+
+type workloadSpec struct {
+	Replicas *int64 `yaml:"replicas"`
+	Template struct {
+		Spec PodSpec `yaml:"spec"`
+	} `yaml:"template"`
+	VolumeClaimTemplates []*PersistentVolumeClaim `yaml:"volumeClaimTemplates"`
+}
+
+// Manifests are the Kubernetes objects of a set of manifests that can be costed.
+type Manifests struct {
+	StorageClasses         []*StorageClass
+	PersistentVolumeClaims []*PersistentVolumeClaim
+	Services               []*Service
+	Workloads              []*Workload
+}
+
+// LoadManifests reads the Kubernetes objects of a manifest file, or of the manifest files in a
+// directory. Files in a directory that aren't manifests are skipped.
+func LoadManifests(path string) (*Manifests, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifests{}
+
+	if !info.IsDir() {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		err = m.add(b)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error parsing %s", path)
+		}
+
+		return m, nil
+	}
+
+	for _, file := range FindManifestFiles(path) {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		err = m.add(b)
+		if err != nil {
+			log.Warnf("Skipping Kubernetes manifest %s: %s", file, err)
+		}
+	}
+
+	return m, nil
+}
+
+// IsManifest returns true if path is a file of Kubernetes objects, e.g. the output of
+// `helm template`, or a directory that contains them.
+func IsManifest(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	if info.IsDir() {
+		return len(FindManifestFiles(path)) > 0
+	}
+
+	return isManifestFile(path)
+}
+
+// FindManifestFiles returns the Kubernetes manifest files in a directory. Helm charts are skipped
+// since their templates have to be rendered with `helm template` first.
+func FindManifestFiles(dir string) []string {
+	var files []string
+
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+
+			depth := strings.Count(strings.TrimPrefix(path, dir), string(filepath.Separator))
+			if strings.HasPrefix(d.Name(), ".") || depth > maxManifestDirDepth {
+				return filepath.SkipDir
+			}
+
+			if _, err := os.Stat(filepath.Join(path, "Chart.yaml")); err == nil {
+				log.Debugf("Skipping Helm chart %s, render it with `helm template` to cost its resources", path)
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if isManifestFile(path) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	sort.Strings(files)
+	return files
+}
+
+func isManifestFile(path string) bool {
+	ext := filepath.Ext(path)
+	if ext != ".yaml" && ext != ".yml" {
+		return false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	docs, err := decodeDocuments(b)
+	if err != nil {
+		return false
+	}
+
+	for _, doc := range docs {
+		var obj object
+		if doc.Decode(&obj) == nil && obj.APIVersion != "" && obj.Kind != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeDocuments returns the documents of a multi-document YAML file. Empty documents, which
+// `helm template` outputs for templates that render nothing, are skipped.
+func decodeDocuments(b []byte) ([]*yaml.Node, error) {
+	var docs []*yaml.Node
+
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+			docs = append(docs, &doc)
+		}
+	}
+
+	return docs, nil
+}
+
+func (m *Manifests) add(b []byte) error {
+	docs, err := decodeDocuments(b)
+	if err != nil {
+		return err
+	}
+
+	for _, doc := range docs {
+		err = m.addObject(doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifests) addObject(node *yaml.Node) error {
+	var obj object
+	err := node.Decode(&obj)
+	if err != nil {
+		return err
+	}
+
+	switch obj.Kind {
+	case "List":
+		var list struct {
+			Items yaml.Node `yaml:"items"`
+		}
+		err = node.Decode(&list)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range list.Items.Content {
+			err = m.addObject(item)
+			if err != nil {
+				return err
+			}
+		}
+	case "StorageClass":
+		var sc StorageClass
+		err = node.Decode(&sc)
+		m.StorageClasses = append(m.StorageClasses, &sc)
+	case "PersistentVolumeClaim":
+		var pvc PersistentVolumeClaim
+		err = node.Decode(&pvc)
+		m.PersistentVolumeClaims = append(m.PersistentVolumeClaims, &pvc)
+	case "Service":
+		var svc Service
+		err = node.Decode(&svc)
+		m.Services = append(m.Services, &svc)
+	case "Pod":
+		var pod struct {
+			Spec PodSpec `yaml:"spec"`
+		}
+		err = node.Decode(&pod)
+		m.Workloads = append(m.Workloads, &Workload{Kind: obj.Kind, Metadata: obj.Metadata, Replicas: 1, Pod: pod.Spec})
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController":
+		var w struct {
+			Spec workloadSpec `yaml:"spec"`
+		}
+		err = node.Decode(&w)
+
+		replicas := int64(1)
+		if w.Spec.Replicas != nil && obj.Kind != "DaemonSet" {
+			replicas = *w.Spec.Replicas
+		}
+
+		m.Workloads = append(m.Workloads, &Workload{
+			Kind:                 obj.Kind,
+			Metadata:             obj.Metadata,
+			Replicas:             replicas,
+			Pod:                  w.Spec.Template.Spec,
+			VolumeClaimTemplates: w.Spec.VolumeClaimTemplates,
+		})
+	}
+
+	return errors.Wrapf(err, "Error parsing %s %s", obj.Kind, obj.Metadata.key())
+}
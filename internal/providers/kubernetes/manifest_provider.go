@@ -0,0 +1,112 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/providers/terraform/aws"
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// ManifestProvider costs the cloud resources of Kubernetes manifests, e.g. the output of
+// `helm template`, on AKS or EKS. The disks, load balancers and nodes that the cluster creates for
+// the objects are converted to Terraform resources, so they're costed with the resources of the
+// Terraform registry.
+type ManifestProvider struct {
+	ctx  *config.ProjectContext
+	Path string
+}
+
+func NewManifestProvider(ctx *config.ProjectContext) schema.Provider {
+	return &ManifestProvider{
+		ctx:  ctx,
+		Path: ctx.ProjectConfig.Path,
+	}
+}
+
+func (p *ManifestProvider) Type() string {
+	return "kubernetes_manifest"
+}
+
+func (p *ManifestProvider) DisplayType() string {
+	return "Kubernetes manifest"
+}
+
+func (p *ManifestProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *ManifestProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	manifests, err := LoadManifests(p.Path)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error reading Kubernetes manifests")
+	}
+
+	cloud := strings.ToLower(p.ctx.ProjectConfig.KubernetesCloud)
+	if cloud == "" {
+		cloud = detectCloud(manifests)
+	}
+
+	if _, ok := terraformProviders[cloud]; !ok {
+		if cloud == "" {
+			return []*schema.Project{project}, errors.New("Could not detect the cloud of the Kubernetes manifests, set kubernetes_cloud to aws or azure in the config file")
+		}
+
+		return []*schema.Project{project}, fmt.Errorf("Unsupported kubernetes_cloud %s, it must be aws or azure", cloud)
+	}
+
+	plan, err := newConverter(cloud, p.region(cloud), manifests, p.ctx.ProjectConfig).planJSON()
+	if err != nil {
+		return []*schema.Project{project}, err
+	}
+
+	j, err := json.Marshal(plan)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error converting Kubernetes manifests to Terraform plan JSON")
+	}
+
+	parser := terraform.NewParser(p.ctx, false)
+	_, project.PartialResources, err = parser.ParseJSON(j, usage)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error parsing Kubernetes manifests")
+	}
+
+	return []*schema.Project{project}, nil
+}
+
+// region returns the region of the cluster, which defaults to the AWS_REGION or AWS_DEFAULT_REGION
+// environment variables on AWS, or the default region of the Terraform provider.
+func (p *ManifestProvider) region(cloud string) string {
+	if p.ctx.ProjectConfig.KubernetesRegion != "" {
+		return p.ctx.ProjectConfig.KubernetesRegion
+	}
+
+	if cloud == cloudAzure {
+		return azure.DefaultProviderRegion
+	}
+
+	for _, k := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+
+	return aws.DefaultProviderRegion
+}
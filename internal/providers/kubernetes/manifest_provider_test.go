@@ -0,0 +1,133 @@
+package kubernetes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func TestManifestProviderAKS(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path:             filepath.Join("testdata", "aks"),
+		KubernetesRegion: "westeurope",
+		KubernetesNodePool: &config.KubernetesNodePool{
+			InstanceType: "Standard_D4s_v3",
+			CPU:          "3860m",
+			Memory:       "12Gi",
+		},
+	}, log.Fields{})
+
+	resources := loadResources(t, ctx)
+
+	assert.ElementsMatch(t, []string{
+		`azurerm_managed_disk.persistent_volume_claim["shop/uploads"]`,
+		`azurerm_managed_disk.persistent_volume_claim["shop/search-index"]`,
+		`azurerm_managed_disk.persistent_volume_claim["shop/data-postgres-0"]`,
+		`azurerm_managed_disk.persistent_volume_claim["shop/data-postgres-1"]`,
+		`kubernetes_persistent_volume_claim.persistent_volume_claim["shop/shared-assets"]`,
+		`azurerm_lb.kubernetes`,
+		`azurerm_lb.kubernetes-internal`,
+		`azurerm_lb_rule.service["shop/web:80/TCP"]`,
+		`azurerm_lb_rule.service["shop/web:443/TCP"]`,
+		`azurerm_lb_rule.service["shop/api.internal:8080/TCP"]`,
+		`azurerm_public_ip.service["shop/web"]`,
+		`azurerm_kubernetes_cluster_node_pool.nodes`,
+	}, keys(resources))
+
+	// Claims without a storage class use the default storage class of AKS
+	uploads := resources[`azurerm_managed_disk.persistent_volume_claim["shop/uploads"]`]
+	assert.Equal(t, "westeurope", *uploads.CostComponents[0].ProductFilter.Region)
+	assert.Contains(t, uploads.CostComponents[0].Name, "E10")
+
+	assert.Contains(t, resources[`azurerm_managed_disk.persistent_volume_claim["shop/data-postgres-0"]`].CostComponents[0].Name, "P6")
+
+	searchIndex := resources[`azurerm_managed_disk.persistent_volume_claim["shop/search-index"]`]
+	require.Len(t, searchIndex.CostComponents, 3)
+	assert.Equal(t, "Storage (ultra, 256 GiB)", searchIndex.CostComponents[0].Name)
+	assert.True(t, decimal.NewFromInt(4000).Equal(*searchIndex.CostComponents[1].HourlyQuantity))
+
+	assert.True(t, resources[`kubernetes_persistent_volume_claim.persistent_volume_claim["shop/shared-assets"]`].IsSkipped)
+
+	// The workloads request 4 CPUs and 12.5Gi, which with the DaemonSet needs 2 nodes
+	nodes := resources["azurerm_kubernetes_cluster_node_pool.nodes"]
+	assert.True(t, decimal.NewFromInt(2*730).Equal(*nodes.CostComponents[0].MonthlyQuantity))
+	assert.Contains(t, nodes.CostComponents[0].Name, "Standard_D4s_v3")
+}
+
+func TestManifestProviderEKS(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-central-1")
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: filepath.Join("testdata", "eks.yaml"),
+	}, log.Fields{})
+
+	resources := loadResources(t, ctx)
+
+	assert.ElementsMatch(t, []string{
+		`aws_ebs_volume.persistent_volume_claim["default/data"]`,
+		`aws_ebs_volume.persistent_volume_claim["default/logs"]`,
+		`aws_lb.service["default/api"]`,
+		`aws_elb.service["default/legacy"]`,
+	}, keys(resources))
+
+	// The claim uses the default StorageClass of the manifests, with 50 IOPS per GiB
+	data := resources[`aws_ebs_volume.persistent_volume_claim["default/data"]`]
+	assert.Equal(t, "eu-central-1", *data.CostComponents[0].ProductFilter.Region)
+	assert.Equal(t, "Storage (provisioned IOPS SSD, io2)", data.CostComponents[0].Name)
+	assert.True(t, decimal.NewFromInt(19).Equal(*data.CostComponents[0].MonthlyQuantity))
+	assert.True(t, decimal.NewFromInt(950).Equal(*data.CostComponents[1].MonthlyQuantity))
+
+	assert.Equal(t, "Storage (general purpose SSD, gp2)", resources[`aws_ebs_volume.persistent_volume_claim["default/logs"]`].CostComponents[0].Name)
+	assert.Equal(t, "Network load balancer", resources[`aws_lb.service["default/api"]`].CostComponents[0].Name)
+}
+
+func TestManifestProviderUnknownCloud(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.yaml")
+	err := os.WriteFile(path, []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  type: LoadBalancer\n"), 0600)
+	require.NoError(t, err)
+
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{Path: path}, log.Fields{})
+
+	_, err = NewManifestProvider(ctx).LoadResources(map[string]*schema.UsageData{})
+	assert.ErrorContains(t, err, "set kubernetes_cloud")
+
+	ctx.ProjectConfig.KubernetesCloud = "Azure"
+	_, err = NewManifestProvider(ctx).LoadResources(map[string]*schema.UsageData{})
+	assert.NoError(t, err)
+}
+
+func loadResources(t *testing.T, ctx *config.ProjectContext) map[string]*schema.Resource {
+	t.Helper()
+
+	usageData := usage.NewBlankUsageFile().ToUsageDataMap()
+	projects, err := NewManifestProvider(ctx).LoadResources(usageData)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	projects[0].BuildResources(usageData)
+
+	resources := make(map[string]*schema.Resource)
+	for _, r := range projects[0].Resources {
+		resources[r.Name] = r
+	}
+
+	return resources
+}
+
+func keys(m map[string]*schema.Resource) []string {
+	var k []string
+	for name := range m {
+		k = append(k, name)
+	}
+
+	return k
+}
@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindManifestFiles(t *testing.T) {
+	// Helm charts and YAML files that aren't manifests are skipped
+	assert.Equal(t, []string{
+		filepath.Join("testdata", "aks", "rendered.yaml"),
+		filepath.Join("testdata", "aks", "storage.yaml"),
+	}, FindManifestFiles(filepath.Join("testdata", "aks")))
+
+	assert.True(t, IsManifest(filepath.Join("testdata", "eks.yaml")))
+	assert.False(t, IsManifest(filepath.Join("testdata", "aks", "values.yaml")))
+}
+
+func TestLoadManifests(t *testing.T) {
+	m, err := LoadManifests(filepath.Join("testdata", "aks"))
+	require.NoError(t, err)
+
+	assert.Len(t, m.StorageClasses, 1)
+	assert.Len(t, m.PersistentVolumeClaims, 4)
+	assert.Len(t, m.Services, 3)
+	require.Len(t, m.Workloads, 3)
+
+	assert.Equal(t, "web", m.Workloads[0].Metadata.Name)
+	assert.Equal(t, int64(4), m.Workloads[0].Replicas)
+	assert.Equal(t, "100m", m.Workloads[0].Pod.Containers[1].Resources.request("cpu"))
+	assert.Equal(t, int64(2), m.Workloads[1].Replicas)
+	assert.Len(t, m.Workloads[1].VolumeClaimTemplates, 1)
+	assert.Equal(t, "DaemonSet", m.Workloads[2].Kind)
+	assert.Equal(t, int64(1), m.Workloads[2].Replicas)
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := map[string]decimal.Decimal{
+		"500m":  decimal.NewFromFloat(0.5),
+		"2":     decimal.NewFromInt(2),
+		"1.5":   decimal.NewFromFloat(1.5),
+		"128Mi": decimal.NewFromInt(128 << 20),
+		"1Gi":   decimal.NewFromInt(1 << 30),
+		"1G":    decimal.NewFromInt(1e9),
+		"129e6": decimal.NewFromInt(129e6),
+		"2Ei":   decimal.NewFromInt(2 << 60),
+		"1E":    decimal.NewFromInt(1e18),
+	}
+
+	for s, expected := range tests {
+		q, err := parseQuantity(s)
+		require.NoError(t, err, s)
+		assert.True(t, expected.Equal(q), "%s: expected %s, got %s", s, expected, q)
+	}
+
+	_, err := parseQuantity("1 gig")
+	assert.Error(t, err)
+}
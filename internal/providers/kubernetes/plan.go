@@ -0,0 +1,317 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+const (
+	azureInternalLBAnnotation = "service.beta.kubernetes.io/azure-load-balancer-internal"
+	awsLBTypeAnnotation       = "service.beta.kubernetes.io/aws-load-balancer-type"
+	awsNLBClass               = "service.k8s.aws/nlb"
+)
+
+// terraformProviders are the Terraform providers of the clouds.
+var terraformProviders = map[string]string{
+	cloudAWS:   "aws",
+	cloudAzure: "azurerm",
+}
+
+// converter converts Kubernetes manifests to Terraform plan JSON of the cloud resources that the
+// cluster creates for them, so they're costed with the resources of the Terraform registry.
+type converter struct {
+	cloud     string
+	region    string
+	manifests *Manifests
+	nodePool  *config.KubernetesNodePool
+
+	storageClasses      map[string]*volumeClass
+	defaultStorageClass string
+
+	plan *terraform.PlanBuilder
+}
+
+func newConverter(cloud string, region string, manifests *Manifests, projectConfig *config.Project) *converter {
+	classes, defaultClass := storageClasses(cloud, manifests, projectConfig.KubernetesStorageClasses)
+
+	return &converter{
+		cloud:               cloud,
+		region:              region,
+		manifests:           manifests,
+		nodePool:            projectConfig.KubernetesNodePool,
+		storageClasses:      classes,
+		defaultStorageClass: defaultClass,
+		plan:                terraform.NewPlanBuilder(),
+	}
+}
+
+// planJSON returns the Terraform plan JSON of the disks of the PersistentVolumeClaims, the load
+// balancers of the LoadBalancer Services and, if a node pool is configured, the nodes that the
+// workloads need.
+func (c *converter) planJSON() (map[string]interface{}, error) {
+	for _, pvc := range c.manifests.PersistentVolumeClaims {
+		c.addVolume(pvc, pvc.Metadata.key())
+	}
+
+	for _, w := range c.manifests.Workloads {
+		// Each pod of a StatefulSet has a claim for each template, named <template>-<pod>
+		for i := int64(0); i < w.Replicas; i++ {
+			for _, tmpl := range w.VolumeClaimTemplates {
+				c.addVolume(tmpl, fmt.Sprintf("%s/%s-%s-%d", w.Metadata.namespace(), tmpl.Metadata.Name, w.Metadata.Name, i))
+			}
+		}
+	}
+
+	for _, svc := range c.manifests.Services {
+		if svc.Spec.Type == "LoadBalancer" {
+			c.addLoadBalancer(svc)
+		}
+	}
+
+	if c.nodePool != nil {
+		err := c.addNodePool()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	provider := terraformProviders[c.cloud]
+	c.plan.AddProvider(provider, provider, c.region)
+
+	return c.plan.PlanJSON(), nil
+}
+
+// add adds a resource with the address <type>.<name>["<namespace>/<name>"]. The names of Kubernetes
+// objects can have dots, so they're the key of the address rather than its name.
+func (c *converter) add(t string, name string, key string, values map[string]interface{}) {
+	address := t + "." + name
+	if key != "" {
+		address += fmt.Sprintf("[%q]", key)
+	}
+
+	if c.cloud == cloudAzure && strings.HasPrefix(t, "azurerm_") {
+		values["location"] = c.region
+	}
+
+	c.plan.AddResource(terraform.NewPlanResource(terraformProviders[c.cloud], address, t, name, values))
+}
+
+// addVolume adds the disk of a PersistentVolumeClaim. Claims of storage classes that don't
+// provision disks are added with their Kubernetes type, so they're shown as unsupported.
+func (c *converter) addVolume(pvc *PersistentVolumeClaim, key string) {
+	className := c.defaultStorageClass
+	if pvc.Spec.StorageClassName != nil {
+		className = *pvc.Spec.StorageClassName
+	}
+
+	if className == "" {
+		log.Debugf("Skipping Kubernetes PersistentVolumeClaim %s since it's bound to a volume that isn't dynamically provisioned", key)
+		return
+	}
+
+	class, ok := c.storageClasses[className]
+	if !ok {
+		log.Warnf("Kubernetes StorageClass %s of PersistentVolumeClaim %s is not in the manifests, set its disk type with kubernetes_storage_classes in the config file. Using the %s StorageClass.", className, key, c.defaultStorageClass)
+		class = c.storageClasses[c.defaultStorageClass]
+		if class == nil {
+			class = builtinStorageClasses[c.cloud][defaultStorageClasses[c.cloud]]
+		}
+	}
+
+	if class.diskType == "" {
+		c.add("kubernetes_persistent_volume_claim", "persistent_volume_claim", key, map[string]interface{}{})
+		return
+	}
+
+	var size int64
+	if storage := pvc.Spec.Resources.request("storage"); storage != "" {
+		var err error
+		size, err = storageGiB(storage)
+		if err != nil {
+			log.Warnf("Ignoring the storage request of Kubernetes PersistentVolumeClaim %s: %s", key, err)
+		}
+	}
+
+	values := make(map[string]interface{})
+
+	switch c.cloud {
+	case cloudAzure:
+		values["storage_account_type"] = class.diskType
+		if size > 0 {
+			values["disk_size_gb"] = size
+		}
+		if class.iops > 0 {
+			values["disk_iops_read_write"] = class.iops
+		}
+		if class.throughput > 0 {
+			values["disk_mbps_read_write"] = class.throughput
+		}
+
+		c.add("azurerm_managed_disk", "persistent_volume_claim", key, values)
+	case cloudAWS:
+		values["type"] = class.diskType
+		if size > 0 {
+			values["size"] = size
+		}
+
+		iops := class.iops
+		if class.iopsPerGB > 0 {
+			iops = class.iopsPerGB * size
+		}
+		if iops > 0 {
+			values["iops"] = iops
+		}
+		if class.throughput > 0 {
+			values["throughput"] = class.throughput
+		}
+
+		c.add("aws_ebs_volume", "persistent_volume_claim", key, values)
+	}
+}
+
+// addLoadBalancer adds the load balancer of a LoadBalancer Service. AKS adds a rule for each port to
+// the shared kubernetes load balancer, and a public IP for Services that aren't internal. EKS
+// creates a Network Load Balancer with the AWS Load Balancer Controller, or a Classic Load
+// Balancer with the in-tree controller.
+func (c *converter) addLoadBalancer(svc *Service) {
+	key := svc.Metadata.key()
+
+	switch c.cloud {
+	case cloudAzure:
+		lbName := "kubernetes"
+		internal := svc.Metadata.Annotations[azureInternalLBAnnotation] == "true"
+		if internal {
+			lbName = "kubernetes-internal"
+		}
+
+		c.add("azurerm_lb", lbName, "", map[string]interface{}{"sku": "Standard"})
+
+		for _, port := range svc.Spec.Ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = "TCP"
+			}
+
+			c.add("azurerm_lb_rule", "service", fmt.Sprintf("%s:%d/%s", key, port.Port, protocol), map[string]interface{}{})
+		}
+
+		if !internal {
+			c.add("azurerm_public_ip", "service", key, map[string]interface{}{
+				"sku":               "Standard",
+				"allocation_method": "Static",
+			})
+		}
+	case cloudAWS:
+		lbType := strings.ToLower(svc.Metadata.Annotations[awsLBTypeAnnotation])
+		if lbType == "nlb" || lbType == "external" || svc.Spec.LoadBalancerClass == awsNLBClass {
+			c.add("aws_lb", "service", key, map[string]interface{}{"load_balancer_type": "network"})
+			return
+		}
+
+		c.add("aws_elb", "service", key, map[string]interface{}{})
+	}
+}
+
+// addNodePool adds the nodes that the CPU and memory requests of the workloads fit on. The requests
+// of DaemonSets are on every node, so they're taken off the allocatable resources of each node.
+func (c *converter) addNodePool() error {
+	nodeCPU, err := parseQuantity(c.nodePool.CPU)
+	if err != nil {
+		return errors.Wrap(err, "Invalid cpu of kubernetes_node_pool")
+	}
+
+	nodeMemory, err := parseQuantity(c.nodePool.Memory)
+	if err != nil {
+		return errors.Wrap(err, "Invalid memory of kubernetes_node_pool")
+	}
+
+	var cpu, memory, daemonCPU, daemonMemory decimal.Decimal
+
+	for _, w := range c.manifests.Workloads {
+		podCPU, podMemory := c.podRequests(w)
+
+		if w.Kind == "DaemonSet" {
+			daemonCPU = daemonCPU.Add(podCPU)
+			daemonMemory = daemonMemory.Add(podMemory)
+			continue
+		}
+
+		if podCPU.GreaterThan(nodeCPU) || podMemory.GreaterThan(nodeMemory) {
+			log.Warnf("The pods of Kubernetes %s %s request more CPU or memory than the nodes of kubernetes_node_pool have", w.Kind, w.Metadata.key())
+		}
+
+		replicas := decimal.NewFromInt(w.Replicas)
+		cpu = cpu.Add(podCPU.Mul(replicas))
+		memory = memory.Add(podMemory.Mul(replicas))
+	}
+
+	allocatableCPU := nodeCPU.Sub(daemonCPU)
+	allocatableMemory := nodeMemory.Sub(daemonMemory)
+	if !allocatableCPU.IsPositive() || !allocatableMemory.IsPositive() {
+		return errors.New("The DaemonSets of the Kubernetes manifests request more CPU or memory than the nodes of kubernetes_node_pool have")
+	}
+
+	nodes := cpu.Div(allocatableCPU).Ceil().IntPart()
+	if n := memory.Div(allocatableMemory).Ceil().IntPart(); n > nodes {
+		nodes = n
+	}
+	if nodes < c.nodePool.MinCount {
+		nodes = c.nodePool.MinCount
+	}
+	if nodes < 1 {
+		nodes = 1
+	}
+
+	switch c.cloud {
+	case cloudAzure:
+		c.add("azurerm_kubernetes_cluster_node_pool", "nodes", "", map[string]interface{}{
+			"vm_size":    c.nodePool.InstanceType,
+			"node_count": nodes,
+		})
+	case cloudAWS:
+		c.add("aws_eks_node_group", "nodes", "", map[string]interface{}{
+			"instance_types": []interface{}{c.nodePool.InstanceType},
+			"scaling_config": []interface{}{map[string]interface{}{
+				"desired_size": nodes,
+				"min_size":     nodes,
+				"max_size":     nodes,
+			}},
+		})
+	}
+
+	return nil
+}
+
+// podRequests returns the CPU and memory that a pod of a workload requests.
+func (c *converter) podRequests(w *Workload) (decimal.Decimal, decimal.Decimal) {
+	var cpu, memory decimal.Decimal
+
+	for _, container := range w.Pod.Containers {
+		for _, r := range []struct {
+			name  string
+			total *decimal.Decimal
+		}{{"cpu", &cpu}, {"memory", &memory}} {
+			v := container.Resources.request(r.name)
+			if v == "" {
+				continue
+			}
+
+			q, err := parseQuantity(v)
+			if err != nil {
+				log.Warnf("Ignoring the %s request of container %s of Kubernetes %s %s: %s", r.name, container.Name, w.Kind, w.Metadata.key(), err)
+				continue
+			}
+
+			*r.total = r.total.Add(q)
+		}
+	}
+
+	return cpu, memory
+}
@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// quantitySuffixes are the multipliers of the suffixes of Kubernetes quantities.
+// See: https://kubernetes.io/docs/reference/kubernetes-api/common-definitions/quantity/
+var quantitySuffixes = map[string]decimal.Decimal{
+	"n":  decimal.New(1, -9),
+	"u":  decimal.New(1, -6),
+	"m":  decimal.New(1, -3),
+	"k":  decimal.New(1, 3),
+	"M":  decimal.New(1, 6),
+	"G":  decimal.New(1, 9),
+	"T":  decimal.New(1, 12),
+	"P":  decimal.New(1, 15),
+	"E":  decimal.New(1, 18),
+	"Ki": decimal.NewFromInt(1 << 10),
+	"Mi": decimal.NewFromInt(1 << 20),
+	"Gi": decimal.NewFromInt(1 << 30),
+	"Ti": decimal.NewFromInt(1 << 40),
+	"Pi": decimal.NewFromInt(1 << 50),
+	"Ei": decimal.NewFromInt(1 << 60),
+}
+
+var gibibyte = decimal.NewFromInt(1 << 30)
+
+// parseQuantity parses a Kubernetes quantity, e.g. 500m CPUs or 10Gi of memory.
+func parseQuantity(s string) (decimal.Decimal, error) {
+	s = strings.TrimSpace(s)
+
+	number := s
+	multiplier := decimal.NewFromInt(1)
+
+	// Binary suffixes are checked first so Mi isn't read as M. Quantities with an exponent, e.g.
+	// 129e6, end with a digit so they don't match a suffix.
+	for _, n := range []int{2, 1} {
+		if len(s) <= n {
+			continue
+		}
+
+		if m, ok := quantitySuffixes[s[len(s)-n:]]; ok {
+			number, multiplier = s[:len(s)-n], m
+			break
+		}
+	}
+
+	d, err := decimal.NewFromString(number)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid quantity %q", s)
+	}
+
+	return d.Mul(multiplier), nil
+}
+
+// storageGiB returns the size of a storage request in GiB, rounded up.
+func storageGiB(s string) (int64, error) {
+	d, err := parseQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.Div(gibibyte).Ceil().IntPart(), nil
+}
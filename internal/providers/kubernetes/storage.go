@@ -0,0 +1,189 @@
+package kubernetes
+
+import (
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	cloudAWS   = "aws"
+	cloudAzure = "azure"
+
+	defaultClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+)
+
+// volumeClass is the type of the volumes that a storage class provisions.
+type volumeClass struct {
+	// diskType is the SKU of Azure disks, e.g. Premium_LRS, or the type of EBS volumes, e.g. gp3.
+	// It's empty if the volumes aren't disks, e.g. Azure Files shares and EFS file systems.
+	diskType   string
+	iops       int64
+	iopsPerGB  int64
+	throughput int64
+}
+
+// builtinStorageClasses are the storage classes that AKS and EKS clusters are created with.
+var builtinStorageClasses = map[string]map[string]*volumeClass{
+	cloudAzure: {
+		"default":               {diskType: "StandardSSD_LRS"},
+		"managed":               {diskType: "StandardSSD_LRS"},
+		"managed-csi":           {diskType: "StandardSSD_LRS"},
+		"managed-premium":       {diskType: "Premium_LRS"},
+		"managed-csi-premium":   {diskType: "Premium_LRS"},
+		"azurefile":             {},
+		"azurefile-csi":         {},
+		"azurefile-premium":     {},
+		"azurefile-csi-premium": {},
+	},
+	cloudAWS: {
+		"gp2": {diskType: "gp2"},
+		"gp3": {diskType: "gp3"},
+	},
+}
+
+// defaultStorageClasses are the default storage classes of AKS and EKS clusters.
+var defaultStorageClasses = map[string]string{
+	cloudAzure: "default",
+	cloudAWS:   "gp2",
+}
+
+// storageClasses returns the volume classes of the storage classes of a cluster by name, and the
+// name of its default storage class. Storage classes of the manifests override the built in
+// storage classes, and the storage classes of the config override both.
+func storageClasses(cloud string, manifests *Manifests, overrides map[string]string) (map[string]*volumeClass, string) {
+	classes := make(map[string]*volumeClass)
+	for name, c := range builtinStorageClasses[cloud] {
+		classes[name] = c
+	}
+
+	defaultClass := defaultStorageClasses[cloud]
+
+	for _, sc := range manifests.StorageClasses {
+		classes[sc.Metadata.Name] = manifestVolumeClass(sc)
+
+		if sc.Metadata.Annotations[defaultClassAnnotation] == "true" {
+			defaultClass = sc.Metadata.Name
+		}
+	}
+
+	for name, diskType := range overrides {
+		c := &volumeClass{diskType: diskType}
+		if existing, ok := classes[name]; ok && existing.diskType != "" {
+			c.iops, c.iopsPerGB, c.throughput = existing.iops, existing.iopsPerGB, existing.throughput
+		}
+
+		classes[name] = c
+	}
+
+	return classes, defaultClass
+}
+
+// manifestVolumeClass returns the volume class of a StorageClass of the Azure Disk or EBS CSI
+// drivers, or of their in-tree provisioners.
+func manifestVolumeClass(sc *StorageClass) *volumeClass {
+	params := make(map[string]string, len(sc.Parameters))
+	for k, v := range sc.Parameters {
+		params[strings.ToLower(k)] = v
+	}
+
+	switch sc.Provisioner {
+	case "disk.csi.azure.com", "kubernetes.io/azure-disk":
+		diskType := params["skuname"]
+		if diskType == "" {
+			diskType = params["storageaccounttype"]
+		}
+		if diskType == "" {
+			diskType = "StandardSSD_LRS"
+		}
+
+		return &volumeClass{
+			diskType:   diskType,
+			iops:       intParam(sc, params, "diskiopsreadwrite"),
+			throughput: intParam(sc, params, "diskmbpsreadwrite"),
+		}
+	case "ebs.csi.aws.com", "kubernetes.io/aws-ebs":
+		diskType := strings.ToLower(params["type"])
+		if diskType == "" {
+			// The in-tree provisioner defaults to gp2 and the CSI driver to gp3
+			diskType = "gp3"
+			if sc.Provisioner == "kubernetes.io/aws-ebs" {
+				diskType = "gp2"
+			}
+		}
+
+		return &volumeClass{
+			diskType:   diskType,
+			iops:       intParam(sc, params, "iops"),
+			iopsPerGB:  intParam(sc, params, "iopspergb"),
+			throughput: intParam(sc, params, "throughput"),
+		}
+	}
+
+	log.Debugf("Kubernetes StorageClass %s with provisioner %s doesn't provision disks", sc.Metadata.Name, sc.Provisioner)
+	return &volumeClass{}
+}
+
+func intParam(sc *StorageClass, params map[string]string, key string) int64 {
+	v, ok := params[key]
+	if !ok {
+		return 0
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		log.Warnf("Ignoring parameter %s of Kubernetes StorageClass %s since it isn't a number: %s", key, sc.Metadata.Name, v)
+		return 0
+	}
+
+	return i
+}
+
+// detectCloud returns the cloud of the manifests from the provisioners of their storage classes,
+// the annotations of their services and the names of the storage classes of their volume claims.
+// An empty string is returned if it can't be detected.
+func detectCloud(manifests *Manifests) string {
+	for _, sc := range manifests.StorageClasses {
+		switch {
+		case strings.Contains(sc.Provisioner, "azure"):
+			return cloudAzure
+		case strings.Contains(sc.Provisioner, "aws"):
+			return cloudAWS
+		}
+	}
+
+	for _, svc := range manifests.Services {
+		if strings.HasPrefix(svc.Spec.LoadBalancerClass, "service.k8s.aws/") {
+			return cloudAWS
+		}
+
+		for k := range svc.Metadata.Annotations {
+			switch {
+			case strings.HasPrefix(k, "service.beta.kubernetes.io/azure-"):
+				return cloudAzure
+			case strings.HasPrefix(k, "service.beta.kubernetes.io/aws-"):
+				return cloudAWS
+			}
+		}
+	}
+
+	claims := append([]*PersistentVolumeClaim{}, manifests.PersistentVolumeClaims...)
+	for _, w := range manifests.Workloads {
+		claims = append(claims, w.VolumeClaimTemplates...)
+	}
+
+	for _, pvc := range claims {
+		if pvc.Spec.StorageClassName == nil {
+			continue
+		}
+
+		for _, cloud := range []string{cloudAzure, cloudAWS} {
+			if _, ok := builtinStorageClasses[cloud][*pvc.Spec.StorageClassName]; ok && *pvc.Spec.StorageClassName != "default" {
+				return cloud
+			}
+		}
+	}
+
+	return ""
+}
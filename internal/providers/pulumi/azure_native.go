@@ -0,0 +1,289 @@
+package pulumi
+
+import (
+	"strings"
+
+	"github.com/infracost/infracost/internal/providers/terraform/azure"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// azureNativeResource maps the properties of an azure-native resource to the values of the
+// equivalent azurerm Terraform resource.
+type azureNativeResource struct {
+	// terraformType returns the type of the Terraform resource, which can depend on the properties
+	terraformType func(props map[string]interface{}) string
+	values        func(props map[string]interface{}) map[string]interface{}
+}
+
+// azureNativeResources are the azure-native resources by <module>:<Name>. Unlike the aws and gcp
+// providers, azure-native isn't bridged from Terraform, its properties are the properties of the
+// Azure Resource Manager API.
+var azureNativeResources = map[string]*azureNativeResource{
+	"compute:VirtualMachine": {
+		terraformType: func(props map[string]interface{}) string {
+			osType, _ := lookup(props, "storageProfile.osDisk.osType").(string)
+			if strings.EqualFold(osType, "Windows") || lookup(props, "osProfile.windowsConfiguration") != nil {
+				return "azurerm_windows_virtual_machine"
+			}
+
+			return "azurerm_linux_virtual_machine"
+		},
+		values: func(props map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"size":         lookup(props, "hardwareProfile.vmSize"),
+				"license_type": props["licenseType"],
+				"os_disk": []interface{}{map[string]interface{}{
+					"storage_account_type": lookup(props, "storageProfile.osDisk.managedDisk.storageAccountType"),
+					"disk_size_gb":         lookup(props, "storageProfile.osDisk.diskSizeGB"),
+				}},
+				"additional_capabilities": []interface{}{map[string]interface{}{
+					"ultra_ssd_enabled": lookup(props, "additionalCapabilities.ultraSSDEnabled"),
+				}},
+			}
+		},
+	},
+	"compute:Disk": {
+		terraformType: staticType("azurerm_managed_disk"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"storage_account_type": lookup(props, "sku.name"),
+				"disk_size_gb":         props["diskSizeGB"],
+				"disk_iops_read_write": props["diskIOPSReadWrite"],
+				"disk_mbps_read_write": props["diskMBpsReadWrite"],
+			}
+		},
+	},
+	"storage:StorageAccount": {
+		terraformType: staticType("azurerm_storage_account"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			// The SKU is the tier and replication type, e.g. Standard_LRS
+			sku, _ := lookup(props, "sku.name").(string)
+			tier, replication, _ := strings.Cut(sku, "_")
+
+			return map[string]interface{}{
+				"account_kind":             props["kind"],
+				"account_tier":             tier,
+				"account_replication_type": replication,
+				"access_tier":              props["accessTier"],
+				"nfsv3_enabled":            props["isNfsV3Enabled"],
+			}
+		},
+	},
+	"network:PublicIPAddress": {
+		terraformType: staticType("azurerm_public_ip"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"sku":               lookup(props, "sku.name"),
+				"allocation_method": props["publicIPAllocationMethod"],
+			}
+		},
+	},
+	"containerservice:ManagedCluster": {
+		terraformType: staticType("azurerm_kubernetes_cluster"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			values := map[string]interface{}{
+				"sku_tier": lookup(props, "sku.tier"),
+				"network_profile": []interface{}{map[string]interface{}{
+					"load_balancer_sku": lookup(props, "networkProfile.loadBalancerSku"),
+				}},
+			}
+
+			// The first agent pool is the default node pool
+			if pools, ok := props["agentPoolProfiles"].([]interface{}); ok && len(pools) > 0 {
+				if pool, ok := pools[0].(map[string]interface{}); ok {
+					values["default_node_pool"] = []interface{}{map[string]interface{}{
+						"vm_size":         pool["vmSize"],
+						"node_count":      pool["count"],
+						"min_count":       pool["minCount"],
+						"os_disk_size_gb": pool["osDiskSizeGB"],
+						"os_disk_type":    pool["osDiskType"],
+					}}
+				}
+			}
+
+			return values
+		},
+	},
+	"web:AppServicePlan": {
+		terraformType: staticType("azurerm_app_service_plan"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			size := lookup(props, "sku.size")
+			if size == nil {
+				size = lookup(props, "sku.name")
+			}
+
+			return map[string]interface{}{
+				"kind": props["kind"],
+				"sku": []interface{}{map[string]interface{}{
+					"tier":     lookup(props, "sku.tier"),
+					"size":     size,
+					"capacity": lookup(props, "sku.capacity"),
+				}},
+			}
+		},
+	},
+	"web:WebApp": {
+		terraformType: func(props map[string]interface{}) string {
+			kind, _ := props["kind"].(string)
+			kind = strings.ToLower(kind)
+			if strings.Contains(kind, "functionapp") {
+				return "azurerm_function_app"
+			}
+
+			// Linux apps have kinds like "app,linux" or "app,linux,container"
+			if strings.Contains(kind, "linux") {
+				return "azurerm_linux_web_app"
+			}
+
+			return "azurerm_windows_web_app"
+		},
+		values: noValues,
+	},
+	"network:LoadBalancer": {
+		terraformType: staticType("azurerm_lb"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"sku": lookup(props, "sku.name"),
+			}
+		},
+	},
+	"network:NatGateway": {
+		terraformType: staticType("azurerm_nat_gateway"),
+		values:        noValues,
+	},
+	"network:PrivateEndpoint": {
+		terraformType: staticType("azurerm_private_endpoint"),
+		values:        noValues,
+	},
+	// The resources below are free, they're mapped so they aren't shown as unsupported
+	"authorization:RoleAssignment": {
+		terraformType: staticType("azurerm_role_assignment"),
+		values:        noValues,
+	},
+	"compute:AvailabilitySet": {
+		terraformType: staticType("azurerm_availability_set"),
+		values:        noValues,
+	},
+	"managedidentity:UserAssignedIdentity": {
+		terraformType: staticType("azurerm_user_assigned_identity"),
+		values:        noValues,
+	},
+	"network:NetworkInterface": {
+		terraformType: staticType("azurerm_network_interface"),
+		values:        noValues,
+	},
+	"network:NetworkSecurityGroup": {
+		terraformType: staticType("azurerm_network_security_group"),
+		values:        noValues,
+	},
+	"network:SecurityRule": {
+		terraformType: staticType("azurerm_network_security_rule"),
+		values:        noValues,
+	},
+	"network:Subnet": {
+		terraformType: staticType("azurerm_subnet"),
+		values:        noValues,
+	},
+	"network:VirtualNetwork": {
+		terraformType: staticType("azurerm_virtual_network"),
+		values:        noValues,
+	},
+	"storage:BlobContainer": {
+		terraformType: staticType("azurerm_storage_container"),
+		values:        noValues,
+	},
+	"resources:ResourceGroup": {
+		terraformType: staticType("azurerm_resource_group"),
+		values: func(props map[string]interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"name": props["resourceGroupName"],
+			}
+		},
+	},
+}
+
+// azureNativeFreeResources are the Terraform resources that azure-native resources map to which have
+// no cost of their own, but aren't free resources of the Terraform provider. Web apps are billed by
+// their App Service plan.
+var azureNativeFreeResources = map[string]bool{
+	"azurerm_linux_web_app":   true,
+	"azurerm_windows_web_app": true,
+}
+
+// markAzureNativeFreeResources marks the resources in azureNativeFreeResources as free resources.
+func markAzureNativeFreeResources(resources []*schema.PartialResource) {
+	for _, r := range resources {
+		if r.ResourceData == nil || !azureNativeFreeResources[r.ResourceData.Type] {
+			continue
+		}
+
+		r.CoreResource = nil
+		r.Resource = &schema.Resource{
+			Name:        r.ResourceData.Address,
+			IsSkipped:   true,
+			NoPrice:     true,
+			SkipMessage: "Free resource.",
+		}
+		r.CloudResourceIDs = azure.DefaultCloudResourceIDFunc(r.ResourceData)
+	}
+}
+
+// azureNativeResourceKey returns the key of an azure-native resource type in azureNativeResources.
+// The module of the type can have an API version, e.g. azure-native:compute/v20230301:VirtualMachine.
+func azureNativeResourceKey(pulumiType string) string {
+	parts := strings.Split(pulumiType, ":")
+	if len(parts) != 3 || parts[0] != "azure-native" {
+		return ""
+	}
+
+	module := strings.Split(parts[1], "/")[0]
+	return module + ":" + parts[2]
+}
+
+// azureNativeValues returns the Terraform type and values of an azure-native resource, or an empty
+// type if it has no equivalent.
+func azureNativeValues(pulumiType string, props map[string]interface{}) (string, map[string]interface{}) {
+	r, ok := azureNativeResources[azureNativeResourceKey(pulumiType)]
+	if !ok {
+		return "", nil
+	}
+
+	values := r.values(props)
+	values["location"] = props["location"]
+	values["resource_group_name"] = props["resourceGroupName"]
+	values["tags"] = props["tags"]
+
+	for k, v := range values {
+		if v == nil {
+			delete(values, k)
+		}
+	}
+
+	return r.terraformType(props), values
+}
+
+func staticType(t string) func(map[string]interface{}) string {
+	return func(map[string]interface{}) string {
+		return t
+	}
+}
+
+func noValues(map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// lookup returns the value of a nested property, e.g. sku.name.
+func lookup(props map[string]interface{}, path string) interface{} {
+	var v interface{} = props
+
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		v = m[key]
+	}
+
+	return v
+}
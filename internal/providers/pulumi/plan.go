@@ -0,0 +1,202 @@
+package pulumi
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// terraformProviders are the Terraform providers of the Pulumi providers, with the Pulumi config
+// key of their region.
+var terraformProviders = map[string]struct {
+	name        string
+	regionKey   string
+	regionInput string
+}{
+	"aws":          {name: "aws", regionKey: "aws:region", regionInput: "region"},
+	"gcp":          {name: "google", regionKey: "gcp:region", regionInput: "region"},
+	"azure-native": {name: "azurerm", regionKey: "azure-native:location", regionInput: "location"},
+}
+
+// converter converts a Pulumi preview to Terraform plan JSON, so the resources are parsed with
+// the resources of the Terraform registry.
+type converter struct {
+	preview *Preview
+	plan    *terraform.PlanBuilder
+	// providerKeys are the keys of the provider configs of the Pulumi provider resources by URN
+	providerKeys map[string]string
+	// resourceGroupLocations are the locations of the Azure resource groups of the preview by name
+	resourceGroupLocations map[string]interface{}
+	// addresses are the Terraform addresses of the resources by URN
+	addresses map[string]string
+}
+
+func newConverter(preview *Preview) *converter {
+	c := &converter{
+		preview:                preview,
+		plan:                   terraform.NewPlanBuilder(),
+		providerKeys:           make(map[string]string),
+		resourceGroupLocations: make(map[string]interface{}),
+		addresses:              make(map[string]string),
+	}
+
+	// The default providers use the region of the stack config
+	for _, provider := range terraformProviders {
+		if region, ok := preview.Config[provider.regionKey].(string); ok && region != "" {
+			c.plan.AddProvider(provider.name, provider.name, region)
+		}
+	}
+
+	for _, step := range preview.Steps {
+		for _, state := range []*ResourceState{step.OldState, step.NewState} {
+			if state == nil {
+				continue
+			}
+
+			c.addProvider(state)
+
+			if azureNativeResourceKey(state.Type) == "resources:ResourceGroup" {
+				props := state.values()
+				if name, ok := props["resourceGroupName"].(string); ok {
+					c.resourceGroupLocations[name] = props["location"]
+				}
+			}
+		}
+	}
+
+	return c
+}
+
+// addProvider adds the provider config of a Pulumi provider resource, e.g. pulumi:providers:aws.
+func (c *converter) addProvider(state *ResourceState) {
+	pkg := strings.TrimPrefix(state.Type, "pulumi:providers:")
+	provider, ok := terraformProviders[pkg]
+	if !ok || pkg == state.Type {
+		return
+	}
+
+	region, _ := state.values()[provider.regionInput].(string)
+	if region == "" {
+		return
+	}
+
+	key := provider.name + "." + state.name()
+	c.plan.AddProvider(key, provider.name, region)
+	c.providerKeys[state.URN] = key
+}
+
+// planJSON returns the Terraform plan JSON of the preview. Created resources are only in the
+// planned values, deleted resources are only in the prior state, and updated and replaced
+// resources are in both.
+func (c *converter) planJSON() map[string]interface{} {
+	for _, step := range c.preview.Steps {
+		var oldState, newState *ResourceState
+		var action []string
+
+		switch step.Op {
+		case OpSame:
+			oldState, newState = step.NewState, step.NewState
+			action = []string{"no-op"}
+		case OpCreate, OpCreateReplacement:
+			newState = step.NewState
+			action = []string{"create"}
+		case OpDelete, OpDeleteReplaced:
+			oldState = step.OldState
+			action = []string{"delete"}
+		case OpUpdate, OpImport:
+			oldState, newState = step.OldState, step.NewState
+			action = []string{"update"}
+		case OpReplace:
+			oldState, newState = step.OldState, step.NewState
+			action = []string{"delete", "create"}
+		default:
+			log.Debugf("Ignoring %s step of Pulumi resource %s", step.Op, step.URN)
+			continue
+		}
+
+		if oldState != nil {
+			if r := c.planResource(oldState); r != nil {
+				c.plan.AddPastResource(r)
+				c.plan.SetActions(r.Address, action)
+			}
+		}
+
+		if newState != nil {
+			if r := c.planResource(newState); r != nil {
+				c.plan.AddResource(r)
+				c.plan.SetActions(r.Address, action)
+			}
+		}
+	}
+
+	return c.plan.PlanJSON()
+}
+
+// planResource returns the Terraform resource of a Pulumi resource, or nil if it's not a resource
+// of a cloud provider, e.g. a component resource or the stack.
+func (c *converter) planResource(state *ResourceState) *terraform.PlanResource {
+	if !state.Custom || strings.HasPrefix(state.Type, "pulumi:") {
+		return nil
+	}
+
+	pkg := strings.Split(state.Type, ":")[0]
+	provider, ok := terraformProviders[pkg]
+	if !ok {
+		log.Debugf("Skipping Pulumi resource %s since the %s provider isn't supported", state.URN, pkg)
+		return nil
+	}
+
+	var t string
+	var values map[string]interface{}
+	if pkg == "azure-native" {
+		t, values = azureNativeValues(state.Type, state.values())
+		if t == "" {
+			// Resources without an equivalent are kept so they're shown as unsupported
+			t, values = state.Type, map[string]interface{}{}
+		}
+
+		// Resources that don't set their location are in the location of their resource group
+		if _, ok := values["location"]; !ok {
+			if rg, ok := values["resource_group_name"].(string); ok && c.resourceGroupLocations[rg] != nil {
+				values["location"] = c.resourceGroupLocations[rg]
+			}
+		}
+	} else {
+		t = terraformType(state.Type)
+		if t == "" {
+			return nil
+		}
+		values = terraformValues(state.values())
+	}
+
+	r := terraform.NewPlanResource(provider.name, c.address(state, t), t, state.name(), values)
+
+	// The provider is referenced by <URN>::<ID>
+	if i := strings.LastIndex(state.Provider, "::"); i >= 0 {
+		r.ProviderConfigKey = c.providerKeys[state.Provider[:i]]
+	}
+
+	return r
+}
+
+// address returns the Terraform address of a Pulumi resource, which is its Terraform type and
+// name. Resources of components can have the same name, so the URN is used to keep them apart.
+func (c *converter) address(state *ResourceState, t string) string {
+	if address, ok := c.addresses[state.URN]; ok {
+		return address
+	}
+
+	address := fmt.Sprintf("%s.%s", t, state.name())
+	for _, a := range c.addresses {
+		if a == address {
+			address = fmt.Sprintf("%s[%q]", address, state.URN)
+			break
+		}
+	}
+
+	c.addresses[state.URN] = address
+	return address
+}
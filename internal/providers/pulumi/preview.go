@@ -0,0 +1,100 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// StepOp is the operation of a step of a Pulumi preview.
+// See: https://www.pulumi.com/docs/reference/cli/pulumi_preview/
+type StepOp string
+
+// Steps with other operations, e.g. read and refresh, are for resources that aren't managed by
+// the stack, so they're ignored.
+const (
+	OpSame              StepOp = "same"
+	OpCreate            StepOp = "create"
+	OpUpdate            StepOp = "update"
+	OpDelete            StepOp = "delete"
+	OpReplace           StepOp = "replace"
+	OpCreateReplacement StepOp = "create-replacement"
+	OpDeleteReplaced    StepOp = "delete-replaced"
+	OpImport            StepOp = "import"
+)
+
+// unknownValue is the value of outputs that aren't known until the resource is created.
+const unknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+
+// Preview is the output of `pulumi preview --json`.
+type Preview struct {
+	Config        map[string]interface{} `json:"config"`
+	Steps         []*Step                `json:"steps"`
+	ChangeSummary map[string]int         `json:"changeSummary"`
+}
+
+type Step struct {
+	Op       StepOp         `json:"op"`
+	URN      string         `json:"urn"`
+	Provider string         `json:"provider"`
+	OldState *ResourceState `json:"oldState"`
+	NewState *ResourceState `json:"newState"`
+}
+
+type ResourceState struct {
+	URN      string                 `json:"urn"`
+	Type     string                 `json:"type"`
+	Custom   bool                   `json:"custom"`
+	Provider string                 `json:"provider"`
+	Parent   string                 `json:"parent"`
+	Inputs   map[string]interface{} `json:"inputs"`
+	Outputs  map[string]interface{} `json:"outputs"`
+}
+
+// IsPreviewJSON returns true if b is the output of `pulumi preview --json`.
+func IsPreviewJSON(b []byte) bool {
+	var preview Preview
+	err := json.Unmarshal(b, &preview)
+	if err != nil || preview.Steps == nil {
+		return false
+	}
+
+	for _, step := range preview.Steps {
+		if !strings.HasPrefix(step.URN, "urn:pulumi:") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// name returns the name of the resource, which is the last part of its URN
+// (urn:pulumi:<stack>::<project>::<qualified type>::<name>).
+func (s *ResourceState) name() string {
+	parts := strings.Split(s.URN, "::")
+	return parts[len(parts)-1]
+}
+
+// values returns the properties of the resource. The outputs have the properties that are set by
+// the provider, and the inputs are the properties that are set by the program. The inputs are
+// preferred since the outputs of the old state of an update are out of date.
+func (s *ResourceState) values() map[string]interface{} {
+	values := make(map[string]interface{})
+
+	for _, props := range []map[string]interface{}{s.Outputs, s.Inputs} {
+		for k, v := range props {
+			// Pulumi adds __defaults, __meta and the like to the inputs
+			if strings.HasPrefix(k, "__") || isUnknown(v) {
+				continue
+			}
+
+			values[k] = v
+		}
+	}
+
+	return values
+}
+
+func isUnknown(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && s == unknownValue
+}
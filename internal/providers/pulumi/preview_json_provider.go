@@ -0,0 +1,80 @@
+package pulumi
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/providers/terraform"
+	"github.com/infracost/infracost/internal/schema"
+)
+
+// PreviewJSONProvider costs the output of `pulumi preview --json`. The resources of the aws, gcp and
+// azure-native providers are converted to Terraform resources, so they're costed with the resources
+// of the Terraform registry.
+type PreviewJSONProvider struct {
+	ctx                  *config.ProjectContext
+	Path                 string
+	includePastResources bool
+}
+
+func NewPreviewJSONProvider(ctx *config.ProjectContext, includePastResources bool) schema.Provider {
+	return &PreviewJSONProvider{
+		ctx:                  ctx,
+		Path:                 ctx.ProjectConfig.Path,
+		includePastResources: includePastResources,
+	}
+}
+
+func (p *PreviewJSONProvider) Type() string {
+	return "pulumi_preview_json"
+}
+
+func (p *PreviewJSONProvider) DisplayType() string {
+	return "Pulumi preview JSON"
+}
+
+func (p *PreviewJSONProvider) AddMetadata(metadata *schema.ProjectMetadata) {
+	metadata.ConfigSha = p.ctx.ProjectConfig.ConfigSha
+}
+
+func (p *PreviewJSONProvider) LoadResources(usage map[string]*schema.UsageData) ([]*schema.Project, error) {
+	b, err := os.ReadFile(p.Path)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error reading Pulumi preview JSON file")
+	}
+
+	var preview Preview
+	err = json.Unmarshal(b, &preview)
+	if err != nil {
+		return []*schema.Project{}, errors.Wrap(err, "Error parsing Pulumi preview JSON file")
+	}
+
+	metadata := config.DetectProjectMetadata(p.ctx.ProjectConfig.Path)
+	metadata.Type = p.Type()
+	p.AddMetadata(metadata)
+	name := p.ctx.ProjectConfig.Name
+	if name == "" {
+		name = metadata.GenerateProjectName(p.ctx.RunContext.VCSMetadata.Remote, p.ctx.RunContext.IsCloudEnabled())
+	}
+
+	project := schema.NewProject(name, metadata)
+
+	j, err := json.Marshal(newConverter(&preview).planJSON())
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error converting Pulumi preview to Terraform plan JSON")
+	}
+
+	parser := terraform.NewParser(p.ctx, p.includePastResources)
+	project.PartialPastResources, project.PartialResources, err = parser.ParseJSON(j, usage)
+	if err != nil {
+		return []*schema.Project{project}, errors.Wrap(err, "Error parsing Pulumi preview JSON file")
+	}
+
+	markAzureNativeFreeResources(project.PartialPastResources)
+	markAzureNativeFreeResources(project.PartialResources)
+
+	return []*schema.Project{project}, nil
+}
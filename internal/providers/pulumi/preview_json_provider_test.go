@@ -0,0 +1,103 @@
+package pulumi
+
+import (
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/infracost/infracost/internal/config"
+	"github.com/infracost/infracost/internal/schema"
+	"github.com/infracost/infracost/internal/usage"
+)
+
+func TestPreviewJSONProvider(t *testing.T) {
+	ctx := config.NewProjectContext(config.EmptyRunContext(), &config.Project{
+		Path: filepath.Join("testdata", "preview.json"),
+	}, log.Fields{})
+
+	usageData := usage.NewBlankUsageFile().ToUsageDataMap()
+	projects, err := NewPreviewJSONProvider(ctx, true).LoadResources(usageData)
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+
+	project := projects[0]
+	project.BuildResources(usageData)
+
+	resources := resourcesByName(project.Resources)
+	pastResources := resourcesByName(project.PastResources)
+
+	assert.ElementsMatch(t, []string{
+		"aws_instance.web",
+		"aws_db_instance.db",
+		"google_compute_instance.worker",
+		"azurerm_resource_group.rg",
+		"azurerm_linux_virtual_machine.vm",
+		"azurerm_storage_account.assets",
+		"azurerm_linux_web_app.site",
+		"azure-native:signalrservice:SignalR.events",
+	}, keys(resources))
+
+	// Created resources aren't in the past resources, and deleted resources are only in the past resources
+	assert.ElementsMatch(t, []string{
+		"aws_db_instance.db",
+		"aws_nat_gateway.nat",
+		"google_compute_instance.worker",
+	}, keys(pastResources))
+
+	web := resources["aws_instance.web"]
+	assert.False(t, web.IsSkipped)
+	assert.Equal(t, "us-west-2", *web.CostComponents[0].ProductFilter.Region)
+	assert.Equal(t, map[string]string{"Name": "web"}, web.Tags)
+	require.Len(t, web.SubResources, 2)
+	assert.Equal(t, "root_block_device", web.SubResources[0].Name)
+	assert.Equal(t, "ebs_block_device[0]", web.SubResources[1].Name)
+
+	// Resources with an explicit provider use its region
+	db := resources["aws_db_instance.db"]
+	assert.Equal(t, "eu-west-1", *db.CostComponents[0].ProductFilter.Region)
+	assert.Contains(t, db.CostComponents[0].Name, "db.t3.large")
+	assert.Contains(t, pastResources["aws_db_instance.db"].CostComponents[0].Name, "db.t3.small")
+
+	worker := resources["google_compute_instance.worker"]
+	assert.False(t, worker.IsSkipped)
+	assert.Equal(t, "us-central1", *worker.CostComponents[0].ProductFilter.Region)
+
+	// The VM doesn't set its location, so it's in the location of its resource group
+	vm := resources["azurerm_linux_virtual_machine.vm"]
+	assert.False(t, vm.IsSkipped)
+	assert.Equal(t, "westeurope", *vm.CostComponents[0].ProductFilter.Region)
+	require.Len(t, vm.SubResources, 1)
+
+	assert.False(t, resources["azurerm_storage_account.assets"].IsSkipped)
+	assert.True(t, resources["azurerm_resource_group.rg"].NoPrice)
+	assert.True(t, resources["azurerm_linux_web_app.site"].NoPrice)
+	assert.True(t, resources["azure-native:signalrservice:SignalR.events"].IsSkipped)
+	assert.False(t, resources["azure-native:signalrservice:SignalR.events"].NoPrice)
+}
+
+func TestIsPreviewJSON(t *testing.T) {
+	assert.True(t, IsPreviewJSON([]byte(`{"steps": [{"op": "create", "urn": "urn:pulumi:dev::app::aws:s3/bucket:Bucket::b"}]}`)))
+	assert.False(t, IsPreviewJSON([]byte(`{"format_version": "1.1", "planned_values": {}}`)))
+	assert.False(t, IsPreviewJSON([]byte(`{"steps": [{"op": "create", "urn": "b"}]}`)))
+}
+
+func resourcesByName(resources []*schema.Resource) map[string]*schema.Resource {
+	m := make(map[string]*schema.Resource)
+	for _, r := range resources {
+		m[r.Name] = r
+	}
+
+	return m
+}
+
+func keys(m map[string]*schema.Resource) []string {
+	var k []string
+	for name := range m {
+		k = append(k, name)
+	}
+
+	return k
+}
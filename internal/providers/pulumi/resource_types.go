@@ -0,0 +1,179 @@
+package pulumi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/infracost/infracost/internal/providers/terraform"
+)
+
+// resourceTypes are the Pulumi resource types whose Terraform type isn't their module and name in
+// snake case, e.g. aws:rds/instance:Instance is aws_db_instance rather than aws_rds_instance.
+var resourceTypes = map[string]string{
+	"aws:alb/loadBalancer:LoadBalancer":                         "aws_alb",
+	"aws:apigateway/restApi:RestApi":                            "aws_api_gateway_rest_api",
+	"aws:apigateway/stage:Stage":                                "aws_api_gateway_stage",
+	"aws:directconnect/connection:Connection":                   "aws_dx_connection",
+	"aws:ec2clientvpn/endpoint:Endpoint":                        "aws_ec2_client_vpn_endpoint",
+	"aws:ec2clientvpn/networkAssociation:NetworkAssociation":    "aws_ec2_client_vpn_network_association",
+	"aws:ec2transitgateway/vpcAttachment:VpcAttachment":         "aws_ec2_transit_gateway_vpc_attachment",
+	"aws:ec2transitgateway/peeringAttachment:PeeringAttachment": "aws_ec2_transit_gateway_peering_attachment",
+	"aws:elb/loadBalancer:LoadBalancer":                         "aws_elb",
+	"aws:lb/loadBalancer:LoadBalancer":                          "aws_lb",
+	"aws:rds/instance:Instance":                                 "aws_db_instance",
+	"aws:s3/bucketV2:BucketV2":                                  "aws_s3_bucket",
+	"gcp:cloudrun/service:Service":                              "google_cloud_run_service",
+	"gcp:cloudrunv2/service:Service":                            "google_cloud_run_v2_service",
+}
+
+// providerPrefixes are the prefixes of the Terraform resource types of the Pulumi providers that
+// are bridged from Terraform providers.
+var providerPrefixes = map[string]string{
+	"aws": "aws",
+	"gcp": "google",
+}
+
+// terraformType returns the Terraform resource type of a resource of the aws or gcp Pulumi
+// providers, whose type is <provider>:<module>/<name>:<Name>. An empty string is returned for
+// resources of other providers.
+func terraformType(pulumiType string) string {
+	if t, ok := resourceTypes[pulumiType]; ok {
+		return t
+	}
+
+	parts := strings.Split(pulumiType, ":")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	prefix, ok := providerPrefixes[parts[0]]
+	if !ok {
+		return ""
+	}
+
+	module := strings.Split(parts[1], "/")[0]
+	name := snakeCase(parts[2])
+
+	t := prefix + "_" + module + "_" + name
+	if strings.HasPrefix(name, module+"_") {
+		t = prefix + "_" + name
+	}
+
+	// The EC2 resources of the Terraform AWS provider don't have a prefix, e.g. aws_instance and
+	// aws_nat_gateway
+	if module == "ec2" {
+		registry := terraform.GetResourceRegistryMap()
+		if _, ok := (*registry)[t]; !ok {
+			if _, ok := (*registry)[prefix+"_"+name]; ok {
+				return prefix + "_" + name
+			}
+		}
+	}
+
+	return t
+}
+
+// mapAttributes are the attributes of Terraform resources that are maps rather than blocks, so
+// their keys are user defined and kept as they are.
+var mapAttributes = map[string]bool{
+	"effective_labels": true,
+	"labels":           true,
+	"metadata":         true,
+	"parameters":       true,
+	"tags":             true,
+	"tags_all":         true,
+	"user_labels":      true,
+	"variables":        true,
+}
+
+// terraformValues converts the properties of a resource of a bridged Pulumi provider to the values of
+// the Terraform resource. The properties are camel case versions of the Terraform attributes, and
+// blocks that can be set once are objects rather than lists. Pulumi also makes the names of lists
+// plural, e.g. ebsBlockDevices is ebs_block_device, so lists are set with both names.
+func terraformValues(props map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(props))
+
+	for k, v := range props {
+		key := snakeCase(k)
+		if mapAttributes[key] {
+			values[key] = v
+			continue
+		}
+
+		values[key] = terraformValue(v)
+	}
+
+	for k, v := range values {
+		if _, ok := v.([]interface{}); !ok || mapAttributes[k] {
+			continue
+		}
+
+		if singular := singularName(k); singular != k {
+			if _, ok := values[singular]; !ok {
+				values[singular] = v
+			}
+		}
+	}
+
+	return values
+}
+
+func terraformValue(v interface{}) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		return []interface{}{terraformValues(x)}
+	case []interface{}:
+		list := make([]interface{}, len(x))
+		for i, item := range x {
+			if m, ok := item.(map[string]interface{}); ok {
+				list[i] = terraformValues(m)
+			} else {
+				list[i] = item
+			}
+		}
+		return list
+	}
+
+	return v
+}
+
+// snakeCase converts a camel case name to snake case, e.g. diskSizeGb to disk_size_gb and
+// ipv6CidrBlock to ipv6_cidr_block.
+func snakeCase(s string) string {
+	runes := []rune(s)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+					b.WriteRune('_')
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// singularName returns the singular of a plural attribute name.
+func singularName(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies"):
+		return strings.TrimSuffix(s, "ies") + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"):
+		return strings.TrimSuffix(s, "es")
+	case strings.HasSuffix(s, "ss"):
+		return s
+	case strings.HasSuffix(s, "s"):
+		return strings.TrimSuffix(s, "s")
+	}
+
+	return s
+}
@@ -0,0 +1,45 @@
+package pulumi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerraformType(t *testing.T) {
+	tests := map[string]string{
+		"aws:ec2/instance:Instance":                 "aws_instance",
+		"aws:ec2/natGateway:NatGateway":             "aws_nat_gateway",
+		"aws:ec2/vpcEndpoint:VpcEndpoint":           "aws_vpc_endpoint",
+		"aws:rds/instance:Instance":                 "aws_db_instance",
+		"aws:s3/bucket:Bucket":                      "aws_s3_bucket",
+		"aws:cloudwatch/logGroup:LogGroup":          "aws_cloudwatch_log_group",
+		"aws:lb/loadBalancer:LoadBalancer":          "aws_lb",
+		"gcp:sql/databaseInstance:DatabaseInstance": "google_sql_database_instance",
+		"gcp:container/nodePool:NodePool":           "google_container_node_pool",
+		"kubernetes:core/v1:Service":                "",
+	}
+
+	for pulumiType, expected := range tests {
+		assert.Equal(t, expected, terraformType(pulumiType), pulumiType)
+	}
+}
+
+func TestTerraformValues(t *testing.T) {
+	values := terraformValues(map[string]interface{}{
+		"instanceType":    "t3.micro",
+		"rootBlockDevice": map[string]interface{}{"volumeSize": 8.0},
+		"ebsBlockDevices": []interface{}{map[string]interface{}{"volumeSize": 100.0}},
+		"tags":            map[string]interface{}{"CostCenter": "shop"},
+		"ipv6CidrBlock":   "::/56",
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"instance_type":     "t3.micro",
+		"root_block_device": []interface{}{map[string]interface{}{"volume_size": 8.0}},
+		"ebs_block_devices": []interface{}{map[string]interface{}{"volume_size": 100.0}},
+		"ebs_block_device":  []interface{}{map[string]interface{}{"volume_size": 100.0}},
+		"tags":              map[string]interface{}{"CostCenter": "shop"},
+		"ipv6_cidr_block":   "::/56",
+	}, values)
+}
@@ -22,7 +22,7 @@ func GetAzureRMCDNEndpointRegistryItem() *schema.RegistryItem {
 }
 
 func NewAzureRMCDNEndpoint(d *schema.ResourceData, u *schema.UsageData) *schema.Resource {
-	region := regionToZone(lookupRegion(d, []string{}))
+	region := RegionToZone(lookupRegion(d, []string{}))
 
 	var costComponents []*schema.CostComponent
 
@@ -244,7 +244,9 @@ func cdnCostComponent(name, unit, region, productName, skuName, meterName, start
 	}
 }
 
-func regionToZone(region string) string {
+// RegionToZone returns the billing zone of a region, which networking services such as CDN and
+// Front Door are priced by.
+func RegionToZone(region string) string {
 	return map[string]string{
 		"westus":             "Zone 1",
 		"westus2":            "Zone 1",
@@ -27,7 +27,7 @@ func newFrontdoor(d *schema.ResourceData, u *schema.UsageData) *schema.Resource
 	if strings.HasPrefix(strings.ToLower(region), "usgov") {
 		region = "US Gov Zone 1"
 	} else {
-		region = regionToZone(region)
+		region = RegionToZone(region)
 	}
 
 	rulesCounter := 0
@@ -27,7 +27,7 @@ func newFrontdoorFirewallPolicy(d *schema.ResourceData, u *schema.UsageData) *sc
 	if strings.HasPrefix(strings.ToLower(region), "usgov") {
 		region = "US Gov Zone 1"
 	} else {
-		region = regionToZone(region)
+		region = RegionToZone(region)
 	}
 
 	customRules := 0
@@ -39,7 +39,7 @@ func newVirtualNetworkPeering(d *schema.ResourceData, u *schema.UsageData) *sche
 }
 
 func virtualNetworkPeeringConvertRegion(region string) string {
-	zone := regionToZone(region)
+	zone := RegionToZone(region)
 
 	if strings.HasPrefix(strings.ToLower(region), "usgov") {
 		zone = "US Gov Zone 1"
@@ -151,6 +151,12 @@ func (p *Parser) populateUsageData(resData map[string]*schema.ResourceData, usag
 	}
 }
 
+// ParseJSON returns the past and current resources of plan JSON that isn't created by Terraform,
+// e.g. plan JSON converted from the resources of another IaC tool.
+func (p *Parser) ParseJSON(j []byte, usage map[string]*schema.UsageData) ([]*schema.PartialResource, []*schema.PartialResource, error) {
+	return p.parseJSON(j, usage)
+}
+
 func (p *Parser) parseJSON(j []byte, usage map[string]*schema.UsageData) ([]*schema.PartialResource, []*schema.PartialResource, error) {
 	baseResources := p.loadUsageFileResources(usage)
 
@@ -0,0 +1,164 @@
+package terraform
+
+import (
+	"sort"
+)
+
+// PlanResource is a resource in the planned values or prior state of Terraform plan JSON.
+type PlanResource struct {
+	Address      string                 `json:"address"`
+	Mode         string                 `json:"mode"`
+	Type         string                 `json:"type"`
+	Name         string                 `json:"name"`
+	ProviderName string                 `json:"provider_name"`
+	Values       map[string]interface{} `json:"values"`
+
+	// ProviderConfigKey is the key of the provider config of the resource in the configuration,
+	// or empty for the default provider.
+	ProviderConfigKey string `json:"-"`
+}
+
+// NewPlanResource returns a managed resource of a HashiCorp provider, e.g. aws or azurerm.
+func NewPlanResource(provider string, address string, t string, name string, values map[string]interface{}) *PlanResource {
+	return &PlanResource{
+		Address:      address,
+		Mode:         "managed",
+		Type:         t,
+		Name:         name,
+		ProviderName: "registry.terraform.io/hashicorp/" + provider,
+		Values:       values,
+	}
+}
+
+// PlanBuilder builds Terraform plan JSON for providers that convert other formats, such as Pulumi
+// previews or Kubernetes manifests, to Terraform resources so they're parsed with the resources
+// of the Terraform registry.
+type PlanBuilder struct {
+	providerConfig map[string]interface{}
+	past           map[string]*PlanResource
+	current        map[string]*PlanResource
+	actions        map[string][]string
+}
+
+func NewPlanBuilder() *PlanBuilder {
+	return &PlanBuilder{
+		providerConfig: make(map[string]interface{}),
+		past:           make(map[string]*PlanResource),
+		current:        make(map[string]*PlanResource),
+		actions:        make(map[string][]string),
+	}
+}
+
+// AddProvider adds the config of a provider in a region. The key is the name of the provider for
+// its default config, or <name>.<alias> for others.
+func (b *PlanBuilder) AddProvider(key string, name string, region string) {
+	b.providerConfig[key] = map[string]interface{}{
+		"name": name,
+		"expressions": map[string]interface{}{
+			"region": map[string]interface{}{"constant_value": region},
+		},
+	}
+}
+
+// AddResource adds a resource to the planned values, replacing any resource with the same address.
+func (b *PlanBuilder) AddResource(r *PlanResource) {
+	b.current[r.Address] = r
+}
+
+// AddPastResource adds a resource to the prior state, replacing any resource with the same address.
+func (b *PlanBuilder) AddPastResource(r *PlanResource) {
+	b.past[r.Address] = r
+}
+
+// SetActions sets the actions of the change of the resource with the address, e.g. create or
+// delete. Plans without resource changes are parsed as if all the resources are created.
+func (b *PlanBuilder) SetActions(address string, actions []string) {
+	b.actions[address] = actions
+}
+
+// PlanJSON returns the plan JSON of the resources, sorted by address.
+func (b *PlanBuilder) PlanJSON() map[string]interface{} {
+	plan := map[string]interface{}{
+		"format_version": "1.0",
+		"planned_values": map[string]interface{}{
+			"root_module": map[string]interface{}{"resources": resourceList(b.current)},
+		},
+		"configuration": map[string]interface{}{
+			"provider_config": b.providerConfig,
+			"root_module":     map[string]interface{}{"resources": b.resourceConfig()},
+		},
+	}
+
+	if len(b.past) > 0 {
+		plan["prior_state"] = map[string]interface{}{
+			"values": map[string]interface{}{
+				"root_module": map[string]interface{}{"resources": resourceList(b.past)},
+			},
+		}
+	}
+
+	if len(b.actions) > 0 {
+		changes := make([]interface{}, 0, len(b.actions))
+		for _, address := range sortedKeys(b.actions) {
+			changes = append(changes, map[string]interface{}{
+				"address": address,
+				"change":  map[string]interface{}{"actions": b.actions[address]},
+			})
+		}
+		plan["resource_changes"] = changes
+	}
+
+	return plan
+}
+
+// resourceConfig returns the configuration of the resources. The configuration has an entry for
+// each resource rather than each instance, so it's addressed without the index of the instance.
+func (b *PlanBuilder) resourceConfig() []interface{} {
+	conf := make([]interface{}, 0, len(b.current))
+	seen := make(map[string]bool)
+
+	for _, resources := range []map[string]*PlanResource{b.current, b.past} {
+		for _, address := range sortedKeys(resources) {
+			r := resources[address]
+
+			base := removeAddressArrayPart(r.Address)
+			if seen[base] {
+				continue
+			}
+			seen[base] = true
+
+			c := map[string]interface{}{
+				"address": base,
+				"mode":    r.Mode,
+				"type":    r.Type,
+				"name":    r.Name,
+			}
+			if r.ProviderConfigKey != "" {
+				c["provider_config_key"] = r.ProviderConfigKey
+			}
+
+			conf = append(conf, c)
+		}
+	}
+
+	return conf
+}
+
+func resourceList(resources map[string]*PlanResource) []*PlanResource {
+	list := make([]*PlanResource, 0, len(resources))
+	for _, address := range sortedKeys(resources) {
+		list = append(list, resources[address])
+	}
+
+	return list
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
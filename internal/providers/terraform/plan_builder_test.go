@@ -0,0 +1,39 @@
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestPlanBuilder(t *testing.T) {
+	b := NewPlanBuilder()
+	b.AddProvider("aws", "aws", "us-east-1")
+	b.AddProvider("aws.west", "aws", "us-west-2")
+
+	b.AddResource(NewPlanResource("aws", `aws_ebs_volume.data["b"]`, "aws_ebs_volume", "data", map[string]interface{}{"size": 20}))
+	b.AddResource(NewPlanResource("aws", `aws_ebs_volume.data["a"]`, "aws_ebs_volume", "data", map[string]interface{}{"size": 10}))
+
+	west := NewPlanResource("aws", "aws_instance.web", "aws_instance", "web", map[string]interface{}{"instance_type": "t3.micro"})
+	west.ProviderConfigKey = "aws.west"
+	b.AddPastResource(west)
+	b.SetActions(west.Address, []string{"delete"})
+
+	j, err := json.Marshal(b.PlanJSON())
+	require.NoError(t, err)
+	plan := gjson.ParseBytes(j)
+
+	assert.Equal(t, []interface{}{`aws_ebs_volume.data["a"]`, `aws_ebs_volume.data["b"]`}, plan.Get("planned_values.root_module.resources.#.address").Value())
+	assert.Equal(t, "registry.terraform.io/hashicorp/aws", plan.Get("planned_values.root_module.resources.0.provider_name").String())
+	assert.Equal(t, "aws_instance.web", plan.Get("prior_state.values.root_module.resources.0.address").String())
+	assert.Equal(t, `["delete"]`, plan.Get(`resource_changes.#(address="aws_instance.web").change.actions`).Raw)
+	assert.Equal(t, "us-west-2", plan.Get("configuration.provider_config.aws\\.west.expressions.region.constant_value").String())
+
+	conf := plan.Get("configuration.root_module.resources")
+	assert.Equal(t, []interface{}{"aws_ebs_volume.data", "aws_instance.web"}, conf.Get("#.address").Value())
+	assert.False(t, conf.Get("0.provider_config_key").Exists())
+	assert.Equal(t, "aws.west", conf.Get("1.provider_config_key").String())
+}
@@ -16,9 +16,15 @@ type CostComponent struct {
 	MonthlyDiscountPerc  float64
 	price                decimal.Decimal
 	customPrice          *decimal.Decimal
+	listPrice            *decimal.Decimal
+	priceAdjustment      string
+	committedPrice       *decimal.Decimal
 	priceHash            string
 	HourlyCost           *decimal.Decimal
 	MonthlyCost          *decimal.Decimal
+	// CommittedMonthlyCost is the monthly cost with the committed price of the commitment scenario
+	// of the run, e.g. a 1-year reservation. It's nil if the component doesn't have a committed price.
+	CommittedMonthlyCost *decimal.Decimal
 }
 
 func (c *CostComponent) CalculateCosts() {
@@ -29,6 +35,9 @@ func (c *CostComponent) CalculateCosts() {
 	if c.MonthlyQuantity != nil {
 		discountMul := decimal.NewFromFloat(1.0 - c.MonthlyDiscountPerc)
 		c.MonthlyCost = decimalPtr(c.price.Mul(*c.MonthlyQuantity).Mul(discountMul))
+		if c.committedPrice != nil {
+			c.CommittedMonthlyCost = decimalPtr(c.committedPrice.Mul(*c.MonthlyQuantity).Mul(discountMul))
+		}
 	}
 }
 
@@ -64,10 +73,58 @@ func (c *CostComponent) CustomPrice() *decimal.Decimal {
 	return c.customPrice
 }
 
+// SetPriceAdjustment replaces the price with the price of a price adjustment, such as a negotiated
+// discount, and keeps the previous price as the list price.
+func (c *CostComponent) SetPriceAdjustment(name string, price decimal.Decimal) {
+	listPrice := c.price
+	c.listPrice = &listPrice
+	c.priceAdjustment = name
+	c.price = price
+}
+
+// ListPrice returns the price before it was adjusted, or nil if the price hasn't been adjusted.
+func (c *CostComponent) ListPrice() *decimal.Decimal {
+	return c.listPrice
+}
+
+// PriceAdjustment returns the name of the price adjustment that was applied to the price.
+func (c *CostComponent) PriceAdjustment() string {
+	return c.priceAdjustment
+}
+
+// SetCommittedPrice sets the price of the component under the commitment scenario of the run.
+func (c *CostComponent) SetCommittedPrice(price decimal.Decimal) {
+	c.committedPrice = &price
+}
+
+// CommittedPrice returns the price under the commitment scenario of the run, or nil if the
+// component can't be committed to.
+func (c *CostComponent) CommittedPrice() *decimal.Decimal {
+	return c.committedPrice
+}
+
 func (c *CostComponent) UnitMultiplierPrice() decimal.Decimal {
 	return c.Price().Mul(c.UnitMultiplier)
 }
 
+func (c *CostComponent) UnitMultiplierListPrice() *decimal.Decimal {
+	if c.listPrice == nil {
+		return nil
+	}
+
+	p := c.listPrice.Mul(c.UnitMultiplier)
+	return &p
+}
+
+func (c *CostComponent) UnitMultiplierCommittedPrice() *decimal.Decimal {
+	if c.committedPrice == nil {
+		return nil
+	}
+
+	p := c.committedPrice.Mul(c.UnitMultiplier)
+	return &p
+}
+
 func (c *CostComponent) UnitMultiplierHourlyQuantity() *decimal.Decimal {
 	if c.HourlyQuantity == nil {
 		return nil
@@ -35,6 +35,11 @@ type ProjectMetadata struct {
 	TerraformWorkspace  string        `json:"terraformWorkspace,omitempty"`
 	VCSSubPath          string        `json:"vcsSubPath,omitempty"`
 	VCSCodeChanged      *bool         `json:"vcsCodeChanged,omitempty"`
+	ArmDeploymentScope  string        `json:"armDeploymentScope,omitempty"`
+	ArmSubscriptionId   string        `json:"armSubscriptionId,omitempty"`
+	ArmResourceGroup    string        `json:"armResourceGroup,omitempty"`
+	ArmLocation         string        `json:"armLocation,omitempty"`
+	ArmParametersFiles  []string      `json:"armParametersFiles,omitempty"`
 	Errors              []ProjectDiag `json:"errors,omitempty"`
 	Warnings            []ProjectDiag `json:"warnings,omitempty"`
 	Policies            Policies      `json:"policies,omitempty"`
@@ -110,6 +115,23 @@ func NewProject(name string, metadata *ProjectMetadata) *Project {
 	}
 }
 
+// AddProjectError adds err to the metadata of projects that failed to load. If the provider didn't
+// return any projects, the error is added to a new project with name and metadata instead.
+func AddProjectError(projects []*Project, name string, metadata *ProjectMetadata, err error) []*Project {
+	if len(projects) == 0 {
+		projects = []*Project{NewProject(name, metadata)}
+	}
+
+	for _, project := range projects {
+		project.Metadata.Errors = append(project.Metadata.Errors, ProjectDiag{
+			Code:    DiagModuleEvaluationFailure,
+			Message: err.Error(),
+		})
+	}
+
+	return projects
+}
+
 // NameWithWorkspace returns the proect Name appended with the paranenthized workspace name
 // from Metadata if one exists.
 func (p *Project) NameWithWorkspace() string {
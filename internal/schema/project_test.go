@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -69,3 +70,18 @@ func TestGenerateProjectName(t *testing.T) {
 		assert.True(t, strings.HasPrefix(result, "project_"))
 	})
 }
+
+func TestAddProjectError(t *testing.T) {
+	err := errors.New("template failed")
+
+	projects := AddProjectError(nil, "app", &ProjectMetadata{Path: "app.json"}, err)
+	assert.Len(t, projects, 1)
+	assert.Equal(t, "app", projects[0].Name)
+	assert.Equal(t, []ProjectDiag{{Code: DiagModuleEvaluationFailure, Message: "template failed"}}, projects[0].Metadata.Errors)
+
+	loaded := []*Project{NewProject("loaded", &ProjectMetadata{Path: "loaded.json"})}
+	projects = AddProjectError(loaded, "app", &ProjectMetadata{Path: "app.json"}, err)
+	assert.Len(t, projects, 1)
+	assert.Equal(t, "loaded", projects[0].Name)
+	assert.Equal(t, []ProjectDiag{{Code: DiagModuleEvaluationFailure, Message: "template failed"}}, projects[0].Metadata.Errors)
+}
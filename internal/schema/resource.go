@@ -13,21 +13,24 @@ var HourToMonthUnitMultiplier = decimal.NewFromInt(730)
 type ResourceFunc func(*ResourceData, *UsageData) *Resource
 
 type Resource struct {
-	Name              string
-	CostComponents    []*CostComponent
-	ActualCosts       []*ActualCosts
-	SubResources      []*Resource
-	HourlyCost        *decimal.Decimal
-	MonthlyCost       *decimal.Decimal
-	IsSkipped         bool
-	NoPrice           bool
-	SkipMessage       string
-	ResourceType      string
-	Tags              map[string]string
-	UsageSchema       []*UsageItem
-	EstimateUsage     EstimateFunc
-	EstimationSummary map[string]bool
-	Metadata          map[string]gjson.Result
+	Name           string
+	CostComponents []*CostComponent
+	ActualCosts    []*ActualCosts
+	SubResources   []*Resource
+	HourlyCost     *decimal.Decimal
+	MonthlyCost    *decimal.Decimal
+	// CommittedMonthlyCost is the monthly cost under the commitment scenario of the run. It's only
+	// set if the resource has cost components with a committed price.
+	CommittedMonthlyCost *decimal.Decimal
+	IsSkipped            bool
+	NoPrice              bool
+	SkipMessage          string
+	ResourceType         string
+	Tags                 map[string]string
+	UsageSchema          []*UsageItem
+	EstimateUsage        EstimateFunc
+	EstimationSummary    map[string]bool
+	Metadata             map[string]gjson.Result
 }
 
 func CalculateCosts(project *Project) {
@@ -39,7 +42,9 @@ func CalculateCosts(project *Project) {
 func (r *Resource) CalculateCosts() {
 	h := decimal.Zero
 	m := decimal.Zero
+	committed := decimal.Zero
 	hasCost := false
+	hasCommittedCost := false
 
 	for _, c := range r.CostComponents {
 		c.CalculateCosts()
@@ -52,6 +57,12 @@ func (r *Resource) CalculateCosts() {
 		if c.MonthlyCost != nil {
 			m = m.Add(*c.MonthlyCost)
 		}
+		if c.CommittedMonthlyCost != nil {
+			hasCommittedCost = true
+			committed = committed.Add(*c.CommittedMonthlyCost)
+		} else if c.MonthlyCost != nil {
+			committed = committed.Add(*c.MonthlyCost)
+		}
 	}
 
 	for _, s := range r.SubResources {
@@ -65,12 +76,21 @@ func (r *Resource) CalculateCosts() {
 		if s.MonthlyCost != nil {
 			m = m.Add(*s.MonthlyCost)
 		}
+		if s.CommittedMonthlyCost != nil {
+			hasCommittedCost = true
+			committed = committed.Add(*s.CommittedMonthlyCost)
+		} else if s.MonthlyCost != nil {
+			committed = committed.Add(*s.MonthlyCost)
+		}
 	}
 
 	if hasCost {
 		r.HourlyCost = &h
 		r.MonthlyCost = &m
 	}
+	if hasCommittedCost {
+		r.CommittedMonthlyCost = &committed
+	}
 	if r.NoPrice {
 		log.Debugf("Skipping free resource %s", r.Name)
 	}
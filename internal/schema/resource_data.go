@@ -20,6 +20,22 @@ type ResourceData struct {
 	Metadata      map[string]gjson.Result
 }
 
+// PropertyChangesMetadataKey is the ResourceData.Metadata key holding the properties
+// a deployment changes on an existing resource, as a JSON array of PropertyChange.
+const PropertyChangesMetadataKey = "propertyChanges"
+
+// ResourceIDMetadataKey is the ResourceData.Metadata key holding the cloud ID of a resource
+// whose address isn't its ID, e.g. the full ID of an ARM resource.
+const ResourceIDMetadataKey = "resourceId"
+
+// PropertyChange describes a single property of an existing resource whose value
+// changes, e.g. sku.name going from S1 to P1v2. Path uses the same dot notation as gjson.
+type PropertyChange struct {
+	Path   string      `json:"path"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
 func NewResourceData(resourceType string, providerName string, address string, tags map[string]string, rawValues gjson.Result) *ResourceData {
 	return &ResourceData{
 		Type:          resourceType,
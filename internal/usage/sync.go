@@ -182,7 +182,7 @@ func syncWildCardResource(wildCardResources map[string]bool, resource *schema.Re
 			wildCardResources[wildCardName] = true
 			if existingResourceUsage, ok := existingResourceUsagesMap[wildCardName]; ok {
 				// Merge the usage schema from the reference usage file
-				refResourceUsage := referenceFile.FindMatchingResourceUsage(resource.Name)
+				refResourceUsage := findReferenceResourceUsage(referenceFile, resource)
 				if refResourceUsage != nil {
 					replaceResourceUsages(resourceUsage, refResourceUsage, ReplaceResourceUsagesOpts{})
 				}
@@ -203,6 +203,16 @@ func syncWildCardResource(wildCardResources map[string]bool, resource *schema.Re
 	return resourceUsage
 }
 
+// findReferenceResourceUsage returns the reference usage of a resource. Resources are matched by the resource
+// type in their address, or by their resource type for addresses that don't contain it, e.g. ARM resource IDs.
+func findReferenceResourceUsage(referenceFile *ReferenceFile, resource *schema.Resource) *ResourceUsage {
+	if refResourceUsage := referenceFile.FindMatchingResourceUsage(resource.Name); refResourceUsage != nil {
+		return refResourceUsage
+	}
+
+	return referenceFile.FindMatchingResourceTypeUsage(resource.ResourceType)
+}
+
 func syncResourceType(projectCtx *config.ProjectContext, resource *schema.Resource, referenceFile *ReferenceFile, existingResourceUsagesMap map[string]*ResourceUsage) *ResourceUsage {
 	resourceUsage := &ResourceUsage{
 		Name: resource.ResourceType,
@@ -241,7 +251,7 @@ func syncResource(projectCtx *config.ProjectContext, resource *schema.Resource,
 	}
 
 	// Merge the usage schema from the reference usage file
-	refResourceUsage := referenceFile.FindMatchingResourceUsage(resource.Name)
+	refResourceUsage := findReferenceResourceUsage(referenceFile, resource)
 	if refResourceUsage != nil {
 		replaceResourceUsages(resourceUsage, refResourceUsage, ReplaceResourceUsagesOpts{})
 	}
@@ -428,3 +428,34 @@ func TestReplaceResourceUsageWithUsageDataDeep(t *testing.T) {
 	assert.Len(t, subResource2.Items, 1)
 	assert.Equal(t, int64(10), subResource2.Items[0].Value.(int64))
 }
+
+func TestFindReferenceResourceUsage(t *testing.T) {
+	referenceFile := &ReferenceFile{
+		UsageFile: &UsageFile{
+			ResourceUsages: []*ResourceUsage{
+				{
+					Name:  "azurerm_storage_account.my_account",
+					Items: []*schema.UsageItem{{Key: "storage_gb", ValueType: schema.Float64}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resource *schema.Resource
+	}{
+		{"terraform address", &schema.Resource{Name: "azurerm_storage_account.logs", ResourceType: "azurerm_storage_account"}},
+		{"arm resource ID", &schema.Resource{Name: "/subscriptions/0000/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/logs", ResourceType: "azurerm_storage_account"}},
+		{"arm copy address", &schema.Resource{Name: "storageCopy[0]", ResourceType: "azurerm_storage_account"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := findReferenceResourceUsage(referenceFile, tt.resource)
+			if assert.NotNil(t, actual) {
+				assert.Equal(t, "azurerm_storage_account.my_account", actual.Name)
+			}
+		})
+	}
+}